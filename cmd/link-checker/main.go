@@ -1,18 +1,31 @@
 package main
 
 import (
+	"bytes"
 	"encoding/json"
+	"errors"
 	"flag"
 	"fmt"
+	"io"
+	"iter"
 	"log"
+	"math"
+	"net/http"
 	"os"
 	"regexp"
+	"runtime/debug"
+	"slices"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
 
+	"github.com/joshbeard/link-validator/internal/cache"
 	"github.com/joshbeard/link-validator/internal/checker"
 	"github.com/joshbeard/link-validator/internal/config"
+	"github.com/joshbeard/link-validator/internal/github"
+	"github.com/joshbeard/link-validator/internal/notify"
+	"github.com/joshbeard/link-validator/internal/ssg"
 )
 
 // version is set via ldflags during build
@@ -34,12 +47,14 @@ func main() {
 	flag.Usage = func() {
 		fmt.Fprintf(os.Stderr, "Link Validator\n\n")
 		fmt.Fprintf(os.Stderr, "A tool to check for broken links in websites by crawling or using sitemaps.\n\n")
-		fmt.Fprintf(os.Stderr, "Usage: %s [options]\n\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "Usage: %s [options] [url]\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "       %s diagnose [options] <url>\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "       %s diff [-fail-on-error=false] <before.json> <after.json>\n\n", os.Args[0])
 		fmt.Fprintf(os.Stderr, "Options:\n")
 		flag.PrintDefaults()
 		fmt.Fprintf(os.Stderr, "\nEnvironment Variables (GitHub Action inputs):\n")
-		fmt.Fprintf(os.Stderr, "  INPUT_SITEMAP_URL      URL of the sitemap to check (alternative to base-url)\n")
-		fmt.Fprintf(os.Stderr, "  INPUT_BASE_URL         Base URL to start crawling from (alternative to sitemap-url)\n")
+		fmt.Fprintf(os.Stderr, "  INPUT_SITEMAP_URL      Comma-separated URLs of the sitemaps to check (alternative to base-url)\n")
+		fmt.Fprintf(os.Stderr, "  INPUT_BASE_URL         Comma-separated base URLs to start crawling from (alternative to sitemap-url)\n")
 		fmt.Fprintf(os.Stderr, "  INPUT_MAX_DEPTH        Maximum crawl depth (default: 3)\n")
 		fmt.Fprintf(os.Stderr, "  INPUT_TIMEOUT          Request timeout in seconds (default: 30)\n")
 		fmt.Fprintf(os.Stderr, "  INPUT_USER_AGENT       User agent string (default: GitHub-Action-Link-Checker/1.0)\n")
@@ -47,12 +62,123 @@ func main() {
 		fmt.Fprintf(os.Stderr, "  INPUT_FAIL_ON_ERROR    Exit with error code if broken links found (default: true)\n")
 		fmt.Fprintf(os.Stderr, "  INPUT_MAX_CONCURRENT   Maximum concurrent requests (default: 10)\n")
 		fmt.Fprintf(os.Stderr, "  INPUT_VERBOSE          Enable verbose output (default: false)\n")
+		fmt.Fprintf(os.Stderr, "  INPUT_MAX_RETRIES      Maximum retries per link for transient failures (default: 0)\n")
+		fmt.Fprintf(os.Stderr, "  INPUT_RETRY_BUDGET     Maximum total retries across the run, 0 for unlimited (default: 200)\n")
+		fmt.Fprintf(os.Stderr, "  INPUT_TIMEOUT_OVERRIDES Comma-separated pattern=timeout overrides (e.g. '/downloads/=120s')\n")
+		fmt.Fprintf(os.Stderr, "  INPUT_FAIL_ON_CATEGORIES Comma-separated error categories that fail the run (default: all)\n")
+		fmt.Fprintf(os.Stderr, "  INPUT_STATUS_EXCEPTIONS Comma-separated host=status codes to accept (e.g. 'linkedin.com=999')\n")
+		fmt.Fprintf(os.Stderr, "  INPUT_NEWS_MAX_AGE     Only check news sitemap articles published within this age (e.g. '48h')\n")
+		fmt.Fprintf(os.Stderr, "  INPUT_SAMPLE           Check at most this many URLs, sampled deterministically\n")
+		fmt.Fprintf(os.Stderr, "  INPUT_SAMPLE_PERCENT   Check this percentage of URLs, sampled deterministically\n")
+		fmt.Fprintf(os.Stderr, "  INPUT_SAMPLE_SEED      Seed used to select the sample (default: 0)\n")
+		fmt.Fprintf(os.Stderr, "  INPUT_SITEMAP_FALLBACK_CRAWL Crawl the sitemap URL if it serves HTML instead of XML (default: true)\n")
+		fmt.Fprintf(os.Stderr, "  INPUT_PROBE_SITEMAP    Seed the crawl from /sitemap.xml and /sitemap_index.xml (default: false)\n")
+		fmt.Fprintf(os.Stderr, "  INPUT_SEEDS_FILE       File of additional crawl entry points, one URL or path per line\n")
+		fmt.Fprintf(os.Stderr, "  INPUT_IMPORT_URLS      JSON URL list from a previous run to treat as already discovered\n")
+		fmt.Fprintf(os.Stderr, "  INPUT_INVENTORY_FILE   Write every discovered URL with its depth, source and content type to this JSON file\n")
+		fmt.Fprintf(os.Stderr, "  INPUT_LOGIN_PATTERNS   Comma-separated regex patterns for login pages; links redirecting there are reported as auth_required\n")
+		fmt.Fprintf(os.Stderr, "  INPUT_CACHE_FILE       Cache file that lets repeat crawls skip unchanged pages\n")
+		fmt.Fprintf(os.Stderr, "  INPUT_SKIP_UNCHANGED   Skip internal pages whose sitemap lastmod predates their last successful check (default: false)\n")
+		fmt.Fprintf(os.Stderr, "  INPUT_CHANGED_FILES_ONLY Only check links on pages built from files changed in the pull request (default: false)\n")
+		fmt.Fprintf(os.Stderr, "  INPUT_PATH_RULES       Comma-separated regex=path rules mapping repository files to site paths\n")
+		fmt.Fprintf(os.Stderr, "  INPUT_GITHUB_TOKEN     Token for the GitHub API (default: GITHUB_TOKEN)\n")
+		fmt.Fprintf(os.Stderr, "  INPUT_PR_NUMBER        Pull request number (default: read from the workflow event)\n")
+		fmt.Fprintf(os.Stderr, "  INPUT_FILE_RULES       Comma-separated regex=file rules mapping site paths back to repository files\n")
+		fmt.Fprintf(os.Stderr, "  INPUT_SITE_CONFIG      Hugo, Jekyll or MkDocs config to infer base-url, path-rules and file-rules from ('auto' to detect)\n")
+		fmt.Fprintf(os.Stderr, "  INPUT_CONFIG           JSON config file of shared settings and named profiles\n")
+		fmt.Fprintf(os.Stderr, "  INPUT_PROFILE          Config file profile whose settings override the shared ones\n")
+		fmt.Fprintf(os.Stderr, "  INPUT_PREVIEW_URL      Deploy preview URL; links to the production origin are checked against the preview instead\n")
+		fmt.Fprintf(os.Stderr, "  INPUT_LINK_TEXT_AUDIT  Warn about links with empty or generic text and image links without alt text (default: false)\n")
+		fmt.Fprintf(os.Stderr, "  INPUT_DUPLICATE_ID_AUDIT Warn about id attributes used more than once on a crawled page (default: true)\n")
+		fmt.Fprintf(os.Stderr, "  INPUT_PLACEHOLDER_LINK_AUDIT Warn about links to localhost, private IPs and placeholder domains (default: true)\n")
+		fmt.Fprintf(os.Stderr, "  INPUT_TRACKING_PARAM_AUDIT Warn about internal links carrying tracking parameters (default: false)\n")
+		fmt.Fprintf(os.Stderr, "  INPUT_TRACKING_PARAMS  Comma-separated tracking parameter names, '*' as a suffix wildcard (default: utm_*,fbclid,gclid,...)\n")
+		fmt.Fprintf(os.Stderr, "  INPUT_FAIL_ON_TRACKING_PARAMS Fail the run when internal links carry tracking parameters (default: false)\n")
+		fmt.Fprintf(os.Stderr, "  INPUT_URL_SANITY_AUDIT Warn about malformed links and links longer than max-url-length (default: true)\n")
+		fmt.Fprintf(os.Stderr, "  INPUT_MAX_URL_LENGTH   Warn about links longer than this many characters, 0 to disable (default: 2048)\n")
+		fmt.Fprintf(os.Stderr, "  INPUT_REDIRECT_MAP     Write every redirected URL and where it ended up to this file (.csv or JSON)\n")
+		fmt.Fprintf(os.Stderr, "  INPUT_IGNORE_FILE      File of URL regex patterns to exclude, one per line, as in .lycheeignore (default: .lycheeignore)\n")
+		fmt.Fprintf(os.Stderr, "  INPUT_FIX_PR           Open a pull request replacing permanently redirected links in source files (default: false)\n")
+		fmt.Fprintf(os.Stderr, "  INPUT_FORMAT           Result output format: text, ndjson to stream one JSON object per result, checkstyle XML, csv or junit XML (default: text)\n")
+		fmt.Fprintf(os.Stderr, "  INPUT_OUTPUT_FILE      Write ndjson, checkstyle, csv or junit results to this file instead of stdout\n")
+		fmt.Fprintf(os.Stderr, "  INPUT_REPEAT           Check each URL this many times and report success rates and latency variance (default: 1)\n")
+		fmt.Fprintf(os.Stderr, "  INPUT_USER_AGENTS      Comma-separated user agents or presets (chrome, edge, firefox, safari, mobile) to rotate through\n")
+		fmt.Fprintf(os.Stderr, "  INPUT_USER_AGENT_RULES Comma-separated pattern=agent rules choosing a user agent or preset per URL\n")
+		fmt.Fprintf(os.Stderr, "  INPUT_ACCEPT_LANGUAGE  Accept-Language header to send with every request\n")
+		fmt.Fprintf(os.Stderr, "  INPUT_ACCEPT_LANGUAGE_RULES Comma-separated pattern=language rules choosing an Accept-Language per URL\n")
+		fmt.Fprintf(os.Stderr, "  INPUT_CHECK_LOCALES    Comma-separated languages to check every URL in, once each\n")
+		fmt.Fprintf(os.Stderr, "  INPUT_REPORT_FILE      Write every result with run metadata (version, times, settings) to this JSON file\n")
+		fmt.Fprintf(os.Stderr, "  INPUT_CACHE_DIR        Directory holding the cache; use it as the actions/cache path\n")
+		fmt.Fprintf(os.Stderr, "  INPUT_CACHE_MAX_ENTRIES Most pages and checks kept in the cache, oldest dropped first (default: 50000)\n")
+		fmt.Fprintf(os.Stderr, "  INPUT_MAX_MEMORY_MB    Soft memory limit in MiB; the garbage collector works harder to stay under it (default: 0, no limit)\n")
+		fmt.Fprintf(os.Stderr, "  INPUT_FRONTIER_SPILL   Pages waiting to be crawled held in memory before the rest spill to a temporary file (default: 100000, 0 for no limit)\n")
+		fmt.Fprintf(os.Stderr, "  INPUT_RESULT_SPILL     Results held in memory before they all spill to a temporary file (default: 100000, 0 for no limit)\n")
+		fmt.Fprintf(os.Stderr, "  INPUT_LOGIN_URL        Page with a login form to sign in through before checking\n")
+		fmt.Fprintf(os.Stderr, "  INPUT_LOGIN_FIELDS     Comma-separated name=value form fields; $NAME values are read from the environment\n")
+		fmt.Fprintf(os.Stderr, "  INPUT_LOGIN_SUCCESS_SELECTOR Element (e.g. a.logout) that must be on the page after logging in\n")
+		fmt.Fprintf(os.Stderr, "  INPUT_JSON_URLS        Comma-separated JSON API endpoints whose URLs are checked too\n")
+		fmt.Fprintf(os.Stderr, "  INPUT_JSON_PATHS       Comma-separated JSONPath expressions selecting URLs (default: every absolute URL)\n")
+		fmt.Fprintf(os.Stderr, "  INPUT_SEVERITY_RULES   Comma-separated match=severity rules (error, warning, info) for status codes or categories\n")
+		fmt.Fprintf(os.Stderr, "  INPUT_CHECK_ORDER      Order to check URLs in: discovery, or importance to check key pages first (default: discovery)\n")
+		fmt.Fprintf(os.Stderr, "  INPUT_SCHEME_POLICY    Comma-separated scheme=action entries (check, report, fail, ignore; * for others) over http=check,https=check,*=ignore\n")
+		fmt.Fprintf(os.Stderr, "  INPUT_DECISION_HOOK    Command or http(s) endpoint asked whether to check or skip each URL\n")
+		fmt.Fprintf(os.Stderr, "  INPUT_PREFER_HTTPS     Check only the https:// form of URLs also found as http:// (default: false)\n")
+		fmt.Fprintf(os.Stderr, "  INPUT_CHECK_EXTERNAL   Also check links to other hosts found while crawling (default: false)\n")
+		fmt.Fprintf(os.Stderr, "  INPUT_EXTERNAL_MAX_CONCURRENT Maximum concurrent requests for external links (default: 5)\n")
+		fmt.Fprintf(os.Stderr, "  INPUT_RESPECT_ROBOTS   Skip paths robots.txt disallows and honor its Crawl-delay while crawling (default: false)\n")
+		fmt.Fprintf(os.Stderr, "  INPUT_CHECK_ELEMENTS   Comma-separated element types whose links are checked: a, img, script, link, source, iframe, video, audio (default: a)\n")
+		fmt.Fprintf(os.Stderr, "  INPUT_PATH             Built site directory to check from disk instead of crawling a deployed site\n")
+		fmt.Fprintf(os.Stderr, "  INPUT_CACHE_TTL        Skip URLs that passed a check in the cache within this age (e.g. '24h')\n")
+		fmt.Fprintf(os.Stderr, "  INPUT_FOLLOW_REDIRECTS Follow redirects; when false, redirect responses pass without being followed (default: true)\n")
+		fmt.Fprintf(os.Stderr, "  INPUT_MAX_REDIRECTS    Maximum redirects followed for each link (default: 10)\n")
+		fmt.Fprintf(os.Stderr, "  INPUT_WARN_ON_REDIRECT Flag links that redirect elsewhere as redirected warnings (default: false)\n")
+		fmt.Fprintf(os.Stderr, "  INPUT_ACCEPT_STATUS    Comma-separated status codes accepted from every host (e.g. '403,429,999')\n")
+		fmt.Fprintf(os.Stderr, "  INPUT_FILES            Comma-separated globs of Markdown files whose links are checked (e.g. 'docs/**/*.md')\n")
+		fmt.Fprintf(os.Stderr, "  INPUT_HEADERS          Request headers sent to the checked site, one 'Name: value' per line\n")
+		fmt.Fprintf(os.Stderr, "  INPUT_BASIC_AUTH       Basic auth credentials sent to the checked site as 'user:password'\n")
+		fmt.Fprintf(os.Stderr, "  INPUT_PR_COMMENT       Post a summary comment on the pull request, updated on later runs (default: false)\n")
+		fmt.Fprintf(os.Stderr, "  INPUT_INSECURE_SKIP_VERIFY Skip TLS certificate verification (default: false)\n")
+		fmt.Fprintf(os.Stderr, "  INPUT_CA_CERT          PEM file of extra CA certificates to trust, such as a private CA's\n")
+		fmt.Fprintf(os.Stderr, "  INPUT_INCLUDE_PATTERNS Comma-separated regex patterns; only matching URLs are crawled and checked\n")
+		fmt.Fprintf(os.Stderr, "  INPUT_MAX_PAGES        Crawl breadth first until this many pages have been fetched, regardless of max-depth\n")
+		fmt.Fprintf(os.Stderr, "  INPUT_BASELINE         File of known broken links that are reported without failing the run\n")
+		fmt.Fprintf(os.Stderr, "  INPUT_WRITE_BASELINE   Write the broken links found to this file, for use as a baseline\n")
+		fmt.Fprintf(os.Stderr, "  INPUT_URLS_FILE        File of URLs to check, one per line, instead of crawling; '-' reads them from stdin\n")
+		fmt.Fprintf(os.Stderr, "  INPUT_SLOW_THRESHOLD   Report links whose check takes longer than this as slow (e.g. '2s')\n")
+		fmt.Fprintf(os.Stderr, "  INPUT_FAIL_ON_SLOW     Fail the run when slow links are found (default: false)\n")
+		fmt.Fprintf(os.Stderr, "  INPUT_SOFT_404_PATTERNS Comma-separated regex patterns; 2xx pages whose content matches are reported as soft 404s\n")
+		fmt.Fprintf(os.Stderr, "  INPUT_DISCOVER_SITEMAP Check the URLs of sitemaps found in robots.txt or at common paths instead of crawling (default: false)\n")
+		fmt.Fprintf(os.Stderr, "  INPUT_FAIL_ON          Semicolon-separated conditions for failing the run, e.g. 'status:4xx,5xx; category:internal; count>10'\n")
+		fmt.Fprintf(os.Stderr, "  INPUT_COOKIES          Cookies sent with every request, one 'name=value; Domain=example.com' per line\n")
+		fmt.Fprintf(os.Stderr, "  INPUT_MAX_REDIRECT_CHAIN Flag working links that take more redirects than this to resolve (default: 0, off)\n")
+		fmt.Fprintf(os.Stderr, "  INPUT_EXCLUDE_SELECTORS Comma-separated selectors (e.g. 'nav,.footer,[data-nolink]') whose links are skipped\n")
+		fmt.Fprintf(os.Stderr, "  INPUT_NORMALIZE_URLS   Drop fragments and default ports and lower-case hosts before deduplicating URLs (default: true)\n")
+		fmt.Fprintf(os.Stderr, "  INPUT_IGNORE_TRACKING_PARAMS Drop tracking-params from URLs before deduplicating them (default: false)\n")
+		fmt.Fprintf(os.Stderr, "  INPUT_GET_FALLBACK_STATUS Comma-separated HEAD response statuses retried with GET, 'none' to disable (default: 403,405,501)\n")
+		fmt.Fprintf(os.Stderr, "  INPUT_METRICS_FILE     Write run metrics to this file in the OpenMetrics text format\n")
+		fmt.Fprintf(os.Stderr, "  INPUT_PUSHGATEWAY_URL  Push run metrics to this Prometheus Pushgateway\n")
+		fmt.Fprintf(os.Stderr, "  INPUT_METRICS_JOB      Job name metrics are pushed under (default: link-checker)\n")
+		fmt.Fprintf(os.Stderr, "  INPUT_WEBHOOK_URL      Post a summary to this Slack, Teams or Discord webhook when broken links are found\n")
+		fmt.Fprintf(os.Stderr, "  INPUT_WEBHOOK_TEMPLATE Go text/template for the webhook message\n")
+		fmt.Fprintf(os.Stderr, "  INPUT_WRITE_SITEMAP    Write the discovered pages of the site to this file as an XML sitemap\n")
+		fmt.Fprintf(os.Stderr, "  INPUT_MAX_BODY_MB      Most MiB read from each page, sitemap or JSON body, 0 for no limit (default: 50)\n")
+		fmt.Fprintf(os.Stderr, "  INPUT_MAILTO_AUDIT     Warn about mailto: links with invalid email addresses (default: false)\n")
+		fmt.Fprintf(os.Stderr, "  INPUT_MAILTO_MX_LOOKUP Also warn about mailto: addresses whose domain can't receive email (default: false)\n")
+		fmt.Fprintf(os.Stderr, "  INPUT_IGNORE_QUERY_PARAMS Drop the query strings of internal URLs before deduplicating them (default: false)\n")
+		fmt.Fprintf(os.Stderr, "  INPUT_KEEP_PARAMS      Comma-separated query parameters kept when ignoring query strings, e.g. page,id\n")
+		fmt.Fprintf(os.Stderr, "  INPUT_REPAIR_URLS      Repair stray whitespace, unencoded spaces and scheme-less www. links before checking (default: false)\n")
 		fmt.Fprintf(os.Stderr, "\nNote: Command line flags take precedence over environment variables.\n")
 		fmt.Fprintf(os.Stderr, "\nExamples:\n")
 		fmt.Fprintf(os.Stderr, "  # Check links from sitemap using flags\n")
 		fmt.Fprintf(os.Stderr, "  %s --sitemap-url https://example.com/sitemap.xml\n\n", os.Args[0])
 		fmt.Fprintf(os.Stderr, "  # Crawl website using flags\n")
 		fmt.Fprintf(os.Stderr, "  %s --base-url https://example.com --max-depth 2 --verbose\n\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  # Show DNS answers, redirects, timings and headers for one URL\n")
+		fmt.Fprintf(os.Stderr, "  %s diagnose https://example.com/page\n\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  # Compare the broken links of two JSON reports\n")
+		fmt.Fprintf(os.Stderr, "  %s diff main-report.json pr-report.json\n\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  # Check the links of a rendered template\n")
+		fmt.Fprintf(os.Stderr, "  render-email | %s --stdin --base https://example.com/\n\n", os.Args[0])
 		fmt.Fprintf(os.Stderr, "  # Check links from sitemap using environment variables\n")
 		fmt.Fprintf(os.Stderr, "  INPUT_SITEMAP_URL=https://example.com/sitemap.xml %s\n\n", os.Args[0])
 		fmt.Fprintf(os.Stderr, "  # Crawl website using environment variables\n")
@@ -63,8 +189,8 @@ func main() {
 
 	// Define config flags (but don't parse yet)
 	var (
-		sitemapURL      = flag.String("sitemap-url", "", "URL of the sitemap to check")
-		baseURL         = flag.String("base-url", "", "Base URL to start crawling from")
+		sitemapURL      = flag.String("sitemap-url", "", "Comma-separated URLs of the sitemaps to check; or repeat -sitemap-url")
+		baseURL         = flag.String("base-url", "", "Comma-separated base URLs to start crawling from; or repeat -base-url")
 		maxDepth        = flag.Int("max-depth", 3, "Maximum crawl depth")
 		timeout         = flag.Int("timeout", 30, "Request timeout in seconds")
 		userAgent       = flag.String("user-agent", "GitHub-Action-Link-Checker/1.0", "User agent string")
@@ -72,9 +198,133 @@ func main() {
 		failOnError     = flag.Bool("fail-on-error", true, "Exit with error code if broken links found")
 		maxConcurrent   = flag.Int("max-concurrent", 10, "Maximum concurrent requests")
 		verbose         = flag.Bool("verbose", false, "Enable verbose output")
+		maxRetries      = flag.Int("max-retries", 0, "Maximum retries per link for transient failures")
+		retryBudget     = flag.Int("retry-budget", config.DefaultRetryBudget, "Maximum total retries across the run (0 for unlimited)")
+		timeoutOverride = flag.String("timeout-overrides", "", "Comma-separated pattern=timeout overrides (e.g. '/downloads/=120s'); or repeat -timeout-rule 'pattern=timeout'")
+		failOnCategory  = flag.String("fail-on-categories", "", "Comma-separated error categories that fail the run (e.g. '4xx,dns'; default: all)")
+		statusExcept    = flag.String("status-exceptions", "", "Comma-separated host=status codes to accept (e.g. 'linkedin.com=999')")
+		newsMaxAge      = flag.String("news-max-age", "", "Only check news sitemap articles published within this age (e.g. '48h')")
+		sampleSize      = flag.Int("sample", 0, "Check at most this many URLs, sampled deterministically")
+		samplePercent   = flag.String("sample-percent", "", "Check this percentage of URLs, sampled deterministically")
+		sampleSeed      = flag.Int("sample-seed", 0, "Seed used to select the sample")
+		sitemapFallback = flag.Bool("sitemap-fallback-crawl", true, "Crawl the sitemap URL if it serves HTML instead of XML")
+		probeSitemap    = flag.Bool("probe-sitemap", false, "Seed the crawl from /sitemap.xml and /sitemap_index.xml")
+		seedsFile       = flag.String("seeds-file", "", "File of additional crawl entry points, one URL or path per line")
+		importURLs      = flag.String("import-urls", "", "JSON URL list from a previous run to treat as already discovered")
+		inventoryFile   = flag.String("inventory-file", "", "Write every discovered URL with its depth, source and content type to this JSON file")
+		cacheFile       = flag.String("cache-file", "", "Cache file that lets repeat crawls skip unchanged pages")
+		changedFiles    = flag.Bool("changed-files-only", false, "Only check links on pages built from files changed in the pull request")
+		pathRules       = flag.String("path-rules", "", "Comma-separated regex=path rules mapping repository files to site paths (e.g. '^content/(.+)\\.md$=/$1/')")
+		githubToken     = flag.String("github-token", "", "Token for the GitHub API (default: GITHUB_TOKEN)")
+		prNumber        = flag.Int("pr-number", 0, "Pull request number (default: read from the workflow event)")
+		fileRules       = flag.String("file-rules", "", "Comma-separated regex=file rules mapping site paths back to repository files (e.g. '^/docs/(.+)/$=content/docs/$1.md')")
+		siteConfig      = flag.String("site-config", "", "Hugo, Jekyll or MkDocs config to infer base-url, path-rules and file-rules from ('auto' to detect)")
+		configPath      = flag.String("config", "", "JSON config file of shared settings and named profiles")
+		profile         = flag.String("profile", "", "Config file profile whose settings override the shared ones")
+		previewURL      = flag.String("preview-url", "", "Deploy preview URL; links to the production origin are checked against the preview instead")
+		linkTextAudit   = flag.Bool("link-text-audit", false, "Warn about links with empty or generic text and image links without alt text")
+		duplicateIDs    = flag.Bool("duplicate-id-audit", true, "Warn about id attributes used more than once on a crawled page")
+		placeholderLink = flag.Bool("placeholder-link-audit", true, "Warn about links to localhost, private IPs and placeholder domains")
+		trackingAudit   = flag.Bool("tracking-param-audit", false, "Warn about internal links carrying tracking parameters")
+		trackingParams  = flag.String("tracking-params", config.DefaultTrackingParams, "Comma-separated tracking parameter names, '*' as a suffix wildcard")
+		failOnTracking  = flag.Bool("fail-on-tracking-params", false, "Fail the run when internal links carry tracking parameters")
+		urlSanityAudit  = flag.Bool("url-sanity-audit", true, "Warn about malformed links and links longer than max-url-length")
+		maxURLLength    = flag.Int("max-url-length", 2048, "Warn about links longer than this many characters (0 to disable)")
+		redirectMap     = flag.String("redirect-map", "", "Write every redirected URL and where it ended up to this file (.csv or JSON)")
+		fixPR           = flag.Bool("fix-pr", false, "Open a pull request replacing permanently redirected links in source files")
+		ignoreFile      = flag.String("ignore-file", config.DefaultIgnoreFile, "File of URL regex patterns to exclude, one per line, as in .lycheeignore")
+		format          = flag.String("format", "text", "Result output format: text, ndjson to stream one JSON object per result, checkstyle XML, csv or junit XML")
+		outputFile      = flag.String("output-file", "", "Write ndjson, checkstyle, csv or junit results to this file instead of stdout")
+		repeat          = flag.Int("repeat", 1, "Check each URL this many times and report success rates and latency variance")
+		repairURLs      = flag.Bool("repair-urls", false, "Repair stray whitespace, unencoded spaces and scheme-less www. links before checking")
+		userAgents      = flag.String("user-agents", "", "Comma-separated user agents or presets (chrome, edge, firefox, safari, mobile) to rotate through")
+		userAgentRules  = flag.String("user-agent-rules", "", "Comma-separated pattern=agent rules choosing a user agent or preset per URL")
+		acceptLanguage  = flag.String("accept-language", "", "Accept-Language header to send with every request")
+		languageRules   = flag.String("accept-language-rules", "", "Comma-separated pattern=language rules choosing an Accept-Language per URL")
+		checkLocales    = flag.String("check-locales", "", "Comma-separated languages to check every URL in, once each")
+		reportFile      = flag.String("report-file", "", "Write every result with run metadata (version, times, settings) to this JSON file")
+		cacheDir        = flag.String("cache-dir", "", "Directory holding the cache; use it as the actions/cache path")
+		cacheMaxEntries = flag.Int("cache-max-entries", 0, "Most pages and checks kept in the cache, oldest dropped first (default: 50000)")
+		maxMemoryMB     = flag.Int("max-memory-mb", 0, "Soft memory limit in MiB; the garbage collector works harder to stay under it")
+		frontierSpill   = flag.Int("frontier-spill", config.DefaultFrontierSpill, "Pages waiting to be crawled held in memory before the rest spill to a temporary file (0 for no limit)")
+		resultSpill     = flag.Int("result-spill", config.DefaultResultSpill, "Results held in memory before they all spill to a temporary file (0 for no limit)")
+		loginURL        = flag.String("login-url", "", "Page with a login form to sign in through before checking")
+		loginFields     = flag.String("login-fields", "", "Comma-separated name=value form fields; $NAME values are read from the environment")
+		loginSuccess    = flag.String("login-success-selector", "", "Element (e.g. a.logout) that must be on the page after logging in")
+		jsonURLs        = flag.String("json-urls", "", "Comma-separated JSON API endpoints whose URLs are checked too")
+		jsonPaths       = flag.String("json-paths", "", "Comma-separated JSONPath expressions selecting URLs (default: every absolute URL)")
+		severityRules   = flag.String("severity-rules", "", "Comma-separated match=severity rules (error, warning, info) for status codes or categories")
+		checkOrder      = flag.String("check-order", "discovery", "Order to check URLs in: discovery, or importance to check key pages first")
+		schemePolicy    = flag.String("scheme-policy", "", "Comma-separated scheme=action entries (check, report, fail, ignore; * for others) over http=check,https=check,*=ignore")
+		decisionHook    = flag.String("decision-hook", "", "Command or http(s) endpoint asked whether to check or skip each URL")
+		preferHTTPS     = flag.Bool("prefer-https", false, "Check only the https:// form of URLs also found as http://")
+		checkExternal   = flag.Bool("check-external", false, "Also check links to other hosts found while crawling")
+		externalMax     = flag.Int("external-max-concurrent", config.DefaultExternalConcurrency, "Maximum concurrent requests for external links")
+		respectRobots   = flag.Bool("respect-robots", false, "Skip paths robots.txt disallows and honor its Crawl-delay while crawling")
+		checkElements   = flag.String("check-elements", "a", "Comma-separated element types whose links are checked: a, img, script, link, source, iframe, video, audio")
+		sitePath        = flag.String("path", "", "Built site directory to check from disk instead of crawling a deployed site")
+		cacheTTL        = flag.String("cache-ttl", "", "Skip URLs that passed a check in the cache within this age (e.g. '24h')")
+		followRedirects = flag.Bool("follow-redirects", true, "Follow redirects; when false, redirect responses pass without being followed")
+		maxRedirects    = flag.Int("max-redirects", 10, "Maximum redirects followed for each link")
+		warnOnRedirect  = flag.Bool("warn-on-redirect", false, "Flag links that redirect elsewhere as redirected warnings")
+		acceptStatus    = flag.String("accept-status", "", "Comma-separated status codes accepted from every host (e.g. '403,429,999')")
+		files           = flag.String("files", "", "Comma-separated globs of Markdown files whose links are checked (e.g. 'docs/**/*.md')")
+		headers         = flag.String("headers", "", "Request headers sent to the checked site, one 'Name: value' per line; -header may be repeated instead")
+		basicAuth       = flag.String("basic-auth", "", "Basic auth credentials sent to the checked site as 'user:password'")
+		prComment       = flag.Bool("pr-comment", false, "Post a summary comment on the pull request, updated on later runs")
+		insecureTLS     = flag.Bool("insecure-skip-verify", false, "Skip TLS certificate verification, for sites with self-signed certificates")
+		caCert          = flag.String("ca-cert", "", "PEM file of extra CA certificates to trust, such as a private CA's")
+		includePatterns = flag.String("include-patterns", "", "Comma-separated regex patterns; only matching URLs are crawled and checked")
+		maxPages        = flag.Int("max-pages", 0, "Crawl breadth first until this many pages have been fetched, regardless of max-depth")
+		baselineFile    = flag.String("baseline", "", "File of known broken links that are reported without failing the run")
+		writeBaseline   = flag.String("write-baseline", "", "Write the broken links found to this file, for use as a baseline")
+		urlsFile        = flag.String("urls-file", "", "File of URLs to check, one per line, instead of crawling; '-' reads them from stdin")
+		slowThreshold   = flag.String("slow-threshold", "", "Report links whose check takes longer than this as slow (e.g. '2s')")
+		failOnSlow      = flag.Bool("fail-on-slow", false, "Fail the run when slow links are found")
+		soft404Patterns = flag.String("soft-404-patterns", "", "Comma-separated regex patterns; 2xx pages whose content matches are reported as soft 404s")
+		discoverSitemap = flag.Bool("discover-sitemap", false, "Check the URLs of sitemaps found in robots.txt or at common paths instead of crawling base-url")
+		failOn          = flag.String("fail-on", "", "Semicolon-separated conditions for failing the run, e.g. 'status:4xx,5xx; category:internal; count>10'")
+		cookies         = flag.String("cookies", "", "Cookies sent with every request, one 'name=value; Domain=example.com' per line; -cookie may be repeated instead")
+		redirectChain   = flag.Int("max-redirect-chain", 0, "Flag working links that take more redirects than this to resolve as long_redirect_chain (0 to disable)")
+		excludeRegions  = flag.String("exclude-selectors", "", "Comma-separated selectors (e.g. 'nav,.footer,[data-nolink]') of page regions whose links are skipped")
+		normalizeURLs   = flag.Bool("normalize-urls", true, "Drop fragments and default ports and lower-case hosts before deduplicating URLs")
+		ignoreTracking  = flag.Bool("ignore-tracking-params", false, "Drop tracking-params from URLs before deduplicating them")
+		getFallback     = flag.String("get-fallback-status", config.DefaultGetFallbackStatus, "Comma-separated HEAD response statuses retried with GET; empty to disable")
+		metricsFile     = flag.String("metrics-file", "", "Write run metrics to this file in the OpenMetrics text format")
+		pushgateway     = flag.String("pushgateway-url", "", "Push run metrics to this Prometheus Pushgateway")
+		metricsJob      = flag.String("metrics-job", "link-checker", "Job name metrics are pushed under")
+		webhookURL      = flag.String("webhook-url", "", "Post a summary to this Slack, Teams or Discord webhook when broken links are found")
+		webhookTemplate = flag.String("webhook-template", "", "Go text/template for the webhook message")
+		writeSitemap    = flag.String("write-sitemap", "", "Write the discovered pages of the site to this file as an XML sitemap")
+		maxBodyMB       = flag.Int("max-body-mb", 50, "Most MiB read from each page, sitemap or JSON body (0 for no limit)")
+		mailtoAudit     = flag.Bool("mailto-audit", false, "Warn about mailto: links with invalid email addresses")
+		mailtoMXLookup  = flag.Bool("mailto-mx-lookup", false, "Also warn about mailto: addresses whose domain can't receive email, looking up its MX records")
+		ignoreQuery     = flag.Bool("ignore-query-params", false, "Drop the query strings of internal URLs before deduplicating them, so faceted and paginated pages are crawled once")
+		keepParams      = flag.String("keep-params", "", "Comma-separated query parameters kept when ignoring query strings, e.g. page,id (implies -ignore-query-params)")
+		readStdin       = flag.Bool("stdin", false, "Check the links of an HTML document read from stdin instead of crawling")
+		stdinBase       = flag.String("base", "", "Base URL for resolving relative links in the document read with -stdin")
+		skipUnchanged   = flag.Bool("skip-unchanged", false, "Skip internal pages whose sitemap lastmod predates their last successful check")
+		loginPatterns   = flag.String("login-patterns", config.DefaultLoginPatterns, "Comma-separated regex patterns for login pages; links redirecting there are reported as auth_required")
 	)
 
-	flag.Parse()
+	// diff compares reports rather than checking links, so has its own flags
+	args := os.Args[1:]
+	if len(args) > 0 && args[0] == "diff" {
+		os.Exit(runDiff(args[1:], os.Stdout))
+	}
+
+	// Accept muffet and linkinator flags as aliases of the native ones
+	diagnose := len(args) > 0 && args[0] == "diagnose"
+	if diagnose {
+		args = args[1:]
+	}
+	args, notes, argsErr := translateCompatArgs(args, flag.CommandLine)
+	if argsErr != nil {
+		fmt.Fprintln(os.Stderr, argsErr)
+		flag.Usage()
+		os.Exit(2)
+	}
+	_ = flag.CommandLine.Parse(args)
 
 	if showHelp {
 		flag.Usage()
@@ -92,16 +342,52 @@ func main() {
 		os.Exit(0)
 	}
 
+	startedAt := time.Now().UTC()
+
+	// Like muffet and linkinator, accept the URL to check as an argument
+	if flag.NArg() > 1 {
+		log.Fatalf("Expected at most one URL argument, got %d", flag.NArg())
+	}
+	var diagnoseURL string
+	if diagnose {
+		if flag.NArg() != 1 {
+			log.Fatalf("Usage: %s diagnose [options] <url>", os.Args[0])
+		}
+		diagnoseURL = flag.Arg(0)
+	} else if flag.Arg(0) == "-" {
+		// "-" reads the URLs to check from stdin, as in
+		// git grep -o 'https://[^)]*' | link-checker -
+		if err := flag.Set("urls-file", "-"); err != nil {
+			log.Fatalf("Invalid URL argument: %v", err)
+		}
+	} else if flag.NArg() == 1 {
+		if err := flag.Set("base-url", flag.Arg(0)); err != nil {
+			log.Fatalf("Invalid URL argument: %v", err)
+		}
+	}
+
+	// Settings from a config file take the place of environment variables,
+	// so flags still take precedence over them
+	configFile := getValueOrEnv(*configPath, "INPUT_CONFIG", "", "config")
+	profileName := getValueOrEnv(*profile, "INPUT_PROFILE", "", "profile")
+	if configFile != "" {
+		if err := applyConfigFile(configFile, profileName, flag.CommandLine); err != nil {
+			log.Fatalf("Failed to apply config file: %v", err)
+		}
+	}
+
 	// Create config from flags with environment variable fallbacks
 	cfg := &config.Config{
 		SitemapURL:    getValueOrEnv(*sitemapURL, "INPUT_SITEMAP_URL", "", "sitemap-url"),
 		BaseURL:       getValueOrEnv(*baseURL, "INPUT_BASE_URL", "", "base-url"),
 		MaxDepth:      getIntValueOrEnv(*maxDepth, "INPUT_MAX_DEPTH", 3, "max-depth"),
 		Timeout:       time.Duration(getIntValueOrEnv(*timeout, "INPUT_TIMEOUT", 30, "timeout")) * time.Second,
-		UserAgent:     getValueOrEnv(*userAgent, "INPUT_USER_AGENT", "GitHub-Action-Link-Checker/1.0", "user-agent"),
+		UserAgent:     config.ResolveUserAgent(getValueOrEnv(*userAgent, "INPUT_USER_AGENT", "GitHub-Action-Link-Checker/1.0", "user-agent")),
 		FailOnError:   getBoolValueOrEnv(*failOnError, "INPUT_FAIL_ON_ERROR", true, "fail-on-error"),
 		MaxConcurrent: getIntValueOrEnv(*maxConcurrent, "INPUT_MAX_CONCURRENT", 10, "max-concurrent"),
 		Verbose:       getBoolValueOrEnv(*verbose, "INPUT_VERBOSE", false, "verbose"),
+		MaxRetries:    getIntValueOrEnv(*maxRetries, "INPUT_MAX_RETRIES", 0, "max-retries"),
+		RetryBudget:   getIntValueOrEnv(*retryBudget, "INPUT_RETRY_BUDGET", config.DefaultRetryBudget, "retry-budget"),
 	}
 
 	// Parse exclude patterns
@@ -118,69 +404,1128 @@ func main() {
 		}
 	}
 
-	if cfg.SitemapURL == "" && cfg.BaseURL == "" {
-		fmt.Fprintf(os.Stderr, "Error: Either sitemap-url or base-url must be provided\n\n")
+	cfg.TimeoutOverrides = config.ParseTimeoutOverrides(
+		getValueOrEnv(*timeoutOverride, "INPUT_TIMEOUT_OVERRIDES", "", "timeout-overrides"))
+	cfg.FailOnCategories = config.ParseList(
+		getValueOrEnv(*failOnCategory, "INPUT_FAIL_ON_CATEGORIES", "", "fail-on-categories"))
+	cfg.StatusExceptions = config.ParseStatusExceptions(
+		getValueOrEnv(*statusExcept, "INPUT_STATUS_EXCEPTIONS", "", "status-exceptions"))
+	cfg.NewsMaxAge = getDurationValueOrEnv(*newsMaxAge, "INPUT_NEWS_MAX_AGE", "news-max-age")
+	cfg.SampleSize = getIntValueOrEnv(*sampleSize, "INPUT_SAMPLE", 0, "sample")
+	cfg.SamplePercent = getPercentValueOrEnv(*samplePercent, "INPUT_SAMPLE_PERCENT", "sample-percent")
+	cfg.SitemapFallback = getBoolValueOrEnv(*sitemapFallback, "INPUT_SITEMAP_FALLBACK_CRAWL", true, "sitemap-fallback-crawl")
+	cfg.ProbeSitemap = getBoolValueOrEnv(*probeSitemap, "INPUT_PROBE_SITEMAP", false, "probe-sitemap")
+	cfg.SeedsFile = getValueOrEnv(*seedsFile, "INPUT_SEEDS_FILE", "", "seeds-file")
+	cfg.ImportURLs = getValueOrEnv(*importURLs, "INPUT_IMPORT_URLS", "", "import-urls")
+	cfg.InventoryFile = getValueOrEnv(*inventoryFile, "INPUT_INVENTORY_FILE", "", "inventory-file")
+	cfg.LoginPatterns = config.ParsePatterns(
+		getValueOrEnv(*loginPatterns, "INPUT_LOGIN_PATTERNS", config.DefaultLoginPatterns, "login-patterns"))
+	cfg.CacheFile = getValueOrEnv(*cacheFile, "INPUT_CACHE_FILE", "", "cache-file")
+	cfg.SkipUnchanged = getBoolValueOrEnv(*skipUnchanged, "INPUT_SKIP_UNCHANGED", false, "skip-unchanged")
+	cfg.ChangedFiles = getBoolValueOrEnv(*changedFiles, "INPUT_CHANGED_FILES_ONLY", false, "changed-files-only")
+	cfg.PathRules = config.ParsePathRules(getValueOrEnv(*pathRules, "INPUT_PATH_RULES", "", "path-rules"))
+	cfg.GitHubToken = getValueOrEnv(*githubToken, "INPUT_GITHUB_TOKEN", os.Getenv("GITHUB_TOKEN"), "github-token")
+	cfg.PRNumber = getIntValueOrEnv(*prNumber, "INPUT_PR_NUMBER", 0, "pr-number")
+	cfg.FileRules = config.ParsePathRules(getValueOrEnv(*fileRules, "INPUT_FILE_RULES", "", "file-rules"))
+	cfg.SiteConfig = getValueOrEnv(*siteConfig, "INPUT_SITE_CONFIG", "", "site-config")
+	cfg.PreviewURL = getValueOrEnv(*previewURL, "INPUT_PREVIEW_URL", "", "preview-url")
+	cfg.LinkTextAudit = getBoolValueOrEnv(*linkTextAudit, "INPUT_LINK_TEXT_AUDIT", false, "link-text-audit")
+	cfg.DuplicateIDAudit = getBoolValueOrEnv(*duplicateIDs, "INPUT_DUPLICATE_ID_AUDIT", true, "duplicate-id-audit")
+	cfg.PlaceholderLinkAudit = getBoolValueOrEnv(*placeholderLink, "INPUT_PLACEHOLDER_LINK_AUDIT", true, "placeholder-link-audit")
+	cfg.TrackingParamAudit = getBoolValueOrEnv(*trackingAudit, "INPUT_TRACKING_PARAM_AUDIT", false, "tracking-param-audit")
+	cfg.TrackingParams = config.ParseList(
+		getValueOrEnv(*trackingParams, "INPUT_TRACKING_PARAMS", config.DefaultTrackingParams, "tracking-params"))
+	cfg.FailOnTrackingParams = getBoolValueOrEnv(*failOnTracking, "INPUT_FAIL_ON_TRACKING_PARAMS", false, "fail-on-tracking-params")
+	cfg.URLSanityAudit = getBoolValueOrEnv(*urlSanityAudit, "INPUT_URL_SANITY_AUDIT", true, "url-sanity-audit")
+	cfg.MaxURLLength = getIntValueOrEnv(*maxURLLength, "INPUT_MAX_URL_LENGTH", 2048, "max-url-length")
+	cfg.RedirectMap = getValueOrEnv(*redirectMap, "INPUT_REDIRECT_MAP", "", "redirect-map")
+	cfg.FixPR = getBoolValueOrEnv(*fixPR, "INPUT_FIX_PR", false, "fix-pr")
+	cfg.IgnoreFile = getValueOrEnv(*ignoreFile, "INPUT_IGNORE_FILE", config.DefaultIgnoreFile, "ignore-file")
+	cfg.Format = getValueOrEnv(*format, "INPUT_FORMAT", "text", "format")
+	cfg.OutputFile = getValueOrEnv(*outputFile, "INPUT_OUTPUT_FILE", "", "output-file")
+	cfg.Repeat = getIntValueOrEnv(*repeat, "INPUT_REPEAT", 1, "repeat")
+	cfg.RepairURLs = getBoolValueOrEnv(*repairURLs, "INPUT_REPAIR_URLS", false, "repair-urls")
+	cfg.UserAgents = config.ParseUserAgents(getValueOrEnv(*userAgents, "INPUT_USER_AGENTS", "", "user-agents"))
+	cfg.UserAgentRules = config.ParseUserAgentRules(
+		getValueOrEnv(*userAgentRules, "INPUT_USER_AGENT_RULES", "", "user-agent-rules"))
+	cfg.AcceptLanguage = getValueOrEnv(*acceptLanguage, "INPUT_ACCEPT_LANGUAGE", "", "accept-language")
+	cfg.AcceptLanguageRules = config.ParseAcceptLanguageRules(
+		getValueOrEnv(*languageRules, "INPUT_ACCEPT_LANGUAGE_RULES", "", "accept-language-rules"))
+	cfg.CheckLocales = config.ParseList(getValueOrEnv(*checkLocales, "INPUT_CHECK_LOCALES", "", "check-locales"))
+	cfg.ReportFile = getValueOrEnv(*reportFile, "INPUT_REPORT_FILE", "", "report-file")
+	cfg.CacheDir = getValueOrEnv(*cacheDir, "INPUT_CACHE_DIR", "", "cache-dir")
+	cfg.CacheMaxEntries = getIntValueOrEnv(*cacheMaxEntries, "INPUT_CACHE_MAX_ENTRIES", 50000, "cache-max-entries")
+	cfg.MaxMemoryMB = getIntValueOrEnv(*maxMemoryMB, "INPUT_MAX_MEMORY_MB", 0, "max-memory-mb")
+	cfg.FrontierSpill = getIntValueOrEnv(*frontierSpill, "INPUT_FRONTIER_SPILL", config.DefaultFrontierSpill, "frontier-spill")
+	cfg.ResultSpill = getIntValueOrEnv(*resultSpill, "INPUT_RESULT_SPILL", config.DefaultResultSpill, "result-spill")
+	cfg.LoginURL = getValueOrEnv(*loginURL, "INPUT_LOGIN_URL", "", "login-url")
+	cfg.LoginFields = config.ParseLoginFields(getValueOrEnv(*loginFields, "INPUT_LOGIN_FIELDS", "", "login-fields"))
+	cfg.LoginSuccessSelector = getValueOrEnv(*loginSuccess, "INPUT_LOGIN_SUCCESS_SELECTOR", "", "login-success-selector")
+	cfg.JSONURLs = config.ParseList(getValueOrEnv(*jsonURLs, "INPUT_JSON_URLS", "", "json-urls"))
+	cfg.JSONPaths = config.ParseList(getValueOrEnv(*jsonPaths, "INPUT_JSON_PATHS", "", "json-paths"))
+	cfg.SeverityRules = config.ParseSeverityRules(getValueOrEnv(*severityRules, "INPUT_SEVERITY_RULES", "", "severity-rules"))
+	cfg.CheckOrder = getValueOrEnv(*checkOrder, "INPUT_CHECK_ORDER", "discovery", "check-order")
+	cfg.SchemePolicy = config.ParseSchemePolicy(getValueOrEnv(*schemePolicy, "INPUT_SCHEME_POLICY", "", "scheme-policy"))
+	cfg.DecisionHook = getValueOrEnv(*decisionHook, "INPUT_DECISION_HOOK", "", "decision-hook")
+	cfg.PreferHTTPS = getBoolValueOrEnv(*preferHTTPS, "INPUT_PREFER_HTTPS", false, "prefer-https")
+	cfg.CheckExternal = getBoolValueOrEnv(*checkExternal, "INPUT_CHECK_EXTERNAL", false, "check-external")
+	cfg.ExternalConcurrency = getIntValueOrEnv(*externalMax, "INPUT_EXTERNAL_MAX_CONCURRENT", config.DefaultExternalConcurrency, "external-max-concurrent")
+	cfg.RespectRobots = getBoolValueOrEnv(*respectRobots, "INPUT_RESPECT_ROBOTS", false, "respect-robots")
+	cfg.CheckElements = config.ParseList(getValueOrEnv(*checkElements, "INPUT_CHECK_ELEMENTS", "a", "check-elements"))
+	cfg.SitePath = getValueOrEnv(*sitePath, "INPUT_PATH", "", "path")
+	cfg.CacheTTL = getDurationValueOrEnv(*cacheTTL, "INPUT_CACHE_TTL", "cache-ttl")
+	cfg.StopAtRedirects = !getBoolValueOrEnv(*followRedirects, "INPUT_FOLLOW_REDIRECTS", true, "follow-redirects")
+	cfg.MaxRedirects = getIntValueOrEnv(*maxRedirects, "INPUT_MAX_REDIRECTS", 10, "max-redirects")
+	cfg.WarnOnRedirect = getBoolValueOrEnv(*warnOnRedirect, "INPUT_WARN_ON_REDIRECT", false, "warn-on-redirect")
+	cfg.AcceptStatus = config.ParseStatusCodes(getValueOrEnv(*acceptStatus, "INPUT_ACCEPT_STATUS", "", "accept-status"))
+	cfg.Files = config.ParseList(getValueOrEnv(*files, "INPUT_FILES", "", "files"))
+	cfg.Headers = config.ParseHeaders(getValueOrEnv(*headers, "INPUT_HEADERS", "", "headers"))
+	cfg.BasicAuth = getValueOrEnv(*basicAuth, "INPUT_BASIC_AUTH", "", "basic-auth")
+	cfg.PRComment = getBoolValueOrEnv(*prComment, "INPUT_PR_COMMENT", false, "pr-comment")
+	cfg.InsecureSkipVerify = getBoolValueOrEnv(*insecureTLS, "INPUT_INSECURE_SKIP_VERIFY", false, "insecure-skip-verify")
+	cfg.CACert = getValueOrEnv(*caCert, "INPUT_CA_CERT", "", "ca-cert")
+	cfg.IncludePatterns = config.ParsePatterns(getValueOrEnv(*includePatterns, "INPUT_INCLUDE_PATTERNS", "", "include-patterns"))
+	cfg.MaxPages = getIntValueOrEnv(*maxPages, "INPUT_MAX_PAGES", 0, "max-pages")
+	cfg.Baseline = getValueOrEnv(*baselineFile, "INPUT_BASELINE", "", "baseline")
+	cfg.WriteBaseline = getValueOrEnv(*writeBaseline, "INPUT_WRITE_BASELINE", "", "write-baseline")
+	cfg.URLsFile = getValueOrEnv(*urlsFile, "INPUT_URLS_FILE", "", "urls-file")
+	cfg.SlowThreshold = getDurationValueOrEnv(*slowThreshold, "INPUT_SLOW_THRESHOLD", "slow-threshold")
+	cfg.FailOnSlow = getBoolValueOrEnv(*failOnSlow, "INPUT_FAIL_ON_SLOW", false, "fail-on-slow")
+	cfg.Soft404Patterns = config.ParsePatterns(getValueOrEnv(*soft404Patterns, "INPUT_SOFT_404_PATTERNS", "", "soft-404-patterns"))
+	cfg.DiscoverSitemap = getBoolValueOrEnv(*discoverSitemap, "INPUT_DISCOVER_SITEMAP", false, "discover-sitemap")
+	cfg.FailOn = config.ParseFailPolicy(getValueOrEnv(*failOn, "INPUT_FAIL_ON", "", "fail-on"))
+	cfg.Cookies = config.ParseCookies(getValueOrEnv(*cookies, "INPUT_COOKIES", "", "cookies"))
+	cfg.ExcludeSelectors = config.ParseList(getValueOrEnv(*excludeRegions, "INPUT_EXCLUDE_SELECTORS", "", "exclude-selectors"))
+	cfg.MaxRedirectChain = getIntValueOrEnv(*redirectChain, "INPUT_MAX_REDIRECT_CHAIN", 0, "max-redirect-chain")
+	cfg.NormalizeURLs = getBoolValueOrEnv(*normalizeURLs, "INPUT_NORMALIZE_URLS", true, "normalize-urls")
+	cfg.IgnoreTrackingParams = getBoolValueOrEnv(*ignoreTracking, "INPUT_IGNORE_TRACKING_PARAMS", false, "ignore-tracking-params")
+	cfg.GetFallbackStatus = config.ParseStatusCodes(
+		getValueOrEnv(*getFallback, "INPUT_GET_FALLBACK_STATUS", config.DefaultGetFallbackStatus, "get-fallback-status"))
+	cfg.MetricsFile = getValueOrEnv(*metricsFile, "INPUT_METRICS_FILE", "", "metrics-file")
+	cfg.PushgatewayURL = getValueOrEnv(*pushgateway, "INPUT_PUSHGATEWAY_URL", "", "pushgateway-url")
+	cfg.MetricsJob = getValueOrEnv(*metricsJob, "INPUT_METRICS_JOB", "link-checker", "metrics-job")
+	cfg.WebhookURL = getValueOrEnv(*webhookURL, "INPUT_WEBHOOK_URL", "", "webhook-url")
+	cfg.WebhookTemplate = getValueOrEnv(*webhookTemplate, "INPUT_WEBHOOK_TEMPLATE", "", "webhook-template")
+	cfg.WriteSitemap = getValueOrEnv(*writeSitemap, "INPUT_WRITE_SITEMAP", "", "write-sitemap")
+	cfg.MaxBodyMB = getIntValueOrEnv(*maxBodyMB, "INPUT_MAX_BODY_MB", 50, "max-body-mb")
+	cfg.MailtoAudit = getBoolValueOrEnv(*mailtoAudit, "INPUT_MAILTO_AUDIT", false, "mailto-audit")
+	cfg.MailtoMXLookup = getBoolValueOrEnv(*mailtoMXLookup, "INPUT_MAILTO_MX_LOOKUP", false, "mailto-mx-lookup")
+	cfg.IgnoreQueryParams = getBoolValueOrEnv(*ignoreQuery, "INPUT_IGNORE_QUERY_PARAMS", false, "ignore-query-params")
+	cfg.KeepParams = config.ParseList(getValueOrEnv(*keepParams, "INPUT_KEEP_PARAMS", "", "keep-params"))
+
+	if err := checker.ValidateSelectors(cfg.ExcludeSelectors); err != nil {
+		log.Fatalf("Invalid exclude-selectors: %v", err)
+	}
+	if _, err := notify.ParseTemplate(cfg.WebhookTemplate); err != nil {
+		log.Fatalf("Invalid webhook-template: %v", err)
+	}
+
+	// Machine-readable results take over stdout unless written to a file, so
+	// progress messages move to stderr
+	var resultWriter, checkstyleWriter, csvWriter, junitWriter io.Writer
+	switch cfg.Format {
+	case "text":
+	case "ndjson", "checkstyle", "csv", "junit":
+		var w io.Writer
+		if cfg.OutputFile != "" {
+			f, err := os.Create(cfg.OutputFile)
+			if err != nil {
+				log.Fatalf("Failed to create output file: %v", err)
+			}
+			defer f.Close()
+			w = f
+		} else {
+			w = os.Stdout
+			os.Stdout = os.Stderr
+		}
+		switch cfg.Format {
+		case "ndjson":
+			resultWriter = w
+		case "checkstyle":
+			checkstyleWriter = w
+		case "csv":
+			csvWriter = w
+		case "junit":
+			junitWriter = w
+		}
+	default:
+		log.Fatalf("Unknown format %q (expected text, ndjson, checkstyle, csv or junit)", cfg.Format)
+	}
+	if cfg.CheckOrder != checker.OrderDiscovery && cfg.CheckOrder != checker.OrderImportance {
+		log.Fatalf("Unknown check order %q (expected discovery or importance)", cfg.CheckOrder)
+	}
+	for _, element := range cfg.CheckElements {
+		if !checker.CheckableElement(element) {
+			log.Fatalf("Unknown element %q in check-elements (expected a, img, script, link, source, iframe, video or audio)", element)
+		}
+	}
+
+	for _, note := range notes {
+		fmt.Println(note)
+	}
+	if configFile != "" && profileName != "" {
+		fmt.Printf("Using profile %s from %s\n", profileName, configFile)
+	}
+
+	if cfg.IgnoreFile != "" {
+		patterns, err := config.LoadIgnoreFile(cfg.IgnoreFile)
+		switch {
+		case err == nil:
+			cfg.ExcludePatterns = append(cfg.ExcludePatterns, patterns...)
+			if len(patterns) > 0 {
+				fmt.Printf("Loaded %d exclude patterns from %s\n", len(patterns), cfg.IgnoreFile)
+			}
+		case errors.Is(err, os.ErrNotExist) && cfg.IgnoreFile == config.DefaultIgnoreFile:
+			// The default ignore file is optional
+		default:
+			log.Fatalf("Failed to load ignore file: %v", err)
+		}
+	}
+	cfg.SampleSeed = int64(getIntValueOrEnv(*sampleSeed, "INPUT_SAMPLE_SEED", 0, "sample-seed"))
+
+	if cfg.SiteConfig != "" {
+		var site *ssg.Site
+		var err error
+		if cfg.SiteConfig == "auto" {
+			site, err = ssg.Detect(".")
+		} else {
+			site, err = ssg.Load(cfg.SiteConfig)
+		}
+		if err != nil {
+			log.Fatalf("Failed to read site config: %v", err)
+		}
+		site.Apply(cfg)
+		fmt.Printf("Using %s site config (base URL: %s, content: %s)\n", site.Generator, site.BaseURL, site.ContentDir)
+	}
+	cfg.BaseURL, cfg.BaseURLs = config.SplitURLs(cfg.BaseURL)
+	cfg.SitemapURL, cfg.SitemapURLs = config.SplitURLs(cfg.SitemapURL)
+
+	if cfg.SitemapURL == "" && cfg.BaseURL == "" && len(cfg.JSONURLs) == 0 && cfg.SitePath == "" && len(cfg.Files) == 0 && cfg.URLsFile == "" && !*readStdin && diagnoseURL == "" {
+		fmt.Fprintf(os.Stderr, "Error: Either sitemap-url, base-url, json-urls, path, files or urls-file must be provided\n\n")
 		fmt.Fprintf(os.Stderr, "Use --help for usage information.\n")
 		os.Exit(1)
 	}
 
+	if cfg.MaxMemoryMB > 0 {
+		debug.SetMemoryLimit(int64(cfg.MaxMemoryMB) << 20)
+	}
+
 	linkChecker := checker.New(cfg)
+	if resultWriter != nil {
+		linkChecker.StreamResults(resultWriter)
+	}
+	// Verbose output lists every link already, so progress is only shown
+	// without it
+	if !cfg.Verbose {
+		if isTerminal(os.Stdout) {
+			linkChecker.ReportProgress(os.Stdout, true)
+		} else if os.Getenv("GITHUB_ACTIONS") == "true" {
+			linkChecker.ReportProgress(os.Stdout, false)
+		}
+	}
+	if cfg.InsecureSkipVerify {
+		fmt.Printf("Warning: TLS certificates are not verified\n")
+	}
+	if cfg.CACert != "" {
+		if err := linkChecker.LoadCACert(cfg.CACert); err != nil {
+			log.Fatalf("Failed to load CA certificate: %v", err)
+		}
+	}
 
-	var urls []string
+	var baseline map[string]bool
+	if cfg.Baseline != "" {
+		var err error
+		if baseline, err = checker.LoadBaseline(cfg.Baseline); err != nil {
+			log.Fatalf("Failed to load baseline: %v", err)
+		}
+		fmt.Printf("Loaded %d known broken links from %s\n", len(baseline), cfg.Baseline)
+	}
+
+	if cfg.PreviewURL != "" {
+		production := cfg.BaseURL
+		if production == "" {
+			production = cfg.SitemapURL
+		}
+		if err := linkChecker.UsePreview(production, cfg.PreviewURL); err != nil {
+			log.Fatalf("Failed to configure preview URL: %v", err)
+		}
+		cfg.BaseURL = linkChecker.RewritePreview(cfg.BaseURL)
+		cfg.SitemapURL = linkChecker.RewritePreview(cfg.SitemapURL)
+		for i := range cfg.BaseURLs {
+			cfg.BaseURLs[i] = linkChecker.RewritePreview(cfg.BaseURLs[i])
+		}
+		for i := range cfg.SitemapURLs {
+			cfg.SitemapURLs[i] = linkChecker.RewritePreview(cfg.SitemapURLs[i])
+		}
+		fmt.Printf("Checking deploy preview %s in place of %s\n", cfg.PreviewURL, production)
+	}
+
+	if len(cfg.Cookies) > 0 {
+		if err := linkChecker.SeedCookies(); err != nil {
+			log.Fatalf("Failed to set cookies: %v", err)
+		}
+		fmt.Printf("Sending %d cookies with every request\n", len(cfg.Cookies))
+	}
+
+	if cfg.LoginURL != "" {
+		if err := linkChecker.Login(); err != nil {
+			log.Fatalf("Login failed: %v", err)
+		}
+		fmt.Printf("Logged in at %s\n", cfg.LoginURL)
+	}
+
+	if diagnoseURL != "" {
+		diagnosis := linkChecker.Diagnose(linkChecker.RewritePreview(diagnoseURL))
+		printDiagnosis(os.Stdout, diagnosis)
+		if diagnosis.Result.ErrorType != "" {
+			os.Exit(1)
+		}
+		return
+	}
+
+	// The URLs to check are held by a list that spills them to disk past
+	// frontier-spill, so a crawl of a huge site doesn't hold them all
+	urls := checker.NewURLList(cfg.FrontierSpill)
+	var found []string
+	var localResults []checker.LinkResult
 	var err error
 
-	if cfg.SitemapURL != "" {
-		fmt.Printf("Fetching URLs from sitemap: %s\n", cfg.SitemapURL)
-		urls, err = linkChecker.GetURLsFromSitemap(cfg.SitemapURL)
+	if cfg.SkipUnchanged && cfg.CacheFile == "" && cfg.CacheDir == "" {
+		fmt.Printf("Warning: skip-unchanged has no effect without cache-file or cache-dir\n")
+	}
+	if cfg.CacheTTL > 0 && cfg.CacheFile == "" && cfg.CacheDir == "" {
+		fmt.Printf("Warning: cache-ttl has no effect without cache-file or cache-dir\n")
+	}
+	if cfg.BasicAuth != "" && !strings.Contains(cfg.BasicAuth, ":") {
+		fmt.Printf("Warning: basic-auth is ignored, it must be given as user:password\n")
+	}
+
+	var pageCache *cache.Cache
+	if cfg.CacheFile != "" || cfg.CacheDir != "" {
+		if cfg.CacheFile != "" {
+			pageCache, err = cache.Load(cfg.CacheFile)
+		} else {
+			pageCache, err = cache.Open(cfg.CacheDir)
+		}
 		if err != nil {
-			log.Fatalf("Failed to fetch sitemap: %v", err)
+			log.Fatalf("Failed to load cache: %v", err)
 		}
-	} else {
-		fmt.Printf("Crawling website starting from: %s\n", cfg.BaseURL)
-		urls, err = linkChecker.CrawlWebsite(cfg.BaseURL, cfg.MaxDepth)
+		pageCache.SetMaxEntries(cfg.CacheMaxEntries)
+		linkChecker.UseCache(pageCache)
+	}
+
+	if cfg.SitePath != "" {
+		fmt.Printf("Checking the site built in %s\n", cfg.SitePath)
+		localResults, found, err = linkChecker.CheckDirectory(cfg.SitePath)
 		if err != nil {
-			log.Fatalf("Failed to crawl website: %v", err)
+			log.Fatalf("Failed to check site directory: %v", err)
 		}
+		fmt.Printf("Checked %d links within the site against its files\n", len(localResults))
+	} else if len(cfg.Files) > 0 {
+		fmt.Printf("Checking Markdown files matching %s\n", strings.Join(cfg.Files, ", "))
+		localResults, found, err = linkChecker.CheckMarkdownFiles(cfg.Files)
+		if err != nil {
+			log.Fatalf("Failed to check Markdown files: %v", err)
+		}
+		fmt.Printf("Checked %d links to files in the repository\n", len(localResults))
+	} else if *readStdin {
+		found, err = linkChecker.ExtractLinks(os.Stdin, *stdinBase)
+		if err != nil {
+			log.Fatalf("Failed to read HTML from stdin: %v", err)
+		}
+		fmt.Printf("Found %d links in the HTML read from stdin\n", len(found))
+	} else if cfg.URLsFile != "" {
+		found, err = readURLsFile(linkChecker, cfg.URLsFile)
+		if err != nil {
+			log.Fatalf("Failed to read URLs: %v", err)
+		}
+		source := cfg.URLsFile
+		if source == "-" {
+			source = "stdin"
+		}
+		fmt.Printf("Read %d URLs to check from %s\n", len(found), source)
+	} else if cfg.ChangedFiles {
+		if cfg.BaseURL == "" || len(cfg.PathRules) == 0 {
+			log.Fatalf("changed-files-only requires base-url and path-rules")
+		}
+		pages, err := changedPageURLs(cfg)
+		if err != nil {
+			log.Fatalf("Failed to determine changed pages: %v", err)
+		}
+		fmt.Printf("Checking links on %d pages changed by the pull request\n", len(pages))
+		if err := linkChecker.CrawlPagesTo(urls, cfg.BaseURL, pages, 1); err != nil {
+			log.Fatalf("Failed to crawl changed pages: %v", err)
+		}
+	} else if cfg.SitemapURL != "" {
+		for _, sitemap := range cfg.SitemapURLs {
+			fmt.Printf("Fetching URLs from sitemap: %s\n", sitemap)
+			sitemapURLs, err := linkChecker.GetURLsFromSitemap(sitemap)
+			if errors.Is(err, checker.ErrSitemapIsHTML) && cfg.SitemapFallback {
+				fmt.Printf("Sitemap URL returned an HTML page, crawling it instead\n")
+				sitemapURLs, err = linkChecker.CrawlWebsite(sitemap, crawlDepth(cfg))
+			}
+			if err != nil {
+				log.Fatalf("Failed to fetch sitemap: %v", err)
+			}
+			found = append(found, sitemapURLs...)
+		}
+	} else if discovered := discoverSitemapURLs(linkChecker, cfg); len(discovered) > 0 {
+		fmt.Printf("Found %d URLs in sitemaps discovered for %s\n", len(discovered), strings.Join(cfg.BaseURLs, ", "))
+		found = discovered
+	} else if cfg.BaseURL != "" {
+		if cfg.ImportURLs != "" {
+			known, err := checker.LoadURLList(cfg.ImportURLs)
+			if err != nil {
+				log.Fatalf("Failed to import URLs: %v", err)
+			}
+			linkChecker.ImportKnownURLs(known)
+			fmt.Printf("Imported %d known URLs from %s\n", len(known), cfg.ImportURLs)
+		}
+
+		for _, base := range cfg.BaseURLs {
+			var seeds []string
+			if cfg.ProbeSitemap {
+				seeds = linkChecker.ProbeSitemaps(base)
+				fmt.Printf("Found %d URLs in sitemaps to seed the crawl\n", len(seeds))
+			}
+			if cfg.SeedsFile != "" {
+				fileSeeds, err := checker.LoadSeedsFile(cfg.SeedsFile, base)
+				if err != nil {
+					log.Fatalf("Failed to load seeds: %v", err)
+				}
+				fmt.Printf("Loaded %d seed URLs from %s\n", len(fileSeeds), cfg.SeedsFile)
+				seeds = append(seeds, fileSeeds...)
+			}
+
+			if cfg.RespectRobots {
+				if err := linkChecker.LoadRobots(base); err != nil {
+					fmt.Printf("Warning: crawling without robots.txt rules: %v\n", err)
+				}
+			}
+
+			fmt.Printf("Crawling website starting from: %s\n", base)
+			if err := linkChecker.CrawlWebsiteWithSeedsTo(urls, base, seeds, crawlDepth(cfg)); err != nil {
+				log.Fatalf("Failed to crawl website: %v", err)
+			}
+		}
+		if skipped := linkChecker.RobotsSkipped(); len(skipped) > 0 {
+			fmt.Printf("Skipped %d URLs disallowed by robots.txt\n", len(skipped))
+		}
+		if pageCache != nil {
+			fmt.Printf("Reused %d unchanged pages from cache\n", linkChecker.CacheHits())
+		}
+	}
+
+	if err := urls.Add(found...); err != nil {
+		log.Fatalf("Failed to store URLs: %v", err)
 	}
+	found = nil
 
-	fmt.Printf("Found %d URLs to check\n", len(urls))
+	if len(cfg.JSONURLs) > 0 {
+		for _, endpoint := range cfg.JSONURLs {
+			jsonLinks, err := linkChecker.ExtractJSONLinks(endpoint, cfg.JSONPaths)
+			if err != nil {
+				log.Fatalf("Failed to extract URLs from %s: %v", endpoint, err)
+			}
+			fmt.Printf("Found %d URLs in the JSON from %s\n", len(jsonLinks), endpoint)
+			if err := urls.Add(jsonLinks...); err != nil {
+				log.Fatalf("Failed to store URLs: %v", err)
+			}
+		}
+	}
 
-	results := linkChecker.CheckLinks(urls)
+	fmt.Printf("Found %d URLs to check\n", urls.Len())
+	urls, discovered := selectURLs(linkChecker, cfg, urls)
+	checkedPercent := 100.0
+	if discovered > 0 {
+		checkedPercent = float64(urls.Len()) / float64(discovered) * 100
+	}
+	if urls.Len() < discovered {
+		fmt.Printf("Sampling %d of %d URLs (%.1f%%, seed %d)\n", urls.Len(), discovered, checkedPercent, cfg.SampleSeed)
+	}
+
+	// Results go to a store that spills them to disk on huge runs; only the
+	// flagged, slow and redirected results the summary needs are kept
+	store := checker.NewResultStore(cfg.ResultSpill, baseline)
+	if err := store.Add(localResults...); err != nil {
+		log.Fatalf("Failed to store results: %v", err)
+	}
+	localResults = nil
+	if err := linkChecker.CheckListTo(store, urls); err != nil {
+		log.Fatalf("Failed to store results: %v", err)
+	}
+	if cfg.CheckExternal {
+		external := checker.NewURLList(cfg.FrontierSpill)
+		if cfg.PreferHTTPS {
+			err = external.Add(linkChecker.PreferHTTPS(linkChecker.ExternalLinks())...)
+		} else {
+			err = linkChecker.ExternalLinksTo(external)
+		}
+		if err != nil {
+			log.Fatalf("Failed to store URLs: %v", err)
+		}
+		fmt.Printf("Checking %d external links\n", external.Len())
+		if err := linkChecker.CheckExternalListTo(store, external); err != nil {
+			log.Fatalf("Failed to store results: %v", err)
+		}
+		external.Close()
+	}
+	if err := linkChecker.StreamErr(); err != nil {
+		fmt.Printf("Warning: failed to stream results: %v\n", err)
+	}
+
+	// Further rounds only inform the repeat report; failures are judged on
+	// the first round like any other run. Each round rechecks the stored
+	// results and is tallied as it goes, so no round is held in memory.
+	var repeatStats []checker.RepeatStat
+	if cfg.Repeat > 1 {
+		tally := checker.NewRepeatTally()
+		for result := range store.All() {
+			tally.Add(result)
+		}
+		for round := 2; round <= cfg.Repeat; round++ {
+			fmt.Printf("Checking links again (round %d of %d)\n", round, cfg.Repeat)
+			err := linkChecker.RecheckTo(store.All(), func(results ...checker.LinkResult) error {
+				tally.Add(results...)
+				return nil
+			})
+			if err != nil {
+				log.Fatalf("Failed to recheck links: %v", err)
+			}
+		}
+		if err := store.Err(); err != nil {
+			log.Fatalf("Failed to read stored results: %v", err)
+		}
+		repeatStats = tally.Stats()
+	}
+
+	notable := store.Notable()
+	flaggedLinks := []checker.LinkResult{}
+	for _, result := range notable {
+		if result.ErrorType != "" {
+			flaggedLinks = append(flaggedLinks, result)
+		}
+	}
+	acceptedCount := store.Accepted()
+	unchangedCount := store.Unchanged()
+	flaggedLinks = checker.DedupeResults(flaggedLinks)
+	slowLinks := []checker.LinkResult{}
+	for _, result := range checker.DedupeResults(notable) {
+		if result.Slow {
+			slowLinks = append(slowLinks, result)
+		}
+	}
+	if len(cfg.FileRules) > 0 {
+		for i := range flaggedLinks {
+			flaggedLinks[i].SourceFiles = checker.SourceFiles(flaggedLinks[i].Sources, cfg.FileRules)
+		}
+	}
+	baselineCount := 0
+	for _, link := range flaggedLinks {
+		if link.Baseline {
+			baselineCount++
+		}
+	}
+	failingLinks := checker.FailingResults(flaggedLinks)
 
 	brokenLinks := []checker.LinkResult{}
-	for _, result := range results {
-		if result.StatusCode >= 400 {
-			brokenLinks = append(brokenLinks, result)
+	authRequiredLinks := []checker.LinkResult{}
+	challengedLinks := []checker.LinkResult{}
+	redirectedLinks := []checker.LinkResult{}
+	for _, link := range flaggedLinks {
+		switch {
+		case link.ErrorType.IsFailure():
+			brokenLinks = append(brokenLinks, link)
+		case link.ErrorType == checker.ErrorTypeAuthRequired:
+			authRequiredLinks = append(authRequiredLinks, link)
+		case link.ErrorType == checker.ErrorTypeBotChallenge:
+			challengedLinks = append(challengedLinks, link)
+		case link.ErrorType.IsRedirect():
+			redirectedLinks = append(redirectedLinks, link)
 		}
 	}
 
 	// Output results
 	fmt.Printf("\n=== Link Check Results ===\n")
-	fmt.Printf("Total links checked: %d\n", len(results))
+	fmt.Printf("Total links checked: %d\n", store.Len())
+	if urls.Len() < discovered {
+		fmt.Printf("Coverage: %.1f%% of %d discovered URLs (sampled)\n", checkedPercent, discovered)
+	}
 	fmt.Printf("Broken links found: %d\n", len(brokenLinks))
+	skippedLinks := linkChecker.SkippedLinks()
+	categoryCounts := checker.CountByCategory(skippedLinks)
+	for category, count := range store.Categories() {
+		categoryCounts[category] += count
+	}
+	if len(categoryCounts) > 0 {
+		fmt.Printf("Links by category: %s\n", checker.FormatCategoryCounts(categoryCounts))
+	}
+	if len(authRequiredLinks) > 0 {
+		fmt.Printf("Links requiring authentication: %d\n", len(authRequiredLinks))
+	}
+	if len(challengedLinks) > 0 {
+		fmt.Printf("Links blocked by bot protection: %d\n", len(challengedLinks))
+	}
+	if len(redirectedLinks) > 0 {
+		fmt.Printf("Redirected links: %d\n", len(redirectedLinks))
+	}
+	if len(slowLinks) > 0 {
+		fmt.Printf("Slow links (over %s): %d\n", cfg.SlowThreshold, len(slowLinks))
+	}
+	if acceptedCount > 0 {
+		fmt.Printf("Accepted status codes (not counted as broken): %d\n", acceptedCount)
+	}
+	if baselineCount > 0 {
+		fmt.Printf("Known broken links in the baseline (not failing): %d\n", baselineCount)
+	}
+	if unchangedCount > 0 {
+		fmt.Printf("Skipped as unchanged since last successful check: %d\n", unchangedCount)
+	}
+	if cfg.MaxRetries > 0 {
+		if cfg.RetryBudget > 0 {
+			fmt.Printf("Retries used: %d/%d\n", linkChecker.RetriesUsed(), cfg.RetryBudget)
+		} else {
+			fmt.Printf("Retries used: %d\n", linkChecker.RetriesUsed())
+		}
+	}
+
+	if len(authRequiredLinks) > 0 {
+		fmt.Printf("\n=== Links Requiring Authentication ===\n")
+		for _, link := range authRequiredLinks {
+			fmt.Printf("🔒 %s - %s\n", resultLabel(link), link.Error)
+			printSources(link.Sources)
+		}
+	}
+
+	if len(challengedLinks) > 0 {
+		fmt.Printf("\n=== Links Blocked by Bot Protection ===\n")
+		for _, link := range challengedLinks {
+			fmt.Printf("🤖 %s - %s\n", resultLabel(link), link.Error)
+			printSources(link.Sources)
+		}
+	}
+
+	if len(redirectedLinks) > 0 {
+		fmt.Printf("\n=== Redirected Links ===\n")
+		for _, link := range redirectedLinks {
+			fmt.Printf("🔄 %s - %s\n", resultLabel(link), link.Error)
+			if link.ErrorType != checker.ErrorTypeRedirected {
+				printRedirectChain(link)
+			}
+			printSources(link.Sources)
+		}
+	}
+
+	if len(slowLinks) > 0 {
+		fmt.Printf("\n=== Slow Links ===\n")
+		for _, link := range slowLinks {
+			fmt.Printf("🐢 %s (%s)\n", resultLabel(link), link.Duration)
+			printSources(link.Sources)
+		}
+	}
 
 	if len(brokenLinks) > 0 {
 		fmt.Printf("\n=== Broken Links ===\n")
-		for _, link := range brokenLinks {
-			fmt.Printf("❌ %s (Status: %d) - %s\n", link.URL, link.StatusCode, link.Error)
+		for _, group := range checker.GroupByCategory(brokenLinks) {
+			category := string(group[0].Category)
+			if category == "" {
+				category = "other"
+			}
+			fmt.Printf("\n%s (%d):\n", category, len(group))
+			for _, link := range group {
+				known := ""
+				if link.Baseline {
+					known = " [baseline]"
+				}
+				errorType := string(link.ErrorType)
+				if link.ErrorDetail != "" {
+					errorType += "/" + link.ErrorDetail
+				}
+				fmt.Printf("❌ %s (Status: %d, Type: %s, Severity: %s)%s - %s\n",
+					resultLabel(link), link.StatusCode, errorType, link.Severity, known, link.Error)
+				if link.ErrorType == checker.ErrorTypeRedirectLoop || link.ErrorType == checker.ErrorTypeTooManyRedirects {
+					printRedirectChain(link)
+				}
+				printSources(link.Sources)
+				if len(link.SourceFiles) > 0 {
+					fmt.Printf("   Source files: %s\n", strings.Join(link.SourceFiles, ", "))
+				}
+			}
+		}
+		if os.Getenv("GITHUB_ACTIONS") == "true" {
+			annotateBrokenLinks(os.Stdout, brokenLinks)
 		}
 	} else {
 		fmt.Printf("✅ No broken links found!\n")
 	}
 
+	if baseline != nil {
+		if fixed := checker.FixedSinceBaseline(notable, baseline); len(fixed) > 0 {
+			fmt.Printf("\n=== Fixed Since Baseline ===\n")
+			for _, u := range fixed {
+				fmt.Printf("✅ %s\n", u)
+			}
+			fmt.Printf("Remove these %d links from %s\n", len(fixed), cfg.Baseline)
+		}
+	}
+
+	if cfg.Verbose && len(skippedLinks) > 0 {
+		fmt.Printf("\n=== Skipped Links ===\n")
+		for _, link := range skippedLinks {
+			fmt.Printf("⏭️  %s (%s)\n", link.URL, link.Category)
+		}
+	}
+
+	robotsSkipped := linkChecker.RobotsSkipped()
+	if len(robotsSkipped) > 0 {
+		fmt.Printf("\n=== Skipped by robots.txt ===\n")
+		for _, skipped := range robotsSkipped {
+			fmt.Printf("🚫 %s\n", skipped)
+		}
+	}
+
+	pageIssues := linkChecker.Issues()
+	if len(pageIssues) > 0 {
+		fmt.Printf("\n=== Page Issues ===\n")
+		for _, issue := range pageIssues {
+			fmt.Printf("⚠️  %s (%s) - %s\n", issue.Page, issue.Type, issue.Detail)
+			if issue.Link != "" {
+				fmt.Printf("   Link: %s\n", issue.Link)
+			}
+		}
+	}
+
+	flakyCount := 0
+	if len(repeatStats) > 0 {
+		fmt.Printf("\n=== Repeat Results (%d rounds) ===\n", cfg.Repeat)
+		for _, stat := range repeatStats {
+			emoji := "✅"
+			switch {
+			case stat.Flaky():
+				emoji = "⚠️ "
+				flakyCount++
+			case stat.Successes == 0:
+				emoji = "❌"
+			case !cfg.Verbose:
+				continue
+			}
+			fmt.Printf("%s %s: %d/%d succeeded (%.0f%%), latency %s ± %s\n",
+				emoji, resultLabel(checker.LinkResult{URL: stat.URL, Locale: stat.Locale}), stat.Successes, stat.Attempts, stat.SuccessRate(),
+				stat.MeanLatency.Round(100*time.Microsecond), stat.LatencyStdev.Round(100*time.Microsecond))
+		}
+		fmt.Printf("Flaky links: %d\n", flakyCount)
+	}
+
+	policyLinks, policyFails := checker.ApplyFailPolicy(failingLinks, cfg.FailOn)
+	if cfg.FailOn != nil && len(failingLinks) > 0 {
+		fmt.Printf("\n%d of %d failing links match the fail-on policy (%s)\n",
+			len(policyLinks), len(failingLinks), cfg.FailOn)
+	}
+	if len(cfg.FailOnCategories) > 0 && len(flaggedLinks) > 0 {
+		fmt.Printf("\n%d of %d flagged links match fail-on-categories (%s)\n",
+			len(failingLinks), len(flaggedLinks), strings.Join(cfg.FailOnCategories, ", "))
+	} else if len(cfg.SeverityRules) > 0 && len(flaggedLinks) > 0 {
+		fmt.Printf("\n%d of %d flagged links have error severity\n", len(failingLinks), len(flaggedLinks))
+	}
+
 	// Set GitHub Action outputs
-	setOutput("total-links-checked", strconv.Itoa(len(results)))
+	setOutput("total-links-checked", strconv.Itoa(store.Len()))
+	setOutput("checked-percent", strconv.FormatFloat(checkedPercent, 'f', 1, 64))
 	setOutput("broken-links-count", strconv.Itoa(len(brokenLinks)))
+	setOutput("retries-used", strconv.Itoa(linkChecker.RetriesUsed()))
+	setOutput("cache-hits", strconv.Itoa(linkChecker.CacheHits()))
+	setOutput("unchanged-count", strconv.Itoa(unchangedCount))
+	setOutput("accepted-count", strconv.Itoa(acceptedCount))
+	if cfg.SlowThreshold > 0 {
+		setOutput("slow-links-count", strconv.Itoa(len(slowLinks)))
+	}
+	if baseline != nil {
+		setOutput("baseline-count", strconv.Itoa(baselineCount))
+	}
+
+	// Oversized arrays are cut down to fit a step output, and the full
+	// results are written to the report instead
+	brokenLinksJSON, brokenTruncated := truncateJSONArray(brokenLinks, maxOutputSize)
+	setOutput("broken-links", brokenLinksJSON)
+	setOutput("broken-links-truncated", strconv.FormatBool(brokenTruncated))
+	setErrorTypeOutputs(flaggedLinks)
+
+	pageIssuesJSON, issuesTruncated := truncateJSONArray(pageIssues, maxOutputSize)
+	setOutput("page-issues-count", strconv.Itoa(len(pageIssues)))
+	setOutput("page-issues", pageIssuesJSON)
+	setOutput("page-issues-truncated", strconv.FormatBool(issuesTruncated))
+	robotsSkippedJSON, robotsTruncated := truncateJSONArray(robotsSkipped, maxOutputSize)
+	if cfg.RespectRobots {
+		setOutput("robots-skipped-count", strconv.Itoa(len(robotsSkipped)))
+		setOutput("robots-skipped", robotsSkippedJSON)
+	}
+	// The report-file output only reports a report the run was asked for;
+	// report-path also covers one written because an output was too large
+	reportRequested := cfg.ReportFile != ""
+	if (brokenTruncated || issuesTruncated || robotsTruncated) && cfg.ReportFile == "" {
+		cfg.ReportFile = defaultReportFile
+		fmt.Printf("Outputs exceed GitHub's size limit; writing the full results to %s\n", cfg.ReportFile)
+	}
+	if len(repeatStats) > 0 {
+		setOutput("flaky-links-count", strconv.Itoa(flakyCount))
+	}
+
+	inventoryLen := linkChecker.InventoryLen()
+	setOutput("discovered-urls-count", strconv.Itoa(inventoryLen))
+	if cfg.InventoryFile != "" {
+		if err := checker.WriteInventory(cfg.InventoryFile, linkChecker.Inventory()); err != nil {
+			log.Printf("Failed to write URL inventory: %v", err)
+		} else {
+			fmt.Printf("Wrote %d discovered URLs to %s\n", inventoryLen, cfg.InventoryFile)
+			setOutput("inventory-file", cfg.InventoryFile)
+		}
+	}
+	if cfg.WriteSitemap != "" {
+		pages := linkChecker.SitemapPages(notable)
+		if err := checker.WriteSitemap(cfg.WriteSitemap, pages); err != nil {
+			log.Printf("Failed to write sitemap: %v", err)
+		} else {
+			fmt.Printf("Wrote %d pages to %s\n", len(pages), cfg.WriteSitemap)
+			setOutput("sitemap-file", cfg.WriteSitemap)
+		}
+	}
+
+	dedupedResults := checker.DedupeResults(notable)
+	redirects := checker.Redirects(dedupedResults)
+	setOutput("redirects-count", strconv.Itoa(len(redirects)))
+	if cfg.RedirectMap != "" {
+		if err := checker.WriteRedirectMap(cfg.RedirectMap, redirects); err != nil {
+			log.Printf("Failed to write redirect map: %v", err)
+		} else {
+			fmt.Printf("Wrote %d redirects to %s\n", len(redirects), cfg.RedirectMap)
+			setOutput("redirect-map", cfg.RedirectMap)
+		}
+	}
+
+	if cfg.WriteBaseline != "" {
+		if err := checker.WriteBaseline(cfg.WriteBaseline, brokenLinks); err != nil {
+			log.Printf("Failed to write baseline: %v", err)
+		} else {
+			fmt.Printf("Wrote %d broken links to %s\n", len(brokenLinks), cfg.WriteBaseline)
+		}
+	}
+
+	if cfg.FixPR {
+		if len(cfg.FileRules) == 0 {
+			log.Printf("fix-pr requires file-rules to find the files to fix")
+		} else if prURL, err := openFixPullRequest(cfg, checker.ProposeFixes(dedupedResults, cfg.FileRules)); err != nil {
+			log.Printf("Failed to open fix pull request: %v", err)
+		} else if prURL != "" {
+			fmt.Printf("Opened pull request with link fixes: %s\n", prURL)
+			setOutput("fix-pr-url", prURL)
+		}
+	}
+
+	if pageCache != nil {
+		if err := pageCache.Save(); err != nil {
+			log.Printf("Failed to save cache: %v", err)
+		}
+	}
+
+	if checkstyleWriter != nil {
+		if err := checker.WriteCheckstyle(checkstyleWriter, flaggedLinks); err != nil {
+			log.Printf("Failed to write checkstyle report: %v", err)
+		}
+	}
+	if csvWriter != nil {
+		if err := checker.WriteCSV(csvWriter, store.All()); err != nil {
+			log.Printf("Failed to write CSV report: %v", err)
+		}
+	}
+	if junitWriter != nil {
+		if err := checker.WriteJUnit(junitWriter, store.All()); err != nil {
+			log.Printf("Failed to write JUnit report: %v", err)
+		}
+	}
+
+	finishedAt := time.Now().UTC()
+	summary := checker.Summary{
+		Checked:       store.Len(),
+		Broken:        brokenLinks,
+		AuthRequired:  len(authRequiredLinks),
+		BotChallenges: len(challengedLinks),
+		PageIssues:    len(pageIssues),
+		Accepted:      acceptedCount,
+		Baseline:      baselineCount,
+		Slow:          len(slowLinks),
+		Duration:      finishedAt.Sub(startedAt),
+	}
+	writeStepSummary(summary)
+	if cfg.PRComment {
+		if err := commentOnPullRequest(cfg, summary); err != nil {
+			log.Printf("Failed to comment on pull request: %v", err)
+		}
+	}
+	if cfg.WebhookURL != "" && len(summary.Broken) > summary.Baseline {
+		if err := notifyWebhook(cfg, summary); err != nil {
+			log.Printf("Failed to send webhook notification: %v", err)
+		}
+	}
+	setOutput("started-at", startedAt.Format(time.RFC3339))
+	setOutput("finished-at", finishedAt.Format(time.RFC3339))
+	if cfg.ReportFile != "" {
+		report := checker.Report{
+			Run: checker.RunInfo{
+				Version:    version,
+				StartedAt:  startedAt.Format(time.RFC3339),
+				FinishedAt: finishedAt.Format(time.RFC3339),
+				Config:     configSnapshot(flag.CommandLine),
+			},
+			Issues:        pageIssues,
+			RobotsSkipped: robotsSkipped,
+			Categories:    categoryCounts,
+			Skipped:       skippedLinks,
+		}
+		if commit != "unknown" {
+			report.Run.Commit = commit
+		}
+		if err := checker.WriteReportFrom(cfg.ReportFile, report, store.All()); err != nil {
+			log.Printf("Failed to write report: %v", err)
+		} else {
+			fmt.Printf("Wrote report of %d results to %s\n", store.Len(), cfg.ReportFile)
+			if reportRequested {
+				setOutput("report-file", cfg.ReportFile)
+			}
+			setOutput("report-path", cfg.ReportFile)
+		}
+	}
+	if cfg.MetricsFile != "" || cfg.PushgatewayURL != "" {
+		exportMetrics(linkChecker, cfg, summary, store.All())
+	}
+	if err := store.Err(); err != nil {
+		log.Printf("Failed to read back results: %v", err)
+	}
+	store.Close()
+	urls.Close()
+	linkChecker.Close()
+
+	trackingFailures := 0
+	if cfg.FailOnTrackingParams {
+		trackingFailures = checker.CountIssues(pageIssues, checker.IssueTrackingParams)
+		if trackingFailures > 0 {
+			fmt.Printf("\n%d internal links carry tracking parameters (fail-on-tracking-params)\n", trackingFailures)
+		}
+	}
 
-	brokenLinksJSON, _ := json.Marshal(brokenLinks)
-	setOutput("broken-links", string(brokenLinksJSON))
+	slowFailures := 0
+	if cfg.FailOnSlow {
+		slowFailures = len(slowLinks)
+		if slowFailures > 0 {
+			fmt.Printf("\n%d links are slower than %s (fail-on-slow)\n", slowFailures, cfg.SlowThreshold)
+		}
+	}
 
-	// Exit with error if broken links found and fail-on-error is true
-	if len(brokenLinks) > 0 && cfg.FailOnError {
+	// Exit with error if failing links meet the fail-on policy and
+	// fail-on-error is true
+	if (policyFails || trackingFailures > 0 || slowFailures > 0) && cfg.FailOnError {
 		os.Exit(1)
 	}
 }
 
+// applyConfigFile exports the settings of a config file profile as the
+// INPUT_* environment variables they correspond to. Settings must name one
+// of the given flags.
+func applyConfigFile(path, profile string, flags *flag.FlagSet) error {
+	file, err := config.LoadFile(path)
+	if err != nil {
+		return err
+	}
+	settings, err := file.Resolve(profile)
+	if err != nil {
+		return err
+	}
+
+	for name := range settings {
+		if name == "config" || name == "profile" || flags.Lookup(name) == nil {
+			return fmt.Errorf("unknown setting %q in %s", name, path)
+		}
+	}
+	for name, value := range settings {
+		if err := os.Setenv(config.InputEnv(name), value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// compatFlag describes a muffet or linkinator flag in terms of the native
+// flag it stands for
+type compatFlag struct {
+	native string // native flag name, empty when the flag has no equivalent
+	value  string // value of the native flag for boolean aliases
+	isBool bool
+}
+
+// compatFlags are the muffet and linkinator flags accepted as aliases of
+// native flags, to ease switching from those tools
+var compatFlags = map[string]compatFlag{
+	"buffer-size":     {},                                                // muffet
+	"max-connections": {native: "max-concurrent"},                        // muffet
+	"exclude":         {native: "exclude-patterns"},                      // muffet
+	"header":          {native: "headers"},                               // muffet
+	"concurrency":     {native: "max-concurrent"},                        // linkinator
+	"recurse":         {native: "max-depth", value: "100", isBool: true}, // linkinator
+	"skip":            {native: "exclude-patterns"},                      // linkinator
+	"retry":           {native: "max-retries", value: "3", isBool: true}, // linkinator
+	"timeout-rule":    {native: "timeout-overrides"},                     // repeatable form of timeout-overrides
+	"cookie":          {native: "cookies"},                               // repeatable form of cookies
+}
+
+// translateCompatArgs rewrites muffet and linkinator flags in args to the
+// native flags of the given set. Repeated exclusions are merged into a
+// single exclude-patterns flag, and positional arguments are moved after the
+// flags so flags following a URL are still parsed. Repeated headers are
+// merged into a single headers flag, one per line, repeated timeout rules
+// into a single timeout-overrides flag, and repeated cookies into a single
+// cookies flag, one per line. It returns notes about
+// aliases that have no native equivalent.
+func translateCompatArgs(args []string, flags *flag.FlagSet) ([]string, []string, error) {
+	var translated, positional, excludes, headers, timeouts, cookies, bases, sitemaps, notes []string
+
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if arg == "--" {
+			positional = append(positional, args[i+1:]...)
+			break
+		}
+		if len(arg) < 2 || arg[0] != '-' {
+			positional = append(positional, arg)
+			continue
+		}
+
+		name, value, hasValue := strings.Cut(strings.TrimLeft(arg, "-"), "=")
+		alias, isAlias := compatFlags[name]
+		if !isAlias {
+			if f := flags.Lookup(name); f != nil && !hasValue && !isBoolFlag(f) && i+1 < len(args) {
+				i++
+				value, hasValue = args[i], true
+			}
+			switch {
+			case name == "exclude-patterns" && hasValue:
+				excludes = append(excludes, value)
+			case name == "headers" && hasValue:
+				headers = append(headers, value)
+			case name == "timeout-overrides" && hasValue:
+				timeouts = append(timeouts, value)
+			case name == "cookies" && hasValue:
+				cookies = append(cookies, value)
+			case name == "base-url" && hasValue:
+				bases = append(bases, value)
+			case name == "sitemap-url" && hasValue:
+				sitemaps = append(sitemaps, value)
+			case hasValue:
+				translated = append(translated, "-"+name+"="+value)
+			default:
+				translated = append(translated, arg)
+			}
+			continue
+		}
+
+		if alias.isBool {
+			if hasValue {
+				enabled, err := strconv.ParseBool(value)
+				if err != nil {
+					return nil, nil, fmt.Errorf("invalid boolean value %q for -%s", value, name)
+				}
+				if !enabled {
+					continue
+				}
+			}
+			translated = append(translated, "-"+alias.native+"="+alias.value)
+			continue
+		}
+
+		if !hasValue {
+			if i+1 >= len(args) {
+				return nil, nil, fmt.Errorf("flag needs an argument: -%s", name)
+			}
+			i++
+			value = args[i]
+		}
+		switch alias.native {
+		case "":
+			notes = append(notes, fmt.Sprintf("Ignoring -%s, which has no equivalent", name))
+		case "exclude-patterns":
+			excludes = append(excludes, value)
+		case "headers":
+			headers = append(headers, value)
+		case "timeout-overrides":
+			timeouts = append(timeouts, value)
+		case "cookies":
+			cookies = append(cookies, value)
+		default:
+			translated = append(translated, "-"+alias.native+"="+value)
+		}
+	}
+
+	if len(excludes) > 0 {
+		translated = append(translated, "-exclude-patterns="+strings.Join(excludes, ","))
+	}
+	if len(headers) > 0 {
+		translated = append(translated, "-headers="+strings.Join(headers, "\n"))
+	}
+	if len(timeouts) > 0 {
+		translated = append(translated, "-timeout-overrides="+strings.Join(timeouts, ","))
+	}
+	if len(cookies) > 0 {
+		translated = append(translated, "-cookies="+strings.Join(cookies, "\n"))
+	}
+	if len(bases) > 0 {
+		translated = append(translated, "-base-url="+strings.Join(bases, ","))
+	}
+	if len(sitemaps) > 0 {
+		translated = append(translated, "-sitemap-url="+strings.Join(sitemaps, ","))
+	}
+	if len(positional) > 0 {
+		translated = append(translated, "--")
+		translated = append(translated, positional...)
+	}
+	return translated, notes, nil
+}
+
+// isBoolFlag reports whether a flag can be given without a value
+func isBoolFlag(f *flag.Flag) bool {
+	b, ok := f.Value.(interface{ IsBoolFlag() bool })
+	return ok && b.IsBoolFlag()
+}
+
+// maxOutputSize is the largest JSON array written to a step output. GitHub
+// limits each output to 1 MB and fails or cuts off larger values.
+const maxOutputSize = 1000 * 1000
+
+// defaultReportFile is where the full results go when an output is too large
+// and report-file isn't set
+const defaultReportFile = "link-checker-report.json"
+
+// truncateJSONArray encodes items as a JSON array of at most limit bytes,
+// keeping as many leading items as fit. It reports whether any were dropped.
+func truncateJSONArray[T any](items []T, limit int) (string, bool) {
+	var buf bytes.Buffer
+	buf.WriteByte('[')
+	for i, item := range items {
+		encoded, err := json.Marshal(item)
+		if err != nil {
+			continue
+		}
+		// Leave room for the separator and the closing bracket
+		if buf.Len()+len(encoded)+2 > limit {
+			buf.WriteByte(']')
+			return buf.String(), i < len(items)
+		}
+		if buf.Len() > 1 {
+			buf.WriteByte(',')
+		}
+		buf.Write(encoded)
+	}
+	buf.WriteByte(']')
+	return buf.String(), false
+}
+
 func setOutput(name, value string) {
 	if githubOutput := os.Getenv("GITHUB_OUTPUT"); githubOutput != "" {
 		f, err := os.OpenFile(githubOutput, os.O_APPEND|os.O_WRONLY, 0o644)
@@ -200,6 +1545,617 @@ func setOutput(name, value string) {
 	}
 }
 
+// writeStepSummary appends a Markdown report of the run to the job summary
+// when running in GitHub Actions
+func writeStepSummary(summary checker.Summary) {
+	path := os.Getenv("GITHUB_STEP_SUMMARY")
+	if path == "" {
+		return
+	}
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY|os.O_CREATE, 0o644)
+	if err != nil {
+		log.Printf("Failed to open GITHUB_STEP_SUMMARY file: %v", err)
+		return
+	}
+	defer f.Close()
+
+	if err := checker.WriteMarkdownSummary(f, summary); err != nil {
+		log.Printf("Failed to write step summary: %v", err)
+	}
+}
+
+// annotateBrokenLinks writes GitHub workflow annotations on the repository
+// files that contain broken links, so they appear on the pull request diff.
+// Warnings and info findings are annotated as warnings and notices.
+func annotateBrokenLinks(w io.Writer, links []checker.LinkResult) {
+	for _, link := range links {
+		command := "error"
+		switch link.Severity {
+		case checker.SeverityWarning:
+			command = "warning"
+		case checker.SeverityInfo:
+			command = "notice"
+		}
+		for _, file := range link.SourceFiles {
+			fmt.Fprintf(w, "::%s file=%s,title=Broken link::%s\n", command,
+				escapeAnnotationProperty(file), escapeAnnotationData(fmt.Sprintf("%s - %s", link.URL, link.Error)))
+		}
+	}
+}
+
+// escapeAnnotationData escapes a workflow command message
+func escapeAnnotationData(value string) string {
+	return strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A").Replace(value)
+}
+
+// escapeAnnotationProperty escapes a workflow command property value
+func escapeAnnotationProperty(value string) string {
+	return strings.NewReplacer(":", "%3A", ",", "%2C").Replace(escapeAnnotationData(value))
+}
+
+// isTerminal reports whether f is a terminal rather than a file or pipe
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// readURLsFile reads the URLs to check from a file, or from stdin for "-"
+func readURLsFile(linkChecker *checker.Checker, path string) ([]string, error) {
+	if path == "-" {
+		return linkChecker.ReadURLs(os.Stdin)
+	}
+	f, err := os.Open(path) // #nosec G304 -- path is provided by the user
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return linkChecker.ReadURLs(f)
+}
+
+// crawlDepth returns the depth a whole-site crawl is limited to. A page
+// budget replaces the depth limit.
+func crawlDepth(cfg *config.Config) int {
+	if cfg.MaxPages > 0 {
+		return math.MaxInt
+	}
+	return cfg.MaxDepth
+}
+
+// discoverSitemapURLs returns the URLs of the sitemaps found for each
+// base-url when discover-sitemap is set, or none if the sites should be
+// crawled because one of them has no sitemap
+func discoverSitemapURLs(linkChecker *checker.Checker, cfg *config.Config) []string {
+	if !cfg.DiscoverSitemap || cfg.BaseURL == "" {
+		return nil
+	}
+	var urls []string
+	for _, base := range cfg.BaseURLs {
+		fmt.Printf("Looking for sitemaps of %s\n", base)
+		found := linkChecker.ProbeSitemaps(base)
+		if len(found) == 0 {
+			fmt.Printf("No sitemaps found for %s, crawling instead\n", base)
+			return nil
+		}
+		urls = appendUnique(urls, found)
+	}
+	return urls
+}
+
+// selectURLs applies prefer-https, sampling and check-order to the URLs to
+// check, returning the URLs to check and how many there were before
+// sampling. Those settings need every URL at once, so only when one is set
+// is the list read into memory and a new list built from what's left.
+func selectURLs(linkChecker *checker.Checker, cfg *config.Config, urls *checker.URLList) (*checker.URLList, int) {
+	sampling := cfg.SampleSize > 0 || cfg.SamplePercent > 0
+	if !cfg.PreferHTTPS && !sampling && cfg.CheckOrder != checker.OrderImportance {
+		return urls, urls.Len()
+	}
+
+	selected := slices.Collect(urls.All())
+	if err := urls.Err(); err != nil {
+		log.Fatalf("Failed to read back URLs: %v", err)
+	}
+	urls.Close()
+	if cfg.PreferHTTPS {
+		found := len(selected)
+		selected = linkChecker.PreferHTTPS(selected)
+		if dropped := found - len(selected); dropped > 0 {
+			fmt.Printf("Checking %d URLs over HTTPS only; their http:// forms are also linked\n", dropped)
+		}
+	}
+	discovered := len(selected)
+	selected = checker.SampleURLs(selected, cfg.SampleSize, cfg.SamplePercent, cfg.SampleSeed)
+	selected = linkChecker.PrioritizeURLs(selected, cfg.CheckOrder)
+
+	list := checker.NewURLList(cfg.FrontierSpill)
+	if err := list.Add(selected...); err != nil {
+		log.Fatalf("Failed to store URLs: %v", err)
+	}
+	return list, discovered
+}
+
+// notifyWebhook posts a summary of the run's broken links to webhook-url
+func notifyWebhook(cfg *config.Config, summary checker.Summary) error {
+	broken := make([]notify.Link, 0, len(summary.Broken))
+	for _, link := range summary.Broken {
+		broken = append(broken, notify.Link{
+			URL:        link.URL,
+			StatusCode: link.StatusCode,
+			ErrorType:  string(link.ErrorType),
+			Error:      link.Error,
+			Sources:    link.Sources,
+		})
+	}
+	site := strings.Join(append(append([]string(nil), cfg.BaseURLs...), cfg.SitemapURLs...), ", ")
+	text, err := notify.Render(cfg.WebhookTemplate, notify.NewMessage(site, summary.Checked, broken, workflowRunURL()))
+	if err != nil {
+		return err
+	}
+	if err := notify.Send(cfg.WebhookURL, text); err != nil {
+		return err
+	}
+	fmt.Printf("Sent a notification of %d broken links to the webhook\n", len(broken))
+	return nil
+}
+
+// workflowRunURL returns the URL of the GitHub Actions run the checker runs
+// in, or "" outside of one
+func workflowRunURL() string {
+	runID, repo := os.Getenv("GITHUB_RUN_ID"), os.Getenv("GITHUB_REPOSITORY")
+	if runID == "" || repo == "" {
+		return ""
+	}
+	server := os.Getenv("GITHUB_SERVER_URL")
+	if server == "" {
+		server = "https://github.com"
+	}
+	return fmt.Sprintf("%s/%s/actions/runs/%s", server, repo, runID)
+}
+
+// exportMetrics writes the metrics of a run to metrics-file and pushes them
+// to pushgateway-url. Failures are reported without failing the run.
+func exportMetrics(linkChecker *checker.Checker, cfg *config.Config, summary checker.Summary, results iter.Seq[checker.LinkResult]) {
+	var metrics bytes.Buffer
+	if err := checker.WriteMetrics(&metrics, summary, results); err != nil {
+		log.Printf("Failed to write metrics: %v", err)
+		return
+	}
+	if cfg.MetricsFile != "" {
+		if err := os.WriteFile(cfg.MetricsFile, metrics.Bytes(), 0o644); err != nil { // #nosec G306 -- metrics are read by other tools
+			log.Printf("Failed to write metrics: %v", err)
+		} else {
+			fmt.Printf("Wrote metrics to %s\n", cfg.MetricsFile)
+		}
+	}
+	if cfg.PushgatewayURL != "" {
+		if err := linkChecker.PushMetrics(cfg.PushgatewayURL, cfg.MetricsJob, metrics.Bytes()); err != nil {
+			log.Printf("Failed to push metrics: %v", err)
+		} else {
+			fmt.Printf("Pushed metrics to the Pushgateway as job %s\n", cfg.MetricsJob)
+		}
+	}
+}
+
+// appendUnique appends the URLs not already in urls, keeping their order
+func appendUnique(urls, more []string) []string {
+	seen := make(map[string]bool, len(urls))
+	for _, u := range urls {
+		seen[u] = true
+	}
+	for _, u := range more {
+		if !seen[u] {
+			seen[u] = true
+			urls = append(urls, u)
+		}
+	}
+	return urls
+}
+
+// pullRequest returns the repository and number of the current pull request
+func pullRequest(cfg *config.Config) (string, int, error) {
+	repo := os.Getenv("GITHUB_REPOSITORY")
+	if repo == "" {
+		return "", 0, errors.New("GITHUB_REPOSITORY is not set")
+	}
+
+	number := cfg.PRNumber
+	if number == 0 {
+		var err error
+		number, err = github.PullRequestNumber(os.Getenv("GITHUB_EVENT_PATH"))
+		if err != nil {
+			return "", 0, err
+		}
+	}
+	return repo, number, nil
+}
+
+// changedPageURLs maps the files changed by the current pull request to the
+// site pages built from them
+func changedPageURLs(cfg *config.Config) ([]string, error) {
+	repo, number, err := pullRequest(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	client := github.NewClient(os.Getenv("GITHUB_API_URL"), cfg.GitHubToken)
+	files, err := client.PullRequestFiles(repo, number)
+	if err != nil {
+		return nil, err
+	}
+
+	var paths []string
+	for _, file := range files {
+		if file.Status != "removed" {
+			paths = append(paths, file.Filename)
+		}
+	}
+	return checker.ChangedPageURLs(paths, cfg.PathRules, cfg.BaseURL), nil
+}
+
+// prCommentMarker identifies the pull request comment updated on each run
+const prCommentMarker = "<!-- link-checker-report -->"
+
+// commentOnPullRequest posts the summary of a run as a comment on the
+// current pull request, updating the comment of an earlier run instead when
+// there is one. No new comment is posted for a run without broken links
+// outside the baseline.
+func commentOnPullRequest(cfg *config.Config, summary checker.Summary) error {
+	if cfg.GitHubToken == "" {
+		return errors.New("github-token is not set")
+	}
+	repo, number, err := pullRequest(cfg)
+	if err != nil {
+		return err
+	}
+
+	var body strings.Builder
+	if err := checker.WriteMarkdownSummary(&body, summary); err != nil {
+		return err
+	}
+	if runURL := workflowRunURL(); runURL != "" {
+		fmt.Fprintf(&body, "\n[View the workflow run](%s)\n", runURL)
+	}
+
+	client := github.NewClient(os.Getenv("GITHUB_API_URL"), cfg.GitHubToken)
+	existing, err := client.FindComment(repo, number, prCommentMarker)
+	if err != nil {
+		return err
+	}
+	text := github.StickyCommentBody(prCommentMarker, body.String())
+	switch {
+	case existing != nil:
+		if err := client.UpdateComment(repo, existing.ID, text); err != nil {
+			return err
+		}
+		fmt.Printf("Updated the link check comment on pull request #%d\n", number)
+	case len(summary.Broken) > summary.Baseline:
+		if err := client.CreateComment(repo, number, text); err != nil {
+			return err
+		}
+		fmt.Printf("Commented on pull request #%d\n", number)
+	}
+	return nil
+}
+
+// openFixPullRequest applies fixes to the source files of the pages that
+// contain them and opens a pull request against the default branch. It
+// returns an empty URL when no file needed changes.
+func openFixPullRequest(cfg *config.Config, fixes []checker.Fix) (string, error) {
+	repo := os.Getenv("GITHUB_REPOSITORY")
+	if repo == "" {
+		return "", errors.New("GITHUB_REPOSITORY is not set")
+	}
+
+	// Group fixes by file, keeping files in the order they were found
+	var files []string
+	fixesByFile := make(map[string][]checker.Fix)
+	for _, fix := range fixes {
+		for _, file := range fix.Files {
+			if _, seen := fixesByFile[file]; !seen {
+				files = append(files, file)
+			}
+			fixesByFile[file] = append(fixesByFile[file], fix)
+		}
+	}
+	if len(files) == 0 {
+		fmt.Printf("No link fixes to propose\n")
+		return "", nil
+	}
+
+	client := github.NewClient(os.Getenv("GITHUB_API_URL"), cfg.GitHubToken)
+	base, err := client.DefaultBranch(repo)
+	if err != nil {
+		return "", err
+	}
+
+	type update struct {
+		path    string
+		content []byte
+		sha     string
+	}
+	var updates []update
+	var applied []checker.Fix
+	appliedSeen := make(map[string]bool)
+	for _, file := range files {
+		content, sha, err := client.FileContents(repo, file, base)
+		if err != nil {
+			log.Printf("Skipping %s: %v", file, err)
+			continue
+		}
+		updated, count := checker.ApplyFixes(string(content), fixesByFile[file])
+		if count == 0 {
+			continue
+		}
+		updates = append(updates, update{path: file, content: []byte(updated), sha: sha})
+		for _, fix := range fixesByFile[file] {
+			if _, n := checker.ApplyFixes(string(content), []checker.Fix{fix}); n > 0 && !appliedSeen[fix.Old] {
+				appliedSeen[fix.Old] = true
+				applied = append(applied, fix)
+			}
+		}
+	}
+	if len(updates) == 0 {
+		fmt.Printf("No source files contain the redirected links verbatim\n")
+		return "", nil
+	}
+
+	sha, err := client.BranchSHA(repo, base)
+	if err != nil {
+		return "", err
+	}
+	branch := fmt.Sprintf("link-checker/fixes-%d", time.Now().Unix())
+	if err := client.CreateBranch(repo, branch, sha); err != nil {
+		return "", err
+	}
+	for _, u := range updates {
+		message := "Update redirected links in " + u.path
+		if err := client.UpdateFile(repo, u.path, branch, message, u.content, u.sha); err != nil {
+			return "", err
+		}
+	}
+
+	var body strings.Builder
+	body.WriteString("Replaces links that permanently redirect or upgrade to https with their final URLs.\n\n")
+	body.WriteString("| Old URL | New URL | Reason |\n|---|---|---|\n")
+	for _, fix := range applied {
+		fmt.Fprintf(&body, "| %s | %s | %s |\n", fix.Old, fix.New, fix.Reason)
+	}
+	return client.CreatePullRequest(repo, github.PullRequest{
+		Title: fmt.Sprintf("Update %d redirected links", len(applied)),
+		Head:  branch,
+		Base:  base,
+		Body:  body.String(),
+	})
+}
+
+// maxPrintedSources limits how many referring pages are listed per broken
+// link in the console report
+const maxPrintedSources = 5
+
+// configSnapshot returns the effective value of every setting, keyed by its
+// flag name, for recording alongside a report. Tokens, credentials, cookies
+// and login fields are redacted.
+func configSnapshot(flags *flag.FlagSet) map[string]string {
+	set := make(map[string]bool)
+	flags.Visit(func(f *flag.Flag) {
+		set[f.Name] = true
+	})
+
+	snapshot := make(map[string]string)
+	flags.VisitAll(func(f *flag.Flag) {
+		if f.Name == "help" || f.Name == "version" {
+			return
+		}
+		value := f.DefValue
+		if set[f.Name] {
+			value = f.Value.String()
+		} else if env := os.Getenv(config.InputEnv(f.Name)); env != "" {
+			value = env
+		}
+		if (strings.Contains(f.Name, "token") || f.Name == "login-fields" || f.Name == "headers" || f.Name == "basic-auth" || f.Name == "cookies" || f.Name == "pushgateway-url" || f.Name == "webhook-url") && value != "" {
+			value = "[redacted]"
+		}
+		snapshot[f.Name] = value
+	})
+	return snapshot
+}
+
+// resultLabel names a result's URL, with the locale it was checked in and
+// whether it's on another host
+func resultLabel(result checker.LinkResult) string {
+	label := result.URL
+	if result.Locale != "" {
+		label = fmt.Sprintf("%s [%s]", label, result.Locale)
+	}
+	if result.External {
+		label += " (external)"
+	}
+	return label
+}
+
+// printSources lists the pages linking to a broken link
+func printSources(sources []string) {
+	if len(sources) == 0 {
+		return
+	}
+	fmt.Printf("   Linked from %d page(s):\n", len(sources))
+	for i, source := range sources {
+		if i == maxPrintedSources {
+			fmt.Printf("   - ... and %d more\n", len(sources)-maxPrintedSources)
+			break
+		}
+		fmt.Printf("   - %s\n", source)
+	}
+}
+
+// printRedirectChain lists each redirect a link went through, ending at its
+// final URL
+func printRedirectChain(link checker.LinkResult) {
+	if len(link.RedirectChain) == 0 {
+		return
+	}
+	fmt.Printf("   Redirect chain:\n")
+	for _, hop := range link.RedirectChain {
+		fmt.Printf("   - %s (%d)\n", hop.URL, hop.StatusCode)
+	}
+	if link.FinalURL != "" {
+		fmt.Printf("   - %s\n", link.FinalURL)
+	}
+}
+
+// printDiagnosis writes a readable account of diagnosing a single URL
+func printDiagnosis(w io.Writer, d *checker.Diagnosis) {
+	fmt.Fprintf(w, "=== Diagnosis of %s ===\n", d.URL)
+
+	fmt.Fprintf(w, "\nDNS:\n")
+	if d.DNSError != "" {
+		fmt.Fprintf(w, "  error: %s\n", d.DNSError)
+	}
+	for _, address := range d.Addresses {
+		fmt.Fprintf(w, "  %s\n", address)
+	}
+
+	fmt.Fprintf(w, "\nRequests:\n")
+	for i, req := range d.Requests {
+		fmt.Fprintf(w, "  %d. %s %s\n", i+1, req.Method, req.URL)
+		if req.Error != "" {
+			fmt.Fprintf(w, "     Error: %s\n", req.Error)
+		} else {
+			fmt.Fprintf(w, "     Status: %d %s\n", req.StatusCode, http.StatusText(req.StatusCode))
+		}
+		timings := req.Timings
+		fmt.Fprintf(w, "     Timing: dns %s, connect %s, tls %s, first byte %s, total %s",
+			timings.DNS, timings.Connect, timings.TLS, timings.FirstByte, timings.Total)
+		if req.Reused {
+			fmt.Fprintf(w, " (reused connection)")
+		}
+		fmt.Fprintln(w)
+
+		names := make([]string, 0, len(req.Header))
+		for name := range req.Header {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			for _, value := range req.Header[name] {
+				fmt.Fprintf(w, "     %s: %s\n", name, value)
+			}
+		}
+	}
+
+	result := d.Result
+	fmt.Fprintf(w, "\nResult:\n")
+	fmt.Fprintf(w, "  Status: %d\n", result.StatusCode)
+	if result.FinalURL != "" {
+		fmt.Fprintf(w, "  Redirected (%d) to: %s\n", result.RedirectStatus, result.FinalURL)
+	}
+	fmt.Fprintf(w, "  Retries: %d\n", result.Retries)
+	fmt.Fprintf(w, "  Duration: %s\n", result.Duration)
+	switch {
+	case result.Accepted:
+		fmt.Fprintf(w, "  Classification: accepted by a status exception\n")
+	case result.ErrorType != "":
+		fmt.Fprintf(w, "  Classification: %s (%s)\n", result.ErrorType, result.Error)
+	default:
+		fmt.Fprintf(w, "  Classification: ok\n")
+	}
+}
+
+// runDiff compares the broken links of two JSON reports, prints the newly
+// broken, fixed and still broken ones and sets an output for each. It returns
+// the exit code: 1 when links are newly broken and -fail-on-error is set, 2
+// for usage errors.
+func runDiff(args []string, w io.Writer) int {
+	flags := flag.NewFlagSet("diff", flag.ContinueOnError)
+	failOnError := flags.Bool("fail-on-error", true, "Exit with error code if links are newly broken")
+	flags.Usage = func() {
+		fmt.Fprintf(flags.Output(), "Usage: %s diff [options] <before.json> <after.json>\n\nOptions:\n", os.Args[0])
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return 0
+		}
+		return 2
+	}
+	if flags.NArg() != 2 {
+		flags.Usage()
+		return 2
+	}
+
+	before, err := checker.LoadReport(flags.Arg(0))
+	if err != nil {
+		log.Printf("Failed to load report: %v", err)
+		return 2
+	}
+	after, err := checker.LoadReport(flags.Arg(1))
+	if err != nil {
+		log.Printf("Failed to load report: %v", err)
+		return 2
+	}
+	diff := checker.DiffReports(before, after)
+
+	fmt.Fprintf(w, "Comparing %s (%d links) with %s (%d links)\n",
+		flags.Arg(0), len(before.Results), flags.Arg(1), len(after.Results))
+	sections := []struct {
+		title string
+		emoji string
+		links []checker.LinkResult
+	}{
+		{"Newly broken", "❌", diff.NewlyBroken},
+		{"Fixed", "✅", diff.Fixed},
+		{"Still broken", "⚠️", diff.StillBroken},
+	}
+	for _, section := range sections {
+		fmt.Fprintf(w, "\n%s (%d):\n", section.title, len(section.links))
+		for _, link := range section.links {
+			fmt.Fprintf(w, "%s %s (Status: %d, Type: %s)\n", section.emoji, resultLabel(link), link.StatusCode, link.ErrorType)
+		}
+	}
+
+	for _, output := range []struct {
+		name  string
+		links []checker.LinkResult
+	}{
+		{"newly-broken", diff.NewlyBroken},
+		{"newly-fixed", diff.Fixed},
+		{"still-broken", diff.StillBroken},
+	} {
+		linksJSON, _ := truncateJSONArray(output.links, maxOutputSize)
+		setOutput(output.name, linksJSON)
+		setOutput(output.name+"-count", strconv.Itoa(len(output.links)))
+	}
+
+	if *failOnError && len(diff.NewlyBroken) > 0 {
+		return 1
+	}
+	return 0
+}
+
+// setErrorTypeOutputs sets per-category failure counts so workflows can
+// branch on the nature of the breakage
+func setErrorTypeOutputs(results []checker.LinkResult) {
+	counts := checker.CountErrorTypes(results)
+
+	networkErrors := 0
+	for errorType, count := range counts {
+		if errorType.IsNetwork() {
+			networkErrors += count
+		}
+	}
+
+	setOutput("broken-4xx-count", strconv.Itoa(counts[checker.ErrorTypeHTTP4xx]))
+	setOutput("broken-5xx-count", strconv.Itoa(counts[checker.ErrorTypeHTTP5xx]))
+	setOutput("network-error-count", strconv.Itoa(networkErrors))
+	setOutput("timeout-count", strconv.Itoa(counts[checker.ErrorTypeTimeout]))
+	setOutput("auth-required-count", strconv.Itoa(counts[checker.ErrorTypeAuthRequired]))
+	setOutput("bot-challenge-count", strconv.Itoa(counts[checker.ErrorTypeBotChallenge]))
+	setOutput("soft-404-count", strconv.Itoa(counts[checker.ErrorTypeSoft404]))
+
+	countsJSON, _ := json.Marshal(counts)
+	setOutput("error-type-counts", string(countsJSON))
+}
+
 // Helper functions for flag/environment variable precedence
 func getValueOrEnv(flagValue, envKey, defaultValue, flagName string) string {
 	// Check if flag was explicitly set
@@ -239,6 +2195,27 @@ func getIntValueOrEnv(flagValue int, envKey string, defaultValue int, flagName s
 	return defaultValue
 }
 
+// getDurationValueOrEnv returns an optional duration option from its flag or
+// environment variable, exiting on a malformed value rather than leaving the
+// option off
+func getDurationValueOrEnv(flagValue, envKey, flagName string) time.Duration {
+	duration, err := config.ParseDurationSetting(getValueOrEnv(flagValue, envKey, "", flagName))
+	if err != nil {
+		log.Fatalf("Invalid %s: %v", flagName, err)
+	}
+	return duration
+}
+
+// getPercentValueOrEnv returns an optional percentage option from its flag or
+// environment variable, exiting on a value that isn't a number in (0, 100]
+func getPercentValueOrEnv(flagValue, envKey, flagName string) float64 {
+	percent, err := config.ParsePercent(getValueOrEnv(flagValue, envKey, "", flagName))
+	if err != nil {
+		log.Fatalf("Invalid %s: %v", flagName, err)
+	}
+	return percent
+}
+
 func getBoolValueOrEnv(flagValue bool, envKey string, defaultValue bool, flagName string) bool {
 	// Check if flag was explicitly set
 	flagSet := false
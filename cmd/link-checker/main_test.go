@@ -1,13 +1,22 @@
 package main
 
 import (
+	"encoding/base64"
+	"encoding/json"
+	"flag"
 	"fmt"
+	"io"
 	"net/http"
 	"net/http/httptest"
 	"os"
+	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/joshbeard/link-validator/internal/checker"
 	"github.com/joshbeard/link-validator/internal/config"
+	"github.com/joshbeard/link-validator/internal/github"
 )
 
 func TestSetOutput(t *testing.T) {
@@ -159,7 +168,10 @@ func TestMainIntegration(t *testing.T) {
 	// In a real integration test, you might use a separate test binary or mock os.Exit
 
 	// For now, let's just verify the environment setup works
-	cfg := config.FromEnvironment()
+	cfg, err := config.FromEnvironment()
+	if err != nil {
+		t.Fatalf("FromEnvironment failed: %v", err)
+	}
 
 	if cfg.SitemapURL != sitemapServer.URL {
 		t.Errorf("Expected sitemap URL %s, got %s", sitemapServer.URL, cfg.SitemapURL)
@@ -177,3 +189,531 @@ func TestMainIntegration(t *testing.T) {
 		t.Errorf("Expected fail on error false, got %v", cfg.FailOnError)
 	}
 }
+
+func TestSetErrorTypeOutputs(t *testing.T) {
+	tmpFile, err := os.CreateTemp("", "github_output_error_types_test")
+	if err != nil {
+		t.Fatalf("Failed to create temp file: %v", err)
+	}
+	defer os.Remove(tmpFile.Name())
+	defer tmpFile.Close()
+
+	t.Setenv("GITHUB_OUTPUT", tmpFile.Name())
+
+	setErrorTypeOutputs([]checker.LinkResult{
+		{StatusCode: 200},
+		{StatusCode: 404, ErrorType: checker.ErrorTypeHTTP4xx},
+		{StatusCode: 404, ErrorType: checker.ErrorTypeHTTP4xx},
+		{StatusCode: 502, ErrorType: checker.ErrorTypeHTTP5xx},
+		{ErrorType: checker.ErrorTypeDNS},
+		{ErrorType: checker.ErrorTypeConnect},
+		{ErrorType: checker.ErrorTypeTimeout},
+		{StatusCode: 200, ErrorType: checker.ErrorTypeAuthRequired},
+		{StatusCode: 403, ErrorType: checker.ErrorTypeBotChallenge},
+	})
+
+	content, err := os.ReadFile(tmpFile.Name())
+	if err != nil {
+		t.Fatalf("Failed to read output file: %v", err)
+	}
+
+	for _, expected := range []string{
+		"broken-4xx-count=2\n",
+		"broken-5xx-count=1\n",
+		"network-error-count=2\n",
+		"timeout-count=1\n",
+		"auth-required-count=1\n",
+		"bot-challenge-count=1\n",
+		`error-type-counts={"auth_required":1,"bot_challenge":1,"connect":1,"dns":1,"http_4xx":2,"http_5xx":1,"timeout":1}` + "\n",
+	} {
+		if !strings.Contains(string(content), expected) {
+			t.Errorf("Expected output to contain %q, got %q", expected, string(content))
+		}
+	}
+}
+
+func TestErrorTypeOutputsMatchBrokenLinksCount(t *testing.T) {
+	tmpFile, err := os.CreateTemp("", "github_output_error_type_sum_test")
+	if err != nil {
+		t.Fatalf("Failed to create temp file: %v", err)
+	}
+	defer os.Remove(tmpFile.Name())
+	defer tmpFile.Close()
+
+	t.Setenv("GITHUB_OUTPUT", tmpFile.Name())
+
+	// The same broken link found on two pages, one of them in the baseline,
+	// is counted once, as broken-links-count counts it
+	flaggedLinks := checker.DedupeResults([]checker.LinkResult{
+		{URL: "https://example.com/gone", StatusCode: 404, ErrorType: checker.ErrorTypeHTTP4xx, Sources: []string{"https://example.com/a"}},
+		{URL: "https://example.com/gone", StatusCode: 404, ErrorType: checker.ErrorTypeHTTP4xx, Sources: []string{"https://example.com/b"}},
+		{URL: "https://example.com/known", StatusCode: 500, ErrorType: checker.ErrorTypeHTTP5xx, Baseline: true},
+		{URL: "https://unresolvable.example", ErrorType: checker.ErrorTypeDNS},
+		{URL: "https://example.com/private", StatusCode: 200, ErrorType: checker.ErrorTypeAuthRequired},
+	})
+	brokenCount := 0
+	for _, link := range flaggedLinks {
+		if link.ErrorType.IsFailure() {
+			brokenCount++
+		}
+	}
+
+	setErrorTypeOutputs(flaggedLinks)
+
+	content, err := os.ReadFile(tmpFile.Name())
+	if err != nil {
+		t.Fatalf("Failed to read output file: %v", err)
+	}
+	_, countsJSON, found := strings.Cut(string(content), "error-type-counts=")
+	if !found {
+		t.Fatalf("Expected an error-type-counts output, got %q", string(content))
+	}
+	var counts map[checker.ErrorType]int
+	if err := json.Unmarshal([]byte(strings.TrimSpace(countsJSON)), &counts); err != nil {
+		t.Fatalf("Failed to parse error-type-counts: %v", err)
+	}
+	sum := 0
+	for errorType, count := range counts {
+		if errorType.IsFailure() {
+			sum += count
+		}
+	}
+	if sum != brokenCount || brokenCount != 3 {
+		t.Errorf("Expected failure counts to add up to %d broken links, got %d (%v)", brokenCount, sum, counts)
+	}
+}
+
+func TestChangedPageURLs(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/repos/owner/site/pulls/3/files" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		fmt.Fprint(w, `[
+			{"filename": "content/docs/intro.md", "status": "modified"},
+			{"filename": "content/docs/old.md", "status": "removed"},
+			{"filename": "go.mod", "status": "modified"}
+		]`)
+	}))
+	defer server.Close()
+
+	t.Setenv("GITHUB_REPOSITORY", "owner/site")
+	t.Setenv("GITHUB_API_URL", server.URL)
+
+	cfg := &config.Config{
+		BaseURL:   "https://example.com",
+		PathRules: config.ParsePathRules(`^content/(.+)\.md$=/$1/`),
+		PRNumber:  3,
+	}
+	pages, err := changedPageURLs(cfg)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(pages) != 1 || pages[0] != "https://example.com/docs/intro/" {
+		t.Errorf("Expected only the modified content page, got %v", pages)
+	}
+
+	t.Setenv("GITHUB_REPOSITORY", "")
+	if _, err := changedPageURLs(cfg); err == nil {
+		t.Error("Expected an error without GITHUB_REPOSITORY")
+	}
+}
+
+func TestAnnotateBrokenLinks(t *testing.T) {
+	var out strings.Builder
+	annotateBrokenLinks(&out, []checker.LinkResult{
+		{URL: "https://example.com/gone", Error: "HTTP 404 404 Not Found", SourceFiles: []string{"content/a.md", "content/b,c.md"}},
+		{URL: "https://example.com/unmapped", Error: "HTTP 500"},
+		{URL: "https://example.com/slow", Error: "request failed: timeout", Severity: checker.SeverityWarning, SourceFiles: []string{"content/c.md"}},
+		{URL: "https://example.com/private", Error: "redirected to login page", Severity: checker.SeverityInfo, SourceFiles: []string{"content/d.md"}},
+	})
+
+	expected := "::error file=content/a.md,title=Broken link::https://example.com/gone - HTTP 404 404 Not Found\n" +
+		"::error file=content/b%2Cc.md,title=Broken link::https://example.com/gone - HTTP 404 404 Not Found\n" +
+		"::warning file=content/c.md,title=Broken link::https://example.com/slow - request failed: timeout\n" +
+		"::notice file=content/d.md,title=Broken link::https://example.com/private - redirected to login page\n"
+	if out.String() != expected {
+		t.Errorf("Expected annotations:\n%s\ngot:\n%s", expected, out.String())
+	}
+}
+
+func TestRunDiff(t *testing.T) {
+	dir := t.TempDir()
+	beforePath := filepath.Join(dir, "before.json")
+	afterPath := filepath.Join(dir, "after.json")
+	if err := checker.WriteReport(beforePath, checker.Report{Results: []checker.LinkResult{
+		{URL: "https://example.com/old", StatusCode: 404, ErrorType: checker.ErrorTypeHTTP4xx},
+		{URL: "https://example.com/moved", StatusCode: 404, ErrorType: checker.ErrorTypeHTTP4xx},
+	}}); err != nil {
+		t.Fatal(err)
+	}
+	if err := checker.WriteReport(afterPath, checker.Report{Results: []checker.LinkResult{
+		{URL: "https://example.com/old", StatusCode: 404, ErrorType: checker.ErrorTypeHTTP4xx},
+		{URL: "https://example.com/moved", StatusCode: 200},
+		{URL: "https://example.com/new", StatusCode: 500, ErrorType: checker.ErrorTypeHTTP5xx},
+	}}); err != nil {
+		t.Fatal(err)
+	}
+
+	outputPath := filepath.Join(dir, "github_output")
+	if err := os.WriteFile(outputPath, nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("GITHUB_OUTPUT", outputPath)
+
+	var out strings.Builder
+	if code := runDiff([]string{beforePath, afterPath}, &out); code != 1 {
+		t.Errorf("Expected exit code 1 for newly broken links, got %d", code)
+	}
+	for _, expected := range []string{
+		"Newly broken (1):\n❌ https://example.com/new (Status: 500, Type: http_5xx)\n",
+		"Fixed (1):\n✅ https://example.com/moved (Status: 404, Type: http_4xx)\n",
+		"Still broken (1):\n⚠️ https://example.com/old (Status: 404, Type: http_4xx)\n",
+	} {
+		if !strings.Contains(out.String(), expected) {
+			t.Errorf("Expected output to contain %q, got:\n%s", expected, out.String())
+		}
+	}
+
+	content, err := os.ReadFile(outputPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, expected := range []string{
+		"newly-broken-count=1\n",
+		"newly-fixed-count=1\n",
+		"still-broken-count=1\n",
+		`newly-broken=[{"url":"https://example.com/new",`,
+		"newly-fixed=[{\"url\":\"https://example.com/moved\",",
+	} {
+		if !strings.Contains(string(content), expected) {
+			t.Errorf("Expected outputs to contain %q, got %q", expected, content)
+		}
+	}
+
+	if code := runDiff([]string{"-fail-on-error=false", beforePath, afterPath}, io.Discard); code != 0 {
+		t.Errorf("Expected exit code 0 without fail-on-error, got %d", code)
+	}
+	if code := runDiff([]string{afterPath, afterPath}, io.Discard); code != 0 {
+		t.Errorf("Expected exit code 0 without newly broken links, got %d", code)
+	}
+	if code := runDiff([]string{beforePath}, io.Discard); code != 2 {
+		t.Errorf("Expected exit code 2 for a missing report argument, got %d", code)
+	}
+	if code := runDiff([]string{beforePath, filepath.Join(dir, "missing.json")}, io.Discard); code != 2 {
+		t.Errorf("Expected exit code 2 for an unreadable report, got %d", code)
+	}
+}
+
+func TestPrintDiagnosis(t *testing.T) {
+	var out strings.Builder
+	printDiagnosis(&out, &checker.Diagnosis{
+		URL:       "https://example.com/old",
+		Addresses: []string{"192.0.2.10"},
+		Requests: []checker.DiagnosticRequest{
+			{Method: "HEAD", URL: "https://example.com/old", StatusCode: 301,
+				Header: http.Header{"Location": {"/new"}, "Cache-Control": {"max-age=60"}}},
+			{Method: "HEAD", URL: "https://example.com/new", Error: "connection reset", Reused: true},
+		},
+		Result: checker.LinkResult{URL: "https://example.com/old", Error: "request failed: connection reset",
+			ErrorType: checker.ErrorTypeConnect, Retries: 2},
+	})
+
+	for _, expected := range []string{
+		"  192.0.2.10\n",
+		"  1. HEAD https://example.com/old\n     Status: 301 Moved Permanently\n",
+		"     Cache-Control: max-age=60\n     Location: /new\n",
+		"     Error: connection reset\n",
+		"(reused connection)",
+		"  Retries: 2\n",
+		"  Classification: connect (request failed: connection reset)\n",
+	} {
+		if !strings.Contains(out.String(), expected) {
+			t.Errorf("Expected output to contain %q, got:\n%s", expected, out.String())
+		}
+	}
+}
+
+func TestApplyConfigFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "link-checker.json")
+	content := `{
+  "settings": {"base-url": "https://example.com", "max-depth": 3},
+  "profiles": {"pr-preview": {"max-depth": 1}}
+}`
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	flags := flag.NewFlagSet("test", flag.ContinueOnError)
+	flags.String("base-url", "", "")
+	flags.Int("max-depth", 3, "")
+
+	t.Setenv("INPUT_BASE_URL", "https://ignored.example.com")
+	t.Setenv("INPUT_MAX_DEPTH", "")
+	if err := applyConfigFile(path, "pr-preview", flags); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if got := os.Getenv("INPUT_BASE_URL"); got != "https://example.com" {
+		t.Errorf("Expected shared base URL, got %q", got)
+	}
+	if got := os.Getenv("INPUT_MAX_DEPTH"); got != "1" {
+		t.Errorf("Expected profile max depth, got %q", got)
+	}
+
+	if err := applyConfigFile(path, "production", flags); err == nil {
+		t.Error("Expected an error for an unknown profile")
+	}
+
+	unknown := filepath.Join(t.TempDir(), "unknown.json")
+	if err := os.WriteFile(unknown, []byte(`{"settings": {"max-dept": 2}}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := applyConfigFile(unknown, "", flags); err == nil || !strings.Contains(err.Error(), "max-dept") {
+		t.Errorf("Expected an error naming the unknown setting, got %v", err)
+	}
+}
+
+func TestTranslateCompatArgs(t *testing.T) {
+	flags := flag.NewFlagSet("test", flag.ContinueOnError)
+	flags.String("exclude-patterns", "", "")
+	flags.Int("max-concurrent", 10, "")
+	flags.Bool("verbose", false, "")
+	flags.String("headers", "", "")
+	flags.String("timeout-overrides", "", "")
+	flags.String("cookies", "", "")
+	flags.String("base-url", "", "")
+
+	args := []string{
+		"https://example.com", "--buffer-size", "8192", "--max-connections=5",
+		"-verbose", "--skip", "twitter\\.com", "--exclude=/private/",
+		"-exclude-patterns", "\\.pdf$", "--recurse", "--retry=false",
+		"--header", "Authorization: Bearer abc", "--header=X-Env: staging",
+		"-timeout-rule", "/downloads/=120s", "--timeout-overrides=api\\.example\\.com=5s", "-timeout-rule=slow\\.example=60s",
+		"--cookie", "session=abc; Secure", "-cookie=locale=en",
+		"-base-url", "https://docs.example.com", "--base-url=https://blog.example.com",
+	}
+	got, notes, err := translateCompatArgs(args, flags)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	want := []string{
+		"-max-concurrent=5", "-verbose", "-max-depth=100",
+		"-exclude-patterns=twitter\\.com,/private/,\\.pdf$",
+		"-headers=Authorization: Bearer abc\nX-Env: staging",
+		"-timeout-overrides=/downloads/=120s,api\\.example\\.com=5s,slow\\.example=60s",
+		"-cookies=session=abc; Secure\nlocale=en",
+		"-base-url=https://docs.example.com,https://blog.example.com",
+		"--", "https://example.com",
+	}
+	if strings.Join(got, " ") != strings.Join(want, " ") {
+		t.Errorf("Expected %q, got %q", want, got)
+	}
+	if len(notes) != 1 || !strings.Contains(notes[0], "buffer-size") {
+		t.Errorf("Expected a note about buffer-size, got %q", notes)
+	}
+
+	if _, _, err := translateCompatArgs([]string{"--skip"}, flags); err == nil {
+		t.Error("Expected an error for a missing value")
+	}
+}
+
+func TestConfigSnapshot(t *testing.T) {
+	flags := flag.NewFlagSet("test", flag.ContinueOnError)
+	flags.Bool("help", false, "")
+	flags.String("base-url", "", "")
+	flags.Int("max-depth", 3, "")
+	flags.Int("timeout", 30, "")
+	flags.String("github-token", "", "")
+	flags.String("login-fields", "", "")
+	flags.String("basic-auth", "", "")
+	if err := flags.Parse([]string{"-base-url", "https://example.com", "-login-fields", "password=hunter2", "-basic-auth", "user:hunter2"}); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("INPUT_MAX_DEPTH", "5")
+	t.Setenv("INPUT_TIMEOUT", "")
+	t.Setenv("INPUT_GITHUB_TOKEN", "ghs_secret")
+
+	snapshot := configSnapshot(flags)
+	expected := map[string]string{
+		"base-url":     "https://example.com",
+		"max-depth":    "5",
+		"timeout":      "30",
+		"github-token": "[redacted]",
+		"login-fields": "[redacted]",
+		"basic-auth":   "[redacted]",
+	}
+	if len(snapshot) != len(expected) {
+		t.Errorf("Expected %v, got %v", expected, snapshot)
+	}
+	for name, value := range expected {
+		if snapshot[name] != value {
+			t.Errorf("Expected %s=%q, got %q", name, value, snapshot[name])
+		}
+	}
+}
+
+func TestCommentOnPullRequest(t *testing.T) {
+	var comments []map[string]any
+	var posted, patched string
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /repos/owner/site/issues/5/comments", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(comments)
+	})
+	mux.HandleFunc("POST /repos/owner/site/issues/5/comments", func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]string
+		json.NewDecoder(r.Body).Decode(&body)
+		posted = body["body"]
+		w.WriteHeader(http.StatusCreated)
+	})
+	mux.HandleFunc("PATCH /repos/owner/site/issues/comments/42", func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]string
+		json.NewDecoder(r.Body).Decode(&body)
+		patched = body["body"]
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	t.Setenv("GITHUB_REPOSITORY", "owner/site")
+	t.Setenv("GITHUB_API_URL", server.URL)
+	t.Setenv("GITHUB_RUN_ID", "99")
+	t.Setenv("GITHUB_SERVER_URL", "")
+	cfg := &config.Config{GitHubToken: "secret", PRNumber: 5}
+
+	if err := commentOnPullRequest(cfg, checker.Summary{Checked: 3}); err != nil || posted != "" {
+		t.Fatalf("Expected no new comment without broken links, got %q (%v)", posted, err)
+	}
+
+	broken := checker.Summary{Checked: 3, Broken: []checker.LinkResult{{URL: "https://example.com/gone", StatusCode: 404}}}
+	if err := commentOnPullRequest(cfg, broken); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !strings.HasPrefix(posted, prCommentMarker) || !strings.Contains(posted, "https://example.com/gone") ||
+		!strings.Contains(posted, "https://github.com/owner/site/actions/runs/99") {
+		t.Errorf("Unexpected comment: %q", posted)
+	}
+
+	comments = []map[string]any{{"id": 42, "body": posted}}
+	if err := commentOnPullRequest(cfg, checker.Summary{Checked: 3}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !strings.Contains(patched, "No broken links found") {
+		t.Errorf("Expected the earlier comment to be updated, got %q", patched)
+	}
+
+	if err := commentOnPullRequest(&config.Config{PRNumber: 5}, broken); err == nil {
+		t.Error("Expected an error without a token")
+	}
+}
+
+func TestOpenFixPullRequest(t *testing.T) {
+	var pr github.PullRequest
+	var updatedFiles []string
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /repos/owner/site", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"default_branch": "main"}`)
+	})
+	mux.HandleFunc("GET /repos/owner/site/contents/content/docs.md", func(w http.ResponseWriter, r *http.Request) {
+		content := base64.StdEncoding.EncodeToString([]byte("See [old](https://example.com/old).\n"))
+		fmt.Fprintf(w, `{"encoding": "base64", "sha": "blob1", "content": "%s"}`, content)
+	})
+	mux.HandleFunc("GET /repos/owner/site/contents/content/about.md", func(w http.ResponseWriter, r *http.Request) {
+		content := base64.StdEncoding.EncodeToString([]byte("No links here.\n"))
+		fmt.Fprintf(w, `{"encoding": "base64", "sha": "blob2", "content": "%s"}`, content)
+	})
+	mux.HandleFunc("GET /repos/owner/site/git/ref/heads/main", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"object": {"sha": "abc123"}}`)
+	})
+	mux.HandleFunc("POST /repos/owner/site/git/refs", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusCreated)
+	})
+	mux.HandleFunc("PUT /repos/owner/site/contents/", func(w http.ResponseWriter, r *http.Request) {
+		updatedFiles = append(updatedFiles, strings.TrimPrefix(r.URL.Path, "/repos/owner/site/contents/"))
+	})
+	mux.HandleFunc("POST /repos/owner/site/pulls", func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&pr)
+		fmt.Fprint(w, `{"html_url": "https://github.com/owner/site/pull/1"}`)
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	t.Setenv("GITHUB_REPOSITORY", "owner/site")
+	t.Setenv("GITHUB_API_URL", server.URL)
+	cfg := &config.Config{}
+
+	fixes := []checker.Fix{{
+		Old:    "https://example.com/old",
+		New:    "https://example.com/new",
+		Reason: "permanent redirect",
+		Files:  []string{"content/docs.md", "content/about.md"},
+	}}
+	prURL, err := openFixPullRequest(cfg, fixes)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if prURL != "https://github.com/owner/site/pull/1" {
+		t.Errorf("Unexpected pull request URL %q", prURL)
+	}
+	if len(updatedFiles) != 1 || updatedFiles[0] != "content/docs.md" {
+		t.Errorf("Expected only the file containing the link to be updated, got %v", updatedFiles)
+	}
+	if pr.Base != "main" || !strings.Contains(pr.Body, "| https://example.com/old | https://example.com/new | permanent redirect |") {
+		t.Errorf("Unexpected pull request: %+v", pr)
+	}
+
+	prURL, err = openFixPullRequest(cfg, nil)
+	if err != nil || prURL != "" {
+		t.Errorf("Expected no pull request without fixes, got %q (%v)", prURL, err)
+	}
+}
+
+func TestTruncateJSONArray(t *testing.T) {
+	links := []checker.LinkResult{
+		{URL: "https://example.com/a", StatusCode: 404},
+		{URL: "https://example.com/b", StatusCode: 404},
+		{URL: "https://example.com/c", StatusCode: 404},
+	}
+	full, err := json.Marshal(links)
+	if err != nil {
+		t.Fatalf("Failed to encode links: %v", err)
+	}
+
+	if got, truncated := truncateJSONArray(links, len(full)); got != string(full) || truncated {
+		t.Errorf("Expected the full array at its exact size, got %s (truncated %v)", got, truncated)
+	}
+
+	got, truncated := truncateJSONArray(links, len(full)-1)
+	if !truncated {
+		t.Error("Expected the array to be truncated")
+	}
+	var decoded []checker.LinkResult
+	if err := json.Unmarshal([]byte(got), &decoded); err != nil {
+		t.Fatalf("Expected valid JSON, got %s: %v", got, err)
+	}
+	if len(decoded) != 2 || decoded[1].URL != "https://example.com/b" {
+		t.Errorf("Expected the first two links, got %+v", decoded)
+	}
+
+	if got, truncated := truncateJSONArray(links, 10); got != "[]" || !truncated {
+		t.Errorf("Expected an empty array when nothing fits, got %s (truncated %v)", got, truncated)
+	}
+	if got, truncated := truncateJSONArray([]checker.PageIssue(nil), maxOutputSize); got != "[]" || truncated {
+		t.Errorf("Expected an empty array for no items, got %s (truncated %v)", got, truncated)
+	}
+}
+
+func TestWriteStepSummary(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "summary.md")
+	t.Setenv("GITHUB_STEP_SUMMARY", path)
+
+	writeStepSummary(checker.Summary{Checked: 3, Duration: time.Second})
+	writeStepSummary(checker.Summary{Checked: 1})
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Failed to read summary: %v", err)
+	}
+	if got := strings.Count(string(content), "## Link Check Results"); got != 2 {
+		t.Errorf("Expected both summaries to be appended, got %d in %q", got, string(content))
+	}
+}
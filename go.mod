@@ -3,6 +3,7 @@ module github.com/joshbeard/link-validator
 go 1.23.0
 
 require (
+	github.com/andybalholm/brotli v1.2.0
 	github.com/boumenot/gocover-cobertura v1.3.0
 	github.com/golangci/golangci-lint v1.64.8
 	github.com/segmentio/golines v0.12.2
@@ -0,0 +1,227 @@
+// Package cache persists what the link checker learns between runs so
+// repeat runs can skip work that has not changed.
+package cache
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+// FileName is the name of the cache file inside a cache directory
+const FileName = "link-checker-cache.json"
+
+// formatVersion is bumped whenever the file layout changes incompatibly.
+// Files with another version are discarded rather than misread.
+const formatVersion = 2
+
+// Page holds the validators and extracted links of a crawled page
+type Page struct {
+	ETag          string    `json:"etag,omitempty"`
+	LastModified  string    `json:"last_modified,omitempty"`
+	ContentType   string    `json:"content_type,omitempty"`
+	Links         []string  `json:"links"`
+	Assets        []string  `json:"assets,omitempty"`
+	ExternalLinks []string  `json:"external_links,omitempty"`
+	StoredAt      time.Time `json:"stored_at"`
+
+	// Elements holds the element each link was first found in on the page,
+	// for links not first found in an <a>
+	Elements map[string]string `json:"elements,omitempty"`
+	// Fragments lists, sorted, the links only ever written with a fragment
+	// on the page, such as /docs/#install
+	Fragments []string `json:"fragments,omitempty"`
+}
+
+// HasValidators reports whether the page can be revalidated with a
+// conditional request
+func (p Page) HasValidators() bool {
+	return p.ETag != "" || p.LastModified != ""
+}
+
+// Check records the last successful check of a URL
+type Check struct {
+	StatusCode int       `json:"status_code"`
+	CheckedAt  time.Time `json:"checked_at"`
+}
+
+// fileFormat is the on-disk JSON layout
+type fileFormat struct {
+	Version int              `json:"version"`
+	Pages   map[string]Page  `json:"pages"`
+	Checks  map[string]Check `json:"checks,omitempty"`
+}
+
+// Cache is a JSON file backed store keyed by URL. It is safe for concurrent
+// use.
+type Cache struct {
+	path       string
+	mu         sync.Mutex
+	pages      map[string]Page
+	checks     map[string]Check
+	maxEntries int
+}
+
+// Open loads the cache kept in dir, creating the directory if needed. Keeping
+// the cache and its temporary files in one directory makes it easy to
+// persist between runs with actions/cache.
+func Open(dir string) (*Cache, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil { // #nosec G301 -- the cache holds no secrets
+		return nil, fmt.Errorf("creating cache directory: %w", err)
+	}
+	return Load(filepath.Join(dir, FileName))
+}
+
+// Load reads the cache at path. A missing file, or one written by an
+// incompatible version, yields an empty cache.
+func Load(path string) (*Cache, error) {
+	c := &Cache{path: path, pages: make(map[string]Page), checks: make(map[string]Check)}
+
+	data, err := os.ReadFile(path) // #nosec G304 -- path is provided by the user
+	if errors.Is(err, os.ErrNotExist) {
+		return c, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading cache: %w", err)
+	}
+
+	var file fileFormat
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("parsing cache: %w", err)
+	}
+	if file.Version != formatVersion {
+		return c, nil
+	}
+	if file.Pages != nil {
+		c.pages = file.Pages
+	}
+	if file.Checks != nil {
+		c.checks = file.Checks
+	}
+	return c, nil
+}
+
+// Page returns the cached entry for a page URL
+func (c *Cache) Page(pageURL string) (Page, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	page, ok := c.pages[pageURL]
+	return page, ok
+}
+
+// SetPage stores the entry for a page URL
+func (c *Cache) SetPage(pageURL string, page Page) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if page.StoredAt.IsZero() {
+		page.StoredAt = time.Now()
+	}
+	c.pages[pageURL] = page
+}
+
+// Check returns the last successful check recorded for a URL
+func (c *Cache) Check(checkURL string) (Check, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	check, ok := c.checks[checkURL]
+	return check, ok
+}
+
+// SetCheck records a successful check of a URL
+func (c *Cache) SetCheck(checkURL string, check Check) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.checks[checkURL] = check
+}
+
+// DeleteCheck forgets the last successful check of a URL
+func (c *Cache) DeleteCheck(checkURL string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	delete(c.checks, checkURL)
+}
+
+// SetMaxEntries bounds the number of pages and checks kept when saving. The
+// least recently stored entries are dropped first. Zero means unbounded.
+func (c *Cache) SetMaxEntries(n int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.maxEntries = n
+}
+
+// Save writes the cache back to its file. The file is replaced atomically so
+// an interrupted run never leaves a truncated cache behind.
+func (c *Cache) Save() error {
+	c.mu.Lock()
+	c.prune()
+	data, err := json.Marshal(fileFormat{Version: formatVersion, Pages: c.pages, Checks: c.checks})
+	c.mu.Unlock()
+	if err != nil {
+		return fmt.Errorf("encoding cache: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(c.path), filepath.Base(c.path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("writing cache: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("writing cache: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("writing cache: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), c.path); err != nil {
+		return fmt.Errorf("writing cache: %w", err)
+	}
+	return nil
+}
+
+// prune drops the oldest entries beyond the entry limit. Entries from older
+// versions without a timestamp count as the oldest. The caller must hold mu.
+func (c *Cache) prune() {
+	excess := len(c.pages) + len(c.checks) - c.maxEntries
+	if c.maxEntries <= 0 || excess <= 0 {
+		return
+	}
+
+	type entry struct {
+		url    string
+		isPage bool
+		stored time.Time
+	}
+	entries := make([]entry, 0, len(c.pages)+len(c.checks))
+	for pageURL, page := range c.pages {
+		entries = append(entries, entry{url: pageURL, isPage: true, stored: page.StoredAt})
+	}
+	for checkURL, check := range c.checks {
+		entries = append(entries, entry{url: checkURL, stored: check.CheckedAt})
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		if !entries[i].stored.Equal(entries[j].stored) {
+			return entries[i].stored.Before(entries[j].stored)
+		}
+		return entries[i].url < entries[j].url
+	})
+
+	for _, e := range entries[:excess] {
+		if e.isPage {
+			delete(c.pages, e.url)
+		} else {
+			delete(c.checks, e.url)
+		}
+	}
+}
@@ -0,0 +1,163 @@
+package cache
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestLoadMissingFile(t *testing.T) {
+	c, err := Load(filepath.Join(t.TempDir(), "cache.json"))
+	if err != nil {
+		t.Fatalf("Expected no error for a missing cache, got %v", err)
+	}
+	if _, ok := c.Page("https://example.com/"); ok {
+		t.Error("Expected an empty cache")
+	}
+}
+
+func TestSaveAndLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cache.json")
+
+	c, err := Load(path)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	c.SetPage("https://example.com/", Page{
+		ETag:        `"abc"`,
+		ContentType: "text/html",
+		Links:       []string{"https://example.com/a", "https://example.com/b"},
+	})
+	if err := c.Save(); err != nil {
+		t.Fatalf("Expected no error saving, got %v", err)
+	}
+
+	reloaded, err := Load(path)
+	if err != nil {
+		t.Fatalf("Expected no error reloading, got %v", err)
+	}
+	page, ok := reloaded.Page("https://example.com/")
+	if !ok {
+		t.Fatal("Expected the page to survive a save and load")
+	}
+	if page.ETag != `"abc"` || page.ContentType != "text/html" || len(page.Links) != 2 {
+		t.Errorf("Unexpected page: %+v", page)
+	}
+	if !page.HasValidators() {
+		t.Error("Expected the page to have validators")
+	}
+
+	entries, _ := os.ReadDir(filepath.Dir(path))
+	if len(entries) != 1 {
+		t.Errorf("Expected only the cache file to remain, found %d entries", len(entries))
+	}
+}
+
+func TestLoadIncompatibleVersion(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cache.json")
+	os.WriteFile(path, []byte(`{"version": 999, "pages": {"https://example.com/": {"etag": "x", "links": []}}}`), 0o600)
+
+	c, err := Load(path)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if _, ok := c.Page("https://example.com/"); ok {
+		t.Error("Expected entries from another format version to be discarded")
+	}
+}
+
+func TestLoadInvalidFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cache.json")
+	os.WriteFile(path, []byte(`{"version": `), 0o600)
+
+	if _, err := Load(path); err == nil {
+		t.Error("Expected an error for a corrupt cache file")
+	}
+}
+
+func TestChecks(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cache.json")
+	checkedAt := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	c, _ := Load(path)
+	c.SetCheck("https://example.com/a", Check{StatusCode: 200, CheckedAt: checkedAt})
+	c.SetCheck("https://example.com/b", Check{StatusCode: 200, CheckedAt: checkedAt})
+	c.DeleteCheck("https://example.com/b")
+	if err := c.Save(); err != nil {
+		t.Fatalf("Expected no error saving, got %v", err)
+	}
+
+	reloaded, _ := Load(path)
+	check, ok := reloaded.Check("https://example.com/a")
+	if !ok || check.StatusCode != 200 || !check.CheckedAt.Equal(checkedAt) {
+		t.Errorf("Unexpected check: %+v (found %v)", check, ok)
+	}
+	if _, ok := reloaded.Check("https://example.com/b"); ok {
+		t.Error("Expected deleted check to stay deleted")
+	}
+}
+
+func TestOpen(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "link-checker")
+
+	c, err := Open(dir)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	c.SetPage("https://example.com/", Page{Links: []string{"https://example.com/a"}})
+	if err := c.Save(); err != nil {
+		t.Fatalf("Expected no error saving, got %v", err)
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 || entries[0].Name() != FileName {
+		t.Errorf("Expected only %s in the cache directory, got %v", FileName, entries)
+	}
+
+	reopened, err := Open(dir)
+	if err != nil {
+		t.Fatalf("Expected no error reopening, got %v", err)
+	}
+	if _, ok := reopened.Page("https://example.com/"); !ok {
+		t.Error("Expected the page to survive reopening the directory")
+	}
+}
+
+func TestSaveBoundsEntries(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cache.json")
+	c, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	now := time.Now()
+	c.SetPage("https://example.com/oldest", Page{StoredAt: now.Add(-3 * time.Hour)})
+	c.SetCheck("https://example.com/old-check", Check{StatusCode: 200, CheckedAt: now.Add(-2 * time.Hour)})
+	c.SetPage("https://example.com/recent", Page{StoredAt: now.Add(-time.Hour)})
+	c.SetCheck("https://example.com/new-check", Check{StatusCode: 200, CheckedAt: now})
+	c.SetMaxEntries(2)
+	if err := c.Save(); err != nil {
+		t.Fatalf("Expected no error saving, got %v", err)
+	}
+
+	reloaded, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := reloaded.Page("https://example.com/oldest"); ok {
+		t.Error("Expected the oldest page to be dropped")
+	}
+	if _, ok := reloaded.Check("https://example.com/old-check"); ok {
+		t.Error("Expected the oldest check to be dropped")
+	}
+	if _, ok := reloaded.Page("https://example.com/recent"); !ok {
+		t.Error("Expected the recent page to be kept")
+	}
+	if _, ok := reloaded.Check("https://example.com/new-check"); !ok {
+		t.Error("Expected the new check to be kept")
+	}
+}
@@ -0,0 +1,54 @@
+package checker
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// authorize adds the configured headers and basic auth credentials to a
+// request for the site being checked. Requests to other hosts never get
+// them, so tokens for a staging site aren't sent to every external link.
+// Headers set here replace those already on the request, such as the
+// User-Agent.
+func (c *Checker) authorize(req *http.Request) {
+	if len(c.config.Headers) == 0 && c.config.BasicAuth == "" {
+		return
+	}
+	if !c.isSiteHost(req.URL) {
+		return
+	}
+	for name, values := range c.config.Headers {
+		req.Header[name] = append([]string(nil), values...)
+	}
+	if username, password, ok := strings.Cut(c.config.BasicAuth, ":"); ok {
+		req.SetBasicAuth(username, password)
+	}
+}
+
+// isSiteHost reports whether a URL is on the host of a configured base URL
+// or sitemap, the JSON URLs, login page or deploy preview
+func (c *Checker) isSiteHost(u *url.URL) bool {
+	sites := append(c.siteURLs(), c.config.LoginURL, c.config.PreviewURL)
+	sites = append(sites, c.config.JSONURLs...)
+	for _, site := range sites {
+		if siteURL, err := url.Parse(site); err == nil && site != "" && strings.EqualFold(siteURL.Host, u.Host) {
+			return true
+		}
+	}
+	return false
+}
+
+// dropHeaders removes Authorization and the given headers from a redirected
+// request that leaves the host and port of the original request. net/http
+// only drops Authorization when the domain changes, and forwards any other
+// header.
+func dropHeaders(req *http.Request, via []*http.Request, headers http.Header) {
+	if len(via) == 0 || strings.EqualFold(req.URL.Host, via[0].URL.Host) {
+		return
+	}
+	req.Header.Del("Authorization")
+	for name := range headers {
+		req.Header.Del(name)
+	}
+}
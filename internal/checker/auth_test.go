@@ -0,0 +1,52 @@
+package checker
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/joshbeard/link-validator/internal/config"
+)
+
+func TestAuthorize(t *testing.T) {
+	var other *httptest.Server
+	site := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/away" {
+			http.Redirect(w, r, other.URL+"/landing", http.StatusFound)
+			return
+		}
+		username, password, ok := r.BasicAuth()
+		if r.Header.Get("X-Api-Key") != "secret" || !ok || username != "staging" || password != "hunter2" {
+			w.WriteHeader(http.StatusUnauthorized)
+		}
+	}))
+	defer site.Close()
+	other = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Api-Key") != "" || r.Header.Get("Authorization") != "" {
+			w.WriteHeader(http.StatusBadRequest)
+		}
+	}))
+	defer other.Close()
+
+	cfg := &config.Config{
+		UserAgent:     "TestBot/1.0",
+		Timeout:       5 * time.Second,
+		MaxConcurrent: 1,
+		BaseURL:       site.URL,
+		Headers:       config.ParseHeaders("X-Api-Key: secret"),
+		BasicAuth:     "staging:hunter2",
+	}
+	checker := New(cfg)
+
+	if result := checker.checkSingleLink(site.URL + "/private"); result.ErrorType != "" {
+		t.Errorf("Expected the site to get the credentials, got %d %s", result.StatusCode, result.Error)
+	}
+	if result := checker.checkSingleLink(other.URL + "/"); result.ErrorType != "" {
+		t.Errorf("Expected other hosts not to get the credentials, got %d", result.StatusCode)
+	}
+	if result := checker.checkSingleLink(site.URL + "/away"); result.ErrorType != "" || !strings.HasPrefix(result.FinalURL, other.URL) {
+		t.Errorf("Expected credentials to be dropped when redirected to another host, got %d %s", result.StatusCode, result.FinalURL)
+	}
+}
@@ -0,0 +1,92 @@
+package checker
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+)
+
+// LoadBaseline reads a baseline of known broken links, one URL per line, as
+// written by WriteBaseline
+func LoadBaseline(path string) (map[string]bool, error) {
+	f, err := os.Open(path) // #nosec G304 -- path is provided by the user
+	if err != nil {
+		return nil, fmt.Errorf("opening baseline: %w", err)
+	}
+	defer f.Close()
+
+	urls, err := ReadURLList(f, "")
+	if err != nil {
+		return nil, err
+	}
+	baseline := make(map[string]bool, len(urls))
+	for _, u := range urls {
+		baseline[u] = true
+	}
+	return baseline, nil
+}
+
+// WriteBaseline writes the URLs of broken links to path, sorted and one per
+// line, for later runs to load with LoadBaseline
+func WriteBaseline(path string, results []LinkResult) error {
+	seen := make(map[string]bool)
+	var urls []string
+	for _, result := range results {
+		if !seen[result.URL] {
+			seen[result.URL] = true
+			urls = append(urls, result.URL)
+		}
+	}
+	sort.Strings(urls)
+
+	var b strings.Builder
+	b.WriteString("# Known broken links. Runs with this file as their baseline only fail on\n")
+	b.WriteString("# links that aren't listed here.\n")
+	for _, u := range urls {
+		b.WriteString(u + "\n")
+	}
+	if err := os.WriteFile(path, []byte(b.String()), 0o644); err != nil { // #nosec G306 -- baseline is committed to the repository
+		return fmt.Errorf("writing baseline: %w", err)
+	}
+	return nil
+}
+
+// MarkBaseline marks the broken links whose URL is in the baseline and
+// lowers error findings among them to warnings, so known broken links are
+// still reported without failing the run. It returns how many were marked.
+func MarkBaseline(results []LinkResult, baseline map[string]bool) int {
+	marked := 0
+	for i := range results {
+		if !results[i].ErrorType.IsFailure() || !baseline[results[i].URL] {
+			continue
+		}
+		results[i].Baseline = true
+		if results[i].Severity == SeverityError {
+			results[i].Severity = SeverityWarning
+		}
+		marked++
+	}
+	return marked
+}
+
+// FixedSinceBaseline returns the baseline URLs that were checked and are no
+// longer broken, in the order they were checked, so they can be removed
+// from the baseline
+func FixedSinceBaseline(results []LinkResult, baseline map[string]bool) []string {
+	broken := make(map[string]bool)
+	for _, result := range results {
+		if result.ErrorType.IsFailure() {
+			broken[result.URL] = true
+		}
+	}
+	seen := make(map[string]bool)
+	var fixed []string
+	for _, result := range results {
+		if baseline[result.URL] && !broken[result.URL] && !seen[result.URL] {
+			seen[result.URL] = true
+			fixed = append(fixed, result.URL)
+		}
+	}
+	return fixed
+}
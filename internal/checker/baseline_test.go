@@ -0,0 +1,61 @@
+package checker
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestBaseline(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "baseline")
+	broken := []LinkResult{
+		{URL: "https://example.com/old", StatusCode: 404, ErrorType: ErrorTypeHTTP4xx},
+		{URL: "https://example.com/gone", StatusCode: 410, ErrorType: ErrorTypeHTTP4xx},
+		{URL: "https://example.com/old", StatusCode: 404, ErrorType: ErrorTypeHTTP4xx},
+	}
+	if err := WriteBaseline(path, broken); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasSuffix(string(data), "\nhttps://example.com/gone\nhttps://example.com/old\n") {
+		t.Errorf("Expected sorted, deduplicated URLs, got %q", data)
+	}
+
+	baseline, err := LoadBaseline(path)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !reflect.DeepEqual(baseline, map[string]bool{"https://example.com/old": true, "https://example.com/gone": true}) {
+		t.Errorf("Unexpected baseline %v", baseline)
+	}
+
+	results := []LinkResult{
+		{URL: "https://example.com/old", StatusCode: 404, ErrorType: ErrorTypeHTTP4xx, Severity: SeverityError},
+		{URL: "https://example.com/new", StatusCode: 404, ErrorType: ErrorTypeHTTP4xx, Severity: SeverityError},
+		{URL: "https://example.com/gone", StatusCode: 200},
+	}
+	if marked := MarkBaseline(results, baseline); marked != 1 {
+		t.Errorf("Expected 1 result in the baseline, got %d", marked)
+	}
+	if !results[0].Baseline || results[0].Severity != SeverityWarning {
+		t.Errorf("Expected the known broken link to be a baseline warning, got %+v", results[0])
+	}
+	if results[1].Baseline || results[1].Severity != SeverityError {
+		t.Errorf("Expected the new broken link to stay an error, got %+v", results[1])
+	}
+	if failing := FailingResults(results); len(failing) != 1 || failing[0].URL != "https://example.com/new" {
+		t.Errorf("Expected only the new broken link to fail, got %v", failing)
+	}
+	if fixed := FixedSinceBaseline(results, baseline); !reflect.DeepEqual(fixed, []string{"https://example.com/gone"}) {
+		t.Errorf("Expected the working link to be fixed, got %v", fixed)
+	}
+
+	if _, err := LoadBaseline(filepath.Join(t.TempDir(), "missing")); err == nil {
+		t.Error("Expected an error for a missing baseline")
+	}
+}
@@ -0,0 +1,47 @@
+package checker
+
+import (
+	"fmt"
+	"io"
+)
+
+// maxBodySize returns the most bytes read from a page, sitemap or JSON
+// endpoint body, or -1 when bodies aren't limited
+func (c *Checker) maxBodySize() int64 {
+	if c.config.MaxBodyMB <= 0 {
+		return -1
+	}
+	return int64(c.config.MaxBodyMB) << 20
+}
+
+// limitBody caps a body at the configured size so an endless or enormous
+// response can't exhaust memory. The body is still streamed; exceeded
+// reports afterwards whether anything was left unread past the cap.
+func (c *Checker) limitBody(body io.Reader) (limited io.Reader, exceeded func() bool) {
+	limit := c.maxBodySize()
+	if limit < 0 {
+		return body, func() bool { return false }
+	}
+	return io.LimitReader(body, limit), func() bool {
+		var next [1]byte
+		n, _ := io.ReadFull(body, next[:])
+		return n > 0
+	}
+}
+
+// readBody reads a whole body that is only useful complete, such as a
+// sitemap, failing when it's larger than the configured size
+func (c *Checker) readBody(body io.Reader) ([]byte, error) {
+	limit := c.maxBodySize()
+	if limit < 0 {
+		return io.ReadAll(body)
+	}
+	data, err := io.ReadAll(io.LimitReader(body, limit+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(data)) > limit {
+		return nil, fmt.Errorf("body is larger than the %d MiB max-body-mb limit", c.config.MaxBodyMB)
+	}
+	return data, nil
+}
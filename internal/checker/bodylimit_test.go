@@ -0,0 +1,71 @@
+package checker
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/joshbeard/link-validator/internal/config"
+)
+
+func TestReadBody(t *testing.T) {
+	limited := New(&config.Config{MaxBodyMB: 1})
+	if data, err := limited.readBody(bytes.NewReader(make([]byte, 1<<20))); err != nil || len(data) != 1<<20 {
+		t.Errorf("Expected a body at the limit to be read whole, got %d bytes (%v)", len(data), err)
+	}
+	if _, err := limited.readBody(bytes.NewReader(make([]byte, 1<<20+1))); err == nil || !strings.Contains(err.Error(), "1 MiB max-body-mb") {
+		t.Errorf("Expected an error for a body over the limit, got %v", err)
+	}
+
+	unlimited := New(&config.Config{})
+	if data, err := unlimited.readBody(bytes.NewReader(make([]byte, 2<<20))); err != nil || len(data) != 2<<20 {
+		t.Errorf("Expected no limit without max-body-mb, got %d bytes (%v)", len(data), err)
+	}
+}
+
+func TestCrawlTruncatesLargePages(t *testing.T) {
+	padding := strings.Repeat("<p>filler</p>", (1<<20)/13+1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		switch r.URL.Path {
+		case "/":
+			w.Write([]byte(`<a href="/huge">Huge</a>`))
+		case "/huge":
+			w.Write([]byte(`<a href="/before">Before</a>` + padding + `<a href="/after">After</a>`))
+		}
+	}))
+	defer server.Close()
+
+	checker := New(&config.Config{UserAgent: "TestBot/1.0", Timeout: 5 * time.Second, MaxConcurrent: 1, MaxBodyMB: 1})
+	urls, err := checker.CrawlWebsite(server.URL+"/", 3)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	found := strings.Join(urls, " ")
+	if !strings.Contains(found, "/before") || strings.Contains(found, "/after") {
+		t.Errorf("Expected only the links before the limit to be followed, got %v", urls)
+	}
+	issues := checker.Issues()
+	if len(issues) != 1 || issues[0].Type != IssueTruncatedPage || issues[0].Page != server.URL+"/huge" {
+		t.Errorf("Expected a truncated_page issue for the large page, got %+v", issues)
+	}
+}
+
+func TestSitemapOverBodyLimit(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/xml")
+		w.Write([]byte(`<?xml version="1.0" encoding="UTF-8"?><urlset xmlns="http://www.sitemaps.org/schemas/sitemap/0.9">`))
+		w.Write(bytes.Repeat([]byte("<url><loc>https://example.com/</loc></url>"), (1<<20)/42+1))
+		w.Write([]byte(`</urlset>`))
+	}))
+	defer server.Close()
+
+	checker := New(&config.Config{UserAgent: "TestBot/1.0", Timeout: 5 * time.Second, MaxConcurrent: 1, MaxBodyMB: 1})
+	if _, err := checker.GetURLsFromSitemap(server.URL + "/sitemap.xml"); err == nil || !strings.Contains(err.Error(), "max-body-mb") {
+		t.Errorf("Expected an error for a sitemap over the limit, got %v", err)
+	}
+}
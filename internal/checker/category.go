@@ -0,0 +1,198 @@
+package checker
+
+import (
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+)
+
+// LinkCategory describes what kind of link a result is for, so that failure
+// policies can treat, say, broken links to other sites differently from
+// broken links within the site
+type LinkCategory string
+
+// Link categories recorded in LinkResult.Category. Checked links are
+// internal, external, asset or anchor links; mailto-skipped and excluded
+// links are found while crawling but never checked.
+const (
+	CategoryInternal      LinkCategory = "internal"
+	CategoryExternal      LinkCategory = "external"
+	CategoryAsset         LinkCategory = "asset"
+	CategoryAnchor        LinkCategory = "anchor"
+	CategoryMailtoSkipped LinkCategory = "mailto-skipped"
+	CategoryExcluded      LinkCategory = "excluded"
+)
+
+// LinkCategories lists every link category in the order output is grouped by
+var LinkCategories = []LinkCategory{
+	CategoryInternal,
+	CategoryExternal,
+	CategoryAsset,
+	CategoryAnchor,
+	CategoryMailtoSkipped,
+	CategoryExcluded,
+}
+
+// linkCategory returns the category of a checked link. Links to a host other
+// than the sites' are external, whatever element they were found in; other
+// links found in an element other than <a> are assets, and links to a
+// fragment are anchors.
+func (c *Checker) linkCategory(link, element string) LinkCategory {
+	u, err := url.Parse(link)
+	if err != nil {
+		return CategoryInternal
+	}
+	if hosts := c.siteHosts(); len(hosts) > 0 && !slices.ContainsFunc(hosts, func(host string) bool {
+		return strings.EqualFold(u.Host, host)
+	}) {
+		return CategoryExternal
+	}
+	if element != "" && element != "a" {
+		return CategoryAsset
+	}
+	if u.Fragment != "" || c.linkedByFragment(link) {
+		return CategoryAnchor
+	}
+	return CategoryInternal
+}
+
+// recordAnchor remembers whether a link, as written, pointed at a fragment of
+// its page. Normalizing drops fragments, so without this a page only ever
+// linked to by fragment couldn't be told apart from one linked to directly.
+func (c *Checker) recordAnchor(link, written string) {
+	c.recordFragment(link, hasFragment(written))
+}
+
+// hasFragment reports whether a link, as written, points at a fragment
+func hasFragment(written string) bool {
+	u, err := url.Parse(written)
+	return err == nil && u.Fragment != ""
+}
+
+// recordFragment remembers whether a link pointed at a fragment of its page,
+// keeping it an anchor only while every link to it did
+func (c *Checker) recordFragment(link string, fragment bool) {
+	id, ok := c.urls.id(link)
+	if !ok {
+		return
+	}
+
+	c.inventoryMu.Lock()
+	defer c.inventoryMu.Unlock()
+	if seen, ok := c.anchors[id]; ok {
+		fragment = fragment && seen
+	}
+	c.anchors[id] = fragment
+}
+
+// linkedByFragment reports whether every link found to a URL pointed at a
+// fragment of it, so it is checked as an anchor even once normalized
+func (c *Checker) linkedByFragment(link string) bool {
+	id, ok := c.urls.lookup(link)
+	if !ok {
+		return false
+	}
+
+	c.inventoryMu.Lock()
+	defer c.inventoryMu.Unlock()
+	return c.anchors[id]
+}
+
+// siteHosts returns the hosts of the sites being checked, from the base and
+// sitemap URLs, with the first base URL's first
+func (c *Checker) siteHosts() []string {
+	var hosts []string
+	for _, site := range c.siteURLs() {
+		u, err := url.Parse(c.RewritePreview(site))
+		if err != nil || u.Host == "" || slices.Contains(hosts, u.Host) {
+			continue
+		}
+		hosts = append(hosts, u.Host)
+	}
+	return hosts
+}
+
+// recordSkipped remembers a link found on a crawled page that won't be
+// checked, either for its mailto: scheme or because it is excluded
+func (c *Checker) recordSkipped(link, pageURL string, category LinkCategory) {
+	c.recordSource(link, pageURL)
+	id, ok := c.urls.id(link)
+	if !ok {
+		return
+	}
+
+	c.inventoryMu.Lock()
+	defer c.inventoryMu.Unlock()
+	if _, ok := c.skipped[id]; !ok {
+		c.skipped[id] = category
+		c.skippedOrder = append(c.skippedOrder, id)
+	}
+}
+
+// SkippedLinks returns the mailto: and excluded links found while crawling,
+// in discovery order, as results carrying their category and sources
+func (c *Checker) SkippedLinks() []LinkResult {
+	c.inventoryMu.Lock()
+	order := append([]uint32(nil), c.skippedOrder...)
+	categories := make([]LinkCategory, len(order))
+	for i, id := range order {
+		categories[i] = c.skipped[id]
+	}
+	c.inventoryMu.Unlock()
+
+	results := make([]LinkResult, 0, len(order))
+	for i, id := range order {
+		link := c.urls.url(id)
+		sources := c.Sources(link)
+		results = append(results, LinkResult{
+			URL:         link,
+			Category:    categories[i],
+			Sources:     sources,
+			SourceCount: len(sources),
+		})
+	}
+	return results
+}
+
+// CountByCategory returns how many results there are in each category
+func CountByCategory(results []LinkResult) map[LinkCategory]int {
+	counts := make(map[LinkCategory]int)
+	for _, result := range results {
+		if result.Category != "" {
+			counts[result.Category]++
+		}
+	}
+	return counts
+}
+
+// GroupByCategory splits results by category, in LinkCategories order, with
+// any results without a category last
+func GroupByCategory(results []LinkResult) [][]LinkResult {
+	categories := append(append([]LinkCategory(nil), LinkCategories...), "")
+	var groups [][]LinkResult
+	for _, category := range categories {
+		var group []LinkResult
+		for _, result := range results {
+			if result.Category == category {
+				group = append(group, result)
+			}
+		}
+		if len(group) > 0 {
+			groups = append(groups, group)
+		}
+	}
+	return groups
+}
+
+// FormatCategoryCounts formats counts as "internal: 3, external: 1" in
+// LinkCategories order, leaving out empty categories
+func FormatCategoryCounts(counts map[LinkCategory]int) string {
+	var parts []string
+	for _, category := range LinkCategories {
+		if counts[category] > 0 {
+			parts = append(parts, fmt.Sprintf("%s: %d", category, counts[category]))
+		}
+	}
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,171 @@
+package checker
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/joshbeard/link-validator/internal/config"
+)
+
+func TestLinkCategory(t *testing.T) {
+	checker := New(&config.Config{BaseURL: "https://example.com/"})
+
+	tests := []struct {
+		link     string
+		element  string
+		expected LinkCategory
+	}{
+		{"https://example.com/docs/", "a", CategoryInternal},
+		{"https://example.com/docs/#install", "a", CategoryAnchor},
+		{"https://example.com/logo.png", "img", CategoryAsset},
+		{"https://cdn.example.net/app.js", "script", CategoryExternal},
+		{"https://other.example.org/#top", "a", CategoryExternal},
+		{"https://example.com/unknown", "", CategoryInternal},
+	}
+	for _, test := range tests {
+		if got := checker.linkCategory(test.link, test.element); got != test.expected {
+			t.Errorf("linkCategory(%q, %q) = %q, expected %q", test.link, test.element, got, test.expected)
+		}
+	}
+
+	sites := New(&config.Config{
+		BaseURL:  "https://example.com/",
+		BaseURLs: []string{"https://example.com/", "https://docs.example.com/"},
+	})
+	if got := sites.linkCategory("https://docs.example.com/install/", "a"); got != CategoryInternal {
+		t.Errorf("Expected links to any of the sites to be internal, got %q", got)
+	}
+	if got := sites.linkCategory("https://other.example.org/", "a"); got != CategoryExternal {
+		t.Errorf("Expected links to other hosts to be external, got %q", got)
+	}
+
+	noSite := New(&config.Config{})
+	if got := noSite.linkCategory("https://anywhere.example/", "a"); got != CategoryInternal {
+		t.Errorf("Expected links to be internal without a site, got %q", got)
+	}
+}
+
+func TestCheckLinksCategory(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	checker := New(&config.Config{UserAgent: "TestBot/1.0", Timeout: 5 * time.Second, MaxConcurrent: 1, BaseURL: server.URL})
+	results := checker.CheckLinks([]string{server.URL + "/page"})
+	if len(results) != 1 || results[0].Category != CategoryInternal {
+		t.Errorf("Expected one internal result, got %+v", results)
+	}
+}
+
+func TestSkippedLinks(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		if r.URL.Path == "/" {
+			fmt.Fprint(w, `<a href="mailto:hello@example.com">Mail</a>
+<a href="/archive/old">Old</a>
+<a href="/docs/">Docs</a>
+<a href=" mailto:hello@example.com ">Mail again</a>`)
+		}
+	}))
+	defer server.Close()
+
+	checker := New(&config.Config{
+		UserAgent:       "TestBot/1.0",
+		Timeout:         5 * time.Second,
+		MaxConcurrent:   1,
+		ExcludePatterns: config.ParsePatterns("/archive/"),
+	})
+	if _, err := checker.CrawlWebsite(server.URL+"/", 2); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	skipped := checker.SkippedLinks()
+	if len(skipped) != 2 {
+		t.Fatalf("Expected 2 skipped links, got %+v", skipped)
+	}
+	if skipped[0].URL != "mailto:hello@example.com" || skipped[0].Category != CategoryMailtoSkipped {
+		t.Errorf("Expected the mailto link to be skipped, got %+v", skipped[0])
+	}
+	if skipped[1].URL != server.URL+"/archive/old" || skipped[1].Category != CategoryExcluded {
+		t.Errorf("Expected the excluded link to be skipped, got %+v", skipped[1])
+	}
+	if !reflect.DeepEqual(skipped[1].Sources, []string{server.URL + "/"}) {
+		t.Errorf("Expected the page the excluded link was found on, got %v", skipped[1].Sources)
+	}
+}
+
+func TestGroupByCategory(t *testing.T) {
+	results := []LinkResult{
+		{URL: "a", Category: CategoryExternal},
+		{URL: "b", Category: CategoryInternal},
+		{URL: "c"},
+		{URL: "d", Category: CategoryExternal},
+	}
+
+	var got [][]string
+	for _, group := range GroupByCategory(results) {
+		var urls []string
+		for _, result := range group {
+			urls = append(urls, result.URL)
+		}
+		got = append(got, urls)
+	}
+	expected := [][]string{{"b"}, {"a", "d"}, {"c"}}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("Expected %v, got %v", expected, got)
+	}
+
+	counts := CountByCategory(results)
+	if got := FormatCategoryCounts(counts); got != "internal: 1, external: 2" {
+		t.Errorf("Expected formatted counts, got %q", got)
+	}
+}
+
+func TestResultSeverityByLinkCategory(t *testing.T) {
+	rules := config.ParseSeverityRules("external=warning")
+	internal := LinkResult{StatusCode: 404, ErrorType: ErrorTypeHTTP4xx, Category: CategoryInternal}
+	external := LinkResult{StatusCode: 404, ErrorType: ErrorTypeHTTP4xx, Category: CategoryExternal}
+
+	if got := ResultSeverity(internal, rules, nil); got != SeverityError {
+		t.Errorf("Expected internal broken links to be errors, got %s", got)
+	}
+	if got := ResultSeverity(external, rules, nil); got != SeverityWarning {
+		t.Errorf("Expected external broken links to be warnings, got %s", got)
+	}
+}
+
+func TestNormalizedAnchorCategory(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		if r.URL.Path == "/" {
+			w.Write([]byte(`<a href="/docs/#install">Install</a>
+<a href="/faq/#billing">Billing</a>
+<a href="/faq/">FAQ</a>`))
+		}
+	}))
+	defer server.Close()
+
+	checker := New(&config.Config{
+		BaseURL:       server.URL,
+		UserAgent:     "TestBot/1.0",
+		Timeout:       5 * time.Second,
+		MaxConcurrent: 1,
+		NormalizeURLs: true,
+	})
+	if _, err := checker.CrawlWebsite(server.URL+"/", 1); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	results := checker.CheckLinks([]string{server.URL + "/docs/"})
+	if len(results) != 1 || results[0].Category != CategoryAnchor {
+		t.Errorf("Expected a page only linked to by fragment to be an anchor, got %+v", results)
+	}
+	if got := checker.linkCategory(server.URL+"/faq/", "a"); got != CategoryInternal {
+		t.Errorf("Expected a page also linked to directly to be internal, got %q", got)
+	}
+}
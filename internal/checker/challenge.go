@@ -0,0 +1,75 @@
+package checker
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// maxChallengeBodySize limits how much of a response body is inspected for
+// challenge page markers
+const maxChallengeBodySize = 64 << 10
+
+// challengeMarkers are fragments of the interstitial pages served by
+// Cloudflare and similar bot protection while a JavaScript challenge runs
+var challengeMarkers = [][]byte{
+	[]byte("<title>Just a moment...</title>"),
+	[]byte("<title>Just a moment…</title>"),
+	[]byte("<title>Attention Required! | Cloudflare</title>"),
+	[]byte("/cdn-cgi/challenge-platform/"),
+	[]byte("cf-browser-verification"),
+	[]byte("Checking your browser before accessing"),
+}
+
+// isBotChallenge reports whether a 403 or 503 response is a bot protection
+// challenge rather than a real error. HEAD responses carry no body, so a
+// Cloudflare-served page is fetched again with GET when its headers alone are
+// not conclusive.
+func isBotChallenge(client *http.Client, req *http.Request, resp *http.Response) bool {
+	if resp.StatusCode != http.StatusForbidden && resp.StatusCode != http.StatusServiceUnavailable {
+		return false
+	}
+	if isChallengeHeader(resp.Header) {
+		return true
+	}
+
+	if req.Method == http.MethodHead {
+		if !isCloudflare(resp.Header) {
+			return false
+		}
+		getReq := req.Clone(req.Context())
+		getReq.Method = http.MethodGet
+		getResp, err := client.Do(getReq)
+		if err != nil {
+			return false
+		}
+		defer getResp.Body.Close()
+		if isChallengeHeader(getResp.Header) {
+			return true
+		}
+		resp = getResp
+	}
+
+	body, err := decodeBody(resp)
+	if err != nil {
+		return false
+	}
+	snippet, _ := io.ReadAll(io.LimitReader(body, maxChallengeBodySize))
+	for _, marker := range challengeMarkers {
+		if bytes.Contains(snippet, marker) {
+			return true
+		}
+	}
+	return false
+}
+
+// isChallengeHeader reports whether response headers mark a challenge page
+func isChallengeHeader(header http.Header) bool {
+	return strings.EqualFold(header.Get("Cf-Mitigated"), "challenge")
+}
+
+// isCloudflare reports whether a response was served through Cloudflare
+func isCloudflare(header http.Header) bool {
+	return header.Get("Cf-Ray") != "" || strings.EqualFold(header.Get("Server"), "cloudflare")
+}
@@ -0,0 +1,65 @@
+package checker
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/joshbeard/link-validator/internal/config"
+)
+
+func TestCheckSingleLinkBotChallenge(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/mitigated":
+			w.Header().Set("Cf-Mitigated", "challenge")
+			w.WriteHeader(http.StatusForbidden)
+		case "/interstitial":
+			w.Header().Set("Server", "cloudflare")
+			w.Header().Set("Content-Type", "text/html")
+			w.WriteHeader(http.StatusServiceUnavailable)
+			if r.Method == http.MethodGet {
+				w.Write([]byte(`<html><head><title>Just a moment...</title></head><body></body></html>`))
+			}
+		case "/forbidden":
+			w.Header().Set("Server", "cloudflare")
+			w.WriteHeader(http.StatusForbidden)
+			w.Write([]byte(`<html><head><title>Forbidden</title></head></html>`))
+		case "/missing":
+			w.Header().Set("Cf-Mitigated", "challenge")
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	checker := New(&config.Config{UserAgent: "TestBot/1.0", Timeout: 5 * time.Second, MaxConcurrent: 1})
+
+	testCases := []struct {
+		path     string
+		expected ErrorType
+	}{
+		{"/mitigated", ErrorTypeBotChallenge},
+		{"/interstitial", ErrorTypeBotChallenge},
+		{"/forbidden", ErrorTypeHTTP4xx},
+		{"/missing", ErrorTypeHTTP4xx},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.path, func(t *testing.T) {
+			result := checker.checkSingleLink(server.URL + tc.path)
+			if result.ErrorType != tc.expected {
+				t.Errorf("Expected %q, got %q (%s)", tc.expected, result.ErrorType, result.Error)
+			}
+		})
+	}
+}
+
+func TestBotChallengeIsNotFailure(t *testing.T) {
+	if ErrorTypeBotChallenge.IsFailure() {
+		t.Errorf("Expected %q not to be a failure", ErrorTypeBotChallenge)
+	}
+	if shouldRetry(LinkResult{StatusCode: 503, ErrorType: ErrorTypeBotChallenge}) {
+		t.Error("Expected bot challenges not to be retried")
+	}
+}
@@ -1,67 +1,245 @@
 package checker
 
 import (
+	"bytes"
 	"context"
+	"crypto/tls"
 	"encoding/xml"
+	"errors"
 	"fmt"
-	"io"
+	"iter"
+	"maps"
+	"net"
 	"net/http"
 	"net/url"
+	"slices"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
+	"github.com/joshbeard/link-validator/internal/cache"
 	"github.com/joshbeard/link-validator/internal/config"
 	"golang.org/x/net/html"
 	"golang.org/x/time/rate"
 )
 
+// ErrSitemapIsHTML is returned when the sitemap URL serves an HTML page
+// (such as a CMS's human-readable sitemap) instead of an XML sitemap
+var ErrSitemapIsHTML = errors.New("sitemap URL returned an HTML page rather than an XML sitemap")
+
 // LinkResult represents the result of checking a single link
 type LinkResult struct {
-	URL        string `json:"url"`
-	StatusCode int    `json:"status_code"`
-	Error      string `json:"error,omitempty"`
-	Duration   string `json:"duration"`
+	URL         string    `json:"url"`
+	StatusCode  int       `json:"status_code"`
+	Error       string    `json:"error,omitempty"`
+	ErrorType   ErrorType `json:"error_type,omitempty"`
+	ErrorDetail string    `json:"error_detail,omitempty"`
+	Duration    string    `json:"duration"`
+	DurationMS  int64     `json:"duration_ms"`
+	Slow        bool      `json:"slow,omitempty"`
+	Retries     int       `json:"retries,omitempty"`
+	Accepted    bool      `json:"accepted,omitempty"`
+	Sources     []string  `json:"sources,omitempty"`
+	SourceCount int       `json:"source_count,omitempty"`
+	SourceFiles []string  `json:"source_files,omitempty"`
+	Unchanged   bool      `json:"unchanged,omitempty"`
+	Baseline    bool      `json:"baseline,omitempty"`
+
+	FinalURL       string        `json:"final_url,omitempty"`
+	RedirectStatus int           `json:"redirect_status,omitempty"`
+	RedirectChain  []RedirectHop `json:"redirect_chain,omitempty"`
+	Locale         string        `json:"locale,omitempty"`
+	CheckedAt      string        `json:"checked_at,omitempty"`
+
+	ContentEncoding string `json:"content_encoding,omitempty"`
+	TransferSize    int64  `json:"transfer_size,omitempty"`
+
+	Severity Severity `json:"severity,omitempty"`
+	External bool     `json:"external,omitempty"`
+	Element  string   `json:"element,omitempty"`
+
+	Category LinkCategory `json:"category,omitempty"`
+
+	// retryLater is set when the response asked to be retried later with a
+	// Retry-After the checker honors
+	retryLater bool
+	// elapsed is how long the check took, which Duration and DurationMS
+	// are formatted from
+	elapsed time.Duration
+}
+
+// Elapsed returns how long a result's check took. Results read back from a
+// ResultStore's file only keep it to the millisecond, as DurationMS.
+func (r LinkResult) Elapsed() time.Duration {
+	if r.elapsed > 0 {
+		return r.elapsed
+	}
+	return time.Duration(r.DurationMS) * time.Millisecond
 }
 
 // Checker handles link checking operations
 type Checker struct {
-	config  *config.Config
-	client  *http.Client
-	limiter *rate.Limiter
+	config     *config.Config
+	client     *http.Client
+	limiter    *rate.Limiter
+	throttle   *hostThrottle
+	retries    *retryBudget
+	retryDelay time.Duration
+	urls       *urlTable
+	sources    map[uint32][]uint32
+	sourceSet  map[uint64]struct{}
+	sourcesMu  sync.Mutex
+	known      urlSet
+	knownOrder []uint32
+	cache      *cache.Cache
+	cacheHits  atomic.Int64
+	lastmod    map[uint32]time.Time
+	priority   map[uint32]float64
+	insecure   map[uint32]uint32
+
+	external        map[uint32]bool
+	externalOrder   []uint32
+	externalLimiter *rate.Limiter
+
+	robots        *robotsRules
+	robotsHost    string
+	robotsSeen    map[uint32]bool
+	robotsSkipped []uint32
+
+	elements   map[uint32]string
+	anchors    map[uint32]bool
+	files      map[string]func() string
+	assets     map[uint32]bool
+	assetOrder []uint32
+
+	skipped      map[uint32]LinkCategory
+	skippedOrder []uint32
+
+	decisions map[string]hookDecision
+	hookMu    sync.Mutex
+
+	previewFrom *url.URL
+	previewTo   *url.URL
+
+	issues   []PageIssue
+	issuesMu sync.Mutex
+
+	resolver      mailResolver
+	mailDomains   map[string]string
+	mailDomainsMu sync.Mutex
+
+	stream   *resultStream
+	progress *progressReporter
+
+	agentIndex atomic.Uint64
+
+	inventory      map[uint32]inventoryRecord
+	inventoryOrder []uint32
+	mediaTypes     []string
+	inventoryMu    sync.Mutex
 }
 
 // Sitemap represents the XML structure of a sitemap
 type Sitemap struct {
-	XMLName xml.Name `xml:"urlset"`
-	URLs    []struct {
+	XMLName xml.Name       `xml:"urlset"`
+	URLs    []SitemapEntry `xml:"url"`
+}
+
+// SitemapIndex represents the XML structure of a sitemap index, which lists
+// other sitemaps rather than pages
+type SitemapIndex struct {
+	XMLName  xml.Name `xml:"sitemapindex"`
+	Sitemaps []struct {
 		Loc string `xml:"loc"`
-	} `xml:"url"`
+	} `xml:"sitemap"`
+}
+
+// maxSitemapIndexDepth limits how deeply sitemap indexes may be nested
+const maxSitemapIndexDepth = 2
+
+// SitemapEntry represents a single <url> entry in a sitemap
+type SitemapEntry struct {
+	Loc        string             `xml:"loc"`
+	LastMod    string             `xml:"lastmod"`
+	Priority   string             `xml:"priority"`
+	Alternates []SitemapAlternate `xml:"http://www.w3.org/1999/xhtml link"`
+	News       *SitemapNews       `xml:"http://www.google.com/schemas/sitemap-news/0.9 news"`
+}
+
+// SitemapAlternate represents an <xhtml:link> alternate declared for a
+// sitemap entry, typically another language version of the page
+type SitemapAlternate struct {
+	Rel      string `xml:"rel,attr"`
+	Hreflang string `xml:"hreflang,attr"`
+	Href     string `xml:"href,attr"`
 }
 
 // New creates a new Checker instance
 func New(cfg *config.Config) *Checker {
 	client := &http.Client{
-		Timeout: cfg.Timeout,
+		Timeout:       cfg.Timeout,
+		CheckRedirect: limitRedirects(cfg.MaxRedirects, cfg.Headers),
+	}
+	if cfg.InsecureSkipVerify {
+		client.Transport = tlsTransport(&tls.Config{InsecureSkipVerify: true}) // #nosec G402 -- explicitly requested with insecure-skip-verify
 	}
 
 	// Rate limiter to be respectful
 	limiter := rate.NewLimiter(rate.Limit(cfg.MaxConcurrent), cfg.MaxConcurrent)
 
 	return &Checker{
-		config:  cfg,
-		client:  client,
-		limiter: limiter,
+		config:     cfg,
+		client:     client,
+		limiter:    limiter,
+		throttle:   newHostThrottle(),
+		retries:    &retryBudget{limit: int64(cfg.RetryBudget)},
+		retryDelay: time.Second,
+		urls:       newURLTable(cfg.FrontierSpill),
+		sources:    make(map[uint32][]uint32),
+		sourceSet:  make(map[uint64]struct{}),
+		known:      make(urlSet),
+		inventory:  make(map[uint32]inventoryRecord),
+		lastmod:    make(map[uint32]time.Time),
+		priority:   make(map[uint32]float64),
+		insecure:   make(map[uint32]uint32),
+		external:   make(map[uint32]bool),
+		robotsSeen: make(map[uint32]bool),
+		elements:   make(map[uint32]string),
+		anchors:    make(map[uint32]bool),
+		files:      make(map[string]func() string),
+		assets:     make(map[uint32]bool),
+		skipped:    make(map[uint32]LinkCategory),
+		decisions:  make(map[string]hookDecision),
+
+		resolver:    net.DefaultResolver,
+		mailDomains: make(map[string]string),
 	}
 }
 
-// GetURLsFromSitemap fetches and parses a sitemap to extract URLs
+// Close removes the temporary file the URLs behind the checker's sources and
+// inventory spilled to on a huge run, if any. The sources and inventory
+// can't be read after it.
+func (c *Checker) Close() {
+	c.urls.close()
+}
+
+// GetURLsFromSitemap fetches and parses a sitemap to extract URLs. Sitemap
+// indexes are followed to the sitemaps they list.
 func (c *Checker) GetURLsFromSitemap(sitemapURL string) ([]string, error) {
+	return c.getURLsFromSitemap(c.RewritePreview(sitemapURL), 0)
+}
+
+// getURLsFromSitemap fetches a sitemap or sitemap index at the given index
+// nesting depth
+func (c *Checker) getURLsFromSitemap(sitemapURL string, indexDepth int) ([]string, error) {
 	req, err := http.NewRequest("GET", sitemapURL, nil)
 	if err != nil {
 		return nil, fmt.Errorf("creating request: %w", err)
 	}
-	req.Header.Set("User-Agent", c.config.UserAgent)
+	req.Header.Set("User-Agent", c.userAgentFor(sitemapURL))
+	req.Header.Set("Accept-Encoding", acceptEncoding)
+	c.authorize(req)
 
 	resp, err := c.client.Do(req)
 	if err != nil {
@@ -73,10 +251,26 @@ func (c *Checker) GetURLsFromSitemap(sitemapURL string) ([]string, error) {
 		return nil, fmt.Errorf("sitemap returned status %d", resp.StatusCode)
 	}
 
-	body, err := io.ReadAll(resp.Body)
+	decoded, err := decodeBody(resp)
 	if err != nil {
 		return nil, fmt.Errorf("reading sitemap: %w", err)
 	}
+	// Large sites often publish their sitemaps as .xml.gz or .xml.br files
+	if decoded, err = decompressFile(resp, decoded); err != nil {
+		return nil, fmt.Errorf("reading sitemap: %w", err)
+	}
+	body, err := c.readBody(decoded)
+	if err != nil {
+		return nil, fmt.Errorf("reading sitemap: %w", err)
+	}
+
+	if isHTMLDocument(resp.Header.Get("Content-Type"), body) {
+		return nil, fmt.Errorf("%w: %s", ErrSitemapIsHTML, sitemapURL)
+	}
+
+	if isSitemapIndex(body) {
+		return c.getURLsFromSitemapIndex(body, indexDepth)
+	}
 
 	var sitemap Sitemap
 	if err := xml.Unmarshal(body, &sitemap); err != nil {
@@ -84,47 +278,203 @@ func (c *Checker) GetURLsFromSitemap(sitemapURL string) ([]string, error) {
 	}
 
 	urls := make([]string, 0, len(sitemap.URLs))
+	seen := make(map[string]bool, len(sitemap.URLs))
+	staleNews := 0
 	for _, urlEntry := range sitemap.URLs {
-		if !c.shouldExclude(urlEntry.Loc) {
+		urlEntry.Loc = c.normalizeURL(c.RewritePreview(urlEntry.Loc))
+		if !c.isFreshNews(urlEntry.News) {
+			staleNews++
+			continue
+		}
+		if !seen[urlEntry.Loc] && !c.shouldExclude(urlEntry.Loc) {
 			urls = append(urls, urlEntry.Loc)
+			seen[urlEntry.Loc] = true
+			c.recordDiscovery(urlEntry.Loc, 0, sitemapURL)
+			c.recordLastMod(urlEntry.Loc, urlEntry.LastMod)
+			c.recordPriority(urlEntry.Loc, urlEntry.Priority)
+		}
+
+		// Multilingual sitemaps declare the other language versions of a
+		// page as xhtml:link alternates, so check those too
+		for _, alternate := range urlEntry.Alternates {
+			alternate.Href = c.normalizeURL(c.RewritePreview(alternate.Href))
+			if alternate.Rel != "alternate" || alternate.Href == "" || c.shouldExclude(alternate.Href) {
+				continue
+			}
+			c.recordSource(alternate.Href, urlEntry.Loc)
+			if !seen[alternate.Href] {
+				seen[alternate.Href] = true
+				urls = append(urls, alternate.Href)
+				c.recordDiscovery(alternate.Href, 0, urlEntry.Loc)
+			}
 		}
 	}
 
+	if staleNews > 0 && c.config.Verbose {
+		fmt.Printf("Skipped %d news articles older than %s\n", staleNews, c.config.NewsMaxAge)
+	}
+
 	return urls, nil
 }
 
+// getURLsFromSitemapIndex fetches every sitemap listed in a sitemap index
+// and merges their URLs. Child sitemaps that fail are reported and skipped.
+func (c *Checker) getURLsFromSitemapIndex(body []byte, indexDepth int) ([]string, error) {
+	if indexDepth >= maxSitemapIndexDepth {
+		return nil, fmt.Errorf("sitemap indexes nested more than %d levels deep", maxSitemapIndexDepth)
+	}
+
+	var index SitemapIndex
+	if err := xml.Unmarshal(body, &index); err != nil {
+		return nil, fmt.Errorf("parsing sitemap index XML: %w", err)
+	}
+
+	var urls []string
+	seen := make(map[string]bool)
+	for _, child := range index.Sitemaps {
+		childURL := c.RewritePreview(strings.TrimSpace(child.Loc))
+		if childURL == "" {
+			continue
+		}
+		if c.config.Verbose {
+			fmt.Printf("Fetching sitemap from index: %s\n", childURL)
+		}
+
+		childURLs, err := c.getURLsFromSitemap(childURL, indexDepth+1)
+		if err != nil {
+			fmt.Printf("Warning: skipping sitemap %s: %v\n", childURL, err)
+			continue
+		}
+		for _, u := range childURLs {
+			if !seen[u] {
+				seen[u] = true
+				urls = append(urls, u)
+			}
+		}
+	}
+
+	return urls, nil
+}
+
+// isSitemapIndex reports whether an XML document's root element is a
+// <sitemapindex>
+func isSitemapIndex(body []byte) bool {
+	decoder := xml.NewDecoder(bytes.NewReader(body))
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			return false
+		}
+		if start, ok := token.(xml.StartElement); ok {
+			return start.Name.Local == "sitemapindex"
+		}
+	}
+}
+
+// isHTMLDocument reports whether a response is an HTML page, based on its
+// Content-Type header or, failing that, by sniffing the body
+func isHTMLDocument(contentType string, body []byte) bool {
+	mimeType := strings.TrimSpace(strings.ToLower(strings.Split(contentType, ";")[0]))
+	if mimeType == "text/html" {
+		return true
+	}
+	return strings.HasPrefix(http.DetectContentType(body), "text/html")
+}
+
 // CrawlWebsite crawls a website starting from baseURL up to maxDepth
 func (c *Checker) CrawlWebsite(baseURL string, maxDepth int) ([]string, error) {
-	visited := make(map[string]bool)
-	var urls []string
-	var mu sync.Mutex
+	return c.CrawlWebsiteWithSeeds(baseURL, nil, maxDepth)
+}
+
+// CrawlWebsiteWithSeeds crawls a website starting from baseURL and any
+// additional seed URLs on the same host, each treated as a depth 0 entry point
+func (c *Checker) CrawlWebsiteWithSeeds(baseURL string, seeds []string, maxDepth int) ([]string, error) {
+	return c.CrawlPages(baseURL, append([]string{baseURL}, seeds...), maxDepth)
+}
+
+// CrawlWebsiteWithSeedsTo crawls as CrawlWebsiteWithSeeds does, adding the
+// URLs it finds to list rather than returning them all at once
+func (c *Checker) CrawlWebsiteWithSeedsTo(list *URLList, baseURL string, seeds []string, maxDepth int) error {
+	return c.CrawlPagesTo(list, baseURL, append([]string{baseURL}, seeds...), maxDepth)
+}
 
-	baseURLParsed, err := url.Parse(baseURL)
+// CrawlPages crawls from the given entry points only, following links on
+// the host of baseURL. Entry points on other hosts, matching an exclude
+// pattern or disallowed by a loaded robots.txt are ignored. Entry points are
+// crawled, though not returned, even when they don't match the include
+// patterns, so a run scoped to part of a site can start from its home page.
+func (c *Checker) CrawlPages(baseURL string, entryPoints []string, maxDepth int) ([]string, error) {
+	list := NewURLList(0)
+	if err := c.CrawlPagesTo(list, baseURL, entryPoints, maxDepth); err != nil {
+		return nil, err
+	}
+	return slices.Collect(list.All()), nil
+}
+
+// CrawlPagesTo crawls as CrawlPages does, adding the URLs it finds to list
+// as it finds them. A list that spills to disk keeps the memory a crawl of a
+// huge site takes from growing with every URL found.
+func (c *Checker) CrawlPagesTo(list *URLList, baseURL string, entryPoints []string, maxDepth int) error {
+	// URLs known from a previous run are reported without being fetched
+	// again, so only entry points and newly discovered pages are crawled
+	for _, id := range c.knownOrder {
+		if knownURL := c.urls.url(id); !c.shouldExclude(knownURL) {
+			if err := list.Add(knownURL); err != nil {
+				return err
+			}
+		}
+	}
+
+	baseURLParsed, err := url.Parse(c.normalizeURL(c.RewritePreview(baseURL)))
 	if err != nil {
-		return nil, fmt.Errorf("parsing base URL: %w", err)
+		return fmt.Errorf("parsing base URL: %w", err)
 	}
 
-	var crawl func(string, int)
-	crawl = func(currentURL string, depth int) {
-		if depth > maxDepth {
-			return
+	// Pages are crawled breadth first, so each is reached by its shortest
+	// path and a page budget covers the shallowest pages. Past FrontierSpill
+	// pages, the queue waits in a temporary file.
+	queue := newCrawlFrontier(c.config.FrontierSpill)
+	defer queue.close()
+	queued := make(urlSet)
+	for _, entryPoint := range entryPoints {
+		entryPoint = c.normalizeURL(c.RewritePreview(entryPoint))
+		entryURL, err := url.Parse(entryPoint)
+		if err != nil || entryURL.Host != baseURLParsed.Host || c.matchesExclude(entryPoint) || c.disallowedByRobots(entryPoint) || queued.has(entryPoint) {
+			continue
 		}
+		queued.add(entryPoint)
+		if err := queue.push(crawlItem{URL: entryPoint}); err != nil {
+			return err
+		}
+	}
 
-		mu.Lock()
-		if visited[currentURL] {
-			mu.Unlock()
-			return
+	fetched := 0
+	for {
+		item, ok, err := queue.pop()
+		if err != nil {
+			return err
+		}
+		if !ok {
+			break
 		}
-		visited[currentURL] = true
-		urls = append(urls, currentURL)
+		currentURL, depth := item.URL, item.Depth
+
+		if !c.known.has(currentURL) && c.isIncluded(currentURL) {
+			if err := list.Add(currentURL); err != nil {
+				return err
+			}
+		}
+		c.recordDiscovery(currentURL, depth, item.Source)
 		if c.config.Verbose {
 			fmt.Printf("Crawling [depth %d]: %s\n", depth, currentURL)
 		}
-		mu.Unlock()
 
-		if depth == maxDepth {
-			return
+		// Pages past the depth limit or page budget are checked without
+		// following their links
+		if depth >= maxDepth || (c.config.MaxPages > 0 && fetched >= c.config.MaxPages) {
+			continue
 		}
+		fetched++
 
 		// Parse the current URL to use as base for relative link resolution
 		currentURLParsed, err := url.Parse(currentURL)
@@ -132,7 +482,7 @@ func (c *Checker) CrawlWebsite(baseURL string, maxDepth int) ([]string, error) {
 			if c.config.Verbose {
 				fmt.Printf("Error parsing current URL %s: %v\n", currentURL, err)
 			}
-			return
+			continue
 		}
 
 		links, err := c.extractLinksFromPage(currentURL, currentURLParsed, baseURLParsed)
@@ -140,7 +490,7 @@ func (c *Checker) CrawlWebsite(baseURL string, maxDepth int) ([]string, error) {
 			if c.config.Verbose {
 				fmt.Printf("Error extracting links from %s: %v\n", currentURL, err)
 			}
-			return
+			continue
 		}
 
 		if c.config.Verbose && len(links) > 0 {
@@ -148,14 +498,35 @@ func (c *Checker) CrawlWebsite(baseURL string, maxDepth int) ([]string, error) {
 		}
 
 		for _, link := range links {
-			if !visited[link] && !c.shouldExclude(link) {
-				crawl(link, depth+1)
+			if c.shouldExclude(link) || c.disallowedByRobots(link) {
+				continue
+			}
+			c.recordSource(link, currentURL)
+			if c.known.has(link) || queued.has(link) {
+				c.recordDiscovery(link, depth+1, currentURL)
+			} else {
+				queued.add(link)
+				if err := queue.push(crawlItem{URL: link, Source: currentURL, Depth: depth + 1}); err != nil {
+					return err
+				}
 			}
 		}
 	}
 
-	crawl(baseURL, 0)
-	return urls, nil
+	// Resources such as images are checked but never crawled
+	c.inventoryMu.Lock()
+	assets := append([]uint32(nil), c.assetOrder...)
+	c.inventoryMu.Unlock()
+	for _, id := range assets {
+		asset := c.urls.url(id)
+		if !queued.has(asset) && !c.known.has(asset) {
+			queued.add(asset)
+			if err := list.Add(asset); err != nil {
+				return err
+			}
+		}
+	}
+	return c.urls.firstErr()
 }
 
 // extractLinksFromPage extracts all links from a web page
@@ -164,22 +535,56 @@ func (c *Checker) extractLinksFromPage(pageURL string, currentURL *url.URL, base
 	if err != nil {
 		return nil, err
 	}
-	req.Header.Set("User-Agent", c.config.UserAgent)
+	req.Header.Set("User-Agent", c.userAgentFor(pageURL))
+	req.Header.Set("Accept-Encoding", acceptEncoding)
+	if language := c.acceptLanguageFor(pageURL, ""); language != "" {
+		req.Header.Set("Accept-Language", language)
+	}
+	c.authorize(req)
 
-	resp, err := c.client.Do(req)
+	cached, hasCached := c.cachedPage(pageURL)
+	if hasCached {
+		setConditionalHeaders(req, cached)
+	}
+
+	c.throttle.wait(req.URL.Host)
+	resp, err := c.clientFor(pageURL).Do(req)
 	if err != nil {
 		return nil, err
 	}
 	defer resp.Body.Close()
+	c.throttle.update(req.URL.Host, resp.Header, time.Now())
+
+	if resp.StatusCode == http.StatusNotModified && hasCached {
+		c.cacheHits.Add(1)
+		c.recordContentType(pageURL, cached.ContentType)
+		c.replayPage(pageURL, cached)
+		return cached.Links, nil
+	}
+	c.recordContentType(pageURL, resp.Header.Get("Content-Type"))
 
 	if resp.StatusCode != http.StatusOK {
 		return nil, fmt.Errorf("page returned status %d", resp.StatusCode)
 	}
 
-	doc, err := html.Parse(resp.Body)
+	decoded, err := decodeBody(resp)
 	if err != nil {
 		return nil, err
 	}
+	// Oversized pages are parsed up to the limit, so the links before it
+	// are still followed
+	body, exceeded := c.limitBody(decoded)
+	doc, err := html.Parse(body)
+	if err != nil {
+		return nil, err
+	}
+	if exceeded() {
+		c.recordIssue(PageIssue{
+			Page:   pageURL,
+			Type:   IssueTruncatedPage,
+			Detail: fmt.Sprintf("page is larger than the %d MiB max-body-mb limit; links past it were not followed", c.config.MaxBodyMB),
+		})
+	}
 
 	// Look for <base> tag to determine the correct base URL for this page
 	resolveBaseURL := currentURL
@@ -215,22 +620,62 @@ func (c *Checker) extractLinksFromPage(pageURL string, currentURL *url.URL, base
 		}
 	}
 
-	var links []string
+	c.auditPage(pageURL, doc, resolveBaseURL)
+
+	var links, assets, external []string
+	elements := make(map[string]string)
+	fragments := make(map[string]bool)
 	var extract func(*html.Node)
 	extract = func(n *html.Node) {
-		if n.Type == html.ElementNode && n.Data == "a" {
-			for _, attr := range n.Attr {
-				if attr.Key == "href" {
-					link := attr.Val
-					if absoluteURL := c.resolveURL(link, resolveBaseURL); absoluteURL != "" {
-						// Only include links from the same domain
-						if linkURL, err := url.Parse(absoluteURL); err == nil {
-							if linkURL.Host == baseURL.Host {
-								links = append(links, absoluteURL)
-							}
-						}
-					}
-					break
+		if c.excludesRegion(n) {
+			return
+		}
+		if n.Type == html.ElementNode && c.checksElement(n.Data) {
+			for _, href := range elementURLs(n) {
+				link := c.repairLink(pageURL, href)
+				// Links with a forbidden scheme are checked like assets, so
+				// they fail without ever being crawled
+				if c.forbidsScheme(link) {
+					c.recordAsset(strings.TrimSpace(link), pageURL)
+					continue
+				}
+				if isMailto(link) {
+					c.recordSkipped(strings.TrimSpace(link), pageURL, CategoryMailtoSkipped)
+					continue
+				}
+				resolved := c.RewritePreview(c.resolveURL(link, resolveBaseURL))
+				absoluteURL := c.normalizeURL(resolved)
+				if absoluteURL == "" {
+					continue
+				}
+				linkURL, err := url.Parse(absoluteURL)
+				if err != nil {
+					continue
+				}
+				if c.shouldExclude(absoluteURL) {
+					c.recordSkipped(absoluteURL, pageURL, CategoryExcluded)
+				}
+				c.recordElement(absoluteURL, n.Data)
+				if _, ok := elements[absoluteURL]; !ok {
+					elements[absoluteURL] = n.Data
+				}
+				c.recordAnchor(absoluteURL, resolved)
+				if fragment, ok := fragments[absoluteURL]; ok {
+					fragments[absoluteURL] = fragment && hasFragment(resolved)
+				} else {
+					fragments[absoluteURL] = hasFragment(resolved)
+				}
+
+				// Only pages on the same domain are crawled
+				switch {
+				case linkURL.Host == baseURL.Host && n.Data == "a":
+					links = append(links, absoluteURL)
+				case linkURL.Host == baseURL.Host:
+					assets = append(assets, absoluteURL)
+					c.recordAsset(absoluteURL, pageURL)
+				case c.config.CheckExternal:
+					external = append(external, absoluteURL)
+					c.recordExternal(absoluteURL, pageURL)
 				}
 			}
 		}
@@ -240,12 +685,31 @@ func (c *Checker) extractLinksFromPage(pageURL string, currentURL *url.URL, base
 	}
 
 	extract(doc)
+	// Links are taken to be from an <a>, and not to a fragment, unless the
+	// cache says otherwise
+	maps.DeleteFunc(elements, func(_, element string) bool { return element == "a" })
+	maps.DeleteFunc(fragments, func(_ string, fragment bool) bool { return !fragment })
+	c.storePage(pageURL, resp.Header, cache.Page{
+		Links:         links,
+		Assets:        assets,
+		ExternalLinks: external,
+		Elements:      elements,
+		Fragments:     slices.Sorted(maps.Keys(fragments)),
+	})
 	return links, nil
 }
 
 // resolveURL converts relative URLs to absolute URLs
 func (c *Checker) resolveURL(href string, baseURL *url.URL) string {
-	if href == "" || strings.HasPrefix(href, "#") || strings.HasPrefix(href, "javascript:") || strings.HasPrefix(href, "mailto:") {
+	if href == "" || strings.HasPrefix(href, "#") {
+		return ""
+	}
+	switch c.schemeAction(href) {
+	case config.SchemeCheck:
+	case config.SchemeFail:
+		// Kept as they are, to be reported as broken
+		return strings.TrimSpace(href)
+	default:
 		return ""
 	}
 
@@ -331,7 +795,8 @@ func (c *Checker) isFileByContentType(urlStr string) (bool, error) {
 	if err != nil {
 		return false, err
 	}
-	req.Header.Set("User-Agent", c.config.UserAgent)
+	req.Header.Set("User-Agent", c.userAgentFor(urlStr))
+	c.authorize(req)
 
 	resp, err := c.client.Do(req)
 	if err != nil {
@@ -490,97 +955,321 @@ func (c *Checker) getResolveBaseURLByExtension(currentURL *url.URL) *url.URL {
 	return &newURL
 }
 
-// CheckLinks checks all provided URLs for broken links
+// CheckLinks checks all provided URLs for broken links. When locales are
+// configured, each URL is checked once per locale. URLs the decision hook
+// skips are left out of the results.
 func (c *Checker) CheckLinks(urls []string) []LinkResult {
-	results := make([]LinkResult, len(urls))
+	return c.checkLinks(urls, c.config.MaxConcurrent, c.limiter)
+}
+
+// CheckLinksTo checks URLs as CheckLinks does, adding their results to store
+// a batch at a time rather than returning them all at once
+func (c *Checker) CheckLinksTo(store *ResultStore, urls []string) error {
+	return c.checkLinksTo(slices.Values(urls), len(urls), c.config.MaxConcurrent, c.limiter, store.Add)
+}
+
+// CheckListTo checks the URLs of list as CheckLinksTo does, reading back
+// those that spilled to disk a batch at a time
+func (c *Checker) CheckListTo(store *ResultStore, list *URLList) error {
+	if err := c.checkLinksTo(list.All(), list.Len(), c.config.MaxConcurrent, c.limiter, store.Add); err != nil {
+		return err
+	}
+	return list.Err()
+}
+
+// checkLinks checks URLs with at most concurrency requests in flight, paced
+// by limiter
+func (c *Checker) checkLinks(urls []string, concurrency int, limiter *rate.Limiter) []LinkResult {
+	var results []LinkResult
+	c.checkLinksTo(slices.Values(urls), len(urls), concurrency, limiter, func(batch ...LinkResult) error {
+		results = append(results, batch...)
+		return nil
+	})
+	return results
+}
+
+// checkBatchSize is how many URLs checkLinksTo checks before handing their
+// results on
+const checkBatchSize = 1000
+
+// checkLinksTo checks the count URLs of a sequence as checkLinks does,
+// passing the results to add every checkBatchSize URLs so a huge list's
+// URLs and results needn't all be held at once. It stops at the first error
+// add returns.
+func (c *Checker) checkLinksTo(urls iter.Seq[string], count, concurrency int, limiter *rate.Limiter, add func(...LinkResult) error) error {
+	locales := c.config.CheckLocales
+	if len(locales) == 0 {
+		locales = []string{""}
+	}
+	total := count * len(locales)
+	c.progress.begin(total)
+	defer c.progress.finish()
+
+	checked := 0
+	batch := make([]string, 0, min(count, checkBatchSize))
+	for u := range urls {
+		batch = append(batch, u)
+		if len(batch) < checkBatchSize {
+			continue
+		}
+		if err := add(c.checkBatch(batch, locales, concurrency, limiter, total, &checked)...); err != nil {
+			return err
+		}
+		batch = batch[:0]
+	}
+	if len(batch) == 0 {
+		return nil
+	}
+	return add(c.checkBatch(batch, locales, concurrency, limiter, total, &checked)...)
+}
+
+// checkBatch checks a batch of URLs in every locale for checkLinksTo,
+// counting the checks reported in verbose mode in checked out of total
+func (c *Checker) checkBatch(urls, locales []string, concurrency int, limiter *rate.Limiter, total int, checked *int) []LinkResult {
+	results := make([]LinkResult, len(urls)*len(locales))
+	skipped := make([]bool, len(results))
 	var wg sync.WaitGroup
 	var mu sync.Mutex
-	checked := 0
 
-	// Use a semaphore to limit concurrent requests
-	semaphore := make(chan struct{}, c.config.MaxConcurrent)
+	// Use a semaphore to limit concurrent requests. It is acquired before
+	// starting each goroutine so that huge URL lists don't park one goroutine
+	// per URL in memory.
+	semaphore := make(chan struct{}, concurrency)
 
 	for i, url := range urls {
-		wg.Add(1)
-		go func(index int, checkURL string) {
-			defer wg.Done()
+		for j, locale := range locales {
+			index := i*len(locales) + j
+			if result, ok := c.unchangedResult(url); ok {
+				result.Locale = locale
+				result.Category = c.linkCategory(url, c.elementFor(url))
+				result.Severity = c.severity(result)
+				results[index] = result
+				c.stream.write(result)
+				c.progress.add()
+				continue
+			}
 
-			// Acquire semaphore
 			semaphore <- struct{}{}
-			defer func() { <-semaphore }()
-
-			// Rate limiting
-			if err := c.limiter.Wait(context.Background()); err != nil {
-				results[index] = LinkResult{
-					URL:      checkURL,
-					Error:    fmt.Sprintf("rate limiter error: %v", err),
-					Duration: "0s",
+			wg.Add(1)
+			go func(index int, checkURL, locale string) {
+				defer wg.Done()
+				defer func() { <-semaphore }()
+				defer c.progress.add()
+
+				if c.decide(checkURL, locale).Action == HookActionSkip {
+					skipped[index] = true
+					if c.config.Verbose {
+						fmt.Printf("Skipping %s (decision hook)\n", checkURL)
+					}
+					return
 				}
-				return
-			}
 
-			result := c.checkSingleLink(checkURL)
-			results[index] = result
+				// Rate limiting
+				if err := limiter.Wait(context.Background()); err != nil {
+					results[index] = LinkResult{
+						URL:       checkURL,
+						Error:     fmt.Sprintf("rate limiter error: %v", err),
+						ErrorType: classifyError(err),
+						Duration:  "0s",
+						Locale:    locale,
+						CheckedAt: time.Now().UTC().Format(time.RFC3339),
+						Category:  c.linkCategory(checkURL, c.elementFor(checkURL)),
+					}
+					results[index].Severity = c.severity(results[index])
+					c.stream.write(results[index])
+					return
+				}
 
-			if c.config.Verbose {
-				mu.Lock()
-				checked++
-				emoji := c.getStatusEmoji(result.StatusCode)
-				fmt.Printf("%s [%d/%d] %s (Status: %d, Duration: %s)\n",
-					emoji, checked, len(urls), result.URL, result.StatusCode, result.Duration)
-				mu.Unlock()
-			}
-		}(i, url)
+				result := c.checkPreferred(checkURL, locale)
+				c.recordCheck(result)
+				c.attachSources(&result)
+				result.Element = c.elementFor(checkURL)
+				result.Category = c.linkCategory(checkURL, result.Element)
+				result.Severity = c.severity(result)
+				results[index] = result
+				c.stream.write(result)
+
+				if c.config.Verbose {
+					mu.Lock()
+					*checked++
+					emoji := c.getStatusEmoji(result.StatusCode)
+					fmt.Printf("%s [%d/%d] %s (Status: %d, Duration: %s)\n",
+						emoji, *checked, total, result.URL, result.StatusCode, result.Duration)
+					mu.Unlock()
+				}
+			}(index, url, locale)
+		}
 	}
 
 	wg.Wait()
-	return results
+
+	kept := results[:0]
+	for i, result := range results {
+		if !skipped[i] {
+			kept = append(kept, result)
+		}
+	}
+	return kept
 }
 
-// checkSingleLink checks a single URL and returns the result
+// checkSingleLink checks a single URL and returns the result, retrying
+// transient failures while the run's retry budget allows it
 func (c *Checker) checkSingleLink(checkURL string) LinkResult {
+	return c.checkLink(checkURL, "")
+}
+
+// checkLink checks a URL as checkSingleLink does, requesting it in the given
+// locale when one is set
+func (c *Checker) checkLink(checkURL, locale string) LinkResult {
+	if c.forbidsScheme(checkURL) {
+		result := forbiddenSchemeResult(checkURL)
+		result.Locale = locale
+		return result
+	}
 	start := time.Now()
 
+	// Responses that ask to come back later with Retry-After are retried
+	// once the host's pause is over, without counting against MaxRetries
+	result := c.attemptLink(checkURL, locale, start)
+	attempts, waits := 0, 0
+	for {
+		waiting := result.retryLater && waits < maxRetryAfterRetries
+		if !waiting && (attempts >= c.config.MaxRetries || !shouldRetry(result)) {
+			break
+		}
+		if !c.retries.take() {
+			if c.config.Verbose {
+				fmt.Printf("Retry budget exhausted, not retrying %s\n", checkURL)
+			}
+			break
+		}
+		if waiting {
+			waits++
+			if c.config.Verbose {
+				fmt.Printf("Retrying %s as asked by Retry-After\n", checkURL)
+			}
+		} else {
+			attempts++
+			time.Sleep(time.Duration(attempts) * c.retryDelay)
+		}
+		result = c.attemptLink(checkURL, locale, start)
+		result.Retries = attempts + waits
+	}
+
+	result.Locale = locale
+	result.CheckedAt = time.Now().UTC().Format(time.RFC3339)
+	result.Duration = result.elapsed.String()
+	result.DurationMS = result.elapsed.Milliseconds()
+	result.Slow = c.config.SlowThreshold > 0 && result.elapsed > c.config.SlowThreshold
+	return result
+}
+
+// attemptLink makes a single check attempt against a URL
+func (c *Checker) attemptLink(checkURL, locale string, start time.Time) LinkResult {
 	req, err := http.NewRequest("HEAD", checkURL, nil)
 	if err != nil {
 		return LinkResult{
-			URL:      checkURL,
-			Error:    fmt.Sprintf("creating request: %v", err),
-			Duration: time.Since(start).String(),
+			URL:       checkURL,
+			Error:     fmt.Sprintf("creating request: %v", err),
+			ErrorType: ErrorTypeOther,
+			elapsed:   time.Since(start),
 		}
 	}
-	req.Header.Set("User-Agent", c.config.UserAgent)
+	req.Header.Set("User-Agent", c.userAgentFor(checkURL))
+	req.Header.Set("Accept-Encoding", acceptEncoding)
+	if language := c.acceptLanguageFor(checkURL, locale); language != "" {
+		req.Header.Set("Accept-Language", language)
+	}
+	c.authorize(req)
 
-	resp, err := c.client.Do(req)
-	if err != nil {
-		// Try GET request if HEAD fails
+	client := c.clientFor(checkURL)
+	if c.config.StopAtRedirects {
+		stopping := *client
+		stopping.CheckRedirect = stopAtRedirect
+		client = &stopping
+	}
+	c.throttle.wait(req.URL.Host)
+	resp, err := client.Do(req)
+	if err != nil || c.isGetFallbackStatus(resp.StatusCode) {
+		// Try GET request if HEAD fails, or is answered with a status
+		// servers give methods they don't support
+		if resp != nil {
+			resp.Body.Close()
+		}
 		req.Method = "GET"
-		resp, err = c.client.Do(req)
+		c.throttle.wait(req.URL.Host)
+		resp, err = client.Do(req)
 		if err != nil {
-			return LinkResult{
-				URL:      checkURL,
-				Error:    fmt.Sprintf("request failed: %v", err),
-				Duration: time.Since(start).String(),
+			result := LinkResult{
+				URL:         checkURL,
+				Error:       fmt.Sprintf("request failed: %v", err),
+				ErrorType:   classifyError(err),
+				ErrorDetail: errorDetail(err),
+				elapsed:     time.Since(start),
+			}
+			if resp != nil {
+				// A redirect policy error still returns the response it
+				// stopped at
+				recordAbandonedRedirect(&result, resp)
 			}
+			return result
 		}
 	}
 	defer resp.Body.Close()
+	now := time.Now()
+	c.throttle.update(req.URL.Host, resp.Header, now)
+	c.recordContentType(checkURL, resp.Header.Get("Content-Type"))
 
 	result := LinkResult{
 		URL:        checkURL,
 		StatusCode: resp.StatusCode,
-		Duration:   time.Since(start).String(),
+		elapsed:    time.Since(start),
+	}
+	if delay, ok := parseRetryAfter(resp, now); ok && delay <= c.throttle.maxWait {
+		// Hold every request to the host, not just this link's retry
+		c.throttle.pause(req.URL.Host, now.Add(delay))
+		result.retryLater = true
+	}
+	recordRedirect(&result, resp)
+	recordEncoding(&result, resp)
+
+	if loginURL := c.loginRedirect(req.URL, resp); loginURL != "" && resp.StatusCode < 500 {
+		result.Error = fmt.Sprintf("redirected to login page %s", loginURL)
+		result.ErrorType = ErrorTypeAuthRequired
+		return result
 	}
 
 	if resp.StatusCode >= 400 {
-		result.Error = fmt.Sprintf("HTTP %d %s", resp.StatusCode, resp.Status)
+		if c.isAcceptedStatus(resp.StatusCode) || c.isStatusException(req.URL, resp.StatusCode) || c.isExpectedStatus(checkURL, resp.StatusCode) {
+			result.Accepted = true
+		} else if isBotChallenge(client, req, resp) {
+			result.Error = fmt.Sprintf("HTTP %d bot protection challenge", resp.StatusCode)
+			result.ErrorType = ErrorTypeBotChallenge
+		} else {
+			result.Error = fmt.Sprintf("HTTP %d %s", resp.StatusCode, resp.Status)
+			result.ErrorType = classifyStatus(resp.StatusCode)
+		}
+	} else if pattern := c.soft404Match(client, req, resp); pattern != "" {
+		result.Error = fmt.Sprintf("soft 404: page content matches %q", pattern)
+		result.ErrorType = ErrorTypeSoft404
+	}
+	c.flagRedirectChain(&result)
+	if c.config.WarnOnRedirect {
+		flagRedirect(&result)
 	}
 
 	return result
 }
 
-// shouldExclude checks if a URL should be excluded based on patterns
+// shouldExclude checks if a URL should be excluded based on patterns: it
+// matches an exclude pattern, or include patterns are set and it matches
+// none of them
 func (c *Checker) shouldExclude(url string) bool {
+	return c.matchesExclude(url) || !c.isIncluded(url)
+}
+
+// matchesExclude reports whether a URL matches an exclude pattern
+func (c *Checker) matchesExclude(url string) bool {
 	for _, pattern := range c.config.ExcludePatterns {
 		if pattern.MatchString(url) {
 			return true
@@ -589,6 +1278,108 @@ func (c *Checker) shouldExclude(url string) bool {
 	return false
 }
 
+// isIncluded reports whether a URL matches an include pattern, or whether
+// there are no include patterns
+func (c *Checker) isIncluded(url string) bool {
+	if len(c.config.IncludePatterns) == 0 {
+		return true
+	}
+	for _, pattern := range c.config.IncludePatterns {
+		if pattern.MatchString(url) {
+			return true
+		}
+	}
+	return false
+}
+
+// siteURLs returns the configured base and sitemap URLs, some of which may
+// be empty
+func (c *Checker) siteURLs() []string {
+	sites := append([]string{c.config.BaseURL, c.config.SitemapURL}, c.config.BaseURLs...)
+	return append(sites, c.config.SitemapURLs...)
+}
+
+// isInternal reports whether a URL is on the host of a configured base URL
+// or sitemap
+func (c *Checker) isInternal(rawURL string) bool {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return false
+	}
+	for _, site := range c.siteURLs() {
+		if siteURL, err := url.Parse(site); err == nil && site != "" && strings.EqualFold(siteURL.Host, u.Host) {
+			return true
+		}
+	}
+	return false
+}
+
+// isGetFallbackStatus reports whether a status code answering a HEAD request
+// is retried with GET
+func (c *Checker) isGetFallbackStatus(statusCode int) bool {
+	for _, code := range c.config.GetFallbackStatus {
+		if code == statusCode {
+			return true
+		}
+	}
+	return false
+}
+
+// isAcceptedStatus reports whether a status code is accepted for every host
+func (c *Checker) isAcceptedStatus(statusCode int) bool {
+	for _, code := range c.config.AcceptStatus {
+		if code == statusCode {
+			return true
+		}
+	}
+	return false
+}
+
+// isStatusException reports whether a status code is configured as acceptable
+// for the URL's host or one of its parent domains
+func (c *Checker) isStatusException(u *url.URL, statusCode int) bool {
+	host := strings.ToLower(u.Hostname())
+	for exceptionHost, codes := range c.config.StatusExceptions {
+		if host != exceptionHost && !strings.HasSuffix(host, "."+exceptionHost) {
+			continue
+		}
+		for _, code := range codes {
+			if code == statusCode {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// loginRedirect returns the final URL of a redirect chain when it lands on a
+// page matching one of the configured login patterns, or "" otherwise
+func (c *Checker) loginRedirect(requested *url.URL, resp *http.Response) string {
+	if resp.Request == nil || resp.Request.URL.String() == requested.String() {
+		return ""
+	}
+	final := resp.Request.URL.String()
+	for _, pattern := range c.config.LoginPatterns {
+		if pattern.MatchString(final) {
+			return final
+		}
+	}
+	return ""
+}
+
+// clientFor returns the HTTP client to use for a URL, honoring any
+// per-pattern timeout override
+func (c *Checker) clientFor(urlStr string) *http.Client {
+	for _, override := range c.config.TimeoutOverrides {
+		if override.Pattern.MatchString(urlStr) {
+			client := *c.client
+			client.Timeout = override.Timeout
+			return &client
+		}
+	}
+	return c.client
+}
+
 // getStatusEmoji returns an emoji based on HTTP status code
 func (c *Checker) getStatusEmoji(statusCode int) string {
 	switch {
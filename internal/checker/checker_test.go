@@ -1,10 +1,12 @@
 package checker
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
+	"reflect"
 	"regexp"
 	"strings"
 	"testing"
@@ -85,6 +87,122 @@ func TestShouldExclude(t *testing.T) {
 	}
 }
 
+func TestIncludePatterns(t *testing.T) {
+	var requested []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requested = append(requested, r.URL.Path)
+		w.Header().Set("Content-Type", "text/html")
+		switch r.URL.Path {
+		case "/":
+			fmt.Fprint(w, `<a href="/docs/">Docs</a><a href="/blog/">Blog</a>`)
+		case "/docs/":
+			fmt.Fprint(w, `<a href="/docs/setup/">Setup</a><a href="/docs/archive/">Archive</a><a href="/pricing/">Pricing</a>`)
+		}
+	}))
+	defer server.Close()
+
+	checker := New(&config.Config{
+		UserAgent:       "TestBot/1.0",
+		Timeout:         5 * time.Second,
+		MaxConcurrent:   1,
+		IncludePatterns: config.ParsePatterns("/docs/"),
+		ExcludePatterns: config.ParsePatterns("/archive/"),
+	})
+	urls, err := checker.CrawlWebsite(server.URL+"/", 3)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	expected := []string{server.URL + "/docs/", server.URL + "/docs/setup/"}
+	if !reflect.DeepEqual(urls, expected) {
+		t.Errorf("Expected %v, got %v", expected, urls)
+	}
+	for _, path := range requested {
+		if path == "/blog/" || path == "/pricing/" || path == "/docs/archive/" {
+			t.Errorf("Expected %s not to be crawled", path)
+		}
+	}
+}
+
+func TestMaxPages(t *testing.T) {
+	var requested []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requested = append(requested, r.URL.Path)
+		w.Header().Set("Content-Type", "text/html")
+		switch r.URL.Path {
+		case "/":
+			fmt.Fprint(w, `<a href="/a/">A</a><a href="/b/">B</a>`)
+		case "/a/":
+			fmt.Fprint(w, `<a href="/a/1/">A1</a>`)
+		case "/b/":
+			fmt.Fprint(w, `<a href="/b/1/">B1</a>`)
+		case "/a/1/":
+			fmt.Fprint(w, `<a href="/a/2/">A2</a>`)
+		}
+	}))
+	defer server.Close()
+
+	checker := New(&config.Config{
+		UserAgent:     "TestBot/1.0",
+		Timeout:       5 * time.Second,
+		MaxConcurrent: 1,
+		MaxPages:      3,
+	})
+	urls, err := checker.CrawlWebsite(server.URL+"/", 100)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	// The shallowest pages are fetched first, and links on the last of them
+	// are still checked
+	expected := []string{server.URL + "/", server.URL + "/a/", server.URL + "/b/", server.URL + "/a/1/", server.URL + "/b/1/"}
+	if !reflect.DeepEqual(urls, expected) {
+		t.Errorf("Expected %v, got %v", expected, urls)
+	}
+	if !reflect.DeepEqual(requested, []string{"/", "/a/", "/b/"}) {
+		t.Errorf("Expected 3 pages to be fetched, got %v", requested)
+	}
+
+	// The depth limit still applies, as for changed pages
+	urls, err = checker.CrawlPages(server.URL+"/", []string{server.URL + "/"}, 1)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	expected = []string{server.URL + "/", server.URL + "/a/", server.URL + "/b/"}
+	if !reflect.DeepEqual(urls, expected) {
+		t.Errorf("Expected %v, got %v", expected, urls)
+	}
+}
+
+func TestSlowThreshold(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/slow" {
+			time.Sleep(150 * time.Millisecond)
+		}
+	}))
+	defer server.Close()
+
+	checker := New(&config.Config{
+		UserAgent:     "TestBot/1.0",
+		Timeout:       5 * time.Second,
+		MaxConcurrent: 1,
+		SlowThreshold: 100 * time.Millisecond,
+	})
+	results := checker.CheckLinks([]string{server.URL + "/fast", server.URL + "/slow"})
+	if len(results) != 2 {
+		t.Fatalf("Expected 2 results, got %d", len(results))
+	}
+	if results[0].Slow {
+		t.Errorf("Expected the fast link not to be slow, took %dms", results[0].DurationMS)
+	}
+	if !results[1].Slow || results[1].DurationMS < 150 {
+		t.Errorf("Expected the slow link to be slow with its duration in milliseconds, got %+v", results[1])
+	}
+	if results[1].ErrorType != "" {
+		t.Errorf("Expected the slow link to pass, got %s", results[1].ErrorType)
+	}
+}
+
 func TestResolveURL(t *testing.T) {
 	cfg := &config.Config{}
 	checker := New(cfg)
@@ -1259,8 +1377,9 @@ func TestResolveURLEdgeCases(t *testing.T) {
 		{"javascript:void(0)", "", "javascript function should return empty"},
 		{"mailto:", "", "mailto protocol should return empty"},
 		{"mailto:test@example.com", "", "mailto address should return empty"},
-		{"tel:+1234567890", "tel:+1234567890", "tel protocol should be preserved as absolute URL"},
-		{"ftp://ftp.example.com/file", "ftp://ftp.example.com/file", "ftp protocol should be preserved"},
+		{"tel:+1234567890", "", "tel protocol should be ignored by the default scheme policy"},
+		{"ftp://ftp.example.com/file", "", "ftp protocol should be ignored by the default scheme policy"},
+		{"HTTPS://other.com/path", "https://other.com/path", "scheme should match case-insensitively"},
 		{"//other.com/path", "https://other.com/path", "protocol-relative URL should use base protocol"},
 	}
 
@@ -1908,10 +2027,48 @@ func TestCheckSingleLinkComprehensive(t *testing.T) {
 
 		result := checker.checkSingleLink(server.URL)
 
-		// Should get the HEAD response (405), not fall back to GET
+		// Without GET fallback statuses, should get the HEAD response (405)
 		if result.StatusCode != 405 {
 			t.Errorf("Expected status 405, got %d", result.StatusCode)
 		}
+
+		fallback := New(&config.Config{
+			UserAgent:         "TestBot/1.0",
+			Timeout:           5 * time.Second,
+			MaxConcurrent:     1,
+			GetFallbackStatus: config.ParseStatusCodes(config.DefaultGetFallbackStatus),
+		})
+		result = fallback.checkSingleLink(server.URL)
+		if result.StatusCode != 200 || result.ErrorType != "" {
+			t.Errorf("Expected the GET fallback to succeed, got %d %s", result.StatusCode, result.Error)
+		}
+	})
+
+	t.Run("GET fallback keeps the GET status", func(t *testing.T) {
+		var methods []string
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			methods = append(methods, r.Method)
+			if r.Method == "HEAD" {
+				w.WriteHeader(http.StatusForbidden)
+				return
+			}
+			w.WriteHeader(http.StatusNotFound)
+		}))
+		defer server.Close()
+
+		fallback := New(&config.Config{
+			UserAgent:         "TestBot/1.0",
+			Timeout:           5 * time.Second,
+			MaxConcurrent:     1,
+			GetFallbackStatus: []int{403},
+		})
+		result := fallback.checkSingleLink(server.URL)
+		if result.StatusCode != 404 || result.ErrorType != ErrorTypeHTTP4xx {
+			t.Errorf("Expected the GET response's 404, got %d %s", result.StatusCode, result.ErrorType)
+		}
+		if strings.Join(methods, ",") != "HEAD,GET" {
+			t.Errorf("Expected HEAD then GET, got %v", methods)
+		}
 	})
 
 	t.Run("malformed URL", func(t *testing.T) {
@@ -2074,3 +2231,260 @@ func TestGetURLsFromSitemapComprehensive(t *testing.T) {
 		}
 	})
 }
+
+func TestClientForTimeoutOverrides(t *testing.T) {
+	cfg := &config.Config{
+		Timeout:          30 * time.Second,
+		TimeoutOverrides: config.ParseTimeoutOverrides(`/downloads/=120s,api\.example\.com=5s`),
+	}
+	checker := New(cfg)
+
+	testCases := []struct {
+		url      string
+		expected time.Duration
+	}{
+		{"https://example.com/downloads/big.iso", 120 * time.Second},
+		{"https://api.example.com/v1/items", 5 * time.Second},
+		{"https://example.com/page", 30 * time.Second},
+	}
+
+	for _, tc := range testCases {
+		if got := checker.clientFor(tc.url).Timeout; got != tc.expected {
+			t.Errorf("URL %s: expected timeout %v, got %v", tc.url, tc.expected, got)
+		}
+	}
+
+	if checker.client.Timeout != 30*time.Second {
+		t.Errorf("Expected shared client timeout to be unchanged, got %v", checker.client.Timeout)
+	}
+
+	slowServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(200 * time.Millisecond)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer slowServer.Close()
+
+	cfg.TimeoutOverrides = config.ParseTimeoutOverrides(regexp.QuoteMeta(slowServer.URL) + "=50ms")
+	result := checker.checkSingleLink(slowServer.URL)
+	if result.Error == "" {
+		t.Error("Expected the overridden timeout to fail the slow request")
+	}
+}
+
+func TestStatusExceptions(t *testing.T) {
+	cfg := &config.Config{
+		UserAgent:        "TestBot/1.0",
+		Timeout:          5 * time.Second,
+		MaxConcurrent:    1,
+		StatusExceptions: config.ParseStatusExceptions("linkedin.com=999,127.0.0.1=403"),
+	}
+	checker := New(cfg)
+
+	testCases := []struct {
+		url        string
+		statusCode int
+		expected   bool
+	}{
+		{"https://linkedin.com/in/someone", 999, true},
+		{"https://www.linkedin.com/in/someone", 999, true},
+		{"https://notlinkedin.com/in/someone", 999, false},
+		{"https://linkedin.com/in/someone", 404, false},
+		{"https://example.org/", 999, false},
+	}
+
+	for _, tc := range testCases {
+		u, _ := url.Parse(tc.url)
+		if got := checker.isStatusException(u, tc.statusCode); got != tc.expected {
+			t.Errorf("URL %s status %d: expected %v, got %v", tc.url, tc.statusCode, tc.expected, got)
+		}
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	defer server.Close()
+
+	result := checker.checkSingleLink(server.URL)
+	if !result.Accepted {
+		t.Error("Expected 403 from an excepted host to be accepted")
+	}
+	if result.Error != "" || result.ErrorType != "" {
+		t.Errorf("Expected accepted result to carry no error, got %q (%s)", result.Error, result.ErrorType)
+	}
+	if result.StatusCode != 403 {
+		t.Errorf("Expected status 403 to be recorded, got %d", result.StatusCode)
+	}
+}
+
+func TestAcceptStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/blocked":
+			w.WriteHeader(999)
+		case "/limited":
+			w.WriteHeader(http.StatusTooManyRequests)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	checker := New(&config.Config{
+		UserAgent:     "TestBot/1.0",
+		Timeout:       5 * time.Second,
+		MaxConcurrent: 1,
+		AcceptStatus:  []int{429, 999},
+	})
+
+	for _, path := range []string{"/blocked", "/limited"} {
+		result := checker.checkSingleLink(server.URL + path)
+		if !result.Accepted || result.ErrorType != "" {
+			t.Errorf("%s: expected an accepted result, got %+v", path, result)
+		}
+	}
+	if result := checker.checkSingleLink(server.URL + "/missing"); result.Accepted || result.ErrorType != ErrorTypeHTTP4xx {
+		t.Errorf("Expected 404 to stay broken, got %+v", result)
+	}
+}
+
+func TestGetURLsFromSitemapAlternates(t *testing.T) {
+	sitemapXML := `<?xml version="1.0" encoding="UTF-8"?>
+<urlset xmlns="http://www.sitemaps.org/schemas/sitemap/0.9"
+        xmlns:xhtml="http://www.w3.org/1999/xhtml">
+  <url>
+    <loc>https://example.com/en/</loc>
+    <xhtml:link rel="alternate" hreflang="de" href="https://example.com/de/"/>
+    <xhtml:link rel="alternate" hreflang="fr" href="https://example.com/fr/"/>
+    <xhtml:link rel="alternate" hreflang="en" href="https://example.com/en/"/>
+  </url>
+  <url>
+    <loc>https://example.com/de/</loc>
+    <xhtml:link rel="alternate" hreflang="en" href="https://example.com/en/"/>
+    <xhtml:link rel="alternate" hreflang="fr" href="https://example.com/fr/"/>
+    <xhtml:link rel="canonical" href="https://example.com/canonical/"/>
+  </url>
+</urlset>`
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/xml")
+		w.Write([]byte(sitemapXML))
+	}))
+	defer server.Close()
+
+	cfg := &config.Config{
+		UserAgent: "TestBot/1.0",
+		Timeout:   5 * time.Second,
+	}
+	checker := New(cfg)
+
+	urls, err := checker.GetURLsFromSitemap(server.URL)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	expectedURLs := []string{
+		"https://example.com/en/",
+		"https://example.com/de/",
+		"https://example.com/fr/",
+	}
+	if len(urls) != len(expectedURLs) {
+		t.Fatalf("Expected %d URLs, got %d: %v", len(expectedURLs), len(urls), urls)
+	}
+	for i, expectedURL := range expectedURLs {
+		if urls[i] != expectedURL {
+			t.Errorf("Expected URL %s at index %d, got %s", expectedURL, i, urls[i])
+		}
+	}
+
+	sources := checker.Sources("https://example.com/fr/")
+	if len(sources) != 2 {
+		t.Errorf("Expected the fr alternate to be sourced from 2 entries, got %v", sources)
+	}
+}
+
+func TestGetURLsFromSitemapHTML(t *testing.T) {
+	cfg := &config.Config{
+		UserAgent: "TestBot/1.0",
+		Timeout:   5 * time.Second,
+	}
+	checker := New(cfg)
+
+	t.Run("html content type", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "text/html; charset=utf-8")
+			w.Write([]byte(`<html><body><a href="/page">Page</a></body></html>`))
+		}))
+		defer server.Close()
+
+		_, err := checker.GetURLsFromSitemap(server.URL)
+		if !errors.Is(err, ErrSitemapIsHTML) {
+			t.Errorf("Expected ErrSitemapIsHTML, got %v", err)
+		}
+	})
+
+	t.Run("html body without content type", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header()["Content-Type"] = nil
+			w.Write([]byte(`<!DOCTYPE html><html><body>Sitemap</body></html>`))
+		}))
+		defer server.Close()
+
+		_, err := checker.GetURLsFromSitemap(server.URL)
+		if !errors.Is(err, ErrSitemapIsHTML) {
+			t.Errorf("Expected ErrSitemapIsHTML, got %v", err)
+		}
+	})
+
+	t.Run("invalid xml is not reported as html", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte("invalid xml content"))
+		}))
+		defer server.Close()
+
+		_, err := checker.GetURLsFromSitemap(server.URL)
+		if err == nil || errors.Is(err, ErrSitemapIsHTML) {
+			t.Errorf("Expected a plain parse error, got %v", err)
+		}
+	})
+}
+
+func TestLoginRedirect(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/private":
+			http.Redirect(w, r, "/login?next=/private", http.StatusFound)
+		case "/moved":
+			http.Redirect(w, r, "/new-home", http.StatusMovedPermanently)
+		default:
+			w.WriteHeader(http.StatusOK)
+		}
+	}))
+	defer server.Close()
+
+	cfg := &config.Config{
+		UserAgent:     "TestBot/1.0",
+		Timeout:       5 * time.Second,
+		MaxConcurrent: 1,
+		LoginPatterns: config.ParsePatterns(config.DefaultLoginPatterns),
+	}
+	checker := New(cfg)
+
+	result := checker.checkSingleLink(server.URL + "/private")
+	if result.ErrorType != ErrorTypeAuthRequired {
+		t.Errorf("Expected %q for a redirect to a login page, got %q", ErrorTypeAuthRequired, result.ErrorType)
+	}
+	if !strings.Contains(result.Error, "/login?next=/private") {
+		t.Errorf("Expected error to name the login page, got %q", result.Error)
+	}
+
+	result = checker.checkSingleLink(server.URL + "/moved")
+	if result.ErrorType != "" {
+		t.Errorf("Expected an ordinary redirect to pass, got %q", result.ErrorType)
+	}
+
+	// A login page linked directly is not a login wall
+	result = checker.checkSingleLink(server.URL + "/login")
+	if result.ErrorType != "" {
+		t.Errorf("Expected a direct link to a login page to pass, got %q", result.ErrorType)
+	}
+}
@@ -0,0 +1,108 @@
+package checker
+
+import (
+	"bufio"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+)
+
+// checkstyleReport is the root of a checkstyle XML document
+type checkstyleReport struct {
+	XMLName xml.Name         `xml:"checkstyle"`
+	Version string           `xml:"version,attr"`
+	Files   []checkstyleFile `xml:"file"`
+}
+
+// checkstyleFile lists the problems found in one file
+type checkstyleFile struct {
+	Name   string            `xml:"name,attr"`
+	Errors []checkstyleError `xml:"error"`
+}
+
+// checkstyleError is a single problem. Line is omitted when unknown.
+type checkstyleError struct {
+	Line     int    `xml:"line,attr,omitempty"`
+	Severity string `xml:"severity,attr"`
+	Message  string `xml:"message,attr"`
+	Source   string `xml:"source,attr"`
+}
+
+// WriteCheckstyle writes flagged links as checkstyle XML, the format many CI
+// systems and reviewdog ingest. Each link is reported on the repository files
+// it maps to, falling back to the pages linking to it and then to the URL
+// itself. When a file exists locally, the first line containing the URL is
+// given. Results without a severity are reported as errors.
+func WriteCheckstyle(w io.Writer, flagged []LinkResult) error {
+	files := make(map[string][]checkstyleError)
+	for _, result := range flagged {
+		severity := string(result.Severity)
+		if severity == "" {
+			severity = string(SeverityError)
+		}
+		message := fmt.Sprintf("Broken link %s: %s", result.URL, result.Error)
+		if result.Locale != "" {
+			message = fmt.Sprintf("Broken link %s [%s]: %s", result.URL, result.Locale, result.Error)
+		}
+
+		names := result.SourceFiles
+		if len(names) == 0 {
+			names = result.Sources
+		}
+		if len(names) == 0 {
+			names = []string{result.URL}
+		}
+		for _, name := range names {
+			files[name] = append(files[name], checkstyleError{
+				Line:     sourceLine(name, result.URL),
+				Severity: severity,
+				Message:  message,
+				Source:   "link-checker." + string(result.ErrorType),
+			})
+		}
+	}
+
+	report := checkstyleReport{Version: "4.3"}
+	for name, errs := range files {
+		report.Files = append(report.Files, checkstyleFile{Name: name, Errors: errs})
+	}
+	sort.Slice(report.Files, func(i, j int) bool {
+		return report.Files[i].Name < report.Files[j].Name
+	})
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(report); err != nil {
+		return fmt.Errorf("encoding checkstyle report: %w", err)
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+// sourceLine returns the first line of a local file that contains target, or
+// 0 when the file can't be read or doesn't mention it
+func sourceLine(path, target string) int {
+	if strings.Contains(path, "://") {
+		return 0
+	}
+	f, err := os.Open(path) // #nosec G304 -- paths come from the configured file rules
+	if err != nil {
+		return 0
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64<<10), 1<<20)
+	for line := 1; scanner.Scan(); line++ {
+		if strings.Contains(scanner.Text(), target) {
+			return line
+		}
+	}
+	return 0
+}
@@ -0,0 +1,65 @@
+package checker
+
+import (
+	"bytes"
+	"encoding/xml"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestWriteCheckstyle(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "intro.md")
+	if err := os.WriteFile(file, []byte("# Intro\n\nSee [the docs](https://example.com/gone).\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	gone := LinkResult{
+		URL:         "https://example.com/gone",
+		StatusCode:  404,
+		Error:       "HTTP 404 404 Not Found",
+		ErrorType:   ErrorTypeHTTP4xx,
+		Severity:    SeverityError,
+		Sources:     []string{"https://example.com/intro/"},
+		SourceFiles: []string{file},
+	}
+	slow := LinkResult{
+		URL:       "https://example.org/slow",
+		Error:     "request failed: timeout",
+		ErrorType: ErrorTypeTimeout,
+		Severity:  SeverityWarning,
+		Sources:   []string{"https://example.com/about/"},
+	}
+	orphan := LinkResult{URL: "https://example.net/", Error: "HTTP 500", ErrorType: ErrorTypeHTTP5xx}
+
+	var buf bytes.Buffer
+	if err := WriteCheckstyle(&buf, []LinkResult{gone, slow, orphan}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	output := buf.String()
+	if !strings.HasPrefix(output, xml.Header) {
+		t.Errorf("Expected an XML header, got %s", output)
+	}
+
+	var report checkstyleReport
+	if err := xml.Unmarshal(buf.Bytes(), &report); err != nil {
+		t.Fatalf("Expected valid XML, got %v", err)
+	}
+	if len(report.Files) != 3 {
+		t.Fatalf("Expected 3 files, got %+v", report.Files)
+	}
+
+	expected := map[string]checkstyleError{
+		file:                         {Line: 3, Severity: "error", Message: "Broken link https://example.com/gone: HTTP 404 404 Not Found", Source: "link-checker.http_4xx"},
+		"https://example.com/about/": {Severity: "warning", Message: "Broken link https://example.org/slow: request failed: timeout", Source: "link-checker.timeout"},
+		"https://example.net/":       {Severity: "error", Message: "Broken link https://example.net/: HTTP 500", Source: "link-checker.http_5xx"},
+	}
+	for _, f := range report.Files {
+		want, ok := expected[f.Name]
+		if !ok || len(f.Errors) != 1 || f.Errors[0] != want {
+			t.Errorf("Unexpected file %s with errors %+v", f.Name, f.Errors)
+		}
+	}
+}
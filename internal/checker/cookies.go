@@ -0,0 +1,50 @@
+package checker
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/cookiejar"
+	"net/url"
+	"strings"
+)
+
+// useCookieJar gives the client a cookie jar, if it doesn't have one yet, so
+// that cookies persist across requests
+func (c *Checker) useCookieJar() error {
+	if c.client.Jar != nil {
+		return nil
+	}
+	jar, err := cookiejar.New(nil)
+	if err != nil {
+		return fmt.Errorf("creating cookie jar: %w", err)
+	}
+	c.client.Jar = jar
+	return nil
+}
+
+// SeedCookies adds the configured cookies to the cookie jar before checking,
+// so that a session from an earlier login can be reused. Cookies with a
+// Domain are sent to that domain and its subdomains; others are sent to the
+// hosts of the sites being checked.
+func (c *Checker) SeedCookies() error {
+	if err := c.useCookieJar(); err != nil {
+		return err
+	}
+	for _, cookie := range c.config.Cookies {
+		hosts := c.siteHosts()
+		if domain := strings.TrimPrefix(cookie.Domain, "."); domain != "" {
+			hosts = []string{domain}
+		}
+		if len(hosts) == 0 {
+			return fmt.Errorf("cookie %s has no domain and there is no base URL to send it to", cookie.Name)
+		}
+		scheme := "http"
+		if cookie.Secure {
+			scheme = "https"
+		}
+		for _, host := range hosts {
+			c.client.Jar.SetCookies(&url.URL{Scheme: scheme, Host: host, Path: "/"}, []*http.Cookie{cookie})
+		}
+	}
+	return nil
+}
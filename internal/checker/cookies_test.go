@@ -0,0 +1,47 @@
+package checker
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/joshbeard/link-validator/internal/config"
+)
+
+func TestSeedCookies(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if cookie, err := r.Cookie("session"); err != nil || cookie.Value != "abc123" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if _, err := r.Cookie("other"); err == nil {
+			t.Error("Expected a cookie for another domain not to be sent")
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	checker := New(&config.Config{
+		UserAgent:     "TestBot/1.0",
+		Timeout:       5 * time.Second,
+		MaxConcurrent: 1,
+		BaseURL:       server.URL,
+		Cookies:       config.ParseCookies("session=abc123; Path=/\nother=1; Domain=elsewhere.example"),
+	})
+	if err := checker.SeedCookies(); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	result := checker.checkSingleLink(server.URL + "/private")
+	if result.StatusCode != http.StatusOK {
+		t.Errorf("Expected the session cookie to be sent, got status %d", result.StatusCode)
+	}
+}
+
+func TestSeedCookiesWithoutSite(t *testing.T) {
+	checker := New(&config.Config{Cookies: config.ParseCookies("session=abc123")})
+	if err := checker.SeedCookies(); err == nil {
+		t.Error("Expected an error for a cookie with nowhere to send it")
+	}
+}
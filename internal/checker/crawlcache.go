@@ -0,0 +1,85 @@
+package checker
+
+import (
+	"mime"
+	"net/http"
+	"slices"
+
+	"github.com/joshbeard/link-validator/internal/cache"
+)
+
+// UseCache enables revalidating crawled pages against a persistent cache.
+// Pages whose ETag or Last-Modified validators still match are answered with
+// 304 Not Modified and reuse their previously extracted links instead of
+// being downloaded and parsed again.
+func (c *Checker) UseCache(pageCache *cache.Cache) {
+	c.cache = pageCache
+}
+
+// CacheHits returns the number of crawled pages served from the cache
+func (c *Checker) CacheHits() int {
+	return int(c.cacheHits.Load())
+}
+
+// cachedPage returns the cached entry for a page when it can be revalidated.
+// Page audits and link repairs report issues from the parsed page, so no
+// page is revalidated while one is enabled.
+func (c *Checker) cachedPage(pageURL string) (cache.Page, bool) {
+	if c.cache == nil || c.auditsPages() || c.config.RepairURLs {
+		return cache.Page{}, false
+	}
+	page, ok := c.cache.Page(pageURL)
+	return page, ok && page.HasValidators()
+}
+
+// setConditionalHeaders makes req conditional on the cached validators
+func setConditionalHeaders(req *http.Request, page cache.Page) {
+	if page.ETag != "" {
+		req.Header.Set("If-None-Match", page.ETag)
+	}
+	if page.LastModified != "" {
+		req.Header.Set("If-Modified-Since", page.LastModified)
+	}
+}
+
+// storePage caches the links extracted from a page, the resources it uses and
+// its links to other hosts when external links are checked, along with the
+// validators needed to revalidate it. Pages without validators are not cached because they could
+// never be confirmed unchanged.
+func (c *Checker) storePage(pageURL string, header http.Header, page cache.Page) {
+	if c.cache == nil {
+		return
+	}
+	page.ETag = header.Get("ETag")
+	page.LastModified = header.Get("Last-Modified")
+	if !page.HasValidators() {
+		return
+	}
+	if mediaType, _, err := mime.ParseMediaType(header.Get("Content-Type")); err == nil {
+		page.ContentType = mediaType
+	}
+	c.cache.SetPage(pageURL, page)
+}
+
+// replayPage records the links of a page answered from the cache as parsing
+// it would have: the element each was found in and whether it pointed at a
+// fragment, then the resources and links to other hosts it uses
+func (c *Checker) replayPage(pageURL string, page cache.Page) {
+	for _, links := range [][]string{page.Links, page.Assets, page.ExternalLinks} {
+		for _, link := range links {
+			element, ok := page.Elements[link]
+			if !ok {
+				element = "a"
+			}
+			c.recordElement(link, element)
+			_, fragment := slices.BinarySearch(page.Fragments, link)
+			c.recordFragment(link, fragment)
+		}
+	}
+	for _, link := range page.Assets {
+		c.recordAsset(link, pageURL)
+	}
+	for _, link := range page.ExternalLinks {
+		c.recordExternal(link, pageURL)
+	}
+}
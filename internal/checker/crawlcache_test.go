@@ -0,0 +1,189 @@
+package checker
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/joshbeard/link-validator/internal/cache"
+	"github.com/joshbeard/link-validator/internal/config"
+)
+
+func TestCrawlWithCache(t *testing.T) {
+	var mu sync.Mutex
+	bodiesServed := make(map[string]int)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodHead {
+			w.Header().Set("Content-Type", "text/html")
+			return
+		}
+
+		etag := `"v1"` + r.URL.Path
+		if r.Header.Get("If-None-Match") == etag {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+
+		mu.Lock()
+		bodiesServed[r.URL.Path]++
+		mu.Unlock()
+
+		w.Header().Set("ETag", etag)
+		w.Header().Set("Content-Type", "text/html")
+		switch r.URL.Path {
+		case "/":
+			w.Write([]byte(`<a href="/a">A</a><a href="/b">B</a>`))
+		default:
+			w.Write([]byte(`<p>Leaf</p>`))
+		}
+	}))
+	defer server.Close()
+
+	path := filepath.Join(t.TempDir(), "cache.json")
+	crawl := func() (*Checker, []string) {
+		pageCache, err := cache.Load(path)
+		if err != nil {
+			t.Fatalf("Expected no error loading cache, got %v", err)
+		}
+		checker := New(&config.Config{UserAgent: "TestBot/1.0", Timeout: 5 * time.Second})
+		checker.UseCache(pageCache)
+
+		urls, err := checker.CrawlWebsite(server.URL, 2)
+		if err != nil {
+			t.Fatalf("Expected no error crawling, got %v", err)
+		}
+		if err := pageCache.Save(); err != nil {
+			t.Fatalf("Expected no error saving cache, got %v", err)
+		}
+		return checker, urls
+	}
+
+	first, firstURLs := crawl()
+	if first.CacheHits() != 0 {
+		t.Errorf("Expected no cache hits on the first run, got %d", first.CacheHits())
+	}
+
+	second, secondURLs := crawl()
+	if second.CacheHits() != 3 {
+		t.Errorf("Expected all 3 pages to be served from cache, got %d", second.CacheHits())
+	}
+	if len(secondURLs) != len(firstURLs) {
+		t.Errorf("Expected the cached crawl to find the same %d URLs, got %v", len(firstURLs), secondURLs)
+	}
+	if bodiesServed["/"] != 1 {
+		t.Errorf("Expected the home page body to be downloaded once, got %d", bodiesServed["/"])
+	}
+}
+
+func TestStorePageWithoutValidators(t *testing.T) {
+	pageCache, _ := cache.Load(filepath.Join(t.TempDir(), "cache.json"))
+	checker := New(&config.Config{UserAgent: "TestBot/1.0", Timeout: 5 * time.Second})
+	checker.UseCache(pageCache)
+
+	checker.storePage("https://example.com/", http.Header{}, cache.Page{Links: []string{"https://example.com/a"}})
+	if _, ok := pageCache.Page("https://example.com/"); ok {
+		t.Error("Expected a page without validators not to be cached")
+	}
+}
+
+func TestCrawlCacheKeepsCategories(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		etag := `"v1"` + r.URL.Path
+		if r.Header.Get("If-None-Match") == etag {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", etag)
+		w.Header().Set("Content-Type", "text/html")
+		if r.URL.Path == "/" {
+			w.Write([]byte(`<a href="/logo.png">Logo</a><img src="/photo.png"><a href="/about">About</a><a href="/faq#install">Install</a>`))
+		}
+	}))
+	defer server.Close()
+
+	path := filepath.Join(t.TempDir(), "cache.json")
+	crawl := func() map[string]LinkCategory {
+		pageCache, err := cache.Load(path)
+		if err != nil {
+			t.Fatalf("Expected no error loading cache, got %v", err)
+		}
+		checker := New(&config.Config{
+			UserAgent:     "TestBot/1.0",
+			Timeout:       5 * time.Second,
+			CheckElements: []string{"a", "img"},
+			NormalizeURLs: true,
+		})
+		checker.UseCache(pageCache)
+		if _, err := checker.CrawlWebsite(server.URL, 1); err != nil {
+			t.Fatalf("Expected no error crawling, got %v", err)
+		}
+		if err := pageCache.Save(); err != nil {
+			t.Fatalf("Expected no error saving cache, got %v", err)
+		}
+
+		categories := make(map[string]LinkCategory)
+		for _, path := range []string{"/logo.png", "/photo.png", "/about", "/faq"} {
+			link := server.URL + path
+			categories[path] = checker.linkCategory(link, checker.elementFor(link))
+		}
+		return categories
+	}
+
+	fresh := crawl()
+	expected := map[string]LinkCategory{
+		"/logo.png":  CategoryInternal,
+		"/photo.png": CategoryAsset,
+		"/about":     CategoryInternal,
+		"/faq":       CategoryAnchor,
+	}
+	if !reflect.DeepEqual(fresh, expected) {
+		t.Fatalf("Expected %v on a fresh crawl, got %v", expected, fresh)
+	}
+	if cached := crawl(); !reflect.DeepEqual(cached, fresh) {
+		t.Errorf("Expected a cached crawl to categorize links as a fresh one, got %v, expected %v", cached, fresh)
+	}
+}
+
+func TestCrawlCacheWithPageAudits(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		etag := `"v1"` + r.URL.Path
+		if r.Header.Get("If-None-Match") == etag {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", etag)
+		w.Header().Set("Content-Type", "text/html")
+		w.Write([]byte(`<a href="/more">click here</a>`))
+	}))
+	defer server.Close()
+
+	path := filepath.Join(t.TempDir(), "cache.json")
+	crawl := func() *Checker {
+		pageCache, err := cache.Load(path)
+		if err != nil {
+			t.Fatalf("Expected no error loading cache, got %v", err)
+		}
+		checker := New(&config.Config{UserAgent: "TestBot/1.0", Timeout: 5 * time.Second, LinkTextAudit: true})
+		checker.UseCache(pageCache)
+		if _, err := checker.CrawlWebsite(server.URL, 1); err != nil {
+			t.Fatalf("Expected no error crawling, got %v", err)
+		}
+		if err := pageCache.Save(); err != nil {
+			t.Fatalf("Expected no error saving cache, got %v", err)
+		}
+		return checker
+	}
+
+	first, second := crawl(), crawl()
+	if second.CacheHits() != 0 {
+		t.Errorf("Expected no pages reused from the cache while auditing, got %d", second.CacheHits())
+	}
+	if len(first.Issues()) != 1 || !reflect.DeepEqual(second.Issues(), first.Issues()) {
+		t.Errorf("Expected the same page issues on both runs, got %v and %v", first.Issues(), second.Issues())
+	}
+}
@@ -0,0 +1,44 @@
+package checker
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"iter"
+	"strconv"
+	"strings"
+)
+
+// csvHeader names the columns written by WriteCSV
+var csvHeader = []string{"url", "status", "error", "duration", "referrer", "category"}
+
+// WriteCSV writes every result as a row of CSV, for loading into
+// spreadsheets and BI tools. The referrer column lists the pages linking to
+// a result separated by spaces, and the category is its error type, empty
+// for working links. Rows are written as results are ranged over, so they
+// can stream from a ResultStore.
+func WriteCSV(w io.Writer, results iter.Seq[LinkResult]) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(csvHeader); err != nil {
+		return fmt.Errorf("encoding CSV report: %w", err)
+	}
+	for result := range results {
+		status := ""
+		if result.StatusCode > 0 {
+			status = strconv.Itoa(result.StatusCode)
+		}
+		record := []string{
+			result.URL,
+			status,
+			result.Error,
+			result.Duration,
+			strings.Join(result.Sources, " "),
+			string(result.ErrorType),
+		}
+		if err := writer.Write(record); err != nil {
+			return fmt.Errorf("encoding CSV report: %w", err)
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
@@ -0,0 +1,43 @@
+package checker
+
+import (
+	"bytes"
+	"encoding/csv"
+	"reflect"
+	"slices"
+	"testing"
+)
+
+func TestWriteCSV(t *testing.T) {
+	results := []LinkResult{
+		{URL: "https://example.com/", StatusCode: 200, Duration: "12ms"},
+		{
+			URL:        "https://example.com/gone",
+			StatusCode: 404,
+			Error:      "HTTP 404, \"Not Found\"",
+			ErrorType:  ErrorTypeHTTP4xx,
+			Duration:   "8ms",
+			Sources:    []string{"https://example.com/", "https://example.com/about/"},
+		},
+		{URL: "https://example.org/", Error: "request failed: timeout", ErrorType: ErrorTypeTimeout, Duration: "5s"},
+	}
+
+	var buf bytes.Buffer
+	if err := WriteCSV(&buf, slices.Values(results)); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	records, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatalf("Expected valid CSV, got %v", err)
+	}
+
+	expected := [][]string{
+		{"url", "status", "error", "duration", "referrer", "category"},
+		{"https://example.com/", "200", "", "12ms", "", ""},
+		{"https://example.com/gone", "404", "HTTP 404, \"Not Found\"", "8ms", "https://example.com/ https://example.com/about/", "http_4xx"},
+		{"https://example.org/", "", "request failed: timeout", "5s", "", "timeout"},
+	}
+	if !reflect.DeepEqual(records, expected) {
+		t.Errorf("Expected %v, got %v", expected, records)
+	}
+}
@@ -0,0 +1,144 @@
+package checker
+
+import (
+	"context"
+	"crypto/tls"
+	"net"
+	"net/http"
+	"net/http/httptrace"
+	"net/url"
+	"sync"
+	"time"
+)
+
+// Diagnosis is a detailed account of checking a single URL, for working out
+// why a link is reported the way it is
+type Diagnosis struct {
+	URL       string
+	Addresses []string
+	DNSError  string
+	Requests  []DiagnosticRequest
+	Result    LinkResult
+}
+
+// DiagnosticRequest is one HTTP request made while checking a URL. Redirects,
+// HEAD to GET fallbacks and retries each show up as a separate request.
+type DiagnosticRequest struct {
+	Method     string
+	URL        string
+	StatusCode int
+	Error      string
+	Header     http.Header
+	Reused     bool
+	Timings    RequestTimings
+}
+
+// RequestTimings breaks a request's duration down into its phases. Phases
+// that didn't happen, such as DNS on a reused connection, are zero.
+type RequestTimings struct {
+	DNS       time.Duration
+	Connect   time.Duration
+	TLS       time.Duration
+	FirstByte time.Duration
+	Total     time.Duration
+}
+
+// Diagnose checks a single URL the same way a run would, recording its DNS
+// answers and every request made on the way to the final result
+func (c *Checker) Diagnose(rawURL string) *Diagnosis {
+	d := &Diagnosis{URL: rawURL}
+	if u, err := url.Parse(rawURL); err == nil && u.Hostname() != "" {
+		ctx, cancel := context.WithTimeout(context.Background(), c.config.Timeout)
+		addresses, err := net.DefaultResolver.LookupHost(ctx, u.Hostname())
+		cancel()
+		if err != nil {
+			d.DNSError = err.Error()
+		}
+		d.Addresses = addresses
+	}
+
+	original := c.client.Transport
+	transport := &diagnosticTransport{base: original, diagnosis: d}
+	if transport.base == nil {
+		transport.base = http.DefaultTransport
+	}
+	c.client.Transport = transport
+	defer func() { c.client.Transport = original }()
+
+	d.Result = c.checkSingleLink(rawURL)
+	return d
+}
+
+// diagnosticTransport records each request it carries in a Diagnosis
+type diagnosticTransport struct {
+	base      http.RoundTripper
+	diagnosis *Diagnosis
+	mu        sync.Mutex
+}
+
+func (t *diagnosticTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	record := DiagnosticRequest{Method: req.Method, URL: req.URL.String()}
+	var dnsStart, connectStart, tlsStart time.Time
+	start := time.Now()
+
+	var mu sync.Mutex
+	trace := &httptrace.ClientTrace{
+		DNSStart: func(httptrace.DNSStartInfo) {
+			mu.Lock()
+			dnsStart = time.Now()
+			mu.Unlock()
+		},
+		DNSDone: func(httptrace.DNSDoneInfo) {
+			mu.Lock()
+			record.Timings.DNS = time.Since(dnsStart)
+			mu.Unlock()
+		},
+		ConnectStart: func(string, string) {
+			mu.Lock()
+			connectStart = time.Now()
+			mu.Unlock()
+		},
+		ConnectDone: func(string, string, error) {
+			mu.Lock()
+			record.Timings.Connect = time.Since(connectStart)
+			mu.Unlock()
+		},
+		TLSHandshakeStart: func() {
+			mu.Lock()
+			tlsStart = time.Now()
+			mu.Unlock()
+		},
+		TLSHandshakeDone: func(tls.ConnectionState, error) {
+			mu.Lock()
+			record.Timings.TLS = time.Since(tlsStart)
+			mu.Unlock()
+		},
+		GotConn: func(info httptrace.GotConnInfo) {
+			mu.Lock()
+			record.Reused = info.Reused
+			mu.Unlock()
+		},
+		GotFirstResponseByte: func() {
+			mu.Lock()
+			record.Timings.FirstByte = time.Since(start)
+			mu.Unlock()
+		},
+	}
+
+	resp, err := t.base.RoundTrip(req.WithContext(httptrace.WithClientTrace(req.Context(), trace)))
+
+	mu.Lock()
+	record.Timings.Total = time.Since(start)
+	if err != nil {
+		record.Error = err.Error()
+	} else {
+		record.StatusCode = resp.StatusCode
+		record.Header = resp.Header.Clone()
+	}
+	mu.Unlock()
+
+	t.mu.Lock()
+	t.diagnosis.Requests = append(t.diagnosis.Requests, record)
+	t.mu.Unlock()
+	return resp, err
+}
@@ -0,0 +1,55 @@
+package checker
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/joshbeard/link-validator/internal/config"
+)
+
+func TestDiagnose(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/new", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Test", "final")
+		w.WriteHeader(http.StatusNotFound)
+	})
+	mux.HandleFunc("/old", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/new", http.StatusMovedPermanently)
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	checker := New(&config.Config{UserAgent: "TestBot/1.0", Timeout: 5 * time.Second, MaxConcurrent: 1})
+	diagnosis := checker.Diagnose(server.URL + "/old")
+
+	if len(diagnosis.Addresses) != 1 || diagnosis.Addresses[0] != "127.0.0.1" || diagnosis.DNSError != "" {
+		t.Errorf("Expected the server address, got %v (%s)", diagnosis.Addresses, diagnosis.DNSError)
+	}
+	if len(diagnosis.Requests) != 2 {
+		t.Fatalf("Expected the redirect and its target, got %+v", diagnosis.Requests)
+	}
+
+	first, second := diagnosis.Requests[0], diagnosis.Requests[1]
+	if first.Method != "HEAD" || first.URL != server.URL+"/old" || first.StatusCode != http.StatusMovedPermanently {
+		t.Errorf("Unexpected first request: %+v", first)
+	}
+	if first.Timings.Connect == 0 || first.Timings.Total < first.Timings.FirstByte {
+		t.Errorf("Expected connect and total timings, got %+v", first.Timings)
+	}
+	if second.URL != server.URL+"/new" || second.StatusCode != http.StatusNotFound || second.Header.Get("X-Test") != "final" {
+		t.Errorf("Unexpected second request: %+v", second)
+	}
+	if !second.Reused {
+		t.Errorf("Expected the redirect to reuse the connection")
+	}
+	if diagnosis.Result.ErrorType != ErrorTypeHTTP4xx || diagnosis.Result.FinalURL != server.URL+"/new" {
+		t.Errorf("Unexpected result: %+v", diagnosis.Result)
+	}
+
+	// The checker's own transport is restored afterwards
+	if checker.client.Transport != nil {
+		t.Errorf("Expected the transport to be restored, got %T", checker.client.Transport)
+	}
+}
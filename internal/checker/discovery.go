@@ -0,0 +1,127 @@
+package checker
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"strings"
+)
+
+// commonSitemapPaths are the standard locations probed for a site's sitemap
+var commonSitemapPaths = []string{"/sitemap.xml", "/sitemap_index.xml"}
+
+// ProbeSitemaps looks for sitemaps listed by Sitemap directives in the site's
+// robots.txt and at the standard locations under baseURL, and returns the
+// URLs they list, so a crawl can be seeded with pages that aren't reachable
+// through the site's navigation
+func (c *Checker) ProbeSitemaps(baseURL string) []string {
+	base, err := url.Parse(baseURL)
+	if err != nil {
+		return nil
+	}
+
+	sitemaps := c.RobotsSitemaps(baseURL)
+	for _, path := range commonSitemapPaths {
+		sitemaps = append(sitemaps, base.ResolveReference(&url.URL{Path: path}).String())
+	}
+
+	var urls []string
+	seen := make(map[string]bool)
+	probed := make(map[string]bool)
+	for _, sitemapURL := range sitemaps {
+		if probed[sitemapURL] {
+			continue
+		}
+		probed[sitemapURL] = true
+
+		found, err := c.GetURLsFromSitemap(sitemapURL)
+		if err != nil {
+			if c.config.Verbose {
+				fmt.Printf("No sitemap at %s: %v\n", sitemapURL, err)
+			}
+			continue
+		}
+		if c.config.Verbose {
+			fmt.Printf("Found %d URLs in sitemap %s\n", len(found), sitemapURL)
+		}
+
+		for _, u := range found {
+			if !seen[u] {
+				seen[u] = true
+				urls = append(urls, u)
+			}
+		}
+	}
+
+	return urls
+}
+
+// ReadURLList reads newline-delimited URLs, skipping blank lines and lines
+// starting with '#'. Relative URLs such as "/docs/" are resolved against
+// baseURL when one is given.
+func ReadURLList(r io.Reader, baseURL string) ([]string, error) {
+	var base *url.URL
+	if baseURL != "" {
+		parsed, err := url.Parse(baseURL)
+		if err != nil {
+			return nil, fmt.Errorf("parsing base URL: %w", err)
+		}
+		base = parsed
+	}
+
+	var urls []string
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if base != nil {
+			if ref, err := url.Parse(line); err == nil {
+				line = base.ResolveReference(ref).String()
+			}
+		}
+		urls = append(urls, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("reading URL list: %w", err)
+	}
+
+	return urls, nil
+}
+
+// ReadURLs reads the URLs to check from a newline-delimited list, such as
+// the output of another tool. Relative URLs are resolved against the base
+// URL when one is configured, and duplicates and excluded URLs are dropped.
+func (c *Checker) ReadURLs(r io.Reader) ([]string, error) {
+	listed, err := ReadURLList(r, c.config.BaseURL)
+	if err != nil {
+		return nil, err
+	}
+
+	var urls []string
+	seen := make(map[string]bool)
+	for _, u := range listed {
+		u = c.normalizeURL(u)
+		if seen[u] || c.shouldExclude(u) {
+			continue
+		}
+		seen[u] = true
+		urls = append(urls, u)
+	}
+	return urls, nil
+}
+
+// LoadSeedsFile reads additional crawl entry points from a file, one URL or
+// site-relative path per line
+func LoadSeedsFile(path, baseURL string) ([]string, error) {
+	f, err := os.Open(path) // #nosec G304 -- path is provided by the user
+	if err != nil {
+		return nil, fmt.Errorf("opening seeds file: %w", err)
+	}
+	defer f.Close()
+
+	return ReadURLList(f, baseURL)
+}
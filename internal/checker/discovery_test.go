@@ -0,0 +1,231 @@
+package checker
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/joshbeard/link-validator/internal/config"
+)
+
+func TestProbeSitemaps(t *testing.T) {
+	var serverURL string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/robots.txt":
+			fmt.Fprint(w, "Sitemap: /pages-sitemap.xml\nSitemap: /sitemap.xml\n")
+		case "/pages-sitemap.xml":
+			w.Header().Set("Content-Type", "application/xml")
+			fmt.Fprintf(w, `<urlset xmlns="http://www.sitemaps.org/schemas/sitemap/0.9">
+  <url><loc>%[1]s/unlinked</loc></url>
+  <url><loc>%[1]s/</loc></url>
+</urlset>`, serverURL)
+		case "/sitemap.xml":
+			w.Header().Set("Content-Type", "application/xml")
+			fmt.Fprintf(w, `<urlset xmlns="http://www.sitemaps.org/schemas/sitemap/0.9">
+  <url><loc>%[1]s/</loc></url>
+  <url><loc>%[1]s/hidden</loc></url>
+</urlset>`, serverURL)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+	serverURL = server.URL
+
+	checker := New(&config.Config{UserAgent: "TestBot/1.0", Timeout: 5 * time.Second, Verbose: true})
+
+	urls := checker.ProbeSitemaps(server.URL + "/some/page")
+	want := []string{server.URL + "/unlinked", server.URL + "/", server.URL + "/hidden"}
+	if !reflect.DeepEqual(urls, want) {
+		t.Errorf("Expected %v from the probed sitemaps, got %v", want, urls)
+	}
+
+	if urls := checker.ProbeSitemaps("://bad"); urls != nil {
+		t.Errorf("Expected no URLs for an invalid base URL, got %v", urls)
+	}
+}
+
+func TestGetURLsFromSitemapIndex(t *testing.T) {
+	var serverURL string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/xml")
+		switch r.URL.Path {
+		case "/sitemap_index.xml":
+			fmt.Fprintf(w, `<?xml version="1.0" encoding="UTF-8"?>
+<sitemapindex xmlns="http://www.sitemaps.org/schemas/sitemap/0.9">
+  <sitemap><loc>%[1]s/pages.xml</loc></sitemap>
+  <sitemap><loc>%[1]s/posts.xml</loc></sitemap>
+  <sitemap><loc>%[1]s/missing.xml</loc></sitemap>
+</sitemapindex>`, serverURL)
+		case "/pages.xml":
+			fmt.Fprintf(w, `<urlset xmlns="http://www.sitemaps.org/schemas/sitemap/0.9">
+  <url><loc>%[1]s/</loc></url>
+  <url><loc>%[1]s/about</loc></url>
+</urlset>`, serverURL)
+		case "/posts.xml":
+			fmt.Fprintf(w, `<urlset xmlns="http://www.sitemaps.org/schemas/sitemap/0.9">
+  <url><loc>%[1]s/about</loc></url>
+  <url><loc>%[1]s/blog/post</loc></url>
+</urlset>`, serverURL)
+		case "/loop.xml":
+			fmt.Fprintf(w, `<sitemapindex xmlns="http://www.sitemaps.org/schemas/sitemap/0.9">
+  <sitemap><loc>%[1]s/loop.xml</loc></sitemap>
+</sitemapindex>`, serverURL)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+	serverURL = server.URL
+
+	checker := New(&config.Config{UserAgent: "TestBot/1.0", Timeout: 5 * time.Second})
+
+	t.Run("follows child sitemaps", func(t *testing.T) {
+		urls, err := checker.GetURLsFromSitemap(server.URL + "/sitemap_index.xml")
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		expected := []string{server.URL + "/", server.URL + "/about", server.URL + "/blog/post"}
+		if len(urls) != len(expected) {
+			t.Fatalf("Expected %d URLs, got %d: %v", len(expected), len(urls), urls)
+		}
+		for i := range expected {
+			if urls[i] != expected[i] {
+				t.Errorf("Expected URL %s at index %d, got %s", expected[i], i, urls[i])
+			}
+		}
+	})
+
+	t.Run("nested indexes are bounded", func(t *testing.T) {
+		urls, err := checker.GetURLsFromSitemap(server.URL + "/loop.xml")
+		if err != nil {
+			t.Fatalf("Expected the loop to be cut off without failing the index, got %v", err)
+		}
+		if len(urls) != 0 {
+			t.Errorf("Expected no URLs from a looping index, got %v", urls)
+		}
+	})
+}
+
+func TestCrawlWebsiteWithSeeds(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		switch r.URL.Path {
+		case "/":
+			w.Write([]byte(`<a href="/about">About</a>`))
+		case "/about":
+			w.Write([]byte(`<p>About</p>`))
+		case "/docs/":
+			w.Write([]byte(`<a href="/docs/intro">Intro</a>`))
+		case "/docs/intro":
+			w.Write([]byte(`<p>Intro</p>`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	checker := New(&config.Config{UserAgent: "TestBot/1.0", Timeout: 5 * time.Second})
+
+	urls, err := checker.CrawlWebsiteWithSeeds(server.URL, []string{
+		server.URL + "/docs/",
+		"https://other.example.com/docs/",
+	}, 1)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	found := make(map[string]bool)
+	for _, u := range urls {
+		found[u] = true
+	}
+	for _, expected := range []string{server.URL, server.URL + "/about", server.URL + "/docs/", server.URL + "/docs/intro"} {
+		if !found[expected] {
+			t.Errorf("Expected %s to be crawled, got %v", expected, urls)
+		}
+	}
+	if found["https://other.example.com/docs/"] {
+		t.Error("Expected seeds on other hosts to be ignored")
+	}
+}
+
+func TestReadURLList(t *testing.T) {
+	input := `# Sections not linked from the home page
+/docs/
+
+  /blog/
+https://example.com/absolute
+   # indented comment
+`
+
+	t.Run("resolves against base URL", func(t *testing.T) {
+		urls, err := ReadURLList(strings.NewReader(input), "https://example.com/")
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		expected := []string{"https://example.com/docs/", "https://example.com/blog/", "https://example.com/absolute"}
+		if len(urls) != len(expected) {
+			t.Fatalf("Expected %d URLs, got %d: %v", len(expected), len(urls), urls)
+		}
+		for i := range expected {
+			if urls[i] != expected[i] {
+				t.Errorf("Expected URL %s at index %d, got %s", expected[i], i, urls[i])
+			}
+		}
+	})
+
+	t.Run("without base URL", func(t *testing.T) {
+		urls, err := ReadURLList(strings.NewReader(input), "")
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if len(urls) != 3 || urls[0] != "/docs/" {
+			t.Errorf("Expected unresolved URLs, got %v", urls)
+		}
+	})
+}
+
+func TestLoadSeedsFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "seeds.txt")
+	if err := os.WriteFile(path, []byte("/docs/\n/blog/\n"), 0o600); err != nil {
+		t.Fatalf("Failed to write seeds file: %v", err)
+	}
+
+	seeds, err := LoadSeedsFile(path, "https://example.com")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(seeds) != 2 || seeds[0] != "https://example.com/docs/" {
+		t.Errorf("Unexpected seeds: %v", seeds)
+	}
+
+	if _, err := LoadSeedsFile(filepath.Join(t.TempDir(), "missing.txt"), ""); err == nil {
+		t.Error("Expected an error for a missing seeds file")
+	}
+}
+
+func TestReadURLs(t *testing.T) {
+	checker := New(&config.Config{
+		BaseURL:         "https://example.com",
+		ExcludePatterns: config.ParsePatterns("/private/"),
+	})
+	list := "https://example.org/a\n# comment\n/docs/\n\nhttps://example.org/a\nhttps://example.com/private/x\n"
+
+	urls, err := checker.ReadURLs(strings.NewReader(list))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	expected := []string{"https://example.org/a", "https://example.com/docs/"}
+	if !reflect.DeepEqual(urls, expected) {
+		t.Errorf("Expected %v, got %v", expected, urls)
+	}
+}
@@ -0,0 +1,83 @@
+package checker
+
+import (
+	"fmt"
+	"io"
+	"net/url"
+
+	"golang.org/x/net/html"
+)
+
+// ExtractLinks returns the links of an HTML document read from r, such as a
+// rendered template or email, so they can be checked without crawling.
+// Relative links are resolved against baseURL and the document's <base> tag,
+// and are dropped when neither gives an absolute URL. Unlike a crawl, links
+// to any host are returned.
+func (c *Checker) ExtractLinks(r io.Reader, baseURL string) ([]string, error) {
+	base, err := url.Parse(baseURL)
+	if err != nil {
+		return nil, fmt.Errorf("parsing base URL: %w", err)
+	}
+
+	doc, err := html.Parse(r)
+	if err != nil {
+		return nil, err
+	}
+
+	resolveBase := base
+	var findBase func(*html.Node) bool
+	findBase = func(n *html.Node) bool {
+		if n.Type == html.ElementNode && n.Data == "base" {
+			if href, ok := attr(n, "href"); ok {
+				if ref, err := url.Parse(href); err == nil {
+					resolveBase = base.ResolveReference(ref)
+					return true
+				}
+			}
+		}
+		for child := n.FirstChild; child != nil; child = child.NextSibling {
+			if findBase(child) {
+				return true
+			}
+		}
+		return false
+	}
+	findBase(doc)
+
+	if resolveBase.IsAbs() {
+		c.auditPage(resolveBase.String(), doc, resolveBase)
+	}
+
+	var links []string
+	seen := make(map[string]bool)
+	var extract func(*html.Node)
+	extract = func(n *html.Node) {
+		if c.excludesRegion(n) {
+			return
+		}
+		if n.Type == html.ElementNode && c.checksElement(n.Data) {
+			for _, href := range elementURLs(n) {
+				href = c.repairLink(resolveBase.String(), href)
+				resolved := c.RewritePreview(c.resolveURL(href, resolveBase))
+				link := c.normalizeURL(resolved)
+				c.recordAnchor(link, resolved)
+				if linkURL, err := url.Parse(link); err == nil &&
+					(linkURL.Scheme == "http" || linkURL.Scheme == "https" || c.forbidsScheme(link)) &&
+					!seen[link] && !c.shouldExclude(link) {
+					seen[link] = true
+					links = append(links, link)
+					c.recordElement(link, n.Data)
+					if base.IsAbs() {
+						c.recordSource(link, base.String())
+					}
+				}
+			}
+		}
+		for child := n.FirstChild; child != nil; child = child.NextSibling {
+			extract(child)
+		}
+	}
+	extract(doc)
+
+	return links, nil
+}
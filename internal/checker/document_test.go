@@ -0,0 +1,64 @@
+package checker
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/joshbeard/link-validator/internal/config"
+)
+
+func TestExtractLinks(t *testing.T) {
+	checker := New(&config.Config{
+		MaxConcurrent:   1,
+		ExcludePatterns: []*regexp.Regexp{regexp.MustCompile(`unsubscribe`)},
+	})
+
+	doc := `<html><body>
+<a href="/pricing">Pricing</a>
+<a href="docs/start">Start</a>
+<a href="https://other.example.org/page">Other</a>
+<a href="/pricing">Pricing again</a>
+<a href="mailto:team@example.com">Mail</a>
+<a href="#top">Top</a>
+<a href="https://example.com/unsubscribe">Unsubscribe</a>
+</body></html>`
+
+	links, err := checker.ExtractLinks(strings.NewReader(doc), "https://example.com/emails/")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	want := []string{
+		"https://example.com/pricing",
+		"https://example.com/emails/docs/start",
+		"https://other.example.org/page",
+	}
+	if strings.Join(links, " ") != strings.Join(want, " ") {
+		t.Errorf("Expected %v, got %v", want, links)
+	}
+	if sources := checker.Sources("https://other.example.org/page"); len(sources) != 1 || sources[0] != "https://example.com/emails/" {
+		t.Errorf("Expected the base URL as the source, got %v", sources)
+	}
+}
+
+func TestExtractLinksWithoutBase(t *testing.T) {
+	checker := New(&config.Config{MaxConcurrent: 1})
+
+	doc := `<a href="/relative">Relative</a><a href="https://example.com/">Absolute</a>`
+	links, err := checker.ExtractLinks(strings.NewReader(doc), "")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(links) != 1 || links[0] != "https://example.com/" {
+		t.Errorf("Expected only the absolute link, got %v", links)
+	}
+
+	doc = `<head><base href="https://example.com/docs/"></head><a href="guide">Guide</a>`
+	links, err = checker.ExtractLinks(strings.NewReader(doc), "")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(links) != 1 || links[0] != "https://example.com/docs/guide" {
+		t.Errorf("Expected the link resolved against the base tag, got %v", links)
+	}
+}
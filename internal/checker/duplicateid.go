@@ -0,0 +1,47 @@
+package checker
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// idCounter counts the id attributes on a page in document order
+type idCounter struct {
+	counts map[string]int
+	order  []string
+}
+
+func newIDCounter() *idCounter {
+	return &idCounter{counts: make(map[string]int)}
+}
+
+func (ids *idCounter) add(id string) {
+	id = strings.TrimSpace(id)
+	if id == "" {
+		return
+	}
+	if ids.counts[id] == 0 {
+		ids.order = append(ids.order, id)
+	}
+	ids.counts[id]++
+}
+
+// reportDuplicateIDs flags ids used more than once on a page. Browsers
+// scroll to the first element with a fragment's id, so links to the later
+// ones silently land in the wrong place.
+func (c *Checker) reportDuplicateIDs(pageURL string, ids *idCounter) {
+	for _, id := range ids.order {
+		count := ids.counts[id]
+		if count < 2 {
+			continue
+		}
+		link := pageURL
+		if u, err := url.Parse(pageURL); err == nil {
+			u.Fragment = id
+			link = u.String()
+		}
+		c.recordIssue(PageIssue{Page: pageURL, Type: IssueDuplicateID, Link: link,
+			Detail: fmt.Sprintf("id %q is used %d times", id, count)})
+	}
+}
@@ -0,0 +1,50 @@
+package checker
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/joshbeard/link-validator/internal/config"
+	"golang.org/x/net/html"
+)
+
+func TestDuplicateIDAudit(t *testing.T) {
+	markup := `<html><body>
+<h2 id="install">Install</h2>
+<h2 id="usage">Usage</h2>
+<div id="install"></div>
+<section id="install"><span id=" "></span><span id=""></span></section>
+</body></html>`
+	doc, err := html.Parse(strings.NewReader(markup))
+	if err != nil {
+		t.Fatal(err)
+	}
+	base, _ := url.Parse("https://example.com/docs/")
+
+	t.Run("reports duplicates", func(t *testing.T) {
+		checker := New(&config.Config{MaxConcurrent: 1, DuplicateIDAudit: true})
+		checker.auditPage("https://example.com/docs/", doc, base)
+
+		issues := checker.Issues()
+		if len(issues) != 1 {
+			t.Fatalf("Expected one issue, got %+v", issues)
+		}
+		issue := issues[0]
+		if issue.Type != IssueDuplicateID || issue.Link != "https://example.com/docs/#install" {
+			t.Errorf("Unexpected issue: %+v", issue)
+		}
+		if !strings.Contains(issue.Detail, "3 times") {
+			t.Errorf("Expected the duplicate count in the detail, got %q", issue.Detail)
+		}
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		checker := New(&config.Config{MaxConcurrent: 1})
+		checker.auditPage("https://example.com/docs/", doc, base)
+
+		if issues := checker.Issues(); len(issues) != 0 {
+			t.Errorf("Expected no issues, got %+v", issues)
+		}
+	})
+}
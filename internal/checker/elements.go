@@ -0,0 +1,166 @@
+package checker
+
+import (
+	"fmt"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// elementAttributes lists the attributes holding URLs for each element type
+// whose links can be checked
+var elementAttributes = map[string][]string{
+	"a":      {"href"},
+	"img":    {"src", "srcset"},
+	"script": {"src"},
+	"link":   {"href"},
+	"source": {"src", "srcset"},
+	"iframe": {"src"},
+	"video":  {"src", "poster"},
+	"audio":  {"src"},
+}
+
+// originOnlyRels are <link> relations that name an origin to connect to
+// early rather than a resource, so there is nothing to check
+var originOnlyRels = map[string]bool{
+	"preconnect":   true,
+	"dns-prefetch": true,
+}
+
+// CheckableElement reports whether links in elements of the given type can be
+// checked
+func CheckableElement(name string) bool {
+	_, ok := elementAttributes[strings.ToLower(name)]
+	return ok
+}
+
+// checksElement reports whether links in elements of the given type are
+// checked. Only <a> links are checked unless CheckElements says otherwise.
+func (c *Checker) checksElement(name string) bool {
+	if len(c.config.CheckElements) == 0 {
+		return name == "a"
+	}
+	for _, element := range c.config.CheckElements {
+		if strings.EqualFold(element, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// excludesRegion reports whether an element matches one of the
+// ExcludeSelectors, so that the links in it and everything inside it are
+// skipped
+func (c *Checker) excludesRegion(n *html.Node) bool {
+	if n.Type != html.ElementNode {
+		return false
+	}
+	for _, selector := range c.config.ExcludeSelectors {
+		if matchesSelector(n, selector) {
+			return true
+		}
+	}
+	return false
+}
+
+// ValidateSelectors returns an error for the first selector outside the
+// supported subset: a tag name, #id, .class and [attr] or [attr=value]
+// conditions, combined without spaces
+func ValidateSelectors(selectors []string) error {
+	for _, selector := range selectors {
+		selector = strings.TrimSpace(selector)
+		if strings.ContainsAny(selector, " \t>+~,") {
+			return fmt.Errorf("selector %q: combinators aren't supported: %w", selector, errInvalidSelector)
+		}
+		if _, _, err := parseSelector(selector); err != nil {
+			return fmt.Errorf("selector %q: %w", selector, err)
+		}
+	}
+	return nil
+}
+
+// elementURLs returns the URLs an element refers to, in attribute order.
+// Each candidate of a srcset is returned.
+func elementURLs(n *html.Node) []string {
+	attributes, ok := elementAttributes[n.Data]
+	if !ok {
+		return nil
+	}
+	if n.Data == "link" {
+		if rel, ok := attr(n, "rel"); ok && originOnlyRels[strings.ToLower(strings.TrimSpace(rel))] {
+			return nil
+		}
+	}
+
+	var urls []string
+	for _, name := range attributes {
+		value, ok := attr(n, name)
+		if !ok {
+			continue
+		}
+		if name == "srcset" {
+			urls = append(urls, parseSrcset(value)...)
+		} else {
+			urls = append(urls, value)
+		}
+	}
+	return urls
+}
+
+// parseSrcset returns the URLs of a srcset attribute, dropping their width
+// and density descriptors
+func parseSrcset(value string) []string {
+	var urls []string
+	for _, candidate := range strings.Split(value, ",") {
+		if fields := strings.Fields(candidate); len(fields) > 0 {
+			urls = append(urls, fields[0])
+		}
+	}
+	return urls
+}
+
+// recordElement remembers the type of element a link was first found in
+func (c *Checker) recordElement(link, element string) {
+	id, ok := c.urls.id(link)
+	if !ok {
+		return
+	}
+
+	c.inventoryMu.Lock()
+	defer c.inventoryMu.Unlock()
+	if _, ok := c.elements[id]; !ok {
+		c.elements[id] = element
+	}
+}
+
+// elementFor returns the type of element a link was first found in
+func (c *Checker) elementFor(link string) string {
+	id, ok := c.urls.lookup(link)
+	if !ok {
+		return ""
+	}
+
+	c.inventoryMu.Lock()
+	defer c.inventoryMu.Unlock()
+	return c.elements[id]
+}
+
+// recordAsset remembers a resource on the crawled site, such as an image or
+// script, so that it is checked without being crawled
+func (c *Checker) recordAsset(link, pageURL string) {
+	if c.shouldExclude(link) || c.disallowedByRobots(link) {
+		return
+	}
+	c.recordSource(link, pageURL)
+	id, ok := c.urls.id(link)
+	if !ok {
+		return
+	}
+
+	c.inventoryMu.Lock()
+	defer c.inventoryMu.Unlock()
+	if !c.assets[id] {
+		c.assets[id] = true
+		c.assetOrder = append(c.assetOrder, id)
+	}
+}
@@ -0,0 +1,165 @@
+package checker
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/joshbeard/link-validator/internal/config"
+	"golang.org/x/net/html"
+)
+
+func TestElementURLs(t *testing.T) {
+	tests := []struct {
+		name     string
+		html     string
+		expected []string
+	}{
+		{"anchor", `<a href="/docs/">Docs</a>`, []string{"/docs/"}},
+		{"image with srcset", `<img src="/a.png" srcset="/a-2x.png 2x, /a-600.png 600w">`, []string{"/a.png", "/a-2x.png", "/a-600.png"}},
+		{"script", `<script src="/app.js"></script>`, []string{"/app.js"}},
+		{"stylesheet", `<link rel="stylesheet" href="/style.css">`, []string{"/style.css"}},
+		{"preconnect", `<link rel="preconnect" href="https://fonts.example">`, nil},
+		{"video", `<video src="/clip.mp4" poster="/poster.jpg"></video>`, []string{"/clip.mp4", "/poster.jpg"}},
+		{"span", `<span>text</span>`, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc, err := html.Parse(strings.NewReader(tt.html))
+			if err != nil {
+				t.Fatalf("Failed to parse HTML: %v", err)
+			}
+			var got []string
+			var walk func(n *html.Node)
+			walk = func(n *html.Node) {
+				if n.Type == html.ElementNode {
+					got = append(got, elementURLs(n)...)
+				}
+				for child := n.FirstChild; child != nil; child = child.NextSibling {
+					walk(child)
+				}
+			}
+			walk(doc)
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("Expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestCrawlChecksElements(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/":
+			w.Header().Set("Content-Type", "text/html")
+			fmt.Fprint(w, `<html><head><link rel="stylesheet" href="/style.css"></head>
+<body><a href="/about/">About</a><img src="/missing.png"></body></html>`)
+		case "/about/":
+			w.Header().Set("Content-Type", "text/html")
+			fmt.Fprint(w, `<img src="/missing.png">`)
+		case "/style.css":
+			w.Header().Set("Content-Type", "text/css")
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	t.Run("anchors only by default", func(t *testing.T) {
+		checker := New(&config.Config{UserAgent: "TestBot/1.0", Timeout: 5 * time.Second, MaxConcurrent: 1})
+		urls, err := checker.CrawlWebsite(server.URL+"/", 2)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if expected := []string{server.URL + "/", server.URL + "/about/"}; !reflect.DeepEqual(urls, expected) {
+			t.Errorf("Expected %v, got %v", expected, urls)
+		}
+	})
+
+	t.Run("with images and stylesheets", func(t *testing.T) {
+		checker := New(&config.Config{
+			UserAgent:     "TestBot/1.0",
+			Timeout:       5 * time.Second,
+			MaxConcurrent: 1,
+			CheckElements: []string{"a", "img", "link"},
+		})
+		urls, err := checker.CrawlWebsite(server.URL+"/", 2)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		expected := []string{server.URL + "/", server.URL + "/about/", server.URL + "/style.css", server.URL + "/missing.png"}
+		if !reflect.DeepEqual(urls, expected) {
+			t.Fatalf("Expected %v, got %v", expected, urls)
+		}
+
+		results := checker.CheckLinks(urls)
+		missing := results[3]
+		if missing.Element != "img" || missing.ErrorType != ErrorTypeHTTP4xx || missing.SourceCount != 2 {
+			t.Errorf("Expected a broken image linked from 2 pages, got %+v", missing)
+		}
+		if results[2].Element != "link" || results[2].Error != "" {
+			t.Errorf("Expected a working stylesheet, got %+v", results[2])
+		}
+		if results[1].Element != "a" {
+			t.Errorf("Expected the page to be found in an anchor, got %q", results[1].Element)
+		}
+	})
+}
+
+func TestCheckableElement(t *testing.T) {
+	for _, name := range []string{"a", "IMG", "source"} {
+		if !CheckableElement(name) {
+			t.Errorf("Expected %s to be checkable", name)
+		}
+	}
+	if CheckableElement("span") {
+		t.Error("Expected span not to be checkable")
+	}
+}
+
+func TestExcludeSelectors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		if r.URL.Path == "/" {
+			fmt.Fprint(w, `<nav><ul><li><a href="/menu">Menu</a></li></ul></nav>
+<main>
+  <a href="/content">Content</a>
+  <p>Try <a href="/demo" data-nolink>the demo</a>.</p>
+</main>
+<div class="site footer"><a href="/legal">Legal</a></div>`)
+		}
+	}))
+	defer server.Close()
+
+	checker := New(&config.Config{
+		UserAgent:        "TestBot/1.0",
+		Timeout:          5 * time.Second,
+		MaxConcurrent:    1,
+		ExcludeSelectors: []string{"nav", ".footer", "[data-nolink]"},
+	})
+	urls, err := checker.CrawlWebsite(server.URL+"/", 2)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	expected := []string{server.URL + "/", server.URL + "/content"}
+	if !reflect.DeepEqual(urls, expected) {
+		t.Errorf("Expected %v, got %v", expected, urls)
+	}
+}
+
+func TestValidateSelectors(t *testing.T) {
+	if err := ValidateSelectors([]string{"nav", " .footer", "a[rel=nofollow]", "#sidebar"}); err != nil {
+		t.Errorf("Expected valid selectors, got %v", err)
+	}
+	for _, selector := range []string{"[unclosed", "nav a", "ul>li"} {
+		if err := ValidateSelectors([]string{"nav", selector}); err == nil || !strings.Contains(err.Error(), selector) {
+			t.Errorf("Expected an error naming %q, got %v", selector, err)
+		}
+	}
+}
@@ -0,0 +1,106 @@
+package checker
+
+import (
+	"bufio"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+
+	"github.com/andybalholm/brotli"
+)
+
+// acceptEncoding lists the content codings the checker can decode. Setting it
+// explicitly turns off the transport's transparent gzip handling, so the
+// Content-Encoding and Content-Length of the response are kept as sent and
+// bodies are decoded with decodeBody.
+const acceptEncoding = "gzip, deflate, br"
+
+// decodeBody returns a reader of the response body with its content codings
+// removed. Codings are undone in the reverse of the order they were applied.
+func decodeBody(resp *http.Response) (io.Reader, error) {
+	var body io.Reader = resp.Body
+	codings := strings.Split(resp.Header.Get("Content-Encoding"), ",")
+	for i := len(codings) - 1; i >= 0; i-- {
+		switch coding := strings.ToLower(strings.TrimSpace(codings[i])); coding {
+		case "", "identity":
+		case "gzip", "x-gzip":
+			reader, err := gzip.NewReader(body)
+			if err != nil {
+				return nil, fmt.Errorf("decoding gzip body: %w", err)
+			}
+			body = reader
+		case "deflate":
+			reader, err := newDeflateReader(body)
+			if err != nil {
+				return nil, fmt.Errorf("decoding deflate body: %w", err)
+			}
+			body = reader
+		case "br":
+			body = brotli.NewReader(body)
+		default:
+			return nil, fmt.Errorf("unsupported content encoding %q", coding)
+		}
+	}
+	return body, nil
+}
+
+// decompressFile decompresses a body that is itself a compressed file, such
+// as a sitemap.xml.gz or sitemap.xml.br, which servers send without a
+// Content-Encoding. Gzip files are recognized by their magic number; brotli
+// has none, so brotli files are recognized by their .br extension or content
+// type. Other bodies are returned as they are.
+func decompressFile(resp *http.Response, body io.Reader) (io.Reader, error) {
+	if isBrotliFile(resp) {
+		return brotli.NewReader(body), nil
+	}
+	buffered := bufio.NewReader(body)
+	magic, err := buffered.Peek(2)
+	if err != nil || magic[0] != 0x1f || magic[1] != 0x8b {
+		return buffered, nil
+	}
+	reader, err := gzip.NewReader(buffered)
+	if err != nil {
+		return nil, fmt.Errorf("decoding gzip file: %w", err)
+	}
+	return reader, nil
+}
+
+// isBrotliFile reports whether a response is a brotli file by its content
+// type or, for servers that send a generic type, the extension of its URL
+func isBrotliFile(resp *http.Response) bool {
+	mediaType, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	switch mediaType {
+	case "application/x-brotli", "application/brotli":
+		return true
+	case "", "application/octet-stream":
+		return resp.Request != nil && strings.HasSuffix(resp.Request.URL.Path, ".br")
+	}
+	return false
+}
+
+// newDeflateReader decodes an HTTP deflate body. The coding is defined as a
+// zlib stream, but some servers send raw deflate data, so the zlib header is
+// checked before choosing a decoder.
+func newDeflateReader(body io.Reader) (io.Reader, error) {
+	buffered := bufio.NewReader(body)
+	header, err := buffered.Peek(2)
+	if err == nil && header[0]&0x0f == 8 && (uint16(header[0])<<8|uint16(header[1]))%31 == 0 {
+		return zlib.NewReader(buffered)
+	}
+	return flate.NewReader(buffered), nil
+}
+
+// recordEncoding stores the content coding and transfer size of a response
+// in its result. The size is the Content-Length as sent, so it is unknown for
+// chunked responses.
+func recordEncoding(result *LinkResult, resp *http.Response) {
+	result.ContentEncoding = resp.Header.Get("Content-Encoding")
+	if resp.ContentLength > 0 {
+		result.TransferSize = resp.ContentLength
+	}
+}
@@ -0,0 +1,196 @@
+package checker
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/andybalholm/brotli"
+	"github.com/joshbeard/link-validator/internal/config"
+)
+
+func TestDecodeBody(t *testing.T) {
+	const page = `<a href="/docs/">Docs</a>`
+
+	compress := func(newWriter func(io.Writer) io.WriteCloser) []byte {
+		var buf bytes.Buffer
+		w := newWriter(&buf)
+		w.Write([]byte(page))
+		w.Close()
+		return buf.Bytes()
+	}
+	gzipped := compress(func(w io.Writer) io.WriteCloser { return gzip.NewWriter(w) })
+	zlibbed := compress(func(w io.Writer) io.WriteCloser { return zlib.NewWriter(w) })
+	rawDeflate := compress(func(w io.Writer) io.WriteCloser {
+		fw, _ := flate.NewWriter(w, flate.DefaultCompression)
+		return fw
+	})
+	brotlied := compress(func(w io.Writer) io.WriteCloser { return brotli.NewWriter(w) })
+
+	testCases := []struct {
+		encoding string
+		body     []byte
+	}{
+		{"", []byte(page)},
+		{"identity", []byte(page)},
+		{"gzip", gzipped},
+		{"GZIP", gzipped},
+		{"deflate", zlibbed},
+		{"deflate", rawDeflate},
+		{"br", brotlied},
+		{"gzip, br", func() []byte {
+			var buf bytes.Buffer
+			w := brotli.NewWriter(&buf)
+			w.Write(gzipped)
+			w.Close()
+			return buf.Bytes()
+		}()},
+	}
+
+	for _, tc := range testCases {
+		resp := &http.Response{Header: http.Header{}, Body: io.NopCloser(bytes.NewReader(tc.body))}
+		resp.Header.Set("Content-Encoding", tc.encoding)
+		body, err := decodeBody(resp)
+		if err != nil {
+			t.Errorf("%q: expected no error, got %v", tc.encoding, err)
+			continue
+		}
+		decoded, err := io.ReadAll(body)
+		if err != nil || string(decoded) != page {
+			t.Errorf("%q: expected %q, got %q (%v)", tc.encoding, page, decoded, err)
+		}
+	}
+
+	resp := &http.Response{Header: http.Header{"Content-Encoding": {"zstd"}}, Body: io.NopCloser(bytes.NewReader(nil))}
+	if _, err := decodeBody(resp); err == nil {
+		t.Error("Expected an error for an unsupported encoding")
+	}
+}
+
+func TestCrawlDecodesCompressedPages(t *testing.T) {
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	gz.Write([]byte(`<a href="/docs/">Docs</a>`))
+	gz.Close()
+	compressed := buf.Bytes()
+
+	var brBuf bytes.Buffer
+	br := brotli.NewWriter(&brBuf)
+	br.Write([]byte(`<a href="/guide/">Guide</a>`))
+	br.Close()
+
+	var acceptEncoding string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		if r.URL.Path == "/" {
+			acceptEncoding = r.Header.Get("Accept-Encoding")
+			w.Header().Set("Content-Encoding", "gzip")
+			w.Header().Set("Content-Length", strconv.Itoa(len(compressed)))
+			w.Write(compressed)
+		}
+		if r.URL.Path == "/docs/" {
+			w.Header().Set("Content-Encoding", "br")
+			w.Write(brBuf.Bytes())
+		}
+	}))
+	defer server.Close()
+
+	checker := New(&config.Config{UserAgent: "TestBot/1.0", Timeout: 5 * time.Second, MaxConcurrent: 1})
+	urls, err := checker.CrawlWebsite(server.URL+"/", 3)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if acceptEncoding != "gzip, deflate, br" {
+		t.Errorf("Expected Accept-Encoding gzip, deflate, br, got %q", acceptEncoding)
+	}
+	if len(urls) != 3 || urls[1] != server.URL+"/docs/" || urls[2] != server.URL+"/guide/" {
+		t.Errorf("Expected the links in the gzipped and brotli pages to be found, got %v", urls)
+	}
+
+	results := checker.CheckLinks([]string{server.URL + "/"})
+	if results[0].ContentEncoding != "gzip" || results[0].TransferSize != int64(len(compressed)) {
+		t.Errorf("Expected gzip encoding and transfer size %d, got %q and %d",
+			len(compressed), results[0].ContentEncoding, results[0].TransferSize)
+	}
+}
+
+func TestCompressedSitemaps(t *testing.T) {
+	gzipBytes := func(data []byte) []byte {
+		var buf bytes.Buffer
+		gz := gzip.NewWriter(&buf)
+		gz.Write(data)
+		gz.Close()
+		return buf.Bytes()
+	}
+	sitemap := []byte(`<?xml version="1.0" encoding="UTF-8"?>
+<urlset xmlns="http://www.sitemaps.org/schemas/sitemap/0.9"><url><loc>https://example.com/docs/</loc></url></urlset>`)
+	compressed := gzipBytes(sitemap)
+	var brBuf bytes.Buffer
+	br := brotli.NewWriter(&brBuf)
+	br.Write(sitemap)
+	br.Close()
+	brotlied := brBuf.Bytes()
+
+	var acceptEncoding string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		acceptEncoding = r.Header.Get("Accept-Encoding")
+		switch r.URL.Path {
+		case "/sitemap.xml.gz":
+			w.Header().Set("Content-Type", "application/gzip")
+			w.Write(compressed)
+		case "/encoded.xml":
+			w.Header().Set("Content-Type", "application/xml")
+			w.Header().Set("Content-Encoding", "gzip")
+			w.Write(compressed)
+		case "/encoded.xml.gz":
+			// The .gz file compressed again in transit
+			w.Header().Set("Content-Type", "application/gzip")
+			w.Header().Set("Content-Encoding", "gzip")
+			w.Write(gzipBytes(compressed))
+		case "/brotli.xml":
+			w.Header().Set("Content-Type", "application/xml")
+			w.Header().Set("Content-Encoding", "br")
+			w.Write(brotlied)
+		case "/sitemap.xml.br":
+			w.Header().Set("Content-Type", "application/octet-stream")
+			w.Write(brotlied)
+		case "/typed-brotli":
+			w.Header().Set("Content-Type", "application/x-brotli")
+			w.Write(brotlied)
+		case "/corrupt.xml.br":
+			w.Header().Set("Content-Type", "application/octet-stream")
+			w.Write([]byte("not brotli"))
+		case "/corrupt.xml.gz":
+			w.Write([]byte{0x1f, 0x8b, 0x00})
+		}
+	}))
+	defer server.Close()
+
+	checker := New(&config.Config{UserAgent: "TestBot/1.0", Timeout: 5 * time.Second, MaxConcurrent: 1})
+	for _, path := range []string{"/sitemap.xml.gz", "/encoded.xml", "/encoded.xml.gz", "/brotli.xml", "/sitemap.xml.br", "/typed-brotli"} {
+		urls, err := checker.GetURLsFromSitemap(server.URL + path)
+		if err != nil {
+			t.Errorf("%s: expected no error, got %v", path, err)
+			continue
+		}
+		if len(urls) != 1 || urls[0] != "https://example.com/docs/" {
+			t.Errorf("%s: expected the sitemap's URL, got %v", path, urls)
+		}
+	}
+	if acceptEncoding != "gzip, deflate, br" {
+		t.Errorf("Expected Accept-Encoding gzip, deflate, br, got %q", acceptEncoding)
+	}
+
+	for _, path := range []string{"/corrupt.xml.gz", "/corrupt.xml.br"} {
+		if _, err := checker.GetURLsFromSitemap(server.URL + path); err == nil {
+			t.Errorf("%s: expected an error for a corrupt compressed sitemap", path)
+		}
+	}
+}
@@ -0,0 +1,238 @@
+package checker
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"io"
+	"net"
+	"strings"
+	"syscall"
+)
+
+// ErrorType classifies why a link check failed so reports and gating logic
+// can distinguish failure classes without parsing error strings
+type ErrorType string
+
+// Error types recorded in LinkResult.ErrorType
+const (
+	ErrorTypeDNS              ErrorType = "dns"
+	ErrorTypeConnect          ErrorType = "connect"
+	ErrorTypeTLS              ErrorType = "tls"
+	ErrorTypeTimeout          ErrorType = "timeout"
+	ErrorTypeTooManyRedirects ErrorType = "too_many_redirects"
+	ErrorTypeHTTP4xx          ErrorType = "http_4xx"
+	ErrorTypeHTTP5xx          ErrorType = "http_5xx"
+	ErrorTypeCancelled        ErrorType = "cancelled"
+	ErrorTypeOther            ErrorType = "other"
+
+	// ErrorTypeSoft404 marks pages answered with a success status whose
+	// content matches a soft 404 pattern, such as "Page not found"
+	ErrorTypeSoft404 ErrorType = "soft_404"
+
+	// ErrorTypeAuthRequired marks links that redirect to a login page. The
+	// target's real status is hidden, so these are flagged but not counted
+	// as broken.
+	ErrorTypeAuthRequired ErrorType = "auth_required"
+
+	// ErrorTypeBotChallenge marks links answered with a bot protection
+	// challenge page. Like auth_required, these are flagged but not counted
+	// as broken.
+	ErrorTypeBotChallenge ErrorType = "bot_challenge"
+
+	// ErrorTypeRedirected marks links that redirect elsewhere, when
+	// WarnOnRedirect is set. They work, so they are flagged but not counted
+	// as broken.
+	ErrorTypeRedirected ErrorType = "redirected"
+
+	// ErrorTypeRedirectLoop marks links whose redirects lead back to a URL
+	// already visited, so they never resolve
+	ErrorTypeRedirectLoop ErrorType = "redirect_loop"
+
+	// ErrorTypeInsecureRedirect marks links whose redirects downgrade from
+	// HTTPS to HTTP, and ErrorTypeLongRedirectChain links that take more than
+	// MaxRedirectChain redirects to resolve. They work, so like redirected
+	// links they are flagged but not counted as broken.
+	ErrorTypeInsecureRedirect  ErrorType = "insecure_redirect"
+	ErrorTypeLongRedirectChain ErrorType = "long_redirect_chain"
+
+	// ErrorTypeForbiddenScheme marks links whose scheme, such as ftp:, the
+	// scheme policy fails. They are never requested.
+	ErrorTypeForbiddenScheme ErrorType = "forbidden_scheme"
+)
+
+// classifyError maps a transport-level error to an ErrorType
+func classifyError(err error) ErrorType {
+	if err == nil {
+		return ""
+	}
+
+	var dnsErr *net.DNSError
+	var netErr net.Error
+	var certErr *tls.CertificateVerificationError
+	var recordErr tls.RecordHeaderError
+	var unknownAuthErr x509.UnknownAuthorityError
+	var hostnameErr x509.HostnameError
+	var invalidCertErr x509.CertificateInvalidError
+	var opErr *net.OpError
+
+	switch {
+	case errors.Is(err, context.Canceled):
+		return ErrorTypeCancelled
+	case errors.Is(err, context.DeadlineExceeded):
+		return ErrorTypeTimeout
+	case errors.As(err, &dnsErr):
+		return ErrorTypeDNS
+	case errors.As(err, &netErr) && netErr.Timeout():
+		return ErrorTypeTimeout
+	case errors.As(err, &certErr), errors.As(err, &recordErr), errors.As(err, &unknownAuthErr),
+		errors.As(err, &hostnameErr), errors.As(err, &invalidCertErr):
+		return ErrorTypeTLS
+	case strings.Contains(err.Error(), "redirect loop back to"):
+		return ErrorTypeRedirectLoop
+	case strings.Contains(err.Error(), "stopped after") && strings.Contains(err.Error(), "redirects"):
+		// net/http reports redirect limits with an untyped error
+		return ErrorTypeTooManyRedirects
+	case errors.Is(err, syscall.ECONNREFUSED), errors.Is(err, syscall.ECONNRESET),
+		errors.Is(err, io.EOF), errors.Is(err, io.ErrUnexpectedEOF):
+		return ErrorTypeConnect
+	case errors.As(err, &opErr) && opErr.Op == "dial":
+		return ErrorTypeConnect
+	default:
+		return ErrorTypeOther
+	}
+}
+
+// Error details recorded in LinkResult.ErrorDetail, narrowing down dns,
+// connect and tls failures
+const (
+	ErrorDetailNXDomain           = "nxdomain"
+	ErrorDetailDNSTimeout         = "dns_timeout"
+	ErrorDetailDNSTemporary       = "dns_temporary"
+	ErrorDetailConnectionRefused  = "connection_refused"
+	ErrorDetailConnectionReset    = "connection_reset"
+	ErrorDetailCertificateExpired = "certificate_expired"
+	ErrorDetailUnknownAuthority   = "unknown_authority"
+	ErrorDetailHostnameMismatch   = "hostname_mismatch"
+)
+
+// errorDetail narrows down a transport-level error beyond its ErrorType, so
+// that policies can tell a domain that doesn't exist from a resolver that
+// didn't answer. It returns "" when there is nothing more specific to say.
+func errorDetail(err error) string {
+	var dnsErr *net.DNSError
+	var unknownAuthErr x509.UnknownAuthorityError
+	var hostnameErr x509.HostnameError
+	var invalidCertErr x509.CertificateInvalidError
+
+	switch {
+	case err == nil:
+		return ""
+	case errors.As(err, &dnsErr):
+		switch {
+		case dnsErr.IsNotFound:
+			return ErrorDetailNXDomain
+		case dnsErr.IsTimeout:
+			return ErrorDetailDNSTimeout
+		case dnsErr.IsTemporary:
+			return ErrorDetailDNSTemporary
+		}
+	case errors.Is(err, syscall.ECONNREFUSED):
+		return ErrorDetailConnectionRefused
+	case errors.Is(err, syscall.ECONNRESET):
+		return ErrorDetailConnectionReset
+	case errors.As(err, &invalidCertErr):
+		if invalidCertErr.Reason == x509.Expired {
+			return ErrorDetailCertificateExpired
+		}
+	case errors.As(err, &unknownAuthErr):
+		return ErrorDetailUnknownAuthority
+	case errors.As(err, &hostnameErr):
+		return ErrorDetailHostnameMismatch
+	}
+	return ""
+}
+
+// classifyStatus maps an HTTP status code to an ErrorType, returning an empty
+// type for non-error statuses
+func classifyStatus(statusCode int) ErrorType {
+	switch {
+	case statusCode >= 500:
+		return ErrorTypeHTTP5xx
+	case statusCode >= 400:
+		return ErrorTypeHTTP4xx
+	default:
+		return ""
+	}
+}
+
+// IsNetwork reports whether the error type is a network-level failure where
+// no HTTP response was received
+func (t ErrorType) IsNetwork() bool {
+	switch t {
+	case ErrorTypeDNS, ErrorTypeConnect, ErrorTypeTLS:
+		return true
+	default:
+		return false
+	}
+}
+
+// IsFailure reports whether the error type marks a broken link, as opposed
+// to a link that could not be verified
+func (t ErrorType) IsFailure() bool {
+	return t != "" && t != ErrorTypeAuthRequired && t != ErrorTypeBotChallenge && !t.IsRedirect()
+}
+
+// IsRedirect reports whether the error type flags a working link for its
+// redirects
+func (t ErrorType) IsRedirect() bool {
+	return t == ErrorTypeRedirected || t == ErrorTypeInsecureRedirect || t == ErrorTypeLongRedirectChain
+}
+
+// CountErrorTypes tallies results by their ErrorType, ignoring successes
+func CountErrorTypes(results []LinkResult) map[ErrorType]int {
+	counts := make(map[ErrorType]int)
+	for _, result := range results {
+		if result.ErrorType != "" {
+			counts[result.ErrorType]++
+		}
+	}
+	return counts
+}
+
+// Matches reports whether the error type belongs to a configured failure
+// category. Categories are error type names plus the shorthands "4xx", "5xx"
+// and "network". Soft 404s count as 4xx.
+func (t ErrorType) Matches(category string) bool {
+	switch strings.ToLower(category) {
+	case string(t):
+		return true
+	case "4xx":
+		return t == ErrorTypeHTTP4xx || t == ErrorTypeSoft404
+	case "5xx":
+		return t == ErrorTypeHTTP5xx
+	case "network":
+		return t.IsNetwork()
+	default:
+		return false
+	}
+}
+
+// matches reports whether a result belongs to a configured failure category:
+// one its error type matches, or its error detail
+func (r LinkResult) matches(category string) bool {
+	return r.ErrorType.Matches(category) || (r.ErrorDetail != "" && strings.EqualFold(category, r.ErrorDetail))
+}
+
+// FailingResults returns the results with error severity, the ones that fail
+// the run
+func FailingResults(results []LinkResult) []LinkResult {
+	var failing []LinkResult
+	for _, result := range results {
+		if result.Severity == SeverityError {
+			failing = append(failing, result)
+		}
+	}
+	return failing
+}
@@ -0,0 +1,378 @@
+package checker
+
+import (
+	"context"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/joshbeard/link-validator/internal/config"
+)
+
+func TestClassifyError(t *testing.T) {
+	testCases := []struct {
+		name     string
+		err      error
+		expected ErrorType
+	}{
+		{"nil", nil, ""},
+		{"cancelled", &url.Error{Op: "Get", URL: "x", Err: context.Canceled}, ErrorTypeCancelled},
+		{"deadline", &url.Error{Op: "Get", URL: "x", Err: context.DeadlineExceeded}, ErrorTypeTimeout},
+		{"dns", &url.Error{Op: "Get", URL: "x", Err: &net.DNSError{Err: "no such host", Name: "x", IsNotFound: true}}, ErrorTypeDNS},
+		{"net timeout", &url.Error{Op: "Get", URL: "x", Err: os.ErrDeadlineExceeded}, ErrorTypeTimeout},
+		{"tls", &url.Error{Op: "Get", URL: "x", Err: x509.UnknownAuthorityError{}}, ErrorTypeTLS},
+		{"redirects", &url.Error{Op: "Get", URL: "x", Err: errors.New("stopped after 10 redirects")}, ErrorTypeTooManyRedirects},
+		{"refused", &url.Error{Op: "Get", URL: "x", Err: &net.OpError{Op: "dial", Err: syscall.ECONNREFUSED}}, ErrorTypeConnect},
+		{"eof", &url.Error{Op: "Get", URL: "x", Err: io.EOF}, ErrorTypeConnect},
+		{"other", fmt.Errorf("something odd"), ErrorTypeOther},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := classifyError(tc.err); got != tc.expected {
+				t.Errorf("Expected %q, got %q", tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestClassifyStatus(t *testing.T) {
+	testCases := []struct {
+		statusCode int
+		expected   ErrorType
+	}{
+		{200, ""},
+		{301, ""},
+		{404, ErrorTypeHTTP4xx},
+		{429, ErrorTypeHTTP4xx},
+		{500, ErrorTypeHTTP5xx},
+		{503, ErrorTypeHTTP5xx},
+	}
+
+	for _, tc := range testCases {
+		if got := classifyStatus(tc.statusCode); got != tc.expected {
+			t.Errorf("Status %d: expected %q, got %q", tc.statusCode, tc.expected, got)
+		}
+	}
+}
+
+func TestCheckSingleLinkErrorTypes(t *testing.T) {
+	cfg := &config.Config{
+		UserAgent:     "TestBot/1.0",
+		Timeout:       100 * time.Millisecond,
+		MaxConcurrent: 1,
+	}
+	checker := New(cfg)
+
+	t.Run("http 4xx", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNotFound)
+		}))
+		defer server.Close()
+
+		result := checker.checkSingleLink(server.URL)
+		if result.ErrorType != ErrorTypeHTTP4xx {
+			t.Errorf("Expected %q, got %q", ErrorTypeHTTP4xx, result.ErrorType)
+		}
+	})
+
+	t.Run("http 5xx", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusBadGateway)
+		}))
+		defer server.Close()
+
+		result := checker.checkSingleLink(server.URL)
+		if result.ErrorType != ErrorTypeHTTP5xx {
+			t.Errorf("Expected %q, got %q", ErrorTypeHTTP5xx, result.ErrorType)
+		}
+	})
+
+	t.Run("timeout", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			time.Sleep(300 * time.Millisecond)
+			w.WriteHeader(http.StatusOK)
+		}))
+		defer server.Close()
+
+		result := checker.checkSingleLink(server.URL)
+		if result.ErrorType != ErrorTypeTimeout {
+			t.Errorf("Expected %q, got %q (%s)", ErrorTypeTimeout, result.ErrorType, result.Error)
+		}
+	})
+
+	t.Run("connection refused", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+		closedURL := server.URL
+		server.Close()
+
+		result := checker.checkSingleLink(closedURL)
+		if result.ErrorType != ErrorTypeConnect {
+			t.Errorf("Expected %q, got %q (%s)", ErrorTypeConnect, result.ErrorType, result.Error)
+		}
+	})
+
+	t.Run("success has no error type", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusOK)
+		}))
+		defer server.Close()
+
+		result := checker.checkSingleLink(server.URL)
+		if result.ErrorType != "" {
+			t.Errorf("Expected no error type, got %q", result.ErrorType)
+		}
+	})
+}
+
+func TestCountErrorTypes(t *testing.T) {
+	results := []LinkResult{
+		{StatusCode: 200},
+		{StatusCode: 404, ErrorType: ErrorTypeHTTP4xx},
+		{StatusCode: 410, ErrorType: ErrorTypeHTTP4xx},
+		{StatusCode: 503, ErrorType: ErrorTypeHTTP5xx},
+		{ErrorType: ErrorTypeDNS},
+		{ErrorType: ErrorTypeTimeout},
+	}
+
+	counts := CountErrorTypes(results)
+
+	expected := map[ErrorType]int{
+		ErrorTypeHTTP4xx: 2,
+		ErrorTypeHTTP5xx: 1,
+		ErrorTypeDNS:     1,
+		ErrorTypeTimeout: 1,
+	}
+	if len(counts) != len(expected) {
+		t.Errorf("Expected %d error types, got %d: %v", len(expected), len(counts), counts)
+	}
+	for errorType, count := range expected {
+		if counts[errorType] != count {
+			t.Errorf("Error type %q: expected %d, got %d", errorType, count, counts[errorType])
+		}
+	}
+}
+
+func TestErrorTypeIsNetwork(t *testing.T) {
+	network := []ErrorType{ErrorTypeDNS, ErrorTypeConnect, ErrorTypeTLS}
+	other := []ErrorType{ErrorTypeTimeout, ErrorTypeHTTP4xx, ErrorTypeHTTP5xx, ErrorTypeTooManyRedirects, ErrorTypeOther, ""}
+
+	for _, errorType := range network {
+		if !errorType.IsNetwork() {
+			t.Errorf("Expected %q to be a network error", errorType)
+		}
+	}
+	for _, errorType := range other {
+		if errorType.IsNetwork() {
+			t.Errorf("Expected %q not to be a network error", errorType)
+		}
+	}
+}
+
+func TestErrorTypeIsFailure(t *testing.T) {
+	if ErrorType("").IsFailure() {
+		t.Error("Expected success not to be a failure")
+	}
+	if ErrorTypeAuthRequired.IsFailure() {
+		t.Errorf("Expected %q not to be a failure", ErrorTypeAuthRequired)
+	}
+	for _, errorType := range []ErrorType{ErrorTypeHTTP4xx, ErrorTypeDNS, ErrorTypeTimeout, ErrorTypeOther} {
+		if !errorType.IsFailure() {
+			t.Errorf("Expected %q to be a failure", errorType)
+		}
+	}
+}
+
+func TestErrorTypeMatches(t *testing.T) {
+	testCases := []struct {
+		errorType ErrorType
+		category  string
+		expected  bool
+	}{
+		{ErrorTypeHTTP4xx, "http_4xx", true},
+		{ErrorTypeHTTP4xx, "4xx", true},
+		{ErrorTypeHTTP4xx, "5xx", false},
+		{ErrorTypeSoft404, "4xx", true},
+		{ErrorTypeSoft404, "soft_404", true},
+		{ErrorTypeHTTP5xx, "5XX", true},
+		{ErrorTypeDNS, "dns", true},
+		{ErrorTypeDNS, "network", true},
+		{ErrorTypeTLS, "network", true},
+		{ErrorTypeTimeout, "network", false},
+		{ErrorTypeTimeout, "timeout", true},
+		{ErrorTypeTimeout, "bogus", false},
+	}
+
+	for _, tc := range testCases {
+		if got := tc.errorType.Matches(tc.category); got != tc.expected {
+			t.Errorf("%q matches %q: expected %v, got %v", tc.errorType, tc.category, tc.expected, got)
+		}
+	}
+}
+
+func TestErrorDetail(t *testing.T) {
+	testCases := []struct {
+		name     string
+		err      error
+		expected string
+	}{
+		{"nxdomain", &url.Error{Op: "Get", URL: "x", Err: &net.DNSError{Err: "no such host", Name: "x", IsNotFound: true}}, ErrorDetailNXDomain},
+		{"dns timeout", &url.Error{Op: "Get", URL: "x", Err: &net.DNSError{Err: "i/o timeout", Name: "x", IsTimeout: true}}, ErrorDetailDNSTimeout},
+		{"dns temporary", &url.Error{Op: "Get", URL: "x", Err: &net.DNSError{Err: "server misbehaving", Name: "x", IsTemporary: true}}, ErrorDetailDNSTemporary},
+		{"refused", &url.Error{Op: "Get", URL: "x", Err: &net.OpError{Op: "dial", Err: syscall.ECONNREFUSED}}, ErrorDetailConnectionRefused},
+		{"reset", &url.Error{Op: "Get", URL: "x", Err: &net.OpError{Op: "read", Err: syscall.ECONNRESET}}, ErrorDetailConnectionReset},
+		{"expired", &url.Error{Op: "Get", URL: "x", Err: x509.CertificateInvalidError{Reason: x509.Expired}}, ErrorDetailCertificateExpired},
+		{"unknown authority", &url.Error{Op: "Get", URL: "x", Err: x509.UnknownAuthorityError{}}, ErrorDetailUnknownAuthority},
+		{"hostname", &url.Error{Op: "Get", URL: "x", Err: x509.HostnameError{Host: "x"}}, ErrorDetailHostnameMismatch},
+		{"timeout", &url.Error{Op: "Get", URL: "x", Err: context.DeadlineExceeded}, ""},
+		{"other", fmt.Errorf("something odd"), ""},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := errorDetail(tc.err); got != tc.expected {
+				t.Errorf("Expected %q, got %q", tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestCheckSingleLinkConnectionRefused(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	addr := listener.Addr().String()
+	listener.Close()
+
+	checker := New(&config.Config{UserAgent: "TestBot/1.0", Timeout: 5 * time.Second, MaxConcurrent: 1})
+	result := checker.checkSingleLink("http://" + addr + "/")
+	if result.ErrorType != ErrorTypeConnect || result.ErrorDetail != ErrorDetailConnectionRefused {
+		t.Errorf("Expected a refused connection, got %s/%s", result.ErrorType, result.ErrorDetail)
+	}
+}
+
+func TestResultSeverityByErrorDetail(t *testing.T) {
+	nxdomain := LinkResult{ErrorType: ErrorTypeDNS, ErrorDetail: ErrorDetailNXDomain}
+	flaky := LinkResult{ErrorType: ErrorTypeDNS, ErrorDetail: ErrorDetailDNSTimeout}
+	slow := LinkResult{ErrorType: ErrorTypeTimeout}
+
+	categories := []string{"NXDOMAIN"}
+	if got := ResultSeverity(nxdomain, nil, categories); got != SeverityError {
+		t.Errorf("Expected nxdomain to fail, got %s", got)
+	}
+	if got := ResultSeverity(flaky, nil, categories); got != SeverityWarning {
+		t.Errorf("Expected a DNS timeout not to fail, got %s", got)
+	}
+	if got := ResultSeverity(slow, nil, categories); got != SeverityWarning {
+		t.Errorf("Expected a timeout not to fail, got %s", got)
+	}
+
+	rules := config.ParseSeverityRules("dns_timeout=info")
+	if got := ResultSeverity(flaky, rules, nil); got != SeverityInfo {
+		t.Errorf("Expected the dns_timeout rule to apply, got %s", got)
+	}
+	if got := ResultSeverity(nxdomain, rules, nil); got != SeverityError {
+		t.Errorf("Expected the dns_timeout rule not to apply to nxdomain, got %s", got)
+	}
+
+	policy := config.ParseFailPolicy("status:nxdomain")
+	if matched, fails := ApplyFailPolicy([]LinkResult{nxdomain, flaky, slow}, policy); !fails || len(matched) != 1 {
+		t.Errorf("Expected only the nxdomain link to match the policy, got %+v", matched)
+	}
+}
+
+func TestResultSeverity(t *testing.T) {
+	results := []LinkResult{
+		{URL: "ok", StatusCode: 200},
+		{URL: "gone", StatusCode: 404, ErrorType: ErrorTypeHTTP4xx},
+		{URL: "forbidden", StatusCode: 403, ErrorType: ErrorTypeHTTP4xx},
+		{URL: "down", StatusCode: 503, ErrorType: ErrorTypeHTTP5xx},
+		{URL: "nxdomain", ErrorType: ErrorTypeDNS},
+		{URL: "slow", ErrorType: ErrorTypeTimeout},
+		{URL: "private", StatusCode: 200, ErrorType: ErrorTypeAuthRequired},
+	}
+
+	severities := func(rules []config.SeverityRule, categories []string) string {
+		var got []string
+		for _, result := range results {
+			got = append(got, string(ResultSeverity(result, rules, categories)))
+		}
+		return strings.Join(got, ",")
+	}
+
+	t.Run("no categories fails on every broken link", func(t *testing.T) {
+		expected := ",error,error,error,error,error,warning"
+		if got := severities(nil, nil); got != expected {
+			t.Errorf("Expected %s, got %s", expected, got)
+		}
+	})
+
+	t.Run("selected categories", func(t *testing.T) {
+		expected := ",error,error,warning,error,warning,warning"
+		if got := severities(nil, []string{"4xx", "dns"}); got != expected {
+			t.Errorf("Expected %s, got %s", expected, got)
+		}
+	})
+
+	t.Run("auth required only fails when selected", func(t *testing.T) {
+		expected := ",warning,warning,warning,warning,warning,error"
+		if got := severities(nil, []string{"auth_required"}); got != expected {
+			t.Errorf("Expected %s, got %s", expected, got)
+		}
+	})
+
+	t.Run("rules take precedence in order", func(t *testing.T) {
+		rules := config.ParseSeverityRules("403=info,4xx=warning,network=error,auth_required=error,timeout=info")
+		expected := ",warning,info,error,error,info,error"
+		if got := severities(rules, nil); got != expected {
+			t.Errorf("Expected %s, got %s", expected, got)
+		}
+	})
+}
+
+func TestFailingResults(t *testing.T) {
+	results := []LinkResult{
+		{URL: "ok", StatusCode: 200},
+		{URL: "gone", StatusCode: 404, ErrorType: ErrorTypeHTTP4xx, Severity: SeverityError},
+		{URL: "down", StatusCode: 503, ErrorType: ErrorTypeHTTP5xx, Severity: SeverityWarning},
+		{URL: "slow", ErrorType: ErrorTypeTimeout, Severity: SeverityInfo},
+	}
+
+	failing := FailingResults(results)
+	if len(failing) != 1 || failing[0].URL != "gone" {
+		t.Errorf("Expected only the error severity result, got %+v", failing)
+	}
+}
+
+func TestCheckLinksAssignsSeverity(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/moved-on" {
+			w.WriteHeader(http.StatusGone)
+			return
+		}
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	checker := New(&config.Config{
+		UserAgent:     "TestBot/1.0",
+		Timeout:       5 * time.Second,
+		MaxConcurrent: 1,
+		SeverityRules: config.ParseSeverityRules("410=info"),
+	})
+	results := checker.CheckLinks([]string{server.URL + "/missing", server.URL + "/moved-on"})
+	if results[0].Severity != SeverityError || results[1].Severity != SeverityInfo {
+		t.Errorf("Expected error and info severities, got %q and %q", results[0].Severity, results[1].Severity)
+	}
+}
@@ -0,0 +1,101 @@
+package checker
+
+import (
+	"iter"
+	"slices"
+
+	"golang.org/x/time/rate"
+)
+
+// recordExternal remembers a link from a crawled page to another host so it
+// can be checked by CheckExternalLinks
+func (c *Checker) recordExternal(link, pageURL string) {
+	if c.shouldExclude(link) {
+		return
+	}
+	c.recordSource(link, pageURL)
+	id, ok := c.urls.id(link)
+	if !ok {
+		return
+	}
+
+	c.inventoryMu.Lock()
+	defer c.inventoryMu.Unlock()
+	if !c.external[id] {
+		c.external[id] = true
+		c.externalOrder = append(c.externalOrder, id)
+	}
+}
+
+// ExternalLinks returns the links to other hosts found while crawling, in
+// discovery order. They are only collected when CheckExternal is set.
+func (c *Checker) ExternalLinks() []string {
+	list := NewURLList(0)
+	c.ExternalLinksTo(list)
+	return slices.Collect(list.All())
+}
+
+// ExternalLinksTo adds the links to other hosts found while crawling to list,
+// in discovery order, so that a list that spills to disk needn't hold them
+// all in memory
+func (c *Checker) ExternalLinksTo(list *URLList) error {
+	c.inventoryMu.Lock()
+	order := append([]uint32(nil), c.externalOrder...)
+	c.inventoryMu.Unlock()
+
+	for _, id := range order {
+		if err := list.Add(c.urls.url(id)); err != nil {
+			return err
+		}
+	}
+	return c.urls.firstErr()
+}
+
+// CheckExternalLinks checks links to other hosts as CheckLinks does, but with
+// their own concurrency and rate limits so that slow third-party sites don't
+// hold up the site's own pages or get as many requests at once. Results are
+// marked as external.
+func (c *Checker) CheckExternalLinks(urls []string) []LinkResult {
+	var results []LinkResult
+	c.checkExternalLinksTo(slices.Values(urls), len(urls), func(batch ...LinkResult) error {
+		results = append(results, batch...)
+		return nil
+	})
+	return results
+}
+
+// CheckExternalLinksTo checks links to other hosts as CheckExternalLinks
+// does, adding their results to store a batch at a time rather than
+// returning them all at once
+func (c *Checker) CheckExternalLinksTo(store *ResultStore, urls []string) error {
+	return c.checkExternalLinksTo(slices.Values(urls), len(urls), store.Add)
+}
+
+// CheckExternalListTo checks the links of list as CheckExternalLinksTo
+// does, reading back those that spilled to disk a batch at a time
+func (c *Checker) CheckExternalListTo(store *ResultStore, list *URLList) error {
+	if err := c.checkExternalLinksTo(list.All(), list.Len(), store.Add); err != nil {
+		return err
+	}
+	return list.Err()
+}
+
+// checkExternalLinksTo checks the count links to other hosts of a sequence,
+// passing their results, marked as external, to add a batch at a time
+func (c *Checker) checkExternalLinksTo(urls iter.Seq[string], count int, add func(...LinkResult) error) error {
+	concurrency := c.config.ExternalConcurrency
+	if concurrency <= 0 {
+		concurrency = c.config.MaxConcurrent
+	}
+	if c.externalLimiter == nil {
+		c.externalLimiter = rate.NewLimiter(rate.Limit(concurrency), concurrency)
+	}
+
+	return c.checkLinksTo(urls, count, concurrency, c.externalLimiter, func(batch ...LinkResult) error {
+		for i := range batch {
+			batch[i].External = true
+			batch[i].Category = CategoryExternal
+		}
+		return add(batch...)
+	})
+}
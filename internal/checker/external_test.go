@@ -0,0 +1,83 @@
+package checker
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/joshbeard/link-validator/internal/config"
+)
+
+func TestCheckExternalLinks(t *testing.T) {
+	other := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/missing" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer other.Close()
+
+	site := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		switch r.URL.Path {
+		case "/":
+			fmt.Fprintf(w, `<a href="/about/">About</a><a href="%s/ok">Partner</a>`, other.URL)
+		case "/about/":
+			fmt.Fprintf(w, `<a href="%s/ok">Partner</a><a href="%s/missing">Gone</a>`, other.URL, other.URL)
+		}
+	}))
+	defer site.Close()
+
+	t.Run("disabled", func(t *testing.T) {
+		checker := New(&config.Config{UserAgent: "TestBot/1.0", Timeout: 5 * time.Second, MaxConcurrent: 1})
+		if _, err := checker.CrawlWebsite(site.URL+"/", 2); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if got := checker.ExternalLinks(); len(got) != 0 {
+			t.Errorf("Expected no external links, got %v", got)
+		}
+	})
+
+	t.Run("enabled", func(t *testing.T) {
+		checker := New(&config.Config{
+			UserAgent:           "TestBot/1.0",
+			Timeout:             5 * time.Second,
+			MaxConcurrent:       1,
+			CheckExternal:       true,
+			ExternalConcurrency: 2,
+		})
+		urls, err := checker.CrawlWebsite(site.URL+"/", 2)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if len(urls) != 2 {
+			t.Errorf("Expected external links not to be crawled, got %v", urls)
+		}
+
+		external := checker.ExternalLinks()
+		expected := []string{other.URL + "/ok", other.URL + "/missing"}
+		if !reflect.DeepEqual(external, expected) {
+			t.Fatalf("Expected %v, got %v", expected, external)
+		}
+
+		results := checker.CheckExternalLinks(external)
+		if len(results) != 2 {
+			t.Fatalf("Expected 2 results, got %d", len(results))
+		}
+		for _, result := range results {
+			if !result.External {
+				t.Errorf("Expected %s to be marked external", result.URL)
+			}
+		}
+		if results[0].Error != "" || results[0].SourceCount != 2 {
+			t.Errorf("Expected %s to pass with 2 sources, got %+v", results[0].URL, results[0])
+		}
+		if results[1].ErrorType != ErrorTypeHTTP4xx {
+			t.Errorf("Expected %s to fail with a 4xx, got %+v", results[1].URL, results[1])
+		}
+	})
+}
@@ -0,0 +1,54 @@
+package checker
+
+import (
+	"strconv"
+
+	"github.com/joshbeard/link-validator/internal/config"
+)
+
+// ApplyFailPolicy returns the failing results the policy counts and whether
+// they fail the run. Without a policy, any failing result fails the run.
+func ApplyFailPolicy(failing []LinkResult, policy *config.FailPolicy) ([]LinkResult, bool) {
+	if policy == nil {
+		return failing, len(failing) > 0
+	}
+	var matched []LinkResult
+	for _, result := range failing {
+		if matchesStatus(result, policy.Statuses) && matchesCategory(result, policy.Categories) {
+			matched = append(matched, result)
+		}
+	}
+	return matched, len(matched) > policy.MaxCount
+}
+
+// matchesStatus reports whether a result has one of the status codes or
+// error categories, or whether there are none
+func matchesStatus(result LinkResult, statuses []string) bool {
+	if len(statuses) == 0 {
+		return true
+	}
+	for _, status := range statuses {
+		if code, err := strconv.Atoi(status); err == nil {
+			if result.StatusCode == code {
+				return true
+			}
+		} else if result.matches(status) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchesCategory reports whether a result is in one of the link
+// categories, or whether there are none
+func matchesCategory(result LinkResult, categories []string) bool {
+	if len(categories) == 0 {
+		return true
+	}
+	for _, category := range categories {
+		if string(result.Category) == category {
+			return true
+		}
+	}
+	return false
+}
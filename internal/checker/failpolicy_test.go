@@ -0,0 +1,44 @@
+package checker
+
+import (
+	"testing"
+
+	"github.com/joshbeard/link-validator/internal/config"
+)
+
+func TestApplyFailPolicy(t *testing.T) {
+	failing := []LinkResult{
+		{URL: "gone", StatusCode: 404, ErrorType: ErrorTypeHTTP4xx, Category: CategoryInternal},
+		{URL: "down", StatusCode: 503, ErrorType: ErrorTypeHTTP5xx, Category: CategoryExternal},
+		{URL: "nxdomain", ErrorType: ErrorTypeDNS, Category: CategoryExternal},
+		{URL: "logo", StatusCode: 404, ErrorType: ErrorTypeHTTP4xx, Category: CategoryAsset},
+	}
+
+	tests := []struct {
+		policy   string
+		matched  int
+		expected bool
+	}{
+		{"", 4, true},
+		{"status:5xx", 1, true},
+		{"status:404", 2, true},
+		{"status:dns", 1, true},
+		{"category:internal,asset", 2, true},
+		{"category:anchor", 0, false},
+		{"status:4xx; category:external", 0, false},
+		{"count>3", 4, true},
+		{"count>4", 4, false},
+		{"category:external; count>=2", 2, true},
+	}
+	for _, test := range tests {
+		matched, fails := ApplyFailPolicy(failing, config.ParseFailPolicy(test.policy))
+		if len(matched) != test.matched || fails != test.expected {
+			t.Errorf("Policy %q: expected %d matched and failing %v, got %d and %v",
+				test.policy, test.matched, test.expected, len(matched), fails)
+		}
+	}
+
+	if _, fails := ApplyFailPolicy(nil, nil); fails {
+		t.Error("Expected no failing links not to fail the run")
+	}
+}
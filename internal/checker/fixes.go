@@ -0,0 +1,107 @@
+package checker
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/joshbeard/link-validator/internal/config"
+)
+
+// Fix is a confident replacement for a link: a permanently moved URL or an
+// http:// URL that upgrades to https://
+type Fix struct {
+	Old    string   `json:"old"`
+	New    string   `json:"new"`
+	Reason string   `json:"reason"`
+	Files  []string `json:"files,omitempty"`
+}
+
+// ProposeFixes returns fixes for results whose redirects can be followed in
+// the source without changing what readers land on. Only redirects that
+// ended in a working page qualify: permanent moves (301 or 308) and
+// upgrades from http to https on the same host and path. Results checked in
+// a specific locale are skipped, since their redirects may depend on the
+// language negotiated, as are redirects that weren't followed, since where
+// they end hasn't been checked. Files lists the repository files of the
+// pages linking to each URL.
+func ProposeFixes(results []LinkResult, fileRules []config.PathRule) []Fix {
+	var fixes []Fix
+	for _, result := range results {
+		if result.FinalURL == "" || result.FinalURL == result.URL || result.Locale != "" {
+			continue
+		}
+		if (result.ErrorType != "" && result.ErrorType != ErrorTypeRedirected && result.ErrorType != ErrorTypeLongRedirectChain) || isRedirectStatus(result.StatusCode) {
+			continue
+		}
+
+		var reason string
+		switch {
+		case isHTTPSUpgrade(result.URL, result.FinalURL):
+			reason = "http to https upgrade"
+		case result.RedirectStatus == http.StatusMovedPermanently || result.RedirectStatus == http.StatusPermanentRedirect:
+			reason = "permanent redirect"
+		default:
+			continue
+		}
+
+		fixes = append(fixes, Fix{
+			Old:    result.URL,
+			New:    result.FinalURL,
+			Reason: reason,
+			Files:  SourceFiles(result.Sources, fileRules),
+		})
+	}
+	return fixes
+}
+
+// isHTTPSUpgrade reports whether target is source moved from http to https
+// without any other change
+func isHTTPSUpgrade(source, target string) bool {
+	from, err := url.Parse(source)
+	if err != nil || from.Scheme != "http" {
+		return false
+	}
+	to, err := url.Parse(target)
+	if err != nil || to.Scheme != "https" {
+		return false
+	}
+	from.Scheme = "https"
+	return from.String() == to.String()
+}
+
+// ApplyFixes rewrites occurrences of each fix's old URL in content and
+// returns the new content with the number of replacements. An occurrence
+// only counts when the URL isn't followed by more path or query, so fixing
+// /docs/ leaves /docs/intro alone.
+func ApplyFixes(content string, fixes []Fix) (string, int) {
+	total := 0
+	for _, fix := range fixes {
+		var b strings.Builder
+		rest := content
+		for {
+			i := strings.Index(rest, fix.Old)
+			if i < 0 {
+				b.WriteString(rest)
+				break
+			}
+			end := i + len(fix.Old)
+			b.WriteString(rest[:i])
+			if end == len(rest) || isURLTerminator(rest[end]) {
+				b.WriteString(fix.New)
+				total++
+			} else {
+				b.WriteString(fix.Old)
+			}
+			rest = rest[end:]
+		}
+		content = b.String()
+	}
+	return content, total
+}
+
+// isURLTerminator reports whether a character ends a URL in Markdown, HTML
+// or plain text. A fragment is allowed to follow.
+func isURLTerminator(ch byte) bool {
+	return strings.IndexByte(" \t\r\n\"'()<>[]{}|`#,", ch) >= 0
+}
@@ -0,0 +1,60 @@
+package checker
+
+import (
+	"testing"
+
+	"github.com/joshbeard/link-validator/internal/config"
+)
+
+func TestProposeFixes(t *testing.T) {
+	rules := config.ParsePathRules(`^/(.+)/$=content/$1.md`)
+	results := []LinkResult{
+		{URL: "https://example.com/ok", StatusCode: 200},
+		{URL: "https://example.com/old", StatusCode: 200, FinalURL: "https://example.com/new",
+			RedirectStatus: 301, Sources: []string{"https://site.com/docs/"}},
+		{URL: "http://example.com/page", StatusCode: 200, FinalURL: "https://example.com/page", RedirectStatus: 302},
+		{URL: "https://example.com/temp", StatusCode: 200, FinalURL: "https://example.com/elsewhere", RedirectStatus: 302},
+		{URL: "https://example.com/gone", StatusCode: 404, ErrorType: ErrorTypeHTTP4xx,
+			FinalURL: "https://example.com/missing", RedirectStatus: 301},
+		{URL: "http://example.com/a", StatusCode: 200, FinalURL: "https://example.com/b", RedirectStatus: 307},
+		{URL: "https://example.com/docs", StatusCode: 200, FinalURL: "https://example.com/de/docs",
+			RedirectStatus: 301, Locale: "de"},
+	}
+
+	fixes := ProposeFixes(results, rules)
+	if len(fixes) != 2 {
+		t.Fatalf("Expected 2 fixes, got %+v", fixes)
+	}
+	if fixes[0].New != "https://example.com/new" || fixes[0].Reason != "permanent redirect" {
+		t.Errorf("Unexpected fix: %+v", fixes[0])
+	}
+	if len(fixes[0].Files) != 1 || fixes[0].Files[0] != "content/docs.md" {
+		t.Errorf("Expected source files to be mapped, got %v", fixes[0].Files)
+	}
+	if fixes[1].Old != "http://example.com/page" || fixes[1].Reason != "http to https upgrade" {
+		t.Errorf("Unexpected fix: %+v", fixes[1])
+	}
+}
+
+func TestApplyFixes(t *testing.T) {
+	fixes := []Fix{
+		{Old: "https://example.com/docs/", New: "https://docs.example.com/"},
+		{Old: "http://example.com/page", New: "https://example.com/page"},
+	}
+	content := `See [the docs](https://example.com/docs/) and <a href="https://example.com/docs/#setup">setup</a>.
+Not https://example.com/docs/intro or http://example.com/pages or http://example.com/page?id=1.
+Plain http://example.com/page
+`
+
+	updated, count := ApplyFixes(content, fixes)
+	expected := `See [the docs](https://docs.example.com/) and <a href="https://docs.example.com/#setup">setup</a>.
+Not https://example.com/docs/intro or http://example.com/pages or http://example.com/page?id=1.
+Plain https://example.com/page
+`
+	if updated != expected {
+		t.Errorf("Unexpected content:\n%s", updated)
+	}
+	if count != 3 {
+		t.Errorf("Expected 3 replacements, got %d", count)
+	}
+}
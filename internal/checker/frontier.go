@@ -0,0 +1,175 @@
+package checker
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"io"
+	"os"
+)
+
+// crawlItem is a page waiting to be crawled: its URL, the page it was first
+// found on and how many links deep it is from an entry point
+type crawlItem struct {
+	URL    string `json:"url"`
+	Source string `json:"source,omitempty"`
+	Depth  int    `json:"depth"`
+}
+
+// crawlFrontier is the first-in, first-out queue of pages a crawl has yet to
+// fetch. At most limit items are held in memory; past that, new items are
+// appended to a temporary file and read back in order once those in memory
+// run out, so the frontier of a huge site doesn't have to fit in memory. A
+// limit of 0 or less keeps every item in memory.
+type crawlFrontier struct {
+	limit int
+	items []crawlItem
+
+	// spilled counts the items waiting in the file, which are all newer
+	// than those in memory
+	spilled int
+	file    *os.File
+	writer  *bufio.Writer
+	source  *os.File
+	reader  *bufio.Reader
+}
+
+// newCrawlFrontier returns an empty frontier holding up to limit items in
+// memory
+func newCrawlFrontier(limit int) *crawlFrontier {
+	return &crawlFrontier{limit: limit}
+}
+
+// push adds an item to the back of the frontier
+func (f *crawlFrontier) push(item crawlItem) error {
+	if f.spilled == 0 && (f.limit <= 0 || len(f.items) < f.limit) {
+		f.items = append(f.items, item)
+		return nil
+	}
+	if f.file == nil {
+		if err := f.createFile(); err != nil {
+			return err
+		}
+	}
+	data, err := json.Marshal(item)
+	if err != nil {
+		return fmt.Errorf("encoding crawl frontier: %w", err)
+	}
+	if _, err := f.writer.Write(append(data, '\n')); err != nil {
+		return fmt.Errorf("writing crawl frontier: %w", err)
+	}
+	f.spilled++
+	return nil
+}
+
+// pop removes and returns the item at the front of the frontier, reporting
+// false when it is empty
+func (f *crawlFrontier) pop() (crawlItem, bool, error) {
+	if len(f.items) == 0 && f.spilled > 0 {
+		if err := f.refill(); err != nil {
+			return crawlItem{}, false, err
+		}
+	}
+	if len(f.items) == 0 {
+		return crawlItem{}, false, nil
+	}
+	item := f.items[0]
+	f.items = f.items[1:]
+	return item, true, nil
+}
+
+// len returns how many items are waiting, in memory and in the file
+func (f *crawlFrontier) len() int {
+	return len(f.items) + f.spilled
+}
+
+// createFile creates the temporary file items spill to, with separate
+// handles for appending and reading back
+func (f *crawlFrontier) createFile() error {
+	file, err := os.CreateTemp("", "link-checker-frontier-*.jsonl")
+	if err != nil {
+		return fmt.Errorf("creating crawl frontier file: %w", err)
+	}
+	source, err := os.Open(file.Name())
+	if err != nil {
+		file.Close()
+		os.Remove(file.Name())
+		return fmt.Errorf("opening crawl frontier file: %w", err)
+	}
+	f.file, f.writer = file, bufio.NewWriter(file)
+	f.source, f.reader = source, bufio.NewReader(source)
+	return nil
+}
+
+// refill reads the oldest spilled items back into memory, as many as the
+// limit allows. Once the file has been read to its end it is emptied, so it
+// only ever holds items still waiting.
+func (f *crawlFrontier) refill() error {
+	if err := f.writer.Flush(); err != nil {
+		return fmt.Errorf("writing crawl frontier: %w", err)
+	}
+	n := min(f.spilled, f.limit)
+	f.items = make([]crawlItem, 0, n)
+	for range n {
+		line, err := f.reader.ReadBytes('\n')
+		if err != nil {
+			return fmt.Errorf("reading crawl frontier: %w", err)
+		}
+		var item crawlItem
+		if err := json.Unmarshal(line, &item); err != nil {
+			return fmt.Errorf("decoding crawl frontier: %w", err)
+		}
+		f.items = append(f.items, item)
+	}
+	f.spilled -= n
+
+	if f.spilled == 0 {
+		if err := f.file.Truncate(0); err != nil {
+			return fmt.Errorf("truncating crawl frontier: %w", err)
+		}
+		if _, err := f.file.Seek(0, io.SeekStart); err != nil {
+			return fmt.Errorf("truncating crawl frontier: %w", err)
+		}
+		if _, err := f.source.Seek(0, io.SeekStart); err != nil {
+			return fmt.Errorf("truncating crawl frontier: %w", err)
+		}
+		f.reader.Reset(f.source)
+	}
+	return nil
+}
+
+// close removes the frontier's file, if items ever spilled to one
+func (f *crawlFrontier) close() {
+	if f.file == nil {
+		return
+	}
+	f.source.Close()
+	f.file.Close()
+	os.Remove(f.file.Name())
+	f.file = nil
+}
+
+// urlSet is a set of URLs that keeps a 64-bit hash of each instead of the URL
+// itself, so remembering every URL of a huge crawl takes a fraction of the
+// memory. Two URLs sharing a hash would make the crawl skip one of them, which
+// is vanishingly unlikely below billions of URLs.
+type urlSet map[uint64]struct{}
+
+// add adds a URL to the set
+func (s urlSet) add(u string) {
+	s[hashURL(u)] = struct{}{}
+}
+
+// has reports whether a URL is in the set
+func (s urlSet) has(u string) bool {
+	_, ok := s[hashURL(u)]
+	return ok
+}
+
+// hashURL returns the FNV-1a hash of a URL
+func hashURL(u string) uint64 {
+	h := fnv.New64a()
+	h.Write([]byte(u))
+	return h.Sum64()
+}
@@ -0,0 +1,117 @@
+package checker
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/joshbeard/link-validator/internal/config"
+)
+
+func TestCrawlFrontier(t *testing.T) {
+	frontier := newCrawlFrontier(4)
+	defer frontier.close()
+
+	pushed, popped := 0, 0
+	push := func(n int) {
+		for range n {
+			if err := frontier.push(crawlItem{URL: fmt.Sprintf("https://example.com/%d", pushed), Depth: pushed}); err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			pushed++
+		}
+	}
+	pop := func(n int) {
+		for range n {
+			item, ok, err := frontier.pop()
+			if err != nil || !ok {
+				t.Fatalf("Expected an item, got %v, %v", ok, err)
+			}
+			if expected := fmt.Sprintf("https://example.com/%d", popped); item.URL != expected || item.Depth != popped {
+				t.Fatalf("Expected %s first in, first out, got %+v", expected, item)
+			}
+			popped++
+		}
+	}
+
+	// Pushes and pops interleave as they do during a crawl, refilling from
+	// and emptying the file several times
+	for _, step := range [][2]int{{10, 3}, {7, 5}, {1, 10}, {12, 4}, {0, 8}} {
+		push(step[0])
+		if len(frontier.items) > 4 {
+			t.Fatalf("Expected at most 4 items in memory, got %d", len(frontier.items))
+		}
+		pop(step[1])
+		if frontier.len() != pushed-popped {
+			t.Fatalf("Expected %d waiting items, got %d", pushed-popped, frontier.len())
+		}
+	}
+	if _, ok, err := frontier.pop(); ok || err != nil {
+		t.Errorf("Expected an empty frontier, got %v, %v", ok, err)
+	}
+
+	if frontier.file == nil {
+		t.Fatal("Expected items to have spilled to a file")
+	}
+	name := frontier.file.Name()
+	frontier.close()
+	if _, err := os.Stat(name); !os.IsNotExist(err) {
+		t.Errorf("Expected the frontier file to be removed, got %v", err)
+	}
+
+	unlimited := newCrawlFrontier(0)
+	for i := range 100 {
+		unlimited.push(crawlItem{URL: fmt.Sprint(i)})
+	}
+	if unlimited.file != nil || len(unlimited.items) != 100 {
+		t.Errorf("Expected every item in memory without a limit, got %d", len(unlimited.items))
+	}
+}
+
+func TestCrawlSpillsFrontier(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		if r.URL.Path == "/" {
+			for i := range 20 {
+				fmt.Fprintf(w, `<a href="/section/%d/">Section</a>`, i)
+			}
+			return
+		}
+		fmt.Fprintf(w, `<a href="%spage">Page</a>`, r.URL.Path)
+	}))
+	defer server.Close()
+
+	crawl := func(spill int) []string {
+		checker := New(&config.Config{
+			UserAgent:     "TestBot/1.0",
+			Timeout:       5 * time.Second,
+			MaxConcurrent: 1,
+			FrontierSpill: spill,
+		})
+		urls, err := checker.CrawlWebsite(server.URL+"/", 3)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		return urls
+	}
+
+	inMemory, spilled := crawl(0), crawl(3)
+	if len(inMemory) != 61 {
+		t.Fatalf("Expected the home page and 20 pages at each of three depths, got %d URLs", len(inMemory))
+	}
+	if !reflect.DeepEqual(spilled, inMemory) {
+		t.Errorf("Expected a spilled frontier to crawl in the same order, got %v, expected %v", spilled, inMemory)
+	}
+}
+
+func TestURLSet(t *testing.T) {
+	set := make(urlSet)
+	set.add("https://example.com/")
+	if !set.has("https://example.com/") || set.has("https://example.com/other") {
+		t.Error("Expected only the added URL to be in the set")
+	}
+}
@@ -0,0 +1,138 @@
+package checker
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os/exec"
+	"strings"
+)
+
+// Actions a decision hook may return
+const (
+	HookActionCheck = "check"
+	HookActionSkip  = "skip"
+)
+
+// hookRequest is the JSON document sent to the decision hook for each URL
+type hookRequest struct {
+	URL      string   `json:"url"`
+	Locale   string   `json:"locale,omitempty"`
+	Internal bool     `json:"internal"`
+	Sources  []string `json:"sources,omitempty"`
+}
+
+// hookDecision is the JSON document the decision hook answers with
+type hookDecision struct {
+	Action         string `json:"action"`
+	ExpectedStatus []int  `json:"expected_status,omitempty"`
+}
+
+// decide consults the configured decision hook about a URL. Decisions are
+// remembered per URL, so locales share one call. A hook that fails or
+// answers with something unusable doesn't stop the run; the URL is checked.
+func (c *Checker) decide(checkURL, locale string) hookDecision {
+	if c.config.DecisionHook == "" {
+		return hookDecision{Action: HookActionCheck}
+	}
+
+	c.hookMu.Lock()
+	decision, ok := c.decisions[checkURL]
+	c.hookMu.Unlock()
+	if ok {
+		return decision
+	}
+
+	decision, err := c.callHook(hookRequest{
+		URL:      checkURL,
+		Locale:   locale,
+		Internal: c.isInternal(checkURL),
+		Sources:  c.Sources(checkURL),
+	})
+	if err != nil {
+		fmt.Printf("Warning: decision hook failed for %s, checking it: %v\n", checkURL, err)
+		decision = hookDecision{Action: HookActionCheck}
+	}
+
+	c.hookMu.Lock()
+	c.decisions[checkURL] = decision
+	c.hookMu.Unlock()
+	return decision
+}
+
+// callHook sends a request to the decision hook. An http(s) URL is sent the
+// request as a POST body; anything else is run as a shell command with the
+// request on stdin and the decision read from stdout.
+func (c *Checker) callHook(request hookRequest) (hookDecision, error) {
+	payload, err := json.Marshal(request)
+	if err != nil {
+		return hookDecision{}, fmt.Errorf("encoding request: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), c.config.Timeout)
+	defer cancel()
+
+	var output []byte
+	hook := c.config.DecisionHook
+	if strings.HasPrefix(hook, "http://") || strings.HasPrefix(hook, "https://") {
+		output, err = c.postHook(ctx, hook, payload)
+	} else {
+		cmd := exec.CommandContext(ctx, "sh", "-c", hook)
+		cmd.Stdin = bytes.NewReader(payload)
+		output, err = cmd.Output()
+	}
+	if err != nil {
+		return hookDecision{}, err
+	}
+
+	var decision hookDecision
+	if err := json.Unmarshal(output, &decision); err != nil {
+		return hookDecision{}, fmt.Errorf("parsing decision: %w", err)
+	}
+	decision.Action = strings.ToLower(strings.TrimSpace(decision.Action))
+	switch decision.Action {
+	case "":
+		decision.Action = HookActionCheck
+	case HookActionCheck, HookActionSkip:
+	default:
+		return hookDecision{}, fmt.Errorf("unknown action %q", decision.Action)
+	}
+	return decision, nil
+}
+
+// postHook sends a decision request to an HTTP hook endpoint
+func (c *Checker) postHook(ctx context.Context, endpoint string, payload []byte) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, "POST", endpoint, bytes.NewReader(payload))
+	if err != nil {
+		return nil, fmt.Errorf("creating request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", c.userAgentFor(endpoint))
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("hook returned status %d", resp.StatusCode)
+	}
+	return io.ReadAll(resp.Body)
+}
+
+// isExpectedStatus reports whether the decision hook declared a status code
+// as expected for a URL
+func (c *Checker) isExpectedStatus(checkURL string, statusCode int) bool {
+	c.hookMu.Lock()
+	defer c.hookMu.Unlock()
+	for _, code := range c.decisions[checkURL].ExpectedStatus {
+		if code == statusCode {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,105 @@
+package checker
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/joshbeard/link-validator/internal/config"
+)
+
+func TestDecisionHookEndpoint(t *testing.T) {
+	site := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/members" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer site.Close()
+
+	var calls atomic.Int64
+	hook := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		var request hookRequest
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			t.Errorf("Expected a JSON request, got %v", err)
+		}
+		switch {
+		case strings.HasSuffix(request.URL, "/internal-only"):
+			fmt.Fprint(w, `{"action": "skip"}`)
+		case strings.HasSuffix(request.URL, "/members"):
+			fmt.Fprint(w, `{"action": "check", "expected_status": [403]}`)
+		default:
+			fmt.Fprint(w, `{}`)
+		}
+	}))
+	defer hook.Close()
+
+	checker := New(&config.Config{
+		UserAgent:     "TestBot/1.0",
+		Timeout:       5 * time.Second,
+		MaxConcurrent: 1,
+		DecisionHook:  hook.URL,
+		CheckLocales:  []string{"en", "de"},
+	})
+	results := checker.CheckLinks([]string{site.URL + "/", site.URL + "/internal-only", site.URL + "/members"})
+
+	if len(results) != 4 {
+		t.Fatalf("Expected 4 results without the skipped URL, got %d: %+v", len(results), results)
+	}
+	for _, result := range results {
+		if strings.HasSuffix(result.URL, "/internal-only") {
+			t.Errorf("Expected %s to be skipped", result.URL)
+		}
+		if result.Error != "" {
+			t.Errorf("Expected %s to pass, got %s", result.URL, result.Error)
+		}
+		if strings.HasSuffix(result.URL, "/members") && !result.Accepted {
+			t.Errorf("Expected the 403 from %s to be accepted", result.URL)
+		}
+	}
+	if got := calls.Load(); got != 3 {
+		t.Errorf("Expected one hook call per URL, got %d", got)
+	}
+}
+
+func TestDecisionHookCommand(t *testing.T) {
+	checker := New(&config.Config{
+		Timeout:      5 * time.Second,
+		DecisionHook: `grep -q '"internal":true' && echo '{"action":"skip"}' || echo '{"action":"check"}'`,
+		BaseURL:      "https://example.com",
+	})
+
+	if got := checker.decide("https://example.com/private", "").Action; got != HookActionSkip {
+		t.Errorf("Expected the internal URL to be skipped, got %q", got)
+	}
+	if got := checker.decide("https://other.example/", "").Action; got != HookActionCheck {
+		t.Errorf("Expected the external URL to be checked, got %q", got)
+	}
+}
+
+func TestDecisionHookFailure(t *testing.T) {
+	tests := []struct {
+		name string
+		hook string
+	}{
+		{"command fails", "exit 1"},
+		{"invalid JSON", "echo nope"},
+		{"unknown action", `echo '{"action":"maybe"}'`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			checker := New(&config.Config{Timeout: 5 * time.Second, DecisionHook: tt.hook})
+			if got := checker.decide("https://example.com/", "").Action; got != HookActionCheck {
+				t.Errorf("Expected a failing hook to fall back to checking, got %q", got)
+			}
+		})
+	}
+}
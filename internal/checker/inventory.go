@@ -0,0 +1,211 @@
+package checker
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"iter"
+	"mime"
+	"os"
+	"slices"
+)
+
+// InventoryEntry describes a discovered URL and how it was found,
+// independent of whether it was checked or what the check found
+type InventoryEntry struct {
+	URL         string `json:"url"`
+	Depth       int    `json:"depth"`
+	Source      string `json:"source,omitempty"`
+	ContentType string `json:"content_type,omitempty"`
+}
+
+// inventoryRecord is what the inventory holds for a discovered URL, with
+// the page it was found on given by its ID in the URL table and its media
+// type by its index in mediaTypes, so a huge inventory holds no strings
+type inventoryRecord struct {
+	depth       int32
+	source      uint32 // ID of the source page plus one, 0 for none
+	contentType uint16 // index in mediaTypes plus one, 0 for unknown
+}
+
+// ImportKnownURLs marks URLs discovered by a previous run as already known.
+// Known URLs are included in crawl results without being fetched again, so
+// repeated runs only spend requests on discovering new pages.
+func (c *Checker) ImportKnownURLs(urls []string) {
+	for _, u := range urls {
+		if u == "" || c.known.has(u) {
+			continue
+		}
+		id, ok := c.urls.id(u)
+		if !ok {
+			return
+		}
+		c.known.add(u)
+		c.knownOrder = append(c.knownOrder, id)
+	}
+}
+
+// LoadURLList reads a previously exported URL list. The file may contain a
+// JSON array of URL strings or of objects with a "url" field, such as the
+// broken-links output or an exported URL inventory.
+func LoadURLList(path string) ([]string, error) {
+	data, err := os.ReadFile(path) // #nosec G304 -- path is provided by the user
+	if err != nil {
+		return nil, fmt.Errorf("reading URL list: %w", err)
+	}
+
+	var urls []string
+	if err := json.Unmarshal(data, &urls); err == nil {
+		return urls, nil
+	}
+
+	var entries []struct {
+		URL string `json:"url"`
+	}
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, fmt.Errorf("parsing URL list: %w", err)
+	}
+
+	urls = make([]string, 0, len(entries))
+	for _, entry := range entries {
+		if entry.URL != "" {
+			urls = append(urls, entry.URL)
+		}
+	}
+	return urls, nil
+}
+
+// recordDiscovery adds a URL to the inventory the first time it is found, and
+// keeps the shallowest depth it is found at. Source is the page or sitemap it
+// was found on at that depth, empty for entry points.
+func (c *Checker) recordDiscovery(discoveredURL string, depth int, source string) {
+	id, ok := c.urls.id(discoveredURL)
+	if !ok {
+		return
+	}
+	var sourceID uint32
+	if source != "" {
+		if sourceID, ok = c.urls.id(source); !ok {
+			return
+		}
+		sourceID++
+	}
+
+	c.inventoryMu.Lock()
+	defer c.inventoryMu.Unlock()
+
+	if record, exists := c.inventory[id]; exists {
+		// A seed or known page may be found again at a shallower depth
+		// than it was first recorded at
+		if int32(depth) < record.depth { // #nosec G115 -- crawl depths are small
+			record.depth = int32(depth) // #nosec G115 -- crawl depths are small
+			record.source = sourceID
+			c.inventory[id] = record
+		}
+		return
+	}
+	c.inventory[id] = inventoryRecord{depth: int32(depth), source: sourceID} // #nosec G115 -- crawl depths are small
+	c.inventoryOrder = append(c.inventoryOrder, id)
+}
+
+// recordContentType notes the media type served for an inventoried URL
+func (c *Checker) recordContentType(discoveredURL, contentType string) {
+	if contentType == "" {
+		return
+	}
+	if mediaType, _, err := mime.ParseMediaType(contentType); err == nil {
+		contentType = mediaType
+	}
+
+	id, ok := c.urls.lookup(discoveredURL)
+	if !ok {
+		return
+	}
+
+	c.inventoryMu.Lock()
+	defer c.inventoryMu.Unlock()
+
+	record, exists := c.inventory[id]
+	if !exists || record.contentType != 0 {
+		return
+	}
+	index := slices.Index(c.mediaTypes, contentType)
+	if index < 0 {
+		index = len(c.mediaTypes)
+		c.mediaTypes = append(c.mediaTypes, contentType)
+	}
+	record.contentType = uint16(index + 1) // #nosec G115 -- a site serves a handful of media types
+	c.inventory[id] = record
+}
+
+// Inventory returns every URL discovered so far, in discovery order. URLs
+// that spilled to disk are read back as the sequence is ranged over.
+func (c *Checker) Inventory() iter.Seq[InventoryEntry] {
+	return func(yield func(InventoryEntry) bool) {
+		for i := 0; ; i++ {
+			c.inventoryMu.Lock()
+			if i >= len(c.inventoryOrder) {
+				c.inventoryMu.Unlock()
+				return
+			}
+			id := c.inventoryOrder[i]
+			record := c.inventory[id]
+			var contentType string
+			if record.contentType > 0 {
+				contentType = c.mediaTypes[record.contentType-1]
+			}
+			c.inventoryMu.Unlock()
+
+			entry := InventoryEntry{URL: c.urls.url(id), Depth: int(record.depth), ContentType: contentType}
+			if record.source > 0 {
+				entry.Source = c.urls.url(record.source - 1)
+			}
+			if !yield(entry) {
+				return
+			}
+		}
+	}
+}
+
+// InventoryLen returns how many URLs have been discovered so far
+func (c *Checker) InventoryLen() int {
+	c.inventoryMu.Lock()
+	defer c.inventoryMu.Unlock()
+	return len(c.inventoryOrder)
+}
+
+// WriteInventory writes inventory entries to path as a JSON array, which
+// LoadURLList can read back to resume a later crawl. Entries are written as
+// they are ranged over, so a huge inventory needn't be held at once.
+func WriteInventory(path string, entries iter.Seq[InventoryEntry]) error {
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o644) // #nosec G302 G304 -- inventory is a shareable report
+	if err != nil {
+		return fmt.Errorf("writing URL inventory: %w", err)
+	}
+	defer file.Close()
+	w := bufio.NewWriter(file)
+	w.WriteString("[")
+	first := true
+	for entry := range entries {
+		data, err := json.MarshalIndent(entry, "  ", "  ")
+		if err != nil {
+			return fmt.Errorf("encoding URL inventory: %w", err)
+		}
+		if !first {
+			w.WriteString(",")
+		}
+		first = false
+		fmt.Fprintf(w, "\n  %s", data)
+	}
+	if !first {
+		w.WriteString("\n")
+	}
+	w.WriteString("]\n")
+	if err := w.Flush(); err != nil {
+		return fmt.Errorf("writing URL inventory: %w", err)
+	}
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("writing URL inventory: %w", err)
+	}
+	return nil
+}
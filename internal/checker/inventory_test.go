@@ -0,0 +1,174 @@
+package checker
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/joshbeard/link-validator/internal/config"
+)
+
+func TestLoadURLList(t *testing.T) {
+	dir := t.TempDir()
+
+	t.Run("array of strings", func(t *testing.T) {
+		path := filepath.Join(dir, "strings.json")
+		os.WriteFile(path, []byte(`["https://example.com/", "https://example.com/about"]`), 0o600)
+
+		urls, err := LoadURLList(path)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if len(urls) != 2 || urls[1] != "https://example.com/about" {
+			t.Errorf("Unexpected URLs: %v", urls)
+		}
+	})
+
+	t.Run("array of objects", func(t *testing.T) {
+		path := filepath.Join(dir, "objects.json")
+		os.WriteFile(path, []byte(`[{"url": "https://example.com/", "depth": 0}, {"url": ""}, {"url": "https://example.com/a"}]`), 0o600)
+
+		urls, err := LoadURLList(path)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if len(urls) != 2 || urls[1] != "https://example.com/a" {
+			t.Errorf("Unexpected URLs: %v", urls)
+		}
+	})
+
+	t.Run("invalid json", func(t *testing.T) {
+		path := filepath.Join(dir, "invalid.json")
+		os.WriteFile(path, []byte(`{"url": `), 0o600)
+
+		if _, err := LoadURLList(path); err == nil {
+			t.Error("Expected an error for invalid JSON")
+		}
+	})
+
+	t.Run("missing file", func(t *testing.T) {
+		if _, err := LoadURLList(filepath.Join(dir, "missing.json")); err == nil {
+			t.Error("Expected an error for a missing file")
+		}
+	})
+}
+
+func TestCrawlWithKnownURLs(t *testing.T) {
+	var mu sync.Mutex
+	fetched := make(map[string]int)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		fetched[r.URL.Path]++
+		mu.Unlock()
+
+		w.Header().Set("Content-Type", "text/html")
+		switch r.URL.Path {
+		case "/":
+			w.Write([]byte(`<a href="/old">Old</a><a href="/new">New</a>`))
+		case "/old":
+			w.Write([]byte(`<a href="/old/child">Child</a>`))
+		case "/new", "/old/child":
+			w.Write([]byte(`<p>Page</p>`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	checker := New(&config.Config{UserAgent: "TestBot/1.0", Timeout: 5 * time.Second})
+	checker.ImportKnownURLs([]string{server.URL, server.URL + "/old", server.URL + "/old", ""})
+
+	urls, err := checker.CrawlWebsite(server.URL, 2)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	expected := []string{server.URL, server.URL + "/old", server.URL + "/new"}
+	if len(urls) != len(expected) {
+		t.Fatalf("Expected %d URLs, got %d: %v", len(expected), len(urls), urls)
+	}
+	for i := range expected {
+		if urls[i] != expected[i] {
+			t.Errorf("Expected URL %s at index %d, got %s", expected[i], i, urls[i])
+		}
+	}
+
+	if fetched["/old"] != 0 {
+		t.Errorf("Expected known page /old not to be fetched, got %d requests", fetched["/old"])
+	}
+	if fetched["/"] == 0 {
+		t.Error("Expected the entry point to be crawled even though it is known")
+	}
+}
+
+func TestCrawlInventory(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/":
+			w.Header().Set("Content-Type", "text/html; charset=utf-8")
+			w.Write([]byte(`<a href="/docs/">Docs</a><a href="/guide.pdf">Guide</a>`))
+		case "/docs/":
+			w.Header().Set("Content-Type", "text/html")
+			w.Write([]byte(`<a href="/">Home</a><a href="/docs/intro">Intro</a>`))
+		default:
+			w.Header().Set("Content-Type", "application/pdf")
+		}
+	}))
+	defer server.Close()
+
+	checker := New(&config.Config{UserAgent: "TestBot/1.0", Timeout: 5 * time.Second, MaxConcurrent: 1})
+	if _, err := checker.CrawlWebsite(server.URL+"/", 2); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	expected := []InventoryEntry{
+		{URL: server.URL + "/", Depth: 0, ContentType: "text/html"},
+		{URL: server.URL + "/docs/", Depth: 1, Source: server.URL + "/", ContentType: "text/html"},
+		{URL: server.URL + "/guide.pdf", Depth: 1, Source: server.URL + "/", ContentType: "application/pdf"},
+		{URL: server.URL + "/docs/intro", Depth: 2, Source: server.URL + "/docs/"},
+	}
+	inventory := slices.Collect(checker.Inventory())
+	if len(inventory) != len(expected) {
+		t.Fatalf("Expected %d inventory entries, got %d: %+v", len(expected), len(inventory), inventory)
+	}
+	for i := range expected {
+		if inventory[i] != expected[i] {
+			t.Errorf("Entry %d: expected %+v, got %+v", i, expected[i], inventory[i])
+		}
+	}
+
+	// Content types seen while checking fill in pages that were not crawled
+	checker.CheckLinks([]string{server.URL + "/docs/intro"})
+	for entry := range checker.Inventory() {
+		if entry.URL == server.URL+"/docs/intro" && entry.ContentType != "application/pdf" {
+			t.Errorf("Expected content type application/pdf for checked URL, got %q", entry.ContentType)
+		}
+	}
+}
+
+func TestWriteInventory(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "inventory.json")
+	entries := []InventoryEntry{
+		{URL: "https://example.com/", Depth: 0, ContentType: "text/html"},
+		{URL: "https://example.com/a", Depth: 1, Source: "https://example.com/"},
+	}
+
+	if err := WriteInventory(path, slices.Values(entries)); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	// An exported inventory can be imported to resume a later crawl
+	urls, err := LoadURLList(path)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(urls) != 2 || urls[0] != "https://example.com/" || urls[1] != "https://example.com/a" {
+		t.Errorf("Unexpected URLs: %v", urls)
+	}
+}
@@ -0,0 +1,138 @@
+package checker
+
+import (
+	"net/url"
+
+	"golang.org/x/net/html"
+)
+
+// IssueType classifies a problem found in a crawled page's markup. Issues are
+// warnings: they are reported but never fail the run.
+type IssueType string
+
+// Issue types recorded in PageIssue.Type
+const (
+	IssueEmptyLinkText       IssueType = "empty_link_text"
+	IssueImageLinkWithoutAlt IssueType = "image_link_without_alt"
+	IssueGenericLinkText     IssueType = "generic_link_text"
+	IssueDuplicateID         IssueType = "duplicate_id"
+	IssueLocalLink           IssueType = "local_link"
+	IssuePlaceholderLink     IssueType = "placeholder_link"
+	IssueTrackingParams      IssueType = "tracking_params"
+	IssueMalformedURL        IssueType = "malformed_url"
+	IssueLongURL             IssueType = "long_url"
+	IssueRepairedLink        IssueType = "repaired_link"
+	IssueUnsupportedScheme   IssueType = "unsupported_scheme"
+	IssueInsecureLink        IssueType = "insecure_link"
+	IssueTruncatedPage       IssueType = "truncated_page"
+	IssueInvalidEmail        IssueType = "invalid_email"
+	IssueUndeliverableEmail  IssueType = "undeliverable_email"
+)
+
+// PageIssue is a problem found in the markup of a crawled page
+type PageIssue struct {
+	Page   string    `json:"page"`
+	Type   IssueType `json:"type"`
+	Link   string    `json:"link,omitempty"`
+	Detail string    `json:"detail"`
+}
+
+// CountIssues returns the number of issues of the given type
+func CountIssues(issues []PageIssue, issueType IssueType) int {
+	count := 0
+	for _, issue := range issues {
+		if issue.Type == issueType {
+			count++
+		}
+	}
+	return count
+}
+
+// recordIssue remembers a problem found on a page
+func (c *Checker) recordIssue(issue PageIssue) {
+	c.issuesMu.Lock()
+	defer c.issuesMu.Unlock()
+	c.issues = append(c.issues, issue)
+}
+
+// Issues returns the problems found in crawled pages, in discovery order
+func (c *Checker) Issues() []PageIssue {
+	c.issuesMu.Lock()
+	defer c.issuesMu.Unlock()
+	return append([]PageIssue(nil), c.issues...)
+}
+
+// auditsPages reports whether any markup audit is enabled
+func (c *Checker) auditsPages() bool {
+	return c.config.LinkTextAudit || c.config.DuplicateIDAudit || c.config.PlaceholderLinkAudit ||
+		c.config.TrackingParamAudit || c.config.URLSanityAudit || c.auditsMailto() || c.reportsSchemes()
+}
+
+// auditPage runs the enabled markup audits over a parsed page. Links are
+// resolved against resolveBase for reporting.
+func (c *Checker) auditPage(pageURL string, doc *html.Node, resolveBase *url.URL) {
+	if !c.auditsPages() {
+		return
+	}
+	page, err := url.Parse(pageURL)
+	if err != nil {
+		return
+	}
+
+	ids := newIDCounter()
+	var walk func(*html.Node)
+	walk = func(n *html.Node) {
+		if n.Type == html.ElementNode {
+			if id, ok := attr(n, "id"); ok {
+				ids.add(id)
+			}
+			if href, ok := attr(n, "href"); ok && n.Data == "a" {
+				c.auditScheme(pageURL, href)
+				link := resolveReference(resolveBase, href)
+				if c.config.LinkTextAudit {
+					c.auditLinkText(pageURL, n, link)
+				}
+				if c.config.PlaceholderLinkAudit {
+					c.auditLinkTarget(page, href, link)
+				}
+				if c.config.TrackingParamAudit {
+					c.auditTrackingParams(page, link)
+				}
+				if c.config.URLSanityAudit {
+					c.auditURLSanity(pageURL, href, link)
+				}
+				if c.auditsMailto() {
+					c.auditMailto(pageURL, href)
+				}
+			}
+		}
+		for child := n.FirstChild; child != nil; child = child.NextSibling {
+			walk(child)
+		}
+	}
+	walk(doc)
+
+	if c.config.DuplicateIDAudit {
+		c.reportDuplicateIDs(pageURL, ids)
+	}
+}
+
+// attr returns the value of a node's attribute
+func attr(n *html.Node, key string) (string, bool) {
+	for _, a := range n.Attr {
+		if a.Key == key {
+			return a.Val, true
+		}
+	}
+	return "", false
+}
+
+// resolveReference resolves href against base for display, falling back to
+// the raw value when it doesn't parse
+func resolveReference(base *url.URL, href string) string {
+	ref, err := url.Parse(href)
+	if err != nil {
+		return href
+	}
+	return base.ResolveReference(ref).String()
+}
@@ -0,0 +1,242 @@
+package checker
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/joshbeard/link-validator/internal/config"
+)
+
+// errInvalidJSONPath is returned for expressions outside the supported
+// JSONPath subset
+var errInvalidJSONPath = errors.New("invalid JSONPath")
+
+// jsonPathStep is one step of a JSONPath expression. An empty key with index
+// -1 is a wildcard. Recursive steps apply at any depth below the current
+// value (the .. operator).
+type jsonPathStep struct {
+	recursive bool
+	key       string
+	index     int
+}
+
+// wildcard reports whether the step matches every member or element
+func (s jsonPathStep) wildcard() bool {
+	return s.key == "" && s.index < 0
+}
+
+// ExtractJSONLinks fetches a JSON API endpoint and returns the URLs found at
+// the given JSONPath expressions, such as `$.data[*].links.self`. Relative
+// references are resolved against the endpoint. Without any expressions,
+// every string value that is an absolute http or https URL is returned.
+func (c *Checker) ExtractJSONLinks(endpoint string, paths []string) ([]string, error) {
+	var expressions [][]jsonPathStep
+	for _, path := range paths {
+		steps, err := parseJSONPath(path)
+		if err != nil {
+			return nil, fmt.Errorf("%w %q", err, path)
+		}
+		expressions = append(expressions, steps)
+	}
+
+	endpoint = c.RewritePreview(endpoint)
+	base, err := url.Parse(endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("parsing endpoint URL: %w", err)
+	}
+
+	req, err := http.NewRequest("GET", endpoint, nil)
+	if err != nil {
+		return nil, fmt.Errorf("creating request: %w", err)
+	}
+	req.Header.Set("User-Agent", c.userAgentFor(endpoint))
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("Accept-Encoding", acceptEncoding)
+	c.authorize(req)
+
+	resp, err := c.clientFor(endpoint).Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("fetching endpoint: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("endpoint returned status %d", resp.StatusCode)
+	}
+	body, err := decodeBody(resp)
+	if err != nil {
+		return nil, err
+	}
+	data, err := c.readBody(body)
+	if err != nil {
+		return nil, fmt.Errorf("reading endpoint: %w", err)
+	}
+	var document any
+	if err := json.Unmarshal(data, &document); err != nil {
+		return nil, fmt.Errorf("decoding JSON: %w", err)
+	}
+
+	var values []any
+	if len(expressions) == 0 {
+		values = evalJSONPath([]jsonPathStep{{recursive: true, index: -1}}, document)
+	}
+	for _, steps := range expressions {
+		values = append(values, evalJSONPath(steps, document)...)
+	}
+
+	var links []string
+	seen := make(map[string]bool)
+	for _, value := range values {
+		raw, ok := value.(string)
+		if !ok || strings.TrimSpace(raw) == "" {
+			continue
+		}
+		ref, err := url.Parse(strings.TrimSpace(raw))
+		if err != nil || (len(expressions) == 0 && !ref.IsAbs()) {
+			continue
+		}
+		linkURL := base.ResolveReference(ref)
+		link := c.normalizeURL(c.RewritePreview(linkURL.String()))
+		if action := c.schemeAction(link); (action != config.SchemeCheck && action != config.SchemeFail) || seen[link] || c.shouldExclude(link) {
+			continue
+		}
+		seen[link] = true
+		links = append(links, link)
+		c.recordSource(link, endpoint)
+	}
+	return links, nil
+}
+
+// parseJSONPath parses the supported JSONPath subset: $ followed by .name,
+// .*, ..name, ..*, [n], [*] and ['name'] steps
+func parseJSONPath(path string) ([]jsonPathStep, error) {
+	rest, ok := strings.CutPrefix(strings.TrimSpace(path), "$")
+	if !ok {
+		return nil, errInvalidJSONPath
+	}
+
+	var steps []jsonPathStep
+	for rest != "" {
+		step := jsonPathStep{index: -1}
+		if after, ok := strings.CutPrefix(rest, ".."); ok {
+			step.recursive = true
+			rest = after
+		} else if after, ok := strings.CutPrefix(rest, "."); ok {
+			rest = after
+		} else if !strings.HasPrefix(rest, "[") {
+			return nil, errInvalidJSONPath
+		}
+
+		if strings.HasPrefix(rest, "[") {
+			end := strings.IndexByte(rest, ']')
+			if end < 0 {
+				return nil, errInvalidJSONPath
+			}
+			selector := strings.TrimSpace(rest[1:end])
+			rest = rest[end+1:]
+			switch {
+			case selector == "*":
+			case len(selector) > 2 && (selector[0] == '\'' || selector[0] == '"') && selector[len(selector)-1] == selector[0]:
+				step.key = selector[1 : len(selector)-1]
+			default:
+				index, err := strconv.Atoi(selector)
+				if err != nil || index < 0 {
+					return nil, errInvalidJSONPath
+				}
+				step.index = index
+			}
+		} else {
+			end := strings.IndexAny(rest, ".[")
+			if end < 0 {
+				end = len(rest)
+			}
+			name := rest[:end]
+			if name == "" {
+				return nil, errInvalidJSONPath
+			}
+			if name != "*" {
+				step.key = name
+			}
+			rest = rest[end:]
+		}
+		steps = append(steps, step)
+	}
+	return steps, nil
+}
+
+// evalJSONPath returns the values a parsed JSONPath selects in a decoded
+// JSON document
+func evalJSONPath(steps []jsonPathStep, document any) []any {
+	current := []any{document}
+	for _, step := range steps {
+		var next []any
+		for _, value := range current {
+			if step.recursive {
+				walkJSON(value, func(descendant any) {
+					next = append(next, selectJSON(step, descendant)...)
+				})
+			} else {
+				next = append(next, selectJSON(step, value)...)
+			}
+		}
+		current = next
+	}
+	return current
+}
+
+// selectJSON applies a single step to a value without descending further
+func selectJSON(step jsonPathStep, value any) []any {
+	switch v := value.(type) {
+	case map[string]any:
+		if step.wildcard() {
+			keys := make([]string, 0, len(v))
+			for key := range v {
+				keys = append(keys, key)
+			}
+			sort.Strings(keys)
+			selected := make([]any, 0, len(keys))
+			for _, key := range keys {
+				selected = append(selected, v[key])
+			}
+			return selected
+		}
+		if member, ok := v[step.key]; ok && step.key != "" {
+			return []any{member}
+		}
+	case []any:
+		if step.wildcard() {
+			return v
+		}
+		if step.key == "" && step.index < len(v) {
+			return []any{v[step.index]}
+		}
+	}
+	return nil
+}
+
+// walkJSON calls fn for a value and each of its descendants, visiting object
+// members in key order
+func walkJSON(value any, fn func(any)) {
+	fn(value)
+	switch v := value.(type) {
+	case map[string]any:
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			walkJSON(v[key], fn)
+		}
+	case []any:
+		for _, element := range v {
+			walkJSON(element, fn)
+		}
+	}
+}
@@ -0,0 +1,105 @@
+package checker
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/joshbeard/link-validator/internal/config"
+)
+
+func TestEvalJSONPath(t *testing.T) {
+	var document any
+	err := json.Unmarshal([]byte(`{
+		"data": [
+			{"id": 1, "links": {"self": "/items/1", "docs": "https://example.com/docs"}},
+			{"id": 2, "links": {"self": "/items/2"}}
+		],
+		"meta": {"next": "/items?page=2"}
+	}`), &document)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []struct {
+		path     string
+		expected []any
+	}{
+		{"$.data[*].links.self", []any{"/items/1", "/items/2"}},
+		{"$.data[1].links.self", []any{"/items/2"}},
+		{"$['meta']['next']", []any{"/items?page=2"}},
+		{`$["meta"].next`, []any{"/items?page=2"}},
+		{"$..self", []any{"/items/1", "/items/2"}},
+		{"$.data[0].links.*", []any{"https://example.com/docs", "/items/1"}},
+		{"$.data[5].links.self", nil},
+		{"$.missing", nil},
+	}
+
+	for _, tc := range testCases {
+		steps, err := parseJSONPath(tc.path)
+		if err != nil {
+			t.Errorf("%s: expected no error, got %v", tc.path, err)
+			continue
+		}
+		if got := evalJSONPath(steps, document); !reflect.DeepEqual(got, tc.expected) {
+			t.Errorf("%s: expected %v, got %v", tc.path, tc.expected, got)
+		}
+	}
+
+	for _, path := range []string{"", "data.links", "$.", "$[", "$[-1]", "$[x]", "$.a..", "$..[''"} {
+		if _, err := parseJSONPath(path); err == nil {
+			t.Errorf("%q: expected an error", path)
+		}
+	}
+}
+
+func TestExtractJSONLinks(t *testing.T) {
+	var accept string
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		accept = r.Header.Get("Accept")
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{
+			"data": [
+				{"title": "See https://example.com/inline", "links": {"self": "/api/items/1"}},
+				{"links": {"self": "%s/api/items/2", "home": "https://example.com/"}},
+				{"links": {"self": "/api/items/1", "mail": "mailto:team@example.com"}}
+			]
+		}`, server.URL)
+	}))
+	defer server.Close()
+
+	checker := New(&config.Config{UserAgent: "TestBot/1.0", Timeout: 5 * time.Second, MaxConcurrent: 1})
+	endpoint := server.URL + "/api/items"
+	links, err := checker.ExtractJSONLinks(endpoint, []string{"$.data[*].links.self"})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	expected := []string{server.URL + "/api/items/1", server.URL + "/api/items/2"}
+	if !reflect.DeepEqual(links, expected) {
+		t.Errorf("Expected %v, got %v", expected, links)
+	}
+	if accept != "application/json" {
+		t.Errorf("Expected Accept application/json, got %q", accept)
+	}
+	if sources := checker.Sources(server.URL + "/api/items/1"); len(sources) != 1 || sources[0] != endpoint {
+		t.Errorf("Expected the endpoint as the source, got %v", sources)
+	}
+
+	links, err = checker.ExtractJSONLinks(endpoint, nil)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	expected = []string{"https://example.com/", server.URL + "/api/items/2"}
+	if !reflect.DeepEqual(links, expected) {
+		t.Errorf("Expected only absolute URLs without paths, got %v", links)
+	}
+
+	if _, err := checker.ExtractJSONLinks(endpoint, []string{"data"}); err == nil {
+		t.Error("Expected an error for an invalid JSONPath")
+	}
+}
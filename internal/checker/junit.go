@@ -0,0 +1,149 @@
+package checker
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"iter"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// junitSuites is the root of a JUnit XML document
+type junitSuites struct {
+	XMLName  xml.Name     `xml:"testsuites"`
+	Name     string       `xml:"name,attr"`
+	Tests    int          `xml:"tests,attr"`
+	Failures int          `xml:"failures,attr"`
+	Time     string       `xml:"time,attr"`
+	Suites   []junitSuite `xml:"testsuite"`
+}
+
+// junitSuite groups the test cases of a run
+type junitSuite struct {
+	Name     string      `xml:"name,attr"`
+	Tests    int         `xml:"tests,attr"`
+	Failures int         `xml:"failures,attr"`
+	Time     string      `xml:"time,attr"`
+	Cases    []junitCase `xml:"testcase"`
+}
+
+// junitCase is the check of a single URL
+type junitCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Time      string        `xml:"time,attr"`
+	Failure   *junitFailure `xml:"failure,omitempty"`
+	SystemOut string        `xml:"system-out,omitempty"`
+}
+
+// junitFailure describes why a test case failed
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Text    string `xml:",chardata"`
+}
+
+// WriteJUnit writes every result as a JUnit XML test case, so CI systems
+// such as Jenkins, GitLab and Azure DevOps show the run in their test UI.
+// Test cases are named by URL and grouped by host. Links that fail the run
+// are failures; other flagged links pass with their error as output.
+//
+// Results are ranged over twice, once for the totals the document opens
+// with and once to write the test cases as they are read, so the cases can
+// stream from a ResultStore.
+func WriteJUnit(w io.Writer, results iter.Seq[LinkResult]) error {
+	tests, failures := 0, 0
+	var total time.Duration
+	for result := range results {
+		duration, _ := time.ParseDuration(result.Duration)
+		total += duration
+		tests++
+		if result.Severity == SeverityError {
+			failures++
+		}
+	}
+	attrs := []xml.Attr{
+		junitAttr("tests", strconv.Itoa(tests)),
+		junitAttr("failures", strconv.Itoa(failures)),
+		junitAttr("time", junitSeconds(total)),
+	}
+	root := xml.StartElement{
+		Name: xml.Name{Local: "testsuites"},
+		Attr: append([]xml.Attr{junitAttr("name", "link-checker")}, attrs...),
+	}
+	suite := xml.StartElement{
+		Name: xml.Name{Local: "testsuite"},
+		Attr: append([]xml.Attr{junitAttr("name", "links")}, attrs...),
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	if err := encoder.EncodeToken(root); err != nil {
+		return fmt.Errorf("encoding JUnit report: %w", err)
+	}
+	if err := encoder.EncodeToken(suite); err != nil {
+		return fmt.Errorf("encoding JUnit report: %w", err)
+	}
+	for result := range results {
+		testCase := junitCaseFor(result)
+		if err := encoder.EncodeElement(testCase, xml.StartElement{Name: xml.Name{Local: "testcase"}}); err != nil {
+			return fmt.Errorf("encoding JUnit report: %w", err)
+		}
+	}
+	if err := encoder.EncodeToken(suite.End()); err != nil {
+		return fmt.Errorf("encoding JUnit report: %w", err)
+	}
+	if err := encoder.EncodeToken(root.End()); err != nil {
+		return fmt.Errorf("encoding JUnit report: %w", err)
+	}
+	if err := encoder.Flush(); err != nil {
+		return fmt.Errorf("encoding JUnit report: %w", err)
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+// junitCaseFor returns the test case of a result
+func junitCaseFor(result LinkResult) junitCase {
+	duration, _ := time.ParseDuration(result.Duration)
+	testCase := junitCase{
+		Name:      result.URL,
+		ClassName: junitClassName(result.URL),
+		Time:      junitSeconds(duration),
+	}
+	switch {
+	case result.Severity == SeverityError:
+		text := result.Error
+		if len(result.Sources) > 0 {
+			text = fmt.Sprintf("%s\nLinked from: %s", result.Error, summarySources(result.Sources))
+		}
+		testCase.Failure = &junitFailure{Message: result.Error, Type: string(result.ErrorType), Text: text}
+	case result.ErrorType != "":
+		testCase.SystemOut = fmt.Sprintf("%s: %s", result.ErrorType, result.Error)
+	}
+	return testCase
+}
+
+// junitAttr returns an XML attribute of a JUnit element
+func junitAttr(name, value string) xml.Attr {
+	return xml.Attr{Name: xml.Name{Local: name}, Value: value}
+}
+
+// junitClassName returns the host of a URL, which CI test UIs group cases
+// by, or the URL itself for paths without one
+func junitClassName(link string) string {
+	if u, err := url.Parse(link); err == nil && u.Host != "" {
+		return u.Host
+	}
+	return link
+}
+
+// junitSeconds formats a duration as the seconds JUnit times are given in
+func junitSeconds(d time.Duration) string {
+	return strconv.FormatFloat(d.Seconds(), 'f', 3, 64)
+}
@@ -0,0 +1,53 @@
+package checker
+
+import (
+	"bytes"
+	"encoding/xml"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestWriteJUnit(t *testing.T) {
+	results := []LinkResult{
+		{URL: "https://example.com/", StatusCode: 200, Duration: "250ms"},
+		{
+			URL:        "https://example.com/gone",
+			StatusCode: 404,
+			Error:      "HTTP 404 404 Not Found",
+			ErrorType:  ErrorTypeHTTP4xx,
+			Severity:   SeverityError,
+			Duration:   "1.5s",
+			Sources:    []string{"https://example.com/"},
+		},
+		{URL: "https://example.org/login", Error: "redirected to a login page", ErrorType: ErrorTypeAuthRequired, Severity: SeverityWarning},
+	}
+
+	var buf bytes.Buffer
+	if err := WriteJUnit(&buf, slices.Values(results)); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !strings.HasPrefix(buf.String(), xml.Header) {
+		t.Error("Expected an XML declaration")
+	}
+
+	var report junitSuites
+	if err := xml.Unmarshal(buf.Bytes(), &report); err != nil {
+		t.Fatalf("Expected valid XML, got %v", err)
+	}
+	if report.Tests != 3 || report.Failures != 1 || report.Time != "1.750" {
+		t.Errorf("Expected 3 tests, 1 failure in 1.750s, got %d, %d in %s", report.Tests, report.Failures, report.Time)
+	}
+	cases := report.Suites[0].Cases
+	if cases[0].Failure != nil || cases[0].ClassName != "example.com" || cases[0].Time != "0.250" {
+		t.Errorf("Unexpected passing case %+v", cases[0])
+	}
+	failure := cases[1].Failure
+	if failure == nil || failure.Type != "http_4xx" || failure.Message != "HTTP 404 404 Not Found" ||
+		!strings.Contains(failure.Text, "Linked from: https://example.com/") {
+		t.Errorf("Unexpected failure %+v", failure)
+	}
+	if cases[2].Failure != nil || cases[2].SystemOut != "auth_required: redirected to a login page" {
+		t.Errorf("Expected the warning to pass with output, got %+v", cases[2])
+	}
+}
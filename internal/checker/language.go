@@ -0,0 +1,16 @@
+package checker
+
+// acceptLanguageFor returns the Accept-Language header to send to a URL: the
+// locale it is being checked in, otherwise the first matching language rule,
+// otherwise the configured default. An empty result sends no header.
+func (c *Checker) acceptLanguageFor(rawURL, locale string) string {
+	if locale != "" {
+		return locale
+	}
+	for _, rule := range c.config.AcceptLanguageRules {
+		if rule.Pattern.MatchString(rawURL) {
+			return rule.Language
+		}
+	}
+	return c.config.AcceptLanguage
+}
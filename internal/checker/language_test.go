@@ -0,0 +1,75 @@
+package checker
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/joshbeard/link-validator/internal/config"
+)
+
+func TestAcceptLanguageFor(t *testing.T) {
+	checker := New(&config.Config{
+		MaxConcurrent:       1,
+		AcceptLanguage:      "en-US,en;q=0.9",
+		AcceptLanguageRules: config.ParseAcceptLanguageRules("/de/=de-DE"),
+	})
+
+	testCases := []struct {
+		url      string
+		locale   string
+		expected string
+	}{
+		{"https://example.com/docs/", "", "en-US,en;q=0.9"},
+		{"https://example.com/de/docs/", "", "de-DE"},
+		{"https://example.com/de/docs/", "fr", "fr"},
+	}
+	for _, tc := range testCases {
+		if got := checker.acceptLanguageFor(tc.url, tc.locale); got != tc.expected {
+			t.Errorf("acceptLanguageFor(%q, %q) = %q, expected %q", tc.url, tc.locale, got, tc.expected)
+		}
+	}
+
+	if got := New(&config.Config{MaxConcurrent: 1}).acceptLanguageFor("https://example.com/", ""); got != "" {
+		t.Errorf("Expected no Accept-Language by default, got %q", got)
+	}
+}
+
+func TestCheckLinksPerLocale(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Only the English page exists
+		if r.Header.Get("Accept-Language") != "en" {
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	checker := New(&config.Config{
+		UserAgent:     "TestBot/1.0",
+		Timeout:       5 * time.Second,
+		MaxConcurrent: 1,
+		CheckLocales:  []string{"en", "de"},
+	})
+	results := checker.CheckLinks([]string{server.URL + "/a", server.URL + "/b"})
+	if len(results) != 4 {
+		t.Fatalf("Expected one result per URL and locale, got %+v", results)
+	}
+
+	for i, expected := range []struct {
+		url    string
+		locale string
+		status int
+	}{
+		{server.URL + "/a", "en", http.StatusOK},
+		{server.URL + "/a", "de", http.StatusNotFound},
+		{server.URL + "/b", "en", http.StatusOK},
+		{server.URL + "/b", "de", http.StatusNotFound},
+	} {
+		result := results[i]
+		if result.URL != expected.url || result.Locale != expected.locale || result.StatusCode != expected.status {
+			t.Errorf("Result %d: expected %s [%s] %d, got %s [%s] %d", i,
+				expected.url, expected.locale, expected.status, result.URL, result.Locale, result.StatusCode)
+		}
+	}
+}
@@ -0,0 +1,97 @@
+package checker
+
+import (
+	"strings"
+	"time"
+
+	"github.com/joshbeard/link-validator/internal/cache"
+)
+
+// w3cDateLayouts are the W3C datetime forms used by sitemap dates
+var w3cDateLayouts = []string{
+	time.RFC3339,
+	"2006-01-02T15:04Z07:00",
+	"2006-01-02",
+}
+
+// parseW3CDate parses a sitemap date such as lastmod or publication_date
+func parseW3CDate(value string) (time.Time, bool) {
+	value = strings.TrimSpace(value)
+	for _, layout := range w3cDateLayouts {
+		if parsed, err := time.Parse(layout, value); err == nil {
+			return parsed, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// recordLastMod remembers the modification time a sitemap declares for a URL
+func (c *Checker) recordLastMod(pageURL, value string) {
+	lastmod, ok := parseW3CDate(value)
+	if !ok {
+		return
+	}
+
+	id, ok := c.urls.id(pageURL)
+	if !ok {
+		return
+	}
+
+	c.inventoryMu.Lock()
+	defer c.inventoryMu.Unlock()
+	c.lastmod[id] = lastmod
+}
+
+// unchangedResult returns the cached result for a URL that doesn't need
+// checking again: any URL that passed a check within CacheTTL, or, when
+// SkipUnchanged is enabled, an internal page whose sitemap lastmod is older
+// than its last successful check
+func (c *Checker) unchangedResult(checkURL string) (LinkResult, bool) {
+	if c.cache == nil {
+		return LinkResult{}, false
+	}
+	check, ok := c.cache.Check(checkURL)
+	if !ok {
+		return LinkResult{}, false
+	}
+
+	fresh := c.config.CacheTTL > 0 && time.Since(check.CheckedAt) < c.config.CacheTTL
+	if !fresh {
+		if !c.config.SkipUnchanged || !c.isInternal(checkURL) {
+			return LinkResult{}, false
+		}
+		id, ok := c.urls.lookup(checkURL)
+		if !ok {
+			return LinkResult{}, false
+		}
+		c.inventoryMu.Lock()
+		lastmod, ok := c.lastmod[id]
+		c.inventoryMu.Unlock()
+		if !ok || !check.CheckedAt.After(lastmod) {
+			return LinkResult{}, false
+		}
+	}
+
+	return LinkResult{
+		URL:        checkURL,
+		StatusCode: check.StatusCode,
+		Duration:   "0s",
+		Unchanged:  true,
+		CheckedAt:  check.CheckedAt.UTC().Format(time.RFC3339),
+	}, true
+}
+
+// recordCheck stores the outcome of a check in the cache. Successful checks
+// are remembered for later incremental runs and broken links are forgotten
+// so they are always rechecked.
+func (c *Checker) recordCheck(result LinkResult) {
+	if c.cache == nil {
+		return
+	}
+	switch {
+	case result.ErrorType == "":
+		c.cache.SetCheck(result.URL, cache.Check{StatusCode: result.StatusCode, CheckedAt: time.Now()})
+	case result.ErrorType.IsFailure():
+		c.cache.DeleteCheck(result.URL)
+	}
+}
@@ -0,0 +1,168 @@
+package checker
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/joshbeard/link-validator/internal/cache"
+	"github.com/joshbeard/link-validator/internal/config"
+)
+
+func TestParseW3CDate(t *testing.T) {
+	testCases := []struct {
+		value string
+		ok    bool
+	}{
+		{"2024-05-01", true},
+		{"2024-05-01T12:30+02:00", true},
+		{" 2024-05-01T12:30:45Z ", true},
+		{"2024-05-01T12:30:45.123Z", true},
+		{"May 1, 2024", false},
+		{"", false},
+	}
+
+	for _, tc := range testCases {
+		if _, ok := parseW3CDate(tc.value); ok != tc.ok {
+			t.Errorf("%q: expected ok=%v, got %v", tc.value, tc.ok, ok)
+		}
+	}
+}
+
+func TestSkipUnchangedFromLastMod(t *testing.T) {
+	var pageHits atomic.Int32
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/sitemap.xml" {
+			w.Header().Set("Content-Type", "application/xml")
+			fmt.Fprintf(w, `<?xml version="1.0" encoding="UTF-8"?>
+<urlset xmlns="http://www.sitemaps.org/schemas/sitemap/0.9">
+  <url><loc>%[1]s/old</loc><lastmod>2020-01-01</lastmod></url>
+  <url><loc>%[1]s/fresh</loc><lastmod>2999-01-01</lastmod></url>
+  <url><loc>%[1]s/undated</loc></url>
+</urlset>`, server.URL)
+			return
+		}
+		pageHits.Add(1)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	pageCache, _ := cache.Load(filepath.Join(t.TempDir(), "cache.json"))
+	recently := time.Now().Add(-time.Hour)
+	for _, path := range []string{"/old", "/fresh", "/undated"} {
+		pageCache.SetCheck(server.URL+path, cache.Check{StatusCode: 200, CheckedAt: recently})
+	}
+
+	cfg := &config.Config{
+		SitemapURL:    server.URL + "/sitemap.xml",
+		UserAgent:     "TestBot/1.0",
+		Timeout:       5 * time.Second,
+		MaxConcurrent: 1,
+		SkipUnchanged: true,
+	}
+	checker := New(cfg)
+	checker.UseCache(pageCache)
+
+	urls, err := checker.GetURLsFromSitemap(cfg.SitemapURL)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	results := checker.CheckLinks(urls)
+
+	unchanged := 0
+	for _, result := range results {
+		if result.Unchanged {
+			unchanged++
+			if result.URL != server.URL+"/old" || result.StatusCode != 200 {
+				t.Errorf("Unexpected unchanged result: %+v", result)
+			}
+		}
+	}
+	if unchanged != 1 {
+		t.Errorf("Expected 1 unchanged result, got %d", unchanged)
+	}
+	if pageHits.Load() != 2 {
+		t.Errorf("Expected only the fresh and undated pages to be requested, got %d requests", pageHits.Load())
+	}
+
+	if check, ok := pageCache.Check(server.URL + "/fresh"); !ok || !check.CheckedAt.After(recently) {
+		t.Error("Expected the successful check of /fresh to be recorded")
+	}
+}
+
+func TestSkipUnchangedDisabled(t *testing.T) {
+	pageCache, _ := cache.Load(filepath.Join(t.TempDir(), "cache.json"))
+	pageCache.SetCheck("https://example.com/old", cache.Check{StatusCode: 200, CheckedAt: time.Now()})
+
+	checker := New(&config.Config{BaseURL: "https://example.com", UserAgent: "TestBot/1.0", MaxConcurrent: 1})
+	checker.UseCache(pageCache)
+	checker.recordLastMod("https://example.com/old", "2020-01-01")
+
+	if _, ok := checker.unchangedResult("https://example.com/old"); ok {
+		t.Error("Expected pages to be rechecked unless SkipUnchanged is enabled")
+	}
+
+	checker.config.SkipUnchanged = true
+	if _, ok := checker.unchangedResult("https://example.com/old"); !ok {
+		t.Error("Expected the unchanged page to be skipped once enabled")
+	}
+
+	checker.recordLastMod("https://other.example.org/old", "2020-01-01")
+	pageCache.SetCheck("https://other.example.org/old", cache.Check{StatusCode: 200, CheckedAt: time.Now()})
+	if _, ok := checker.unchangedResult("https://other.example.org/old"); ok {
+		t.Error("Expected external pages to always be rechecked")
+	}
+}
+
+func TestSkipWithinCacheTTL(t *testing.T) {
+	var requests atomic.Int64
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	pageCache, _ := cache.Load(filepath.Join(t.TempDir(), "cache.json"))
+	pageCache.SetCheck(server.URL+"/recent", cache.Check{StatusCode: 200, CheckedAt: time.Now().Add(-time.Hour)})
+	pageCache.SetCheck(server.URL+"/stale", cache.Check{StatusCode: 200, CheckedAt: time.Now().Add(-48 * time.Hour)})
+
+	checker := New(&config.Config{UserAgent: "TestBot/1.0", Timeout: 5 * time.Second, MaxConcurrent: 1, CacheTTL: 24 * time.Hour})
+	checker.UseCache(pageCache)
+
+	results := checker.CheckLinks([]string{server.URL + "/recent", server.URL + "/stale", server.URL + "/new"})
+	if !results[0].Unchanged {
+		t.Errorf("Expected the recently checked URL to be skipped, got %+v", results[0])
+	}
+	if results[1].Unchanged || results[2].Unchanged {
+		t.Error("Expected stale and unknown URLs to be checked")
+	}
+	if got := requests.Load(); got != 2 {
+		t.Errorf("Expected 2 requests, got %d", got)
+	}
+
+	check, ok := pageCache.Check(server.URL + "/stale")
+	if !ok || time.Since(check.CheckedAt) > time.Minute {
+		t.Errorf("Expected the stale check to be refreshed, got %+v", check)
+	}
+}
+
+func TestRecordCheckForgetsBrokenLinks(t *testing.T) {
+	pageCache, _ := cache.Load(filepath.Join(t.TempDir(), "cache.json"))
+	checker := New(&config.Config{UserAgent: "TestBot/1.0", MaxConcurrent: 1})
+	checker.UseCache(pageCache)
+
+	checker.recordCheck(LinkResult{URL: "https://example.com/a", StatusCode: 200})
+	if _, ok := pageCache.Check("https://example.com/a"); !ok {
+		t.Fatal("Expected a successful check to be recorded")
+	}
+
+	checker.recordCheck(LinkResult{URL: "https://example.com/a", StatusCode: 404, ErrorType: ErrorTypeHTTP4xx})
+	if _, ok := pageCache.Check("https://example.com/a"); ok {
+		t.Error("Expected a broken link to be forgotten")
+	}
+}
@@ -0,0 +1,81 @@
+package checker
+
+import (
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// genericLinkTexts are link texts that say nothing about their target, which
+// leaves screen reader users navigating by links without context
+var genericLinkTexts = map[string]bool{
+	"click here": true,
+	"click":      true,
+	"here":       true,
+	"link":       true,
+	"this link":  true,
+	"more":       true,
+	"read more":  true,
+	"learn more": true,
+	"this":       true,
+	"go":         true,
+}
+
+// auditLinkText flags an anchor whose accessible name is empty or generic.
+// The name comes from aria-label or title when present, otherwise from the
+// anchor's text and the alt text of images inside it.
+func (c *Checker) auditLinkText(pageURL string, a *html.Node, link string) {
+	for _, key := range []string{"aria-label", "aria-labelledby", "title"} {
+		if value, ok := attr(a, key); ok && strings.TrimSpace(value) != "" {
+			return
+		}
+	}
+
+	text, images, missingAlt := linkText(a)
+	switch {
+	case text != "":
+		normalized := strings.ToLower(strings.Trim(text, " .!?:»›→…"))
+		if genericLinkTexts[normalized] {
+			c.recordIssue(PageIssue{Page: pageURL, Type: IssueGenericLinkText, Link: link,
+				Detail: "link text \"" + text + "\" does not describe its target"})
+		}
+	case images > 0 && missingAlt:
+		c.recordIssue(PageIssue{Page: pageURL, Type: IssueImageLinkWithoutAlt, Link: link,
+			Detail: "image-only link has no alt text"})
+	default:
+		c.recordIssue(PageIssue{Page: pageURL, Type: IssueEmptyLinkText, Link: link,
+			Detail: "link has no text"})
+	}
+}
+
+// linkText returns the visible text of an anchor including image alt text,
+// the number of images it contains and whether any of them lacks alt text
+func linkText(a *html.Node) (string, int, bool) {
+	var parts []string
+	images := 0
+	missingAlt := false
+
+	var walk func(*html.Node)
+	walk = func(n *html.Node) {
+		switch {
+		case n.Type == html.TextNode:
+			parts = append(parts, n.Data)
+		case n.Type == html.ElementNode && n.Data == "img":
+			images++
+			alt, ok := attr(n, "alt")
+			if !ok || strings.TrimSpace(alt) == "" {
+				missingAlt = true
+			} else {
+				parts = append(parts, alt)
+			}
+		case n.Type == html.ElementNode && (n.Data == "script" || n.Data == "style"):
+			return
+		}
+		for child := n.FirstChild; child != nil; child = child.NextSibling {
+			walk(child)
+		}
+	}
+	walk(a)
+
+	return strings.Join(strings.Fields(strings.Join(parts, " ")), " "), images, missingAlt
+}
@@ -0,0 +1,103 @@
+package checker
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/joshbeard/link-validator/internal/config"
+	"golang.org/x/net/html"
+)
+
+func TestAuditLinkText(t *testing.T) {
+	testCases := []struct {
+		name     string
+		markup   string
+		expected IssueType
+	}{
+		{"descriptive text", `<a href="/docs/">Installation guide</a>`, ""},
+		{"empty", `<a href="/docs/"> </a>`, IssueEmptyLinkText},
+		{"icon only", `<a href="/docs/"><i class="icon"></i></a>`, IssueEmptyLinkText},
+		{"generic", `<a href="/docs/">Click here</a>`, IssueGenericLinkText},
+		{"generic with punctuation", `<a href="/docs/">Read more…</a>`, IssueGenericLinkText},
+		{"image without alt", `<a href="/docs/"><img src="logo.png"></a>`, IssueImageLinkWithoutAlt},
+		{"image with alt", `<a href="/docs/"><img src="logo.png" alt="Docs home"></a>`, ""},
+		{"image with text", `<a href="/docs/"><img src="logo.png"> Docs</a>`, ""},
+		{"aria label", `<a href="/docs/" aria-label="Documentation"><i></i></a>`, ""},
+		{"title", `<a href="/docs/" title="Documentation">here</a>`, ""},
+	}
+
+	base, _ := url.Parse("https://example.com/")
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			checker := New(&config.Config{MaxConcurrent: 1, LinkTextAudit: true})
+			doc, err := html.Parse(strings.NewReader(tc.markup))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			checker.auditPage("https://example.com/page", doc, base)
+			issues := checker.Issues()
+			if tc.expected == "" {
+				if len(issues) != 0 {
+					t.Errorf("Expected no issues, got %+v", issues)
+				}
+				return
+			}
+			if len(issues) != 1 || issues[0].Type != tc.expected {
+				t.Fatalf("Expected one %q issue, got %+v", tc.expected, issues)
+			}
+			if issues[0].Link != "https://example.com/docs/" {
+				t.Errorf("Expected resolved link, got %s", issues[0].Link)
+			}
+		})
+	}
+}
+
+func TestLinkTextAuditDuringCrawl(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		fmt.Fprint(w, `<html><body><a href="/about/">here</a><a href="https://other.org/"></a></body></html>`)
+	}))
+	defer server.Close()
+
+	cfg := &config.Config{
+		UserAgent:     "TestBot/1.0",
+		Timeout:       5 * time.Second,
+		MaxConcurrent: 1,
+	}
+
+	t.Run("disabled by default", func(t *testing.T) {
+		checker := New(cfg)
+		if _, err := checker.CrawlWebsite(server.URL, 1); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if issues := checker.Issues(); len(issues) != 0 {
+			t.Errorf("Expected no issues without the audit, got %+v", issues)
+		}
+	})
+
+	t.Run("enabled", func(t *testing.T) {
+		audited := *cfg
+		audited.LinkTextAudit = true
+		checker := New(&audited)
+		if _, err := checker.CrawlWebsite(server.URL, 1); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		issues := checker.Issues()
+		if len(issues) != 2 {
+			t.Fatalf("Expected 2 issues, got %+v", issues)
+		}
+		if issues[0].Type != IssueGenericLinkText || issues[0].Page != server.URL {
+			t.Errorf("Unexpected first issue: %+v", issues[0])
+		}
+		if issues[1].Type != IssueEmptyLinkText || issues[1].Link != "https://other.org/" {
+			t.Errorf("Expected external links to be audited too, got %+v", issues[1])
+		}
+	})
+}
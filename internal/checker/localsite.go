@@ -0,0 +1,226 @@
+package checker
+
+import (
+	"fmt"
+	"io/fs"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"golang.org/x/net/html"
+)
+
+// CheckDirectory checks the links in a built site on disk, such as Hugo's
+// public/ directory, without a deployed copy of the site. Every HTML file is
+// parsed; links within the site are checked against the files in dir and
+// returned as results, while links to other sites are returned to be checked
+// over HTTP. Root-relative links, and absolute links under the configured
+// base URL, are within the site. A link to a directory needs its index.html,
+// and a link without an extension may also be served by the same name with
+// .html. Sources are the HTML files the links were found in.
+func (c *Checker) CheckDirectory(dir string) ([]LinkResult, []string, error) {
+	info, err := os.Stat(dir)
+	if err != nil {
+		return nil, nil, fmt.Errorf("reading site directory: %w", err)
+	}
+	if !info.IsDir() {
+		return nil, nil, fmt.Errorf("%s is not a directory", dir)
+	}
+
+	base := &url.URL{Scheme: "file", Path: "/"}
+	if c.config.BaseURL != "" {
+		if base, err = url.Parse(c.config.BaseURL); err != nil {
+			return nil, nil, fmt.Errorf("parsing base URL: %w", err)
+		}
+		if !strings.HasSuffix(base.Path, "/") {
+			base.Path += "/"
+		}
+	}
+
+	// Links within the site are named by their URL under the base URL, or by
+	// their path within the site when there is no base URL
+	var local, external []string
+	sitePaths := make(map[string]string)
+	seen := make(map[string]bool)
+	err = filepath.WalkDir(dir, func(filePath string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		ext := strings.ToLower(filepath.Ext(filePath))
+		if entry.IsDir() || (ext != ".html" && ext != ".htm") {
+			return nil
+		}
+		rel, err := filepath.Rel(dir, filePath)
+		if err != nil {
+			return err
+		}
+		pageURL := base.ResolveReference(&url.URL{Path: filepath.ToSlash(rel)})
+
+		links, err := c.extractLinksFromFile(filePath, pageURL)
+		if err != nil {
+			return fmt.Errorf("parsing %s: %w", filePath, err)
+		}
+		for _, link := range links {
+			name := link.url.String()
+			sitePath, inSite := localPath(link.url, base)
+			if inSite && c.config.BaseURL == "" {
+				name = sitePath
+			}
+			if (!inSite && link.url.Scheme != "http" && link.url.Scheme != "https" && !c.forbidsScheme(name)) || c.shouldExclude(name) {
+				continue
+			}
+			c.recordSource(name, filePath)
+			c.recordElement(name, link.element)
+			if seen[name] {
+				continue
+			}
+			seen[name] = true
+			if inSite {
+				local = append(local, name)
+				sitePaths[name] = sitePath
+			} else {
+				external = append(external, name)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	results := make([]LinkResult, 0, len(local))
+	for _, link := range local {
+		sitePath := sitePaths[link]
+		results = append(results, c.checkFile(link, func() string {
+			if !localFileExists(dir, sitePath) {
+				return fmt.Sprintf("no file in %s for %s", dir, sitePath)
+			}
+			return ""
+		}))
+	}
+	return results, external, nil
+}
+
+// checkFile checks a link against files on disk with check, which returns
+// why the file is missing or "" when it exists. The check is kept so the link
+// can be checked again by Recheck.
+func (c *Checker) checkFile(link string, check func() string) LinkResult {
+	c.inventoryMu.Lock()
+	c.files[link] = check
+	c.inventoryMu.Unlock()
+	return c.fileResult(link, check())
+}
+
+// fileCheck returns the check a link to a file on disk was made with
+func (c *Checker) fileCheck(link string) (func() string, bool) {
+	c.inventoryMu.Lock()
+	defer c.inventoryMu.Unlock()
+	check, ok := c.files[link]
+	return check, ok
+}
+
+// fileResult returns the result of a link checked against files on disk
+// rather than over HTTP. A missing file is reported as a 404 with the given
+// error; an empty error means the file was found.
+func (c *Checker) fileResult(link, missing string) LinkResult {
+	result := LinkResult{
+		URL:        link,
+		StatusCode: 200,
+		Duration:   "0s",
+		CheckedAt:  time.Now().UTC().Format(time.RFC3339),
+	}
+	if missing != "" {
+		result.StatusCode = 404
+		result.Error = missing
+		result.ErrorType = ErrorTypeHTTP4xx
+	}
+	c.attachSources(&result)
+	result.Element = c.elementFor(link)
+	result.Category = c.linkCategory(link, result.Element)
+	result.Severity = c.severity(result)
+	c.stream.write(result)
+	return result
+}
+
+// fileLink is a link found in an HTML file and the element it was found in
+type fileLink struct {
+	url     *url.URL
+	element string
+}
+
+// extractLinksFromFile parses an HTML file and returns the links in the
+// checked element types, resolved against the page's URL in the site
+func (c *Checker) extractLinksFromFile(filePath string, pageURL *url.URL) ([]fileLink, error) {
+	f, err := os.Open(filePath) // #nosec G304 -- walking the directory given by the user
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	doc, err := html.Parse(f)
+	if err != nil {
+		return nil, err
+	}
+	c.auditPage(pageURL.String(), doc, pageURL)
+
+	var links []fileLink
+	var extract func(*html.Node)
+	extract = func(n *html.Node) {
+		if c.excludesRegion(n) {
+			return
+		}
+		if n.Type == html.ElementNode && c.checksElement(n.Data) {
+			for _, href := range elementURLs(n) {
+				resolved := c.resolveURL(c.repairLink(pageURL.String(), href), pageURL)
+				if resolved == "" {
+					continue
+				}
+				if link, err := url.Parse(resolved); err == nil {
+					link.Fragment = ""
+					links = append(links, fileLink{url: link, element: n.Data})
+				}
+			}
+		}
+		for child := n.FirstChild; child != nil; child = child.NextSibling {
+			extract(child)
+		}
+	}
+	extract(doc)
+	return links, nil
+}
+
+// localPath returns the path of a link within the site at base, and whether
+// the link is within the site at all
+func localPath(link, base *url.URL) (string, bool) {
+	if link.Scheme != base.Scheme || !strings.EqualFold(link.Host, base.Host) {
+		return "", false
+	}
+	if link.Path+"/" == base.Path {
+		return "/", true
+	}
+	if !strings.HasPrefix(link.Path, base.Path) {
+		return "", false
+	}
+	return "/" + strings.TrimPrefix(link.Path, base.Path), true
+}
+
+// localFileExists reports whether a path within the site is served by a file
+// in dir: the file itself, a directory's index.html, or the path with .html
+func localFileExists(dir, sitePath string) bool {
+	name := filepath.Join(dir, filepath.FromSlash(path.Clean(sitePath)))
+	info, err := os.Stat(name)
+	switch {
+	case err == nil && info.IsDir():
+		_, err = os.Stat(filepath.Join(name, "index.html"))
+		return err == nil
+	case err == nil:
+		return true
+	case path.Ext(sitePath) == "":
+		_, err = os.Stat(name + ".html")
+		return err == nil
+	}
+	return false
+}
@@ -0,0 +1,113 @@
+package checker
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/joshbeard/link-validator/internal/config"
+)
+
+// writeSite writes files into a temporary directory, creating parent
+// directories as needed
+func writeSite(t *testing.T, files map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	for name, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatalf("Failed to create directory: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+	}
+	return dir
+}
+
+func TestCheckDirectory(t *testing.T) {
+	dir := writeSite(t, map[string]string{
+		"index.html": `<a href="/docs/">Docs</a>
+<a href="about">About</a>
+<a href="/missing/">Missing</a>
+<a href="https://partner.example/">Partner</a>
+<a href="mailto:hi@example.com">Mail</a>`,
+		"about.html":       `<a href="/docs/#install">Install</a><a href="/">Home</a><a href="/missing/">Missing</a>`,
+		"docs/index.html":  `<a href="setup/">Setup</a><a href="../logo.png">Logo</a>`,
+		"docs/setup/a.txt": `not an index`,
+		"logo.png":         `png`,
+	})
+
+	checker := New(&config.Config{})
+	results, external, err := checker.CheckDirectory(dir)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if !reflect.DeepEqual(external, []string{"https://partner.example/"}) {
+		t.Errorf("Expected only the partner link to be external, got %v", external)
+	}
+
+	broken := make(map[string]bool)
+	checked := make(map[string]LinkResult)
+	for _, result := range results {
+		checked[result.URL] = result
+		if result.ErrorType != "" {
+			broken[result.URL] = true
+		}
+	}
+	for _, url := range []string{"/", "/docs/", "/about", "/missing/", "/docs/setup/", "/logo.png"} {
+		if _, ok := checked[url]; !ok {
+			t.Errorf("Expected %s to be checked, got %v", url, checked)
+		}
+	}
+	if expected := map[string]bool{"/missing/": true, "/docs/setup/": true}; !reflect.DeepEqual(broken, expected) {
+		t.Errorf("Expected %v to be broken, got %v", expected, broken)
+	}
+
+	missing := checked["/missing/"]
+	if missing.SourceCount != 2 || len(missing.Sources) != 2 || missing.Sources[0] != filepath.Join(dir, "about.html") {
+		t.Errorf("Expected /missing/ to be linked from 2 files, got %v", missing.Sources)
+	}
+	// Working links only count their sources
+	if docs := checked["/docs/"]; docs.SourceCount != 2 || docs.Sources != nil {
+		t.Errorf("Expected /docs/ to count 2 files without listing them, got %d, %v", docs.SourceCount, docs.Sources)
+	}
+}
+
+func TestCheckDirectoryWithBaseURL(t *testing.T) {
+	dir := writeSite(t, map[string]string{
+		"index.html":      `<a href="https://example.com/blog/docs/">Docs</a><a href="/blog/gone/">Gone</a><a href="https://example.com/shop/">Shop</a>`,
+		"docs/index.html": `<a href="https://example.com/blog">Home</a>`,
+	})
+
+	checker := New(&config.Config{BaseURL: "https://example.com/blog"})
+	results, external, err := checker.CheckDirectory(dir)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if !reflect.DeepEqual(external, []string{"https://example.com/shop/"}) {
+		t.Errorf("Expected links outside the base path to be checked over HTTP, got %v", external)
+	}
+	statuses := make(map[string]int)
+	for _, result := range results {
+		statuses[result.URL] = result.StatusCode
+	}
+	expected := map[string]int{
+		"https://example.com/blog/docs/": 200,
+		"https://example.com/blog/gone/": 404,
+		"https://example.com/blog":       200,
+	}
+	if !reflect.DeepEqual(statuses, expected) {
+		t.Errorf("Expected %v, got %v", expected, statuses)
+	}
+}
+
+func TestCheckDirectoryMissing(t *testing.T) {
+	checker := New(&config.Config{})
+	if _, _, err := checker.CheckDirectory(filepath.Join(t.TempDir(), "public")); err == nil {
+		t.Error("Expected an error for a missing directory")
+	}
+}
@@ -0,0 +1,274 @@
+package checker
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// loginForm is the form found on a login page
+type loginForm struct {
+	action string
+	method string
+	fields url.Values
+}
+
+// Login signs in through the configured login form before checking, so the
+// session cookies it sets are sent with every later request. The login page
+// is fetched first to pick up its cookies and hidden fields such as CSRF
+// tokens, then the configured fields are submitted. When a success selector
+// is configured, the page the login lands on must contain a matching element.
+func (c *Checker) Login() error {
+	selector := c.config.LoginSuccessSelector
+	if selector != "" {
+		if _, _, err := parseSelector(selector); err != nil {
+			return fmt.Errorf("login success selector %q: %w", selector, err)
+		}
+	}
+
+	loginURL := c.RewritePreview(c.config.LoginURL)
+	if err := c.useCookieJar(); err != nil {
+		return err
+	}
+
+	page, pageURL, err := c.fetchLoginDocument(http.MethodGet, loginURL, nil)
+	if err != nil {
+		return fmt.Errorf("fetching login page: %w", err)
+	}
+	form := findLoginForm(page, pageURL)
+	for name, values := range c.config.LoginFields {
+		form.fields[name] = values
+	}
+
+	landing, _, err := c.fetchLoginDocument(form.method, form.action, form.fields)
+	if err != nil {
+		return fmt.Errorf("submitting login form: %w", err)
+	}
+	if selector != "" && findElement(landing, selector) == nil {
+		return fmt.Errorf("no element matching %q after logging in", selector)
+	}
+	return nil
+}
+
+// fetchLoginDocument requests a page of the login flow and parses it. Fields
+// are sent as the query of a GET or the form-encoded body of a POST.
+func (c *Checker) fetchLoginDocument(method, target string, fields url.Values) (*html.Node, *url.URL, error) {
+	var req *http.Request
+	var err error
+	if method == http.MethodGet {
+		if len(fields) > 0 {
+			target += "?" + fields.Encode()
+		}
+		req, err = http.NewRequest(method, target, nil)
+	} else {
+		req, err = http.NewRequest(method, target, strings.NewReader(fields.Encode()))
+		if err == nil {
+			req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		}
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	req.Header.Set("User-Agent", c.userAgentFor(target))
+	req.Header.Set("Accept-Encoding", acceptEncoding)
+	c.authorize(req)
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 400 {
+		return nil, nil, fmt.Errorf("%s returned status %d", target, resp.StatusCode)
+	}
+	body, err := decodeBody(resp)
+	if err != nil {
+		return nil, nil, err
+	}
+	doc, err := html.Parse(body)
+	if err != nil {
+		return nil, nil, err
+	}
+	return doc, resp.Request.URL, nil
+}
+
+// findLoginForm returns the form with a password field on a login page, or
+// the first form when none has one. Without any form, fields are posted
+// back to the page itself.
+func findLoginForm(doc *html.Node, pageURL *url.URL) loginForm {
+	var forms []*html.Node
+	var collect func(*html.Node)
+	collect = func(n *html.Node) {
+		if n.Type == html.ElementNode && n.Data == "form" {
+			forms = append(forms, n)
+		}
+		for child := n.FirstChild; child != nil; child = child.NextSibling {
+			collect(child)
+		}
+	}
+	collect(doc)
+
+	form := loginForm{action: pageURL.String(), method: http.MethodPost, fields: make(url.Values)}
+	if len(forms) == 0 {
+		return form
+	}
+	chosen := forms[0]
+	for _, candidate := range forms {
+		if findElement(candidate, `input[type=password]`) != nil {
+			chosen = candidate
+			break
+		}
+	}
+
+	if action, ok := attr(chosen, "action"); ok && strings.TrimSpace(action) != "" {
+		form.action = resolveReference(pageURL, strings.TrimSpace(action))
+	}
+	if method, ok := attr(chosen, "method"); ok && strings.EqualFold(strings.TrimSpace(method), http.MethodGet) {
+		form.method = http.MethodGet
+	}
+
+	var inputs func(*html.Node)
+	inputs = func(n *html.Node) {
+		if n.Type == html.ElementNode && n.Data == "input" {
+			name, _ := attr(n, "name")
+			inputType, _ := attr(n, "type")
+			if name != "" && strings.EqualFold(inputType, "hidden") {
+				value, _ := attr(n, "value")
+				form.fields.Set(name, value)
+			}
+		}
+		for child := n.FirstChild; child != nil; child = child.NextSibling {
+			inputs(child)
+		}
+	}
+	inputs(chosen)
+	return form
+}
+
+// findElement returns the first element under n matching a simple selector:
+// a tag name, #id, .class and [attr] or [attr=value] conditions, combined
+// without spaces, e.g. `a.logout` or `input[type=password]`
+func findElement(n *html.Node, selector string) *html.Node {
+	if n.Type == html.ElementNode && matchesSelector(n, selector) {
+		return n
+	}
+	for child := n.FirstChild; child != nil; child = child.NextSibling {
+		if found := findElement(child, selector); found != nil {
+			return found
+		}
+	}
+	return nil
+}
+
+// errInvalidSelector is returned for selectors outside the supported subset
+var errInvalidSelector = errors.New("invalid selector")
+
+// matchesSelector reports whether an element matches a simple selector.
+// Selectors that can't be parsed match nothing.
+func matchesSelector(n *html.Node, selector string) bool {
+	tag, conditions, err := parseSelector(strings.TrimSpace(selector))
+	if err != nil {
+		return false
+	}
+	if tag != "" && tag != "*" && !strings.EqualFold(n.Data, tag) {
+		return false
+	}
+	for _, condition := range conditions {
+		value, ok := attr(n, condition.attr)
+		if !ok {
+			return false
+		}
+		switch condition.kind {
+		case '#':
+			if value != condition.value {
+				return false
+			}
+		case '.':
+			if !containsField(strings.Fields(value), condition.value) {
+				return false
+			}
+		case '=':
+			if value != condition.value {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// selectorCondition is an #id, .class or attribute condition of a selector.
+// Kind '[' only requires the attribute to be present.
+type selectorCondition struct {
+	kind  byte
+	attr  string
+	value string
+}
+
+// parseSelector splits a simple selector into its tag name and conditions
+func parseSelector(selector string) (string, []selectorCondition, error) {
+	end := strings.IndexAny(selector, "#.[")
+	if end < 0 {
+		end = len(selector)
+	}
+	tag := selector[:end]
+	rest := selector[end:]
+
+	var conditions []selectorCondition
+	for rest != "" {
+		switch rest[0] {
+		case '#', '.':
+			next := strings.IndexAny(rest[1:], "#.[")
+			if next < 0 {
+				next = len(rest) - 1
+			}
+			value := rest[1 : next+1]
+			if value == "" {
+				return "", nil, errInvalidSelector
+			}
+			attrName := "id"
+			if rest[0] == '.' {
+				attrName = "class"
+			}
+			conditions = append(conditions, selectorCondition{kind: rest[0], attr: attrName, value: value})
+			rest = rest[next+1:]
+		case '[':
+			closing := strings.IndexByte(rest, ']')
+			if closing < 0 {
+				return "", nil, errInvalidSelector
+			}
+			name, value, hasValue := strings.Cut(rest[1:closing], "=")
+			name = strings.TrimSpace(name)
+			if name == "" {
+				return "", nil, errInvalidSelector
+			}
+			condition := selectorCondition{kind: '[', attr: name}
+			if hasValue {
+				condition.kind = '='
+				condition.value = strings.Trim(strings.TrimSpace(value), `"'`)
+			}
+			conditions = append(conditions, condition)
+			rest = rest[closing+1:]
+		default:
+			return "", nil, errInvalidSelector
+		}
+	}
+	if tag == "" && len(conditions) == 0 {
+		return "", nil, errInvalidSelector
+	}
+	return tag, conditions, nil
+}
+
+// containsField reports whether fields contains value
+func containsField(fields []string, value string) bool {
+	for _, field := range fields {
+		if field == value {
+			return true
+		}
+	}
+	return false
+}
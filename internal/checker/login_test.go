@@ -0,0 +1,136 @@
+package checker
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/joshbeard/link-validator/internal/config"
+	"golang.org/x/net/html"
+)
+
+func newLoginServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	mux := http.NewServeMux()
+	mux.HandleFunc("/login", func(w http.ResponseWriter, r *http.Request) {
+		http.SetCookie(w, &http.Cookie{Name: "csrf", Value: "token123", Path: "/"})
+		fmt.Fprint(w, `<form action="/search"><input name="q"></form>
+<form action="/session" method="post">
+<input type="hidden" name="csrf" value="token123">
+<input name="username"><input type="password" name="password">
+</form>`)
+	})
+	mux.HandleFunc("/session", func(w http.ResponseWriter, r *http.Request) {
+		cookie, err := r.Cookie("csrf")
+		if r.Method != http.MethodPost || err != nil || cookie.Value != r.FormValue("csrf") {
+			http.Error(w, "bad request", http.StatusBadRequest)
+			return
+		}
+		if r.FormValue("username") != "alice" || r.FormValue("password") != "s3cret" {
+			fmt.Fprint(w, `<p class="error">Wrong password</p>`)
+			return
+		}
+		http.SetCookie(w, &http.Cookie{Name: "session", Value: "ok", Path: "/"})
+		http.Redirect(w, r, "/account", http.StatusFound)
+	})
+	mux.HandleFunc("/account", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `<nav><a class="nav logout" href="/logout">Sign out</a></nav>`)
+	})
+	mux.HandleFunc("/private", func(w http.ResponseWriter, r *http.Request) {
+		if cookie, err := r.Cookie("session"); err != nil || cookie.Value != "ok" {
+			w.WriteHeader(http.StatusUnauthorized)
+		}
+	})
+	return httptest.NewServer(mux)
+}
+
+func TestLogin(t *testing.T) {
+	server := newLoginServer(t)
+	defer server.Close()
+
+	checker := New(&config.Config{
+		UserAgent:            "TestBot/1.0",
+		Timeout:              5 * time.Second,
+		MaxConcurrent:        1,
+		LoginURL:             server.URL + "/login",
+		LoginFields:          url.Values{"username": {"alice"}, "password": {"s3cret"}},
+		LoginSuccessSelector: "a.logout",
+	})
+
+	before := checker.CheckLinks([]string{server.URL + "/private"})
+	if before[0].StatusCode != http.StatusUnauthorized {
+		t.Fatalf("Expected 401 before logging in, got %d", before[0].StatusCode)
+	}
+
+	if err := checker.Login(); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	after := checker.CheckLinks([]string{server.URL + "/private"})
+	if after[0].StatusCode != http.StatusOK {
+		t.Errorf("Expected 200 after logging in, got %d", after[0].StatusCode)
+	}
+}
+
+func TestLoginFailure(t *testing.T) {
+	server := newLoginServer(t)
+	defer server.Close()
+
+	checker := New(&config.Config{
+		UserAgent:            "TestBot/1.0",
+		Timeout:              5 * time.Second,
+		MaxConcurrent:        1,
+		LoginURL:             server.URL + "/login",
+		LoginFields:          url.Values{"username": {"alice"}, "password": {"wrong"}},
+		LoginSuccessSelector: "a.logout",
+	})
+	err := checker.Login()
+	if err == nil || !strings.Contains(err.Error(), "a.logout") {
+		t.Errorf("Expected an error naming the success selector, got %v", err)
+	}
+
+	checker.config.LoginSuccessSelector = "a[href"
+	if err := checker.Login(); err == nil || !strings.Contains(err.Error(), "invalid selector") {
+		t.Errorf("Expected an invalid selector error, got %v", err)
+	}
+}
+
+func TestMatchesSelector(t *testing.T) {
+	doc, err := html.Parse(strings.NewReader(
+		`<div id="main"><a class="nav logout" href="/logout" data-role="exit">Sign out</a><input type="password"></div>`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []struct {
+		selector string
+		expected bool
+	}{
+		{"a", true},
+		{"A", true},
+		{".logout", true},
+		{"a.nav.logout", true},
+		{"a.log", false},
+		{"#main", true},
+		{"div#main", true},
+		{"span#main", false},
+		{"[data-role]", true},
+		{"a[data-role=exit]", true},
+		{`a[data-role="exit"]`, true},
+		{"a[data-role=enter]", false},
+		{"input[type=password]", true},
+		{"*#main", true},
+		{"a[href", false},
+		{"a..logout", false},
+		{"", false},
+	}
+
+	for _, tc := range testCases {
+		if got := findElement(doc, tc.selector) != nil; got != tc.expected {
+			t.Errorf("findElement(%q) = %v, expected %v", tc.selector, got, tc.expected)
+		}
+	}
+}
@@ -0,0 +1,121 @@
+package checker
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/mail"
+	"net/url"
+	"strings"
+)
+
+// mailResolver looks up the DNS records that tell whether a domain accepts
+// email. *net.Resolver implements it.
+type mailResolver interface {
+	LookupMX(ctx context.Context, name string) ([]*net.MX, error)
+	LookupHost(ctx context.Context, host string) ([]string, error)
+}
+
+// auditsMailto reports whether mailto: links are validated. Looking up their
+// domains implies validating them.
+func (c *Checker) auditsMailto() bool {
+	return c.config.MailtoAudit || c.config.MailtoMXLookup
+}
+
+// auditMailto flags the addresses of a mailto: link that aren't valid email
+// addresses and, with MailtoMXLookup, those whose domain can't receive mail
+func (c *Checker) auditMailto(pageURL, href string) {
+	href = strings.TrimSpace(href)
+	if !isMailto(href) {
+		return
+	}
+	for _, address := range mailtoAddresses(href) {
+		if !validEmailAddress(address) {
+			c.recordIssue(PageIssue{Page: pageURL, Type: IssueInvalidEmail, Link: href,
+				Detail: "mailto link has an invalid address: " + address})
+			continue
+		}
+		if !c.config.MailtoMXLookup {
+			continue
+		}
+		domain := address[strings.LastIndex(address, "@")+1:]
+		if reason := c.undeliverableDomain(domain); reason != "" {
+			c.recordIssue(PageIssue{Page: pageURL, Type: IssueUndeliverableEmail, Link: href,
+				Detail: domain + " " + reason})
+		}
+	}
+}
+
+// mailtoAddresses returns the recipients of a mailto: link, from both its
+// path and any "to" fields. A mailto: link may have none, to open a message
+// the reader addresses.
+func mailtoAddresses(href string) []string {
+	u, err := url.Parse(href)
+	if err != nil {
+		return []string{href}
+	}
+	recipients := []string{u.Opaque}
+	if opaque, err := url.PathUnescape(u.Opaque); err == nil {
+		recipients[0] = opaque
+	}
+	recipients = append(recipients, u.Query()["to"]...)
+
+	var addresses []string
+	for _, recipient := range recipients {
+		for _, address := range strings.Split(recipient, ",") {
+			if address = strings.TrimSpace(address); address != "" {
+				addresses = append(addresses, address)
+			}
+		}
+	}
+	return addresses
+}
+
+// validEmailAddress reports whether address is a bare email address whose
+// domain has at least two labels. Names and angle brackets, which mailto:
+// links don't allow, are rejected.
+func validEmailAddress(address string) bool {
+	parsed, err := mail.ParseAddress(address)
+	if err != nil || parsed.Name != "" || parsed.Address != address {
+		return false
+	}
+	domain := address[strings.LastIndex(address, "@")+1:]
+	return strings.Contains(strings.Trim(domain, "."), ".")
+}
+
+// undeliverableDomain returns why mail to a domain can't be delivered, or ""
+// when it can or the lookup didn't give a definite answer. Without MX
+// records, mail goes to the domain's own address, so only a domain with
+// neither, or with a null MX record, is undeliverable. Answers are cached
+// for the run.
+func (c *Checker) undeliverableDomain(domain string) string {
+	domain = strings.ToLower(domain)
+	c.mailDomainsMu.Lock()
+	defer c.mailDomainsMu.Unlock()
+	if reason, ok := c.mailDomains[domain]; ok {
+		return reason
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), c.config.Timeout)
+	defer cancel()
+
+	reason := ""
+	records, err := c.resolver.LookupMX(ctx, domain)
+	switch {
+	case err == nil && len(records) == 1 && records[0].Host == ".":
+		reason = "does not accept email (null MX record)"
+	case isDNSNotFound(err):
+		if _, err := c.resolver.LookupHost(ctx, domain); isDNSNotFound(err) {
+			reason = "has no MX or address records"
+		}
+	}
+	c.mailDomains[domain] = reason
+	return reason
+}
+
+// isDNSNotFound reports whether a lookup failed because the name or record
+// doesn't exist, as opposed to a timeout or unreachable server
+func isDNSNotFound(err error) bool {
+	var dnsErr *net.DNSError
+	return errors.As(err, &dnsErr) && dnsErr.IsNotFound
+}
@@ -0,0 +1,137 @@
+package checker
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/joshbeard/link-validator/internal/config"
+)
+
+// fakeMailResolver answers lookups from fixed records, failing with a not
+// found error for other names
+type fakeMailResolver struct {
+	mx      map[string][]*net.MX
+	hosts   map[string][]string
+	lookups int
+}
+
+func (r *fakeMailResolver) LookupMX(_ context.Context, name string) ([]*net.MX, error) {
+	r.lookups++
+	if records, ok := r.mx[name]; ok {
+		return records, nil
+	}
+	if name == "timeout.example" {
+		return nil, &net.DNSError{Err: "i/o timeout", Name: name, IsTimeout: true}
+	}
+	return nil, &net.DNSError{Err: "no such host", Name: name, IsNotFound: true}
+}
+
+func (r *fakeMailResolver) LookupHost(_ context.Context, host string) ([]string, error) {
+	if addresses, ok := r.hosts[host]; ok {
+		return addresses, nil
+	}
+	return nil, &net.DNSError{Err: "no such host", Name: host, IsNotFound: true}
+}
+
+func TestMailtoAddresses(t *testing.T) {
+	tests := []struct {
+		href     string
+		expected []string
+	}{
+		{"mailto:hello@example.com", []string{"hello@example.com"}},
+		{"mailto:a@example.com,%20b@example.com?subject=Hi", []string{"a@example.com", "b@example.com"}},
+		{"mailto:?to=sales@example.com&subject=Quote", []string{"sales@example.com"}},
+		{"mailto:?subject=Share", nil},
+	}
+	for _, test := range tests {
+		if got := mailtoAddresses(test.href); !reflect.DeepEqual(got, test.expected) {
+			t.Errorf("mailtoAddresses(%q) = %v, expected %v", test.href, got, test.expected)
+		}
+	}
+}
+
+func TestValidEmailAddress(t *testing.T) {
+	tests := []struct {
+		address string
+		valid   bool
+	}{
+		{"hello@example.com", true},
+		{"first.last+tag@mail.example.co.uk", true},
+		{"hello@example", false},
+		{"hello.example.com", false},
+		{"hello@@example.com", false},
+		{"hello @example.com", false},
+		{"Support <help@example.com>", false},
+		{"@example.com", false},
+	}
+	for _, test := range tests {
+		if got := validEmailAddress(test.address); got != test.valid {
+			t.Errorf("validEmailAddress(%q) = %v, expected %v", test.address, got, test.valid)
+		}
+	}
+}
+
+func TestAuditMailto(t *testing.T) {
+	resolver := &fakeMailResolver{
+		mx: map[string][]*net.MX{
+			"example.com":    {{Host: "mx.example.com.", Pref: 10}},
+			"nomail.example": {{Host: ".", Pref: 0}},
+		},
+		hosts: map[string][]string{"implicit.example": {"192.0.2.1"}},
+	}
+	checker := New(&config.Config{MaxConcurrent: 1, MailtoMXLookup: true})
+	checker.resolver = resolver
+
+	page := "https://example.com/contact"
+	for _, href := range []string{
+		"mailto:hello@example.com",
+		"mailto:hello@implicit.example",
+		"mailto:hello@timeout.example",
+		"mailto:hello@nomail.example",
+		"mailto:hello@missing.example",
+		"mailto:hello@example.com,broken",
+		"mailto:sales@missing.example",
+		"https://example.com/",
+	} {
+		checker.auditMailto(page, href)
+	}
+
+	expected := []PageIssue{
+		{Page: page, Type: IssueUndeliverableEmail, Link: "mailto:hello@nomail.example",
+			Detail: "nomail.example does not accept email (null MX record)"},
+		{Page: page, Type: IssueUndeliverableEmail, Link: "mailto:hello@missing.example",
+			Detail: "missing.example has no MX or address records"},
+		{Page: page, Type: IssueInvalidEmail, Link: "mailto:hello@example.com,broken",
+			Detail: "mailto link has an invalid address: broken"},
+		{Page: page, Type: IssueUndeliverableEmail, Link: "mailto:sales@missing.example",
+			Detail: "missing.example has no MX or address records"},
+	}
+	if issues := checker.Issues(); !reflect.DeepEqual(issues, expected) {
+		t.Errorf("Expected %+v, got %+v", expected, issues)
+	}
+	if resolver.lookups != 5 {
+		t.Errorf("Expected each domain to be looked up once, got %d lookups", resolver.lookups)
+	}
+}
+
+func TestCrawlAuditsMailto(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		w.Write([]byte(`<a href="mailto:hello@example.com">Email</a><a href="mailto:hello at example.com">Broken</a>`))
+	}))
+	defer server.Close()
+
+	checker := New(&config.Config{UserAgent: "TestBot/1.0", Timeout: 5 * time.Second, MaxConcurrent: 1, MailtoAudit: true})
+	if _, err := checker.CrawlWebsite(server.URL+"/", 1); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	issues := checker.Issues()
+	if len(issues) != 1 || issues[0].Type != IssueInvalidEmail || issues[0].Link != "mailto:hello at example.com" {
+		t.Errorf("Expected one invalid_email issue, got %+v", issues)
+	}
+}
@@ -0,0 +1,256 @@
+package checker
+
+import (
+	"bufio"
+	"fmt"
+	"io/fs"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+var (
+	// markdownDestination matches the destination of an inline link or image,
+	// such as "](docs/setup.md)" or `](https://example.com "Title")`. One level
+	// of parentheses is allowed within the destination.
+	markdownDestination = regexp.MustCompile(`\]\(\s*(<[^>\n]*>|(?:[^()\s]|\([^()\s]*\))+)(?:\s+(?:"[^"]*"|'[^']*'|\([^)]*\)))?\s*\)`)
+
+	// markdownDefinition matches a reference-style link definition, such as
+	// "[setup]: docs/setup.md"
+	markdownDefinition = regexp.MustCompile(`^ {0,3}\[[^\]]+\]:\s*(<[^>]*>|\S+)`)
+
+	// markdownAutolink matches an autolink, such as "<https://example.com>"
+	markdownAutolink = regexp.MustCompile(`<(https?://[^>\s]+)>`)
+
+	// markdownCodeSpan matches inline code, whose contents aren't links
+	markdownCodeSpan = regexp.MustCompile("`+[^`]*`+")
+)
+
+// CheckMarkdownFiles checks the links in the repository's Markdown files
+// matching the given glob patterns, such as "docs/**/*.md". Inline,
+// reference-style, image and autolinks are extracted, skipping code.
+// Relative links are resolved against each file's directory, and root-
+// relative links against the working directory, and are checked against the
+// files in the repository and returned as results named by their path.
+// Links to other sites are returned to be checked over HTTP. Sources are the
+// Markdown files the links were found in.
+func (c *Checker) CheckMarkdownFiles(patterns []string) ([]LinkResult, []string, error) {
+	files, err := globFiles(patterns)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(files) == 0 {
+		return nil, nil, fmt.Errorf("no files match %s", strings.Join(patterns, ", "))
+	}
+
+	var local, external []string
+	seen := make(map[string]bool)
+	for _, file := range files {
+		links, err := markdownFileLinks(file)
+		if err != nil {
+			return nil, nil, fmt.Errorf("reading %s: %w", file, err)
+		}
+
+		base := &url.URL{Scheme: "file", Path: "/" + filepath.ToSlash(file)}
+		for _, link := range links {
+			resolved := c.resolveURL(c.repairLink(file, link.url), base)
+			if resolved == "" {
+				continue
+			}
+			u, err := url.Parse(resolved)
+			if err != nil {
+				continue
+			}
+			u.Fragment = ""
+
+			name := u.String()
+			inRepo := u.Scheme == "file" && !c.forbidsScheme(link.url)
+			if inRepo {
+				name = strings.TrimPrefix(path.Clean(u.Path), "/")
+				if name == "" {
+					name = "."
+				}
+			}
+			if (!inRepo && u.Scheme != "http" && u.Scheme != "https" && !c.forbidsScheme(name)) || c.shouldExclude(name) {
+				continue
+			}
+			c.recordSource(name, file)
+			c.recordElement(name, link.element)
+			if seen[name] {
+				continue
+			}
+			seen[name] = true
+			if inRepo {
+				local = append(local, name)
+			} else {
+				external = append(external, name)
+			}
+		}
+	}
+
+	results := make([]LinkResult, 0, len(local))
+	for _, link := range local {
+		results = append(results, c.checkFile(link, func() string {
+			if _, err := os.Stat(filepath.FromSlash(link)); err != nil {
+				return fmt.Sprintf("no file %s in the repository", link)
+			}
+			return ""
+		}))
+	}
+	return results, external, nil
+}
+
+// markdownLink is a link destination found in a Markdown file and the
+// element it renders as, "a" or "img"
+type markdownLink struct {
+	url     string
+	element string
+}
+
+// markdownFileLinks returns the link destinations in a Markdown file, in the
+// order they appear. Fenced code blocks and inline code are skipped.
+func markdownFileLinks(file string) ([]markdownLink, error) {
+	f, err := os.Open(file) // #nosec G304 -- reading the files matched by the user's patterns
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var links []markdownLink
+	fence := ""
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		trimmed := strings.TrimSpace(line)
+		if fence != "" {
+			if strings.HasPrefix(trimmed, fence) {
+				fence = ""
+			}
+			continue
+		}
+		if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+			fence = trimmed[:3]
+			continue
+		}
+
+		line = markdownCodeSpan.ReplaceAllStringFunc(line, func(code string) string {
+			return strings.Repeat(" ", len(code))
+		})
+		if match := markdownDefinition.FindStringSubmatch(line); match != nil {
+			links = append(links, markdownLink{url: strings.Trim(match[1], "<>"), element: "a"})
+			continue
+		}
+		for _, match := range markdownDestination.FindAllStringSubmatchIndex(line, -1) {
+			element := "a"
+			if start := openingBracket(line, match[0]); start > 0 && line[start-1] == '!' {
+				element = "img"
+			}
+			links = append(links, markdownLink{url: strings.Trim(line[match[2]:match[3]], "<>"), element: element})
+		}
+		for _, match := range markdownAutolink.FindAllStringSubmatch(line, -1) {
+			links = append(links, markdownLink{url: match[1], element: "a"})
+		}
+	}
+	return links, scanner.Err()
+}
+
+// openingBracket returns the index of the "[" matching the "]" at end, or -1
+func openingBracket(line string, end int) int {
+	depth := 0
+	for i := end; i >= 0; i-- {
+		switch line[i] {
+		case ']':
+			depth++
+		case '[':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// globFiles returns the files matching any of the glob patterns, in lexical
+// order. "*" and "?" match within a path segment and "**" matches any
+// number of directories. The .git directory is never searched.
+func globFiles(patterns []string) ([]string, error) {
+	var files []string
+	seen := make(map[string]bool)
+	for _, pattern := range patterns {
+		pattern = strings.TrimPrefix(filepath.ToSlash(pattern), "./")
+		regex, err := globRegexp(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid pattern %q: %w", pattern, err)
+		}
+
+		root := globRoot(pattern)
+		err = filepath.WalkDir(filepath.FromSlash(root), func(name string, entry fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if entry.IsDir() {
+				if entry.Name() == ".git" {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+			name = filepath.ToSlash(name)
+			if regex.MatchString(name) && !seen[name] {
+				seen[name] = true
+				files = append(files, name)
+			}
+			return nil
+		})
+		if err != nil && !os.IsNotExist(err) {
+			return nil, err
+		}
+	}
+	return files, nil
+}
+
+// globRoot returns the directory a pattern's matches are all under: its
+// leading segments without wildcards
+func globRoot(pattern string) string {
+	segments := strings.Split(pattern, "/")
+	var root []string
+	for _, segment := range segments[:len(segments)-1] {
+		if strings.ContainsAny(segment, "*?[") {
+			break
+		}
+		root = append(root, segment)
+	}
+	if len(root) == 0 {
+		return "."
+	}
+	return strings.Join(root, "/")
+}
+
+// globRegexp converts a glob pattern to a regular expression matching whole
+// slash-separated paths
+func globRegexp(pattern string) (*regexp.Regexp, error) {
+	var b strings.Builder
+	b.WriteString("^")
+	for i := 0; i < len(pattern); i++ {
+		switch {
+		case strings.HasPrefix(pattern[i:], "**/"):
+			b.WriteString("(?:.*/)?")
+			i += 2
+		case strings.HasPrefix(pattern[i:], "**"):
+			b.WriteString(".*")
+			i++
+		case pattern[i] == '*':
+			b.WriteString("[^/]*")
+		case pattern[i] == '?':
+			b.WriteString("[^/]")
+		default:
+			b.WriteString(regexp.QuoteMeta(pattern[i : i+1]))
+		}
+	}
+	b.WriteString("$")
+	return regexp.Compile(b.String())
+}
@@ -0,0 +1,112 @@
+package checker
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/joshbeard/link-validator/internal/config"
+)
+
+func TestMarkdownFileLinks(t *testing.T) {
+	dir := writeSite(t, map[string]string{
+		"doc.md": "# Guide\n" +
+			"See [setup](setup.md#install) and [the API](https://example.com/api \"API\").\n" +
+			"[![Build](badge.svg)](https://ci.example.com/(main))\n" +
+			"Run `[not](a-link.md)` first, or visit <https://example.com/auto>.\n" +
+			"```\n[in a fence](fenced.md)\n```\n" +
+			"[ref]: <docs/ref page.md>\n",
+	})
+
+	links, err := markdownFileLinks(filepath.Join(dir, "doc.md"))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	expected := []markdownLink{
+		{"setup.md#install", "a"},
+		{"https://example.com/api", "a"},
+		{"badge.svg", "img"},
+		{"https://ci.example.com/(main)", "a"},
+		{"https://example.com/auto", "a"},
+		{"docs/ref page.md", "a"},
+	}
+	if !reflect.DeepEqual(links, expected) {
+		t.Errorf("Expected %v, got %v", expected, links)
+	}
+}
+
+func TestCheckMarkdownFiles(t *testing.T) {
+	dir := writeSite(t, map[string]string{
+		"README.md":        "[Guide](docs/guide.md) [Missing](docs/missing.md)",
+		"docs/guide.md":    "[Back](../README.md) [Setup](setup/) [Logo](/images/logo.png) [Site](https://example.com/)",
+		"docs/setup/a.md":  "[Gone](../gone.md)",
+		"images/logo.png":  "png",
+		"vendor/other.txt": "[Not markdown](nowhere.md)",
+	})
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = os.Chdir(wd) }()
+
+	checker := New(&config.Config{})
+	results, external, err := checker.CheckMarkdownFiles([]string{"*.md", "docs/**/*.md"})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if !reflect.DeepEqual(external, []string{"https://example.com/"}) {
+		t.Errorf("Expected the site link to be external, got %v", external)
+	}
+	statuses := make(map[string]int)
+	for _, result := range results {
+		statuses[result.URL] = result.StatusCode
+	}
+	expected := map[string]int{
+		"docs/guide.md":   200,
+		"docs/missing.md": 404,
+		"README.md":       200,
+		"docs/setup":      200,
+		"images/logo.png": 200,
+		"docs/gone.md":    404,
+	}
+	if !reflect.DeepEqual(statuses, expected) {
+		t.Errorf("Expected %v, got %v", expected, statuses)
+	}
+	if sources := checker.Sources("docs/gone.md"); !reflect.DeepEqual(sources, []string{"docs/setup/a.md"}) {
+		t.Errorf("Expected docs/gone.md to be linked from docs/setup/a.md, got %v", sources)
+	}
+
+	if _, _, err := checker.CheckMarkdownFiles([]string{"content/**/*.md"}); err == nil {
+		t.Error("Expected an error when no files match")
+	}
+}
+
+func TestGlobRegexp(t *testing.T) {
+	tests := []struct {
+		pattern string
+		path    string
+		want    bool
+	}{
+		{"docs/**/*.md", "docs/intro.md", true},
+		{"docs/**/*.md", "docs/guide/setup.md", true},
+		{"docs/**/*.md", "blog/intro.md", false},
+		{"*.md", "README.md", true},
+		{"*.md", "docs/intro.md", false},
+		{"**/*.md", "a/b/c.md", true},
+		{"docs/?.md", "docs/a.md", true},
+	}
+	for _, tt := range tests {
+		regex, err := globRegexp(tt.pattern)
+		if err != nil {
+			t.Fatalf("globRegexp(%q): %v", tt.pattern, err)
+		}
+		if got := regex.MatchString(tt.path); got != tt.want {
+			t.Errorf("%q matching %q = %v, want %v", tt.pattern, tt.path, got, tt.want)
+		}
+	}
+}
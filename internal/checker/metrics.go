@@ -0,0 +1,117 @@
+package checker
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"iter"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// metricsBuckets are the upper bounds, in seconds, of the buckets of the
+// link check duration histogram
+var metricsBuckets = []float64{0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30}
+
+// pushContentType is the Prometheus text format that OpenMetrics extends.
+// Every Pushgateway version accepts it, reading the "# EOF" line WriteMetrics
+// ends with as a comment.
+const pushContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+// WriteMetrics writes the totals of a run, its flagged links by error type
+// and a histogram of how long links took to check in the OpenMetrics text
+// format, such as for the node exporter's textfile collector or a
+// Pushgateway. Totals are gauges, since each run replaces the last one's.
+// The error counts and histogram are tallied in a single pass over results.
+func WriteMetrics(w io.Writer, summary Summary, results iter.Seq[LinkResult]) error {
+	bw := bufio.NewWriter(w)
+
+	gauge := func(name, help string, value float64) {
+		fmt.Fprintf(bw, "# HELP %s %s\n# TYPE %s gauge\n%s %s\n", name, help, name, name, formatMetric(value))
+	}
+	gauge("link_checker_links_checked", "Links checked by the run.", float64(summary.Checked))
+	gauge("link_checker_links_broken", "Broken links found by the run.", float64(len(summary.Broken)))
+	gauge("link_checker_links_accepted", "Links answering with an accepted status code.", float64(summary.Accepted))
+	gauge("link_checker_links_slow", "Links slower than the slow threshold.", float64(summary.Slow))
+	gauge("link_checker_run_duration_seconds", "How long the run took.", summary.Duration.Seconds())
+
+	counts := make(map[ErrorType]int)
+	buckets := make([]int, len(metricsBuckets))
+	var sum float64
+	var count int
+	for result := range results {
+		if result.ErrorType != "" {
+			counts[result.ErrorType]++
+		}
+		duration, err := time.ParseDuration(result.Duration)
+		if err != nil {
+			continue
+		}
+		seconds := duration.Seconds()
+		sum += seconds
+		count++
+		for i, bound := range metricsBuckets {
+			if seconds <= bound {
+				buckets[i]++
+			}
+		}
+	}
+
+	errorTypes := make([]string, 0, len(counts))
+	for errorType := range counts {
+		errorTypes = append(errorTypes, string(errorType))
+	}
+	sort.Strings(errorTypes)
+	fmt.Fprintf(bw, "# HELP link_checker_errors Flagged links by error type.\n# TYPE link_checker_errors gauge\n")
+	for _, errorType := range errorTypes {
+		fmt.Fprintf(bw, "link_checker_errors{error_type=%s} %d\n", strconv.Quote(errorType), counts[ErrorType(errorType)])
+	}
+
+	name := "link_checker_check_duration_seconds"
+	fmt.Fprintf(bw, "# HELP %s How long links took to check.\n# TYPE %s histogram\n", name, name)
+	for i, bound := range metricsBuckets {
+		fmt.Fprintf(bw, "%s_bucket{le=\"%s\"} %d\n", name, formatMetric(bound), buckets[i])
+	}
+	fmt.Fprintf(bw, "%s_bucket{le=\"+Inf\"} %d\n%s_sum %s\n%s_count %d\n", name, count, name, formatMetric(sum), name, count)
+
+	fmt.Fprintf(bw, "# EOF\n")
+	return bw.Flush()
+}
+
+// formatMetric formats a metric value without a needless exponent or
+// trailing zeros
+func formatMetric(value float64) string {
+	return strconv.FormatFloat(value, 'f', -1, 64)
+}
+
+// PushMetrics sends metrics written by WriteMetrics to a Prometheus
+// Pushgateway under the given job, replacing those the job pushed before
+func (c *Checker) PushMetrics(gateway, job string, metrics []byte) error {
+	endpoint := strings.TrimSuffix(gateway, "/") + "/metrics/job/" + url.PathEscape(job)
+
+	ctx, cancel := context.WithTimeout(context.Background(), c.config.Timeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, endpoint, bytes.NewReader(metrics))
+	if err != nil {
+		return fmt.Errorf("creating request: %w", err)
+	}
+	req.Header.Set("Content-Type", pushContentType)
+	req.Header.Set("User-Agent", c.userAgentFor(endpoint))
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("pushgateway returned status %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	return nil
+}
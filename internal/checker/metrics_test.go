@@ -0,0 +1,85 @@
+package checker
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/joshbeard/link-validator/internal/config"
+)
+
+func TestWriteMetrics(t *testing.T) {
+	results := []LinkResult{
+		{URL: "https://example.com/", StatusCode: 200, Duration: "50ms"},
+		{URL: "https://example.com/gone", StatusCode: 404, ErrorType: ErrorTypeHTTP4xx, Duration: "300ms"},
+		{URL: "https://example.com/missing", StatusCode: 404, ErrorType: ErrorTypeHTTP4xx, Duration: "2s"},
+		{URL: "https://nowhere.example/", ErrorType: ErrorTypeDNS, Duration: "45s"},
+	}
+	summary := Summary{Checked: 4, Broken: results[1:], Accepted: 1, Duration: 90 * time.Second}
+
+	var buf bytes.Buffer
+	if err := WriteMetrics(&buf, summary, slices.Values(results)); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	output := buf.String()
+
+	for _, line := range []string{
+		"# TYPE link_checker_links_checked gauge\nlink_checker_links_checked 4\n",
+		"link_checker_links_broken 3\n",
+		"link_checker_links_accepted 1\n",
+		"link_checker_links_slow 0\n",
+		"link_checker_run_duration_seconds 90\n",
+		"link_checker_errors{error_type=\"dns\"} 1\nlink_checker_errors{error_type=\"http_4xx\"} 2\n",
+		"# TYPE link_checker_check_duration_seconds histogram\n",
+		"link_checker_check_duration_seconds_bucket{le=\"0.1\"} 1\n",
+		"link_checker_check_duration_seconds_bucket{le=\"0.5\"} 2\n",
+		"link_checker_check_duration_seconds_bucket{le=\"30\"} 3\n",
+		"link_checker_check_duration_seconds_bucket{le=\"+Inf\"} 4\n",
+		"link_checker_check_duration_seconds_sum 47.35\n",
+		"link_checker_check_duration_seconds_count 4\n",
+	} {
+		if !strings.Contains(output, line) {
+			t.Errorf("Expected metrics to contain %q, got:\n%s", line, output)
+		}
+	}
+	if !strings.HasSuffix(output, "# EOF\n") {
+		t.Errorf("Expected metrics to end with # EOF, got:\n%s", output)
+	}
+}
+
+func TestPushMetrics(t *testing.T) {
+	var method, path, contentType, body string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		method, path, contentType = r.Method, r.URL.EscapedPath(), r.Header.Get("Content-Type")
+		data, _ := io.ReadAll(r.Body)
+		body = string(data)
+		if strings.HasSuffix(path, "/broken") {
+			http.Error(w, "text format parsing error", http.StatusBadRequest)
+		}
+	}))
+	defer server.Close()
+
+	checker := New(&config.Config{UserAgent: "TestBot/1.0", Timeout: 5 * time.Second, MaxConcurrent: 1})
+	if err := checker.PushMetrics(server.URL+"/", "docs site", []byte("link_checker_links_checked 4\n")); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if method != http.MethodPut || path != "/metrics/job/docs%20site" {
+		t.Errorf("Expected a PUT to the job's group, got %s %s", method, path)
+	}
+	if !strings.HasPrefix(contentType, "text/plain; version=0.0.4") {
+		t.Errorf("Expected the Prometheus text format, got %q", contentType)
+	}
+	if body != "link_checker_links_checked 4\n" {
+		t.Errorf("Expected the metrics as the body, got %q", body)
+	}
+
+	err := checker.PushMetrics(server.URL, "broken", nil)
+	if err == nil || !strings.Contains(err.Error(), "status 400: text format parsing error") {
+		t.Errorf("Expected the Pushgateway's error, got %v", err)
+	}
+}
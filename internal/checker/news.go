@@ -0,0 +1,34 @@
+package checker
+
+import (
+	"time"
+)
+
+// SitemapNews represents the <news:news> block of a Google News sitemap entry
+type SitemapNews struct {
+	Title           string `xml:"title"`
+	PublicationDate string `xml:"publication_date"`
+	Publication     struct {
+		Name     string `xml:"name"`
+		Language string `xml:"language"`
+	} `xml:"publication"`
+}
+
+// PublishedAt parses the article's publication date
+func (n *SitemapNews) PublishedAt() (time.Time, bool) {
+	return parseW3CDate(n.PublicationDate)
+}
+
+// isFreshNews reports whether a sitemap entry should be kept under the
+// configured news max age. Entries without news data, or with a date that
+// can't be parsed, are always kept.
+func (c *Checker) isFreshNews(news *SitemapNews) bool {
+	if news == nil || c.config.NewsMaxAge <= 0 {
+		return true
+	}
+	published, ok := news.PublishedAt()
+	if !ok {
+		return true
+	}
+	return time.Since(published) <= c.config.NewsMaxAge
+}
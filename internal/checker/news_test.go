@@ -0,0 +1,113 @@
+package checker
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/joshbeard/link-validator/internal/config"
+)
+
+func TestSitemapNewsPublishedAt(t *testing.T) {
+	testCases := []struct {
+		value    string
+		expected time.Time
+		ok       bool
+	}{
+		{"2024-05-01", time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC), true},
+		{"2024-05-01T13:45:00Z", time.Date(2024, 5, 1, 13, 45, 0, 0, time.UTC), true},
+		{"2024-05-01T13:45Z", time.Date(2024, 5, 1, 13, 45, 0, 0, time.UTC), true},
+		{" 2024-05-01T13:45:00+02:00 ", time.Date(2024, 5, 1, 11, 45, 0, 0, time.UTC), true},
+		{"yesterday", time.Time{}, false},
+	}
+
+	for _, tc := range testCases {
+		news := &SitemapNews{PublicationDate: tc.value}
+		published, ok := news.PublishedAt()
+		if ok != tc.ok {
+			t.Errorf("%q: expected ok %v, got %v", tc.value, tc.ok, ok)
+			continue
+		}
+		if ok && !published.Equal(tc.expected) {
+			t.Errorf("%q: expected %v, got %v", tc.value, tc.expected, published)
+		}
+	}
+}
+
+func TestGetURLsFromNewsSitemap(t *testing.T) {
+	fresh := time.Now().Add(-2 * time.Hour).UTC().Format(time.RFC3339)
+	stale := time.Now().Add(-10 * 24 * time.Hour).UTC().Format("2006-01-02")
+
+	sitemapXML := fmt.Sprintf(`<?xml version="1.0" encoding="UTF-8"?>
+<urlset xmlns="http://www.sitemaps.org/schemas/sitemap/0.9"
+        xmlns:news="http://www.google.com/schemas/sitemap-news/0.9">
+  <url>
+    <loc>https://example.com/news/fresh</loc>
+    <news:news>
+      <news:publication>
+        <news:name>Example Times</news:name>
+        <news:language>en</news:language>
+      </news:publication>
+      <news:publication_date>%s</news:publication_date>
+      <news:title>Fresh article</news:title>
+    </news:news>
+  </url>
+  <url>
+    <loc>https://example.com/news/stale</loc>
+    <news:news>
+      <news:publication>
+        <news:name>Example Times</news:name>
+        <news:language>en</news:language>
+      </news:publication>
+      <news:publication_date>%s</news:publication_date>
+      <news:title>Stale article</news:title>
+    </news:news>
+  </url>
+  <url>
+    <loc>https://example.com/about</loc>
+  </url>
+</urlset>`, fresh, stale)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/xml")
+		w.Write([]byte(sitemapXML))
+	}))
+	defer server.Close()
+
+	t.Run("all articles without max age", func(t *testing.T) {
+		checker := New(&config.Config{UserAgent: "TestBot/1.0", Timeout: 5 * time.Second})
+
+		urls, err := checker.GetURLsFromSitemap(server.URL)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if len(urls) != 3 {
+			t.Errorf("Expected 3 URLs, got %d: %v", len(urls), urls)
+		}
+	})
+
+	t.Run("stale articles filtered by max age", func(t *testing.T) {
+		checker := New(&config.Config{
+			UserAgent:  "TestBot/1.0",
+			Timeout:    5 * time.Second,
+			NewsMaxAge: 48 * time.Hour,
+			Verbose:    true,
+		})
+
+		urls, err := checker.GetURLsFromSitemap(server.URL)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		expected := []string{"https://example.com/news/fresh", "https://example.com/about"}
+		if len(urls) != len(expected) {
+			t.Fatalf("Expected %d URLs, got %d: %v", len(expected), len(urls), urls)
+		}
+		for i := range expected {
+			if urls[i] != expected[i] {
+				t.Errorf("Expected URL %s at index %d, got %s", expected[i], i, urls[i])
+			}
+		}
+	})
+}
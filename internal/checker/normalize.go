@@ -0,0 +1,93 @@
+package checker
+
+import (
+	"net/url"
+	"sort"
+	"strings"
+)
+
+// normalizeURL returns the form of a link that crawling and checking dedupe
+// on, so the same page isn't fetched under trivially different URLs. With
+// NormalizeURLs the scheme and host are lower-cased and default ports,
+// fragments and an empty path's missing slash dropped; with
+// IgnoreTrackingParams the TrackingParams are removed from the query, and
+// with ignoresQueryParams the query of an internal link is reduced to its
+// KeepParams. Excluded links and links that don't parse are returned as they
+// are, so that exclude patterns still see the link as written.
+func (c *Checker) normalizeURL(link string) string {
+	if !c.config.NormalizeURLs && !c.config.IgnoreTrackingParams && !c.ignoresQueryParams() {
+		return link
+	}
+	u, err := url.Parse(link)
+	if err != nil || u.Host == "" || c.shouldExclude(link) {
+		return link
+	}
+
+	if c.config.NormalizeURLs {
+		u.Scheme = strings.ToLower(u.Scheme)
+		u.Host = strings.ToLower(u.Host)
+		if port := u.Port(); (u.Scheme == "http" && port == "80") || (u.Scheme == "https" && port == "443") {
+			u.Host = strings.TrimSuffix(u.Host, ":"+port)
+		}
+		if u.Path == "" && u.Opaque == "" {
+			u.Path = "/"
+		}
+		u.Fragment, u.RawFragment = "", ""
+	}
+	if c.config.IgnoreTrackingParams && u.RawQuery != "" {
+		u.RawQuery = stripTrackingParams(u.RawQuery, c.config.TrackingParams)
+		u.ForceQuery = false
+	}
+	if c.ignoresQueryParams() && (u.RawQuery != "" || u.ForceQuery) && c.isInternal(link) {
+		u.RawQuery = canonicalQuery(u.RawQuery, c.config.KeepParams)
+		u.ForceQuery = false
+	}
+	return u.String()
+}
+
+// ignoresQueryParams reports whether internal links are deduplicated without
+// their query parameters. Listing parameters to keep implies ignoring the
+// others.
+func (c *Checker) ignoresQueryParams() bool {
+	return c.config.IgnoreQueryParams || len(c.config.KeepParams) > 0
+}
+
+// canonicalQuery keeps only the parameters of a raw query that match the
+// patterns, sorted by name so the same parameters in another order dedupe
+// together. The values and encoding of kept parameters are unchanged.
+func canonicalQuery(rawQuery string, patterns []string) string {
+	type param struct{ name, raw string }
+	var kept []param
+	for _, raw := range strings.Split(rawQuery, "&") {
+		name, _, _ := strings.Cut(raw, "=")
+		if unescaped, err := url.QueryUnescape(name); err == nil {
+			name = unescaped
+		}
+		if raw != "" && matchesParam(name, patterns) {
+			kept = append(kept, param{name, raw})
+		}
+	}
+	sort.SliceStable(kept, func(i, j int) bool { return kept[i].name < kept[j].name })
+
+	params := make([]string, len(kept))
+	for i, p := range kept {
+		params[i] = p.raw
+	}
+	return strings.Join(params, "&")
+}
+
+// stripTrackingParams removes the tracking parameters from a raw query,
+// keeping the order and encoding of the others
+func stripTrackingParams(rawQuery string, patterns []string) string {
+	var kept []string
+	for _, param := range strings.Split(rawQuery, "&") {
+		name, _, _ := strings.Cut(param, "=")
+		if unescaped, err := url.QueryUnescape(name); err == nil {
+			name = unescaped
+		}
+		if param != "" && !matchesParam(name, patterns) {
+			kept = append(kept, param)
+		}
+	}
+	return strings.Join(kept, "&")
+}
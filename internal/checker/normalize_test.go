@@ -0,0 +1,156 @@
+package checker
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/joshbeard/link-validator/internal/config"
+)
+
+func TestNormalizeURL(t *testing.T) {
+	checker := New(&config.Config{
+		NormalizeURLs:        true,
+		IgnoreTrackingParams: true,
+		TrackingParams:       config.ParseList(config.DefaultTrackingParams),
+		ExcludePatterns:      config.ParsePatterns("#private$"),
+	})
+
+	tests := []struct {
+		link     string
+		expected string
+	}{
+		{"HTTPS://Example.COM:443/Docs/#install", "https://example.com/Docs/"},
+		{"http://example.com:80", "http://example.com/"},
+		{"http://example.com:8080/", "http://example.com:8080/"},
+		{"https://example.com/?utm_source=feed&page=2&fbclid=abc", "https://example.com/?page=2"},
+		{"https://example.com/?utm_source=feed", "https://example.com/"},
+		{"https://example.com/page#private", "https://example.com/page#private"},
+		{"/relative#top", "/relative#top"},
+		{"", ""},
+	}
+	for _, test := range tests {
+		if got := checker.normalizeURL(test.link); got != test.expected {
+			t.Errorf("normalizeURL(%q) = %q, expected %q", test.link, got, test.expected)
+		}
+	}
+
+	off := New(&config.Config{})
+	if got := off.normalizeURL("HTTPS://Example.COM/#install"); got != "HTTPS://Example.COM/#install" {
+		t.Errorf("Expected links to be left alone without normalization, got %q", got)
+	}
+
+	keepTracking := New(&config.Config{NormalizeURLs: true, TrackingParams: []string{"utm_*"}})
+	if got := keepTracking.normalizeURL("https://example.com/?utm_source=feed#top"); got != "https://example.com/?utm_source=feed" {
+		t.Errorf("Expected tracking parameters to be kept, got %q", got)
+	}
+}
+
+func TestCrawlDedupesNormalizedURLs(t *testing.T) {
+	requests := make(map[string]int)
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests[r.URL.RequestURI()]++
+		w.Header().Set("Content-Type", "text/html")
+		if r.URL.Path == "/" {
+			fmt.Fprintf(w, `<a href="/docs/">Docs</a>
+<a href="/docs/#install">Install</a>
+<a href="%s/docs/">Docs again</a>
+<a href="/docs/?utm_source=nav">Tracked</a>`, server.URL)
+		}
+	}))
+	defer server.Close()
+
+	checker := New(&config.Config{
+		UserAgent:            "TestBot/1.0",
+		Timeout:              5 * time.Second,
+		MaxConcurrent:        1,
+		NormalizeURLs:        true,
+		IgnoreTrackingParams: true,
+		TrackingParams:       []string{"utm_*"},
+	})
+	urls, err := checker.CrawlWebsite(server.URL+"/", 2)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	expected := []string{server.URL + "/", server.URL + "/docs/"}
+	if !reflect.DeepEqual(urls, expected) {
+		t.Errorf("Expected %v, got %v", expected, urls)
+	}
+	if requests["/docs/"] != 1 {
+		t.Errorf("Expected /docs/ to be crawled once, got %d requests", requests["/docs/"])
+	}
+}
+
+func TestNormalizeURLQueryParams(t *testing.T) {
+	checker := New(&config.Config{BaseURL: "https://example.com", KeepParams: []string{"page", "id"}})
+
+	tests := []struct {
+		link     string
+		expected string
+	}{
+		{"https://example.com/shop?color=red&size=m", "https://example.com/shop"},
+		{"https://example.com/blog?ref=sidebar&page=2", "https://example.com/blog?page=2"},
+		{"https://example.com/item?page=1&id=7&sort=asc", "https://example.com/item?id=7&page=1"},
+		{"https://example.com/item?PAGE=3", "https://example.com/item?PAGE=3"},
+		{"https://example.com/search?", "https://example.com/search"},
+		{"https://other.example.org/watch?v=abc", "https://other.example.org/watch?v=abc"},
+	}
+	for _, test := range tests {
+		if got := checker.normalizeURL(test.link); got != test.expected {
+			t.Errorf("normalizeURL(%q) = %q, expected %q", test.link, got, test.expected)
+		}
+	}
+
+	ignoreAll := New(&config.Config{BaseURL: "https://example.com", IgnoreQueryParams: true})
+	if got := ignoreAll.normalizeURL("https://example.com/shop?page=2#top"); got != "https://example.com/shop#top" {
+		t.Errorf("Expected the whole query to be dropped, got %q", got)
+	}
+}
+
+func TestCrawlIgnoresQueryParams(t *testing.T) {
+	requests := make(map[string]int)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			requests[r.URL.RequestURI()]++
+		}
+		w.Header().Set("Content-Type", "text/html")
+		if r.URL.Path == "/shop" {
+			// Every facet links to more facets, which would never run out
+			page := r.URL.Query().Get("page")
+			if page == "" {
+				page = "1"
+			}
+			fmt.Fprintf(w, `<a href="/shop?color=red&amp;page=%[1]s">Red</a>
+<a href="/shop?size=m&amp;color=blue&amp;page=%[1]s">Medium</a>
+<a href="/shop?page=2">Next</a>`, page)
+		}
+	}))
+	defer server.Close()
+
+	checker := New(&config.Config{
+		BaseURL:       server.URL,
+		UserAgent:     "TestBot/1.0",
+		Timeout:       5 * time.Second,
+		MaxConcurrent: 1,
+		KeepParams:    []string{"page"},
+	})
+	urls, err := checker.CrawlWebsite(server.URL+"/shop", 5)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	expected := []string{server.URL + "/shop", server.URL + "/shop?page=1", server.URL + "/shop?page=2"}
+	if !reflect.DeepEqual(urls, expected) {
+		t.Errorf("Expected %v, got %v", expected, urls)
+	}
+	for uri, count := range requests {
+		if count > 1 {
+			t.Errorf("Expected %s to be requested once, got %d", uri, count)
+		}
+	}
+}
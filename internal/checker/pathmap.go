@@ -0,0 +1,89 @@
+package checker
+
+import (
+	"net/url"
+	"strings"
+
+	"github.com/joshbeard/link-validator/internal/config"
+)
+
+// MapFileToURL maps a repository file path to the site URL it is published
+// at, using the first matching path rule. Rules produce site paths that are
+// resolved against baseURL.
+func MapFileToURL(filePath string, rules []config.PathRule, baseURL string) (string, bool) {
+	base, err := url.Parse(baseURL)
+	if err != nil {
+		return "", false
+	}
+
+	for _, rule := range rules {
+		match := rule.Pattern.FindStringSubmatchIndex(filePath)
+		if match == nil {
+			continue
+		}
+		sitePath := string(rule.Pattern.ExpandString(nil, rule.Replacement, filePath, match))
+		ref, err := url.Parse(sitePath)
+		if err != nil {
+			return "", false
+		}
+		if !strings.HasPrefix(ref.Path, "/") && ref.Host == "" {
+			ref.Path = "/" + ref.Path
+		}
+		return base.ResolveReference(ref).String(), true
+	}
+	return "", false
+}
+
+// ChangedPageURLs maps changed repository files to the site pages they
+// produce, dropping files no rule matches and duplicate pages
+func ChangedPageURLs(files []string, rules []config.PathRule, baseURL string) []string {
+	var pages []string
+	seen := make(map[string]bool)
+	for _, file := range files {
+		page, ok := MapFileToURL(file, rules, baseURL)
+		if !ok || seen[page] {
+			continue
+		}
+		seen[page] = true
+		pages = append(pages, page)
+	}
+	return pages
+}
+
+// MapURLToFile maps a site URL back to the repository file it is built from,
+// using the first path rule that matches the URL's path
+func MapURLToFile(pageURL string, rules []config.PathRule) (string, bool) {
+	u, err := url.Parse(pageURL)
+	if err != nil {
+		return "", false
+	}
+
+	path := u.Path
+	if path == "" {
+		path = "/"
+	}
+	for _, rule := range rules {
+		match := rule.Pattern.FindStringSubmatchIndex(path)
+		if match == nil {
+			continue
+		}
+		return string(rule.Pattern.ExpandString(nil, rule.Replacement, path, match)), true
+	}
+	return "", false
+}
+
+// SourceFiles maps the pages linking to a URL back to their repository
+// files, dropping pages no rule matches and duplicate files
+func SourceFiles(sources []string, rules []config.PathRule) []string {
+	var files []string
+	seen := make(map[string]bool)
+	for _, source := range sources {
+		file, ok := MapURLToFile(source, rules)
+		if !ok || seen[file] {
+			continue
+		}
+		seen[file] = true
+		files = append(files, file)
+	}
+	return files
+}
@@ -0,0 +1,113 @@
+package checker
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/joshbeard/link-validator/internal/config"
+)
+
+func TestMapFileToURL(t *testing.T) {
+	rules := config.ParsePathRules(`^content/(.+)/_index\.md$=/$1/,^content/(.+)\.md$=/$1/,^static/(.*)=$1`)
+
+	testCases := []struct {
+		file     string
+		expected string
+		ok       bool
+	}{
+		{"content/docs/intro.md", "https://example.com/docs/intro/", true},
+		{"content/docs/_index.md", "https://example.com/docs/", true},
+		{"static/img/logo.png", "https://example.com/img/logo.png", true},
+		{"README.md", "", false},
+	}
+
+	for _, tc := range testCases {
+		got, ok := MapFileToURL(tc.file, rules, "https://example.com/site-root-is-ignored")
+		if ok != tc.ok || got != tc.expected {
+			t.Errorf("%s: expected (%q, %v), got (%q, %v)", tc.file, tc.expected, tc.ok, got, ok)
+		}
+	}
+}
+
+func TestChangedPageURLs(t *testing.T) {
+	rules := config.ParsePathRules(`^content/(.+)\.md$=/$1/,^content/(.+)\.png$=/$1/`)
+	files := []string{"content/a.md", "go.mod", "content/a.png", "content/b.md"}
+
+	pages := ChangedPageURLs(files, rules, "https://example.com")
+	expected := []string{"https://example.com/a/", "https://example.com/b/"}
+	if len(pages) != len(expected) || pages[0] != expected[0] || pages[1] != expected[1] {
+		t.Errorf("Expected %v, got %v", expected, pages)
+	}
+}
+
+func TestMapURLToFile(t *testing.T) {
+	rules := config.ParsePathRules(`^/$=content/_index.md,^/docs/(.+)/$=content/docs/$1.md`)
+
+	testCases := []struct {
+		url      string
+		expected string
+		ok       bool
+	}{
+		{"https://example.com/docs/foo/", "content/docs/foo.md", true},
+		{"https://example.com/docs/foo/?ref=nav#top", "content/docs/foo.md", true},
+		{"https://example.com", "content/_index.md", true},
+		{"https://example.com/blog/post/", "", false},
+	}
+
+	for _, tc := range testCases {
+		got, ok := MapURLToFile(tc.url, rules)
+		if ok != tc.ok || got != tc.expected {
+			t.Errorf("%s: expected (%q, %v), got (%q, %v)", tc.url, tc.expected, tc.ok, got, ok)
+		}
+	}
+}
+
+func TestSourceFiles(t *testing.T) {
+	rules := config.ParsePathRules(`^/docs/(.+)/$=content/docs/$1.md`)
+	sources := []string{
+		"https://example.com/docs/a/",
+		"https://example.com/blog/",
+		"https://example.com/docs/a/#section",
+		"https://example.com/docs/b/",
+	}
+
+	files := SourceFiles(sources, rules)
+	if len(files) != 2 || files[0] != "content/docs/a.md" || files[1] != "content/docs/b.md" {
+		t.Errorf("Expected [content/docs/a.md content/docs/b.md], got %v", files)
+	}
+}
+
+func TestCrawlPages(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		switch r.URL.Path {
+		case "/":
+			w.Write([]byte(`<a href="/unrelated">Unrelated</a>`))
+		case "/changed/":
+			w.Write([]byte(`<a href="/linked">Linked</a><a href="/other">Other</a>`))
+		case "/linked":
+			w.Write([]byte(`<a href="/deeper">Deeper</a>`))
+		default:
+			w.Write([]byte(`<p>Page</p>`))
+		}
+	}))
+	defer server.Close()
+
+	checker := New(&config.Config{UserAgent: "TestBot/1.0", Timeout: 5 * time.Second})
+	urls, err := checker.CrawlPages(server.URL, []string{server.URL + "/changed/", "https://elsewhere.example.com/"}, 1)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	expected := []string{server.URL + "/changed/", server.URL + "/linked", server.URL + "/other"}
+	if len(urls) != len(expected) {
+		t.Fatalf("Expected %d URLs, got %d: %v", len(expected), len(urls), urls)
+	}
+	for i := range expected {
+		if urls[i] != expected[i] {
+			t.Errorf("Expected URL %s at index %d, got %s", expected[i], i, urls[i])
+		}
+	}
+}
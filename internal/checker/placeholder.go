@@ -0,0 +1,78 @@
+package checker
+
+import (
+	"net"
+	"net/url"
+	"strings"
+)
+
+// placeholderHrefs are href values left behind while drafting a page
+var placeholderHrefs = map[string]bool{
+	"todo":        true,
+	"tbd":         true,
+	"fixme":       true,
+	"xxx":         true,
+	"placeholder": true,
+	"url":         true,
+	"link":        true,
+}
+
+// auditLinkTarget flags links that are valid URLs but can't be what the
+// author meant: links to localhost or private addresses that only worked on
+// their machine, and placeholder or reserved example domains. Links to the
+// page's own host are never flagged, so sites served locally aren't.
+func (c *Checker) auditLinkTarget(pageURL *url.URL, href, link string) {
+	trimmed := strings.Trim(strings.ToLower(strings.TrimSpace(href)), "#/")
+	if placeholderHrefs[trimmed] {
+		c.recordIssue(PageIssue{Page: pageURL.String(), Type: IssuePlaceholderLink, Link: link,
+			Detail: "link target \"" + strings.TrimSpace(href) + "\" is a placeholder"})
+		return
+	}
+
+	u, err := url.Parse(link)
+	if err != nil || u.Host == "" || strings.EqualFold(u.Host, pageURL.Host) {
+		return
+	}
+	host := strings.ToLower(u.Hostname())
+
+	switch {
+	case isLocalHost(host):
+		c.recordIssue(PageIssue{Page: pageURL.String(), Type: IssueLocalLink, Link: link,
+			Detail: "link points to a local or private address (" + host + ")"})
+	case isPlaceholderHost(host):
+		c.recordIssue(PageIssue{Page: pageURL.String(), Type: IssuePlaceholderLink, Link: link,
+			Detail: "link points to a placeholder domain (" + host + ")"})
+	}
+}
+
+// isLocalHost reports whether a host is only reachable from the author's
+// machine or network
+func isLocalHost(host string) bool {
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") || strings.HasSuffix(host, ".local") {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && (ip.IsLoopback() || ip.IsPrivate() || ip.IsLinkLocalUnicast() || ip.IsUnspecified())
+}
+
+// isPlaceholderHost reports whether a host is reserved for documentation
+// (RFC 2606) or an obvious stand-in for a real domain
+func isPlaceholderHost(host string) bool {
+	for _, domain := range []string{"example.com", "example.org", "example.net"} {
+		if host == domain || strings.HasSuffix(host, "."+domain) {
+			return true
+		}
+	}
+	for _, tld := range []string{".example", ".test", ".invalid"} {
+		if strings.HasSuffix(host, tld) {
+			return true
+		}
+	}
+	if placeholderHrefs[host] {
+		// e.g. "https://todo/"
+		return true
+	}
+	host = strings.TrimPrefix(host, "www.")
+	return strings.HasPrefix(host, "yourdomain.") || strings.HasPrefix(host, "your-domain.") ||
+		strings.HasPrefix(host, "mydomain.") || strings.HasPrefix(host, "my-domain.")
+}
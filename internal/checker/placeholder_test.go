@@ -0,0 +1,73 @@
+package checker
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/joshbeard/link-validator/internal/config"
+	"golang.org/x/net/html"
+)
+
+func TestAuditLinkTarget(t *testing.T) {
+	testCases := []struct {
+		href     string
+		expected IssueType
+	}{
+		{"/docs/", ""},
+		{"https://github.com/owner/repo", ""},
+		{"https://link.springer.com/article", ""},
+		{"/docs/todo-app/", ""},
+		{"http://localhost:1313/docs/", IssueLocalLink},
+		{"http://127.0.0.1:8080/", IssueLocalLink},
+		{"http://192.168.1.20/admin", IssueLocalLink},
+		{"http://[::1]/", IssueLocalLink},
+		{"http://printer.local/", IssueLocalLink},
+		{"https://example.com/api", IssuePlaceholderLink},
+		{"https://docs.example.org/", IssuePlaceholderLink},
+		{"https://api.test/", IssuePlaceholderLink},
+		{"https://yourdomain.com/", IssuePlaceholderLink},
+		{"TODO", IssuePlaceholderLink},
+		{"#tbd", IssuePlaceholderLink},
+		{"https://todo/", IssuePlaceholderLink},
+		{"https://www.mysite.com/about", ""},
+	}
+
+	base, _ := url.Parse("https://www.mysite.com/page/")
+	for _, tc := range testCases {
+		t.Run(tc.href, func(t *testing.T) {
+			checker := New(&config.Config{MaxConcurrent: 1, PlaceholderLinkAudit: true})
+			doc, err := html.Parse(strings.NewReader(`<a href="` + tc.href + `">Docs</a>`))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			checker.auditPage(base.String(), doc, base)
+			issues := checker.Issues()
+			if tc.expected == "" {
+				if len(issues) != 0 {
+					t.Errorf("Expected no issues, got %+v", issues)
+				}
+				return
+			}
+			if len(issues) != 1 || issues[0].Type != tc.expected {
+				t.Errorf("Expected one %q issue, got %+v", tc.expected, issues)
+			}
+		})
+	}
+}
+
+func TestAuditLinkTargetSameHost(t *testing.T) {
+	checker := New(&config.Config{MaxConcurrent: 1, PlaceholderLinkAudit: true})
+	doc, err := html.Parse(strings.NewReader(`<a href="http://localhost:1313/about/">About</a>`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// A site served locally links to itself on localhost
+	page, _ := url.Parse("http://localhost:1313/")
+	checker.auditPage(page.String(), doc, page)
+	if issues := checker.Issues(); len(issues) != 0 {
+		t.Errorf("Expected links to the page's own host to be ignored, got %+v", issues)
+	}
+}
@@ -0,0 +1,49 @@
+package checker
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// UsePreview redirects checks of the production site to a deploy preview.
+// Every URL on the origin of productionURL, whether from the sitemap, a
+// crawled page or an entry point, is rewritten to the origin of previewURL
+// so pull requests validate the preview without any rewrite rules.
+func (c *Checker) UsePreview(productionURL, previewURL string) error {
+	from, err := url.Parse(productionURL)
+	if err != nil || from.Host == "" {
+		return fmt.Errorf("invalid production URL %q", productionURL)
+	}
+	to, err := url.Parse(previewURL)
+	if err != nil || to.Host == "" {
+		return fmt.Errorf("invalid preview URL %q", previewURL)
+	}
+
+	c.previewFrom = &url.URL{Scheme: from.Scheme, Host: from.Host}
+	c.previewTo = &url.URL{Scheme: to.Scheme, Host: to.Host}
+	return nil
+}
+
+// RewritePreview returns rawURL with the production origin replaced by the
+// preview origin. URLs on other origins, or any URL when no preview is
+// configured, are returned unchanged.
+func (c *Checker) RewritePreview(rawURL string) string {
+	if c.previewFrom == nil || rawURL == "" {
+		return rawURL
+	}
+
+	u, err := url.Parse(rawURL)
+	if err != nil || !strings.EqualFold(u.Host, c.previewFrom.Host) {
+		return rawURL
+	}
+	// Production links often use http:// or the other scheme by accident;
+	// the host alone identifies the site
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return rawURL
+	}
+
+	u.Scheme = c.previewTo.Scheme
+	u.Host = c.previewTo.Host
+	return u.String()
+}
@@ -0,0 +1,117 @@
+package checker
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/joshbeard/link-validator/internal/config"
+)
+
+func TestRewritePreview(t *testing.T) {
+	checker := New(&config.Config{MaxConcurrent: 1})
+
+	if got := checker.RewritePreview("https://example.com/docs/"); got != "https://example.com/docs/" {
+		t.Errorf("Expected no rewrite without a preview, got %s", got)
+	}
+
+	if err := checker.UsePreview("https://example.com/blog/", "https://deploy-preview-7--example.netlify.app/"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	testCases := []struct {
+		url      string
+		expected string
+	}{
+		{"https://example.com/docs/?page=2#intro", "https://deploy-preview-7--example.netlify.app/docs/?page=2#intro"},
+		{"http://EXAMPLE.com/", "https://deploy-preview-7--example.netlify.app/"},
+		{"https://www.example.com/", "https://www.example.com/"},
+		{"https://other.org/example.com", "https://other.org/example.com"},
+		{"", ""},
+	}
+	for _, tc := range testCases {
+		if got := checker.RewritePreview(tc.url); got != tc.expected {
+			t.Errorf("RewritePreview(%q): expected %q, got %q", tc.url, tc.expected, got)
+		}
+	}
+}
+
+func TestUsePreviewInvalid(t *testing.T) {
+	checker := New(&config.Config{MaxConcurrent: 1})
+
+	if err := checker.UsePreview("", "https://preview.example.com"); err == nil {
+		t.Error("Expected an error without a production URL")
+	}
+	if err := checker.UsePreview("https://example.com", "preview"); err == nil {
+		t.Error("Expected an error for a preview URL without a host")
+	}
+}
+
+func TestCrawlPreview(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "text/html")
+		fmt.Fprint(w, `<html><body><a href="https://example.com/about/">About</a></body></html>`)
+	})
+	mux.HandleFunc("/about/", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		fmt.Fprint(w, `<html><body>About</body></html>`)
+	})
+	mux.HandleFunc("/sitemap.xml", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/xml")
+		fmt.Fprint(w, `<?xml version="1.0"?><urlset xmlns="http://www.sitemaps.org/schemas/sitemap/0.9">
+<url><loc>https://example.com/about/</loc></url>
+<url><loc>https://other.org/</loc></url>
+</urlset>`)
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	cfg := &config.Config{
+		UserAgent:     "TestBot/1.0",
+		Timeout:       5 * time.Second,
+		MaxConcurrent: 1,
+	}
+
+	t.Run("crawl", func(t *testing.T) {
+		checker := New(cfg)
+		if err := checker.UsePreview("https://example.com", server.URL); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		urls, err := checker.CrawlWebsite("https://example.com/", 2)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		expected := []string{server.URL + "/", server.URL + "/about/"}
+		if len(urls) != len(expected) {
+			t.Fatalf("Expected %v, got %v", expected, urls)
+		}
+		for i := range expected {
+			if urls[i] != expected[i] {
+				t.Errorf("URL %d: expected %s, got %s", i, expected[i], urls[i])
+			}
+		}
+	})
+
+	t.Run("sitemap", func(t *testing.T) {
+		checker := New(cfg)
+		if err := checker.UsePreview("https://example.com", server.URL); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		urls, err := checker.GetURLsFromSitemap("https://example.com/sitemap.xml")
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if len(urls) != 2 || urls[0] != server.URL+"/about/" || urls[1] != "https://other.org/" {
+			t.Errorf("Expected production URLs to be rewritten, got %v", urls)
+		}
+	})
+}
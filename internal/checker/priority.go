@@ -0,0 +1,106 @@
+package checker
+
+import (
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Check orders accepted by PrioritizeURLs
+const (
+	OrderDiscovery  = "discovery"
+	OrderImportance = "importance"
+)
+
+// defaultSitemapPriority is the priority the sitemap protocol assumes for
+// URLs that don't declare one
+const defaultSitemapPriority = 0.5
+
+// recordPriority remembers the priority a sitemap declares for a URL
+func (c *Checker) recordPriority(pageURL, value string) {
+	priority, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+	if err != nil || priority < 0 || priority > 1 {
+		return
+	}
+
+	id, ok := c.urls.id(pageURL)
+	if !ok {
+		return
+	}
+
+	c.inventoryMu.Lock()
+	defer c.inventoryMu.Unlock()
+	c.priority[id] = priority
+}
+
+// PrioritizeURLs orders URLs for checking, so that a run cut short by a
+// deadline or cancellation has already covered the most important pages.
+// The importance order puts pages with a higher sitemap priority first, then
+// pages found closer to the entry points, then pages linked from more pages
+// (such as those in the navigation), then pages with shorter paths. Ties keep
+// the order the URLs were found in, as does the discovery order.
+func (c *Checker) PrioritizeURLs(urls []string, order string) []string {
+	if order != OrderImportance {
+		return urls
+	}
+
+	type rank struct {
+		priority  float64
+		depth     int
+		inbound   int
+		pathDepth int
+	}
+	ranks := make(map[string]rank, len(urls))
+	for _, u := range urls {
+		ranks[u] = rank{priority: defaultSitemapPriority, pathDepth: pathDepth(u)}
+	}
+
+	for u, r := range ranks {
+		id, ok := c.urls.lookup(u)
+		if !ok {
+			continue
+		}
+		c.inventoryMu.Lock()
+		if priority, ok := c.priority[id]; ok {
+			r.priority = priority
+		}
+		r.depth = int(c.inventory[id].depth)
+		c.inventoryMu.Unlock()
+		c.sourcesMu.Lock()
+		r.inbound = len(c.sources[id])
+		c.sourcesMu.Unlock()
+		ranks[u] = r
+	}
+
+	prioritized := append([]string(nil), urls...)
+	sort.SliceStable(prioritized, func(i, j int) bool {
+		a, b := ranks[prioritized[i]], ranks[prioritized[j]]
+		switch {
+		case a.priority != b.priority:
+			return a.priority > b.priority
+		case a.depth != b.depth:
+			return a.depth < b.depth
+		case a.inbound != b.inbound:
+			return a.inbound > b.inbound
+		default:
+			return a.pathDepth < b.pathDepth
+		}
+	})
+	return prioritized
+}
+
+// pathDepth counts the segments of a URL's path, so the home page is 0
+func pathDepth(rawURL string) int {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return 0
+	}
+	depth := 0
+	for _, segment := range strings.Split(u.Path, "/") {
+		if segment != "" {
+			depth++
+		}
+	}
+	return depth
+}
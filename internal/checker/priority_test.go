@@ -0,0 +1,82 @@
+package checker
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/joshbeard/link-validator/internal/config"
+)
+
+func TestPrioritizeSitemapURLs(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/xml")
+		fmt.Fprint(w, `<?xml version="1.0" encoding="UTF-8"?>
+<urlset xmlns="http://www.sitemaps.org/schemas/sitemap/0.9">
+  <url><loc>https://example.com/blog/2019/old-post/</loc><priority>0.2</priority></url>
+  <url><loc>https://example.com/docs/install/</loc></url>
+  <url><loc>https://example.com/pricing/</loc><priority>0.9</priority></url>
+  <url><loc>https://example.com/docs/</loc></url>
+  <url><loc>https://example.com/</loc><priority>1.0</priority></url>
+  <url><loc>https://example.com/about/</loc><priority>high</priority></url>
+</urlset>`)
+	}))
+	defer server.Close()
+
+	checker := New(&config.Config{UserAgent: "TestBot/1.0", Timeout: 5 * time.Second, MaxConcurrent: 1})
+	urls, err := checker.GetURLsFromSitemap(server.URL)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if got := checker.PrioritizeURLs(urls, OrderDiscovery); !reflect.DeepEqual(got, urls) {
+		t.Errorf("Expected discovery order to be kept, got %v", got)
+	}
+
+	expected := []string{
+		"https://example.com/",
+		"https://example.com/pricing/",
+		"https://example.com/docs/",
+		"https://example.com/about/",
+		"https://example.com/docs/install/",
+		"https://example.com/blog/2019/old-post/",
+	}
+	if got := checker.PrioritizeURLs(urls, OrderImportance); !reflect.DeepEqual(got, expected) {
+		t.Errorf("Expected %v, got %v", expected, got)
+	}
+}
+
+func TestPrioritizeCrawledURLs(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		nav := `<a href="/contact/">Contact</a>`
+		switch r.URL.Path {
+		case "/":
+			fmt.Fprint(w, `<a href="/archive/">Archive</a>`+nav)
+		case "/archive/":
+			fmt.Fprint(w, `<a href="/archive/2019/">2019</a>`+nav)
+		case "/archive/2019/":
+			fmt.Fprint(w, nav)
+		}
+	}))
+	defer server.Close()
+
+	checker := New(&config.Config{UserAgent: "TestBot/1.0", Timeout: 5 * time.Second, MaxConcurrent: 1})
+	urls, err := checker.CrawlWebsite(server.URL+"/", 3)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	expected := []string{
+		server.URL + "/",
+		server.URL + "/contact/",
+		server.URL + "/archive/",
+		server.URL + "/archive/2019/",
+	}
+	if got := checker.PrioritizeURLs(urls, OrderImportance); !reflect.DeepEqual(got, expected) {
+		t.Errorf("Expected %v, got %v", expected, got)
+	}
+}
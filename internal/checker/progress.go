@@ -0,0 +1,115 @@
+package checker
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// progressBarWidth is the number of cells in a live progress bar
+	progressBarWidth = 30
+
+	// progressRedraw is how often a live progress bar is redrawn at most
+	progressRedraw = 100 * time.Millisecond
+
+	// progressStep is the share of links between progress lines, in percent
+	progressStep = 5
+)
+
+// progressReporter shows how far a check has got: a live bar for a
+// terminal, or a line every progressStep percent for logs such as those of
+// GitHub Actions
+type progressReporter struct {
+	w    io.Writer
+	live bool
+
+	mu       sync.Mutex
+	total    int
+	done     int
+	start    time.Time
+	drawn    time.Time
+	lastStep int
+	now      func() time.Time
+}
+
+// ReportProgress writes the progress of every check from now on to w. With
+// live set, a single progress bar is redrawn in place, as on a terminal;
+// otherwise a line is written every 5%.
+func (c *Checker) ReportProgress(w io.Writer, live bool) {
+	c.progress = &progressReporter{w: w, live: live, now: time.Now}
+}
+
+// begin starts reporting on a check of total links
+func (p *progressReporter) begin(total int) {
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.total = total
+	p.done = 0
+	p.lastStep = 0
+	p.start = p.now()
+	p.drawn = time.Time{}
+}
+
+// add records a checked link
+func (p *progressReporter) add() {
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.done++
+	now := p.now()
+
+	if p.live {
+		if p.done == p.total || now.Sub(p.drawn) >= progressRedraw {
+			p.drawn = now
+			fmt.Fprintf(p.w, "\r\033[K%s", p.status(now))
+		}
+		return
+	}
+	if step := p.done * 100 / p.total / progressStep; step > p.lastStep {
+		p.lastStep = step
+		fmt.Fprintf(p.w, "Progress: %s\n", p.status(now))
+	}
+}
+
+// finish clears the live progress bar so later output starts on a clean line
+func (p *progressReporter) finish() {
+	if p == nil || !p.live || p.total == 0 {
+		return
+	}
+	fmt.Fprint(p.w, "\r\033[K")
+}
+
+// status describes the progress so far, such as
+// "[=====>    ] 120/500 (24%) 35.2/s ETA 11s"
+func (p *progressReporter) status(now time.Time) string {
+	percent := p.done * 100 / p.total
+	elapsed := now.Sub(p.start)
+	rate := 0.0
+	if elapsed > 0 {
+		rate = float64(p.done) / elapsed.Seconds()
+	}
+	eta := "?"
+	if rate > 0 {
+		remaining := time.Duration(float64(p.total-p.done) / rate * float64(time.Second))
+		eta = remaining.Round(time.Second).String()
+	}
+	counts := fmt.Sprintf("%d/%d (%d%%) %.1f/s ETA %s", p.done, p.total, percent, rate, eta)
+	if !p.live {
+		return counts
+	}
+
+	filled := p.done * progressBarWidth / p.total
+	bar := strings.Repeat("=", filled)
+	if filled < progressBarWidth {
+		bar += ">" + strings.Repeat(" ", progressBarWidth-filled-1)
+	}
+	return fmt.Sprintf("[%s] %s", bar, counts)
+}
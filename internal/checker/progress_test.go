@@ -0,0 +1,78 @@
+package checker
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/joshbeard/link-validator/internal/config"
+)
+
+func TestProgressLines(t *testing.T) {
+	var buf bytes.Buffer
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	now := start
+	p := &progressReporter{w: &buf, now: func() time.Time { return now }}
+
+	p.begin(40)
+	for i := 0; i < 40; i++ {
+		now = now.Add(250 * time.Millisecond)
+		p.add()
+	}
+	p.finish()
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 20 {
+		t.Fatalf("Expected a line every 5%%, got %d: %q", len(lines), lines)
+	}
+	if lines[0] != "Progress: 2/40 (5%) 4.0/s ETA 10s" {
+		t.Errorf("Unexpected first line %q", lines[0])
+	}
+	if lines[19] != "Progress: 40/40 (100%) 4.0/s ETA 0s" {
+		t.Errorf("Unexpected last line %q", lines[19])
+	}
+}
+
+func TestProgressBar(t *testing.T) {
+	var buf bytes.Buffer
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	p := &progressReporter{w: &buf, live: true, now: func() time.Time { return now }}
+
+	p.begin(4)
+	p.add()
+	if !strings.HasSuffix(buf.String(), "[=======>                      ] 1/4 (25%) 0.0/s ETA ?") {
+		t.Errorf("Unexpected bar %q", buf.String())
+	}
+	// Redraws are throttled until the last link
+	p.add()
+	now = now.Add(time.Second)
+	p.add()
+	p.add()
+	if strings.Count(buf.String(), "\r") != 3 {
+		t.Errorf("Expected 3 draws, got %q", buf.String())
+	}
+	if !strings.Contains(buf.String(), "[==============================] 4/4 (100%) 4.0/s ETA 0s") {
+		t.Errorf("Expected a full bar, got %q", buf.String())
+	}
+	p.finish()
+	if !strings.HasSuffix(buf.String(), "\r\033[K") {
+		t.Error("Expected the bar to be cleared")
+	}
+}
+
+func TestCheckLinksReportsProgress(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	var buf bytes.Buffer
+	checker := New(&config.Config{UserAgent: "TestBot/1.0", Timeout: 5 * time.Second, MaxConcurrent: 2})
+	checker.ReportProgress(&buf, false)
+	checker.CheckLinks([]string{server.URL + "/a", server.URL + "/b"})
+
+	if !strings.Contains(buf.String(), "Progress: 2/2 (100%)") {
+		t.Errorf("Expected progress to reach 100%%, got %q", buf.String())
+	}
+}
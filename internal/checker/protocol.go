@@ -0,0 +1,89 @@
+package checker
+
+import (
+	"fmt"
+	"strings"
+)
+
+// PreferHTTPS drops http:// URLs whose https:// form was also found, so each
+// resource is requested once over HTTPS. Every page linking to a dropped URL
+// gets an insecure_link issue and is recorded as a source of the https://
+// form, so a failing result still names it. When the HTTPS form later fails,
+// CheckLinks falls back to the http:// form.
+func (c *Checker) PreferHTTPS(urls []string) []string {
+	present := make(map[string]bool, len(urls))
+	for _, u := range urls {
+		present[u] = true
+	}
+
+	preferred := make([]string, 0, len(urls))
+	for _, u := range urls {
+		secure, ok := httpsVariant(u)
+		if !ok || !present[secure] {
+			preferred = append(preferred, u)
+			continue
+		}
+
+		if secureID, ok := c.urls.id(secure); ok {
+			if insecureID, ok := c.urls.id(u); ok {
+				c.inventoryMu.Lock()
+				c.insecure[secureID] = insecureID
+				c.inventoryMu.Unlock()
+			}
+		}
+		for _, source := range c.Sources(u) {
+			c.recordSource(secure, source)
+			c.recordIssue(PageIssue{
+				Page:   source,
+				Type:   IssueInsecureLink,
+				Link:   u,
+				Detail: fmt.Sprintf("link uses http:// although %s is also linked", secure),
+			})
+		}
+	}
+	return preferred
+}
+
+// httpsVariant returns the https:// form of an http:// URL
+func httpsVariant(u string) (string, bool) {
+	if len(u) < len("http://") || !strings.EqualFold(u[:len("http://")], "http://") {
+		return "", false
+	}
+	return "https://" + u[len("http://"):], true
+}
+
+// insecureVariant returns the http:// URL PreferHTTPS dropped in favour of
+// the given https:// URL
+func (c *Checker) insecureVariant(secure string) (string, bool) {
+	secureID, ok := c.urls.lookup(secure)
+	if !ok {
+		return "", false
+	}
+
+	c.inventoryMu.Lock()
+	insecureID, ok := c.insecure[secureID]
+	c.inventoryMu.Unlock()
+	if !ok {
+		return "", false
+	}
+	return c.urls.url(insecureID), true
+}
+
+// checkPreferred checks a URL, falling back to the http:// form PreferHTTPS
+// dropped for it when the HTTPS form fails
+func (c *Checker) checkPreferred(checkURL, locale string) LinkResult {
+	result := c.checkLink(checkURL, locale)
+	insecure, ok := c.insecureVariant(checkURL)
+	if !ok || !result.ErrorType.IsFailure() {
+		return result
+	}
+
+	if c.config.Verbose {
+		fmt.Printf("HTTPS failed for %s, trying %s\n", checkURL, insecure)
+	}
+	fallback := c.checkLink(insecure, locale)
+	if fallback.ErrorType.IsFailure() {
+		return result
+	}
+	return fallback
+}
@@ -0,0 +1,94 @@
+package checker
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/joshbeard/link-validator/internal/config"
+)
+
+func TestPreferHTTPS(t *testing.T) {
+	checker := New(&config.Config{})
+	checker.recordSource("http://example.com/docs", "https://example.com/")
+	checker.recordSource("http://example.com/docs", "https://example.com/blog/")
+
+	urls := []string{
+		"https://example.com/",
+		"http://example.com/docs",
+		"https://example.com/docs",
+		"http://legacy.example/",
+		"HTTP://example.com/",
+	}
+	expected := []string{
+		"https://example.com/",
+		"https://example.com/docs",
+		"http://legacy.example/",
+	}
+	if got := checker.PreferHTTPS(urls); !reflect.DeepEqual(got, expected) {
+		t.Errorf("Expected %v, got %v", expected, got)
+	}
+
+	issues := checker.Issues()
+	if got := CountIssues(issues, IssueInsecureLink); got != 2 {
+		t.Fatalf("Expected an insecure_link issue per linking page, got %d: %+v", got, issues)
+	}
+	if issues[0].Page != "https://example.com/" || issues[0].Link != "http://example.com/docs" {
+		t.Errorf("Unexpected issue %+v", issues[0])
+	}
+
+	// Pages linking only to the dropped form are sources of the kept one
+	expectedSources := []string{"https://example.com/", "https://example.com/blog/"}
+	if got := checker.Sources("https://example.com/docs"); !reflect.DeepEqual(got, expectedSources) {
+		t.Errorf("Expected the dropped URL's sources %v, got %v", expectedSources, got)
+	}
+}
+
+func TestPreferHTTPSFailureKeepsSources(t *testing.T) {
+	// Neither form answers, so the HTTPS result is reported
+	checker := New(&config.Config{UserAgent: "TestBot/1.0", Timeout: time.Second, MaxConcurrent: 1})
+	checker.recordSource("http://127.0.0.1:1/old", "https://example.com/blog/")
+	checker.recordSource("https://127.0.0.1:1/old", "https://example.com/")
+
+	urls := checker.PreferHTTPS([]string{"http://127.0.0.1:1/old", "https://127.0.0.1:1/old"})
+	results := checker.CheckExternalLinks(urls)
+	if len(results) != 1 || results[0].URL != "https://127.0.0.1:1/old" || !results[0].ErrorType.IsFailure() {
+		t.Fatalf("Expected a failing HTTPS result, got %+v", results)
+	}
+	expected := []string{"https://example.com/", "https://example.com/blog/"}
+	if !reflect.DeepEqual(results[0].Sources, expected) {
+		t.Errorf("Expected sources %v, got %v", expected, results[0].Sources)
+	}
+}
+
+func TestPreferHTTPSFallback(t *testing.T) {
+	// The test server only speaks plain HTTP, so the https:// form fails
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	insecure := server.URL + "/page"
+	secure := "https://" + strings.TrimPrefix(insecure, "http://")
+
+	checker := New(&config.Config{UserAgent: "TestBot/1.0", Timeout: 5 * time.Second, MaxConcurrent: 1})
+	checker.recordSource(insecure, server.URL+"/")
+	urls := checker.PreferHTTPS([]string{secure, insecure})
+	if len(urls) != 1 || urls[0] != secure {
+		t.Fatalf("Expected only %s, got %v", secure, urls)
+	}
+
+	results := checker.CheckLinks(urls)
+	if len(results) != 1 {
+		t.Fatalf("Expected 1 result, got %d", len(results))
+	}
+	if results[0].URL != insecure || results[0].Error != "" {
+		t.Errorf("Expected a passing fallback to %s, got %+v", insecure, results[0])
+	}
+	if results[0].SourceCount != 1 {
+		t.Errorf("Expected the fallback's sources to be counted, got %d", results[0].SourceCount)
+	}
+}
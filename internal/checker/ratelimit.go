@@ -0,0 +1,150 @@
+package checker
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// maxRateLimitWait caps how long a request waits on a host's advertised rate
+// limit. Longer resets (GitHub's are up to an hour) are not worth stalling the
+// run for, so the request goes ahead and any 429 is handled by retries.
+const maxRateLimitWait = time.Minute
+
+// hostThrottle paces requests to hosts that advertise their rate limits with
+// X-RateLimit-Remaining and X-RateLimit-Reset headers, spreading the
+// remaining requests over the time until the limit resets so the checker
+// slows down before it is rejected. A robots.txt Crawl-delay sets a minimum
+// spacing on top of that.
+type hostThrottle struct {
+	mu       sync.Mutex
+	next     map[string]time.Time     // earliest start of the next request
+	interval map[string]time.Duration // spacing between requests
+	minimum  map[string]time.Duration // spacing asked for by robots.txt
+	maxWait  time.Duration
+}
+
+func newHostThrottle() *hostThrottle {
+	return &hostThrottle{
+		next:     make(map[string]time.Time),
+		interval: make(map[string]time.Duration),
+		minimum:  make(map[string]time.Duration),
+		maxWait:  maxRateLimitWait,
+	}
+}
+
+// wait blocks until a request to host may start and reserves its slot
+func (t *hostThrottle) wait(host string) {
+	t.mu.Lock()
+	now := time.Now()
+	start, limited := t.next[host]
+	if !limited || !start.After(now) {
+		start = now
+	}
+	if delay := start.Sub(now); delay > t.maxWait {
+		start = now
+	}
+	if interval := max(t.interval[host], t.minimum[host]); interval > 0 {
+		t.next[host] = start.Add(interval)
+	}
+	t.mu.Unlock()
+
+	time.Sleep(time.Until(start))
+}
+
+// pause holds requests to host until the given time, as asked for by a
+// Retry-After header
+func (t *hostThrottle) pause(host string, until time.Time) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if until.After(t.next[host]) {
+		t.next[host] = until
+	}
+}
+
+// setMinInterval spaces requests to host at least interval apart, whatever
+// rate limit it advertises
+func (t *hostThrottle) setMinInterval(host string, interval time.Duration) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.minimum[host] = interval
+}
+
+// update records the rate limit advertised in a response from host
+func (t *hostThrottle) update(host string, header http.Header, now time.Time) {
+	remaining, reset, ok := parseRateLimit(header, now)
+	if !ok {
+		return
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	until := reset.Sub(now)
+	switch {
+	case until <= 0:
+		delete(t.next, host)
+		delete(t.interval, host)
+	case remaining <= 0:
+		t.next[host] = reset
+		delete(t.interval, host)
+	default:
+		t.interval[host] = until / time.Duration(remaining)
+	}
+}
+
+// maxRetryAfterRetries is how many times a link is retried after being told
+// to come back later with Retry-After, on top of MaxRetries
+const maxRetryAfterRetries = 3
+
+// parseRetryAfter returns how long a 429 or 503 response asks clients to wait
+// with its Retry-After header, given as seconds or an HTTP date
+func parseRetryAfter(resp *http.Response, now time.Time) (time.Duration, bool) {
+	if resp.StatusCode != http.StatusTooManyRequests && resp.StatusCode != http.StatusServiceUnavailable {
+		return 0, false
+	}
+	value := strings.TrimSpace(resp.Header.Get("Retry-After"))
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+		return time.Duration(seconds) * time.Second, true
+	}
+	date, err := http.ParseTime(value)
+	if err != nil {
+		return 0, false
+	}
+	return max(date.Sub(now), 0), true
+}
+
+// parseRateLimit reads the remaining request count and reset time from
+// X-RateLimit-* or RateLimit-* headers. Reset values may be a Unix timestamp
+// or a number of seconds from now.
+func parseRateLimit(header http.Header, now time.Time) (int, time.Time, bool) {
+	remainingValue := header.Get("X-RateLimit-Remaining")
+	resetValue := header.Get("X-RateLimit-Reset")
+	if remainingValue == "" || resetValue == "" {
+		remainingValue = header.Get("RateLimit-Remaining")
+		resetValue = header.Get("RateLimit-Reset")
+	}
+
+	remaining, err := strconv.Atoi(remainingValue)
+	if err != nil {
+		return 0, time.Time{}, false
+	}
+	reset, err := strconv.ParseInt(resetValue, 10, 64)
+	if err != nil || reset < 0 {
+		return 0, time.Time{}, false
+	}
+
+	// Anything later than 2001 is a timestamp rather than a delay
+	if reset > 1_000_000_000 {
+		return remaining, time.Unix(reset, 0), true
+	}
+	return remaining, now.Add(time.Duration(reset) * time.Second), true
+}
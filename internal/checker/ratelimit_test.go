@@ -0,0 +1,178 @@
+package checker
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/joshbeard/link-validator/internal/config"
+)
+
+func TestParseRateLimit(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+
+	testCases := []struct {
+		name      string
+		headers   map[string]string
+		remaining int
+		reset     time.Time
+		ok        bool
+	}{
+		{"unix timestamp", map[string]string{"X-RateLimit-Remaining": "10", "X-RateLimit-Reset": "1700000060"}, 10, now.Add(time.Minute), true},
+		{"delay seconds", map[string]string{"RateLimit-Remaining": "0", "RateLimit-Reset": "30"}, 0, now.Add(30 * time.Second), true},
+		{"missing reset", map[string]string{"X-RateLimit-Remaining": "10"}, 0, time.Time{}, false},
+		{"invalid remaining", map[string]string{"X-RateLimit-Remaining": "lots", "X-RateLimit-Reset": "30"}, 0, time.Time{}, false},
+		{"no headers", map[string]string{}, 0, time.Time{}, false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			header := http.Header{}
+			for key, value := range tc.headers {
+				header.Set(key, value)
+			}
+
+			remaining, reset, ok := parseRateLimit(header, now)
+			if ok != tc.ok || remaining != tc.remaining || !reset.Equal(tc.reset) {
+				t.Errorf("Expected (%d, %v, %v), got (%d, %v, %v)", tc.remaining, tc.reset, tc.ok, remaining, reset, ok)
+			}
+		})
+	}
+}
+
+func TestHostThrottleUpdate(t *testing.T) {
+	now := time.Now()
+	throttle := newHostThrottle()
+
+	header := http.Header{}
+	header.Set("X-RateLimit-Remaining", "4")
+	header.Set("X-RateLimit-Reset", "2")
+	throttle.update("api.example.com", header, now)
+	if throttle.interval["api.example.com"] != 500*time.Millisecond {
+		t.Errorf("Expected remaining requests spread 500ms apart, got %v", throttle.interval["api.example.com"])
+	}
+
+	header.Set("X-RateLimit-Remaining", "0")
+	throttle.update("api.example.com", header, now)
+	if !throttle.next["api.example.com"].Equal(now.Add(2 * time.Second)) {
+		t.Errorf("Expected exhausted host to be paused until reset, got %v", throttle.next["api.example.com"])
+	}
+
+	header.Set("X-RateLimit-Reset", "0")
+	throttle.update("api.example.com", header, now)
+	if _, limited := throttle.next["api.example.com"]; limited {
+		t.Error("Expected an elapsed reset to clear the limit")
+	}
+}
+
+func TestHostThrottleWait(t *testing.T) {
+	throttle := newHostThrottle()
+	throttle.next["slow.example.com"] = time.Now().Add(100 * time.Millisecond)
+
+	start := time.Now()
+	throttle.wait("fast.example.com")
+	if elapsed := time.Since(start); elapsed > 50*time.Millisecond {
+		t.Errorf("Expected unlimited host not to wait, waited %v", elapsed)
+	}
+
+	throttle.wait("slow.example.com")
+	if elapsed := time.Since(start); elapsed < 100*time.Millisecond {
+		t.Errorf("Expected limited host to wait for its reset, waited %v", elapsed)
+	}
+
+	throttle.maxWait = 10 * time.Millisecond
+	throttle.next["slow.example.com"] = time.Now().Add(time.Hour)
+	start = time.Now()
+	throttle.wait("slow.example.com")
+	if elapsed := time.Since(start); elapsed > 50*time.Millisecond {
+		t.Errorf("Expected waits beyond maxWait to be skipped, waited %v", elapsed)
+	}
+}
+
+func TestCheckSingleLinkRespectsRateLimit(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Every response spends the last request of a one-second window
+		w.Header().Set("X-RateLimit-Remaining", "0")
+		w.Header().Set("X-RateLimit-Reset", "1")
+	}))
+	defer server.Close()
+
+	checker := New(&config.Config{UserAgent: "TestBot/1.0", Timeout: 5 * time.Second, MaxConcurrent: 1})
+
+	start := time.Now()
+	checker.checkSingleLink(server.URL + "/a")
+	result := checker.checkSingleLink(server.URL + "/b")
+
+	if result.StatusCode != 200 {
+		t.Errorf("Expected status 200, got %d", result.StatusCode)
+	}
+	if elapsed := time.Since(start); elapsed < 900*time.Millisecond {
+		t.Errorf("Expected the second request to wait for the rate limit reset, took %v", elapsed)
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2026, 3, 1, 6, 0, 0, 0, time.UTC)
+	tests := []struct {
+		status   int
+		value    string
+		expected time.Duration
+		ok       bool
+	}{
+		{http.StatusTooManyRequests, "30", 30 * time.Second, true},
+		{http.StatusServiceUnavailable, " 0 ", 0, true},
+		{http.StatusTooManyRequests, "Sun, 01 Mar 2026 06:02:00 GMT", 2 * time.Minute, true},
+		{http.StatusTooManyRequests, "Sun, 01 Mar 2026 05:00:00 GMT", 0, true},
+		{http.StatusTooManyRequests, "-5", 0, false},
+		{http.StatusTooManyRequests, "soon", 0, false},
+		{http.StatusTooManyRequests, "", 0, false},
+		{http.StatusNotFound, "30", 0, false},
+	}
+	for _, test := range tests {
+		resp := &http.Response{StatusCode: test.status, Header: http.Header{}}
+		if test.value != "" {
+			resp.Header.Set("Retry-After", test.value)
+		}
+		delay, ok := parseRetryAfter(resp, now)
+		if delay != test.expected || ok != test.ok {
+			t.Errorf("%d with Retry-After %q: expected %v %v, got %v %v", test.status, test.value, test.expected, test.ok, delay, ok)
+		}
+	}
+}
+
+func TestCheckSingleLinkHonorsRetryAfter(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		switch {
+		case r.URL.Path == "/busy" && requests == 1:
+			w.Header().Set("Retry-After", "1")
+			w.WriteHeader(http.StatusTooManyRequests)
+		case r.URL.Path == "/later":
+			w.Header().Set("Retry-After", "3600")
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer server.Close()
+
+	checker := New(&config.Config{UserAgent: "TestBot/1.0", Timeout: 5 * time.Second, MaxConcurrent: 1})
+
+	start := time.Now()
+	result := checker.checkSingleLink(server.URL + "/busy")
+	if result.StatusCode != 200 || result.ErrorType != "" {
+		t.Fatalf("Expected the retry to succeed, got %d %s", result.StatusCode, result.ErrorType)
+	}
+	if result.Retries != 1 {
+		t.Errorf("Expected 1 retry, got %d", result.Retries)
+	}
+	if elapsed := time.Since(start); elapsed < 900*time.Millisecond {
+		t.Errorf("Expected the retry to wait for Retry-After, took %v", elapsed)
+	}
+
+	requests = 0
+	result = checker.checkSingleLink(server.URL + "/later")
+	if result.StatusCode != http.StatusServiceUnavailable || requests != 1 {
+		t.Errorf("Expected a Retry-After beyond the wait limit not to be retried, got %d after %d requests", result.StatusCode, requests)
+	}
+}
@@ -0,0 +1,215 @@
+package checker
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// Redirect maps a checked URL to the URL its redirects ended at
+type Redirect struct {
+	Source string   `json:"source"`
+	Target string   `json:"target"`
+	Status int      `json:"status"`
+	Pages  []string `json:"pages,omitempty"`
+}
+
+// defaultMaxRedirects is how many redirects are followed when MaxRedirects
+// isn't set, the same limit net/http applies
+const defaultMaxRedirects = 10
+
+// RedirectHop is one redirect response in a chain
+type RedirectHop struct {
+	URL        string `json:"url"`
+	StatusCode int    `json:"status_code"`
+}
+
+// limitRedirects returns a redirect policy that gives up after max
+// redirects, with the error net/http uses so it is classified as
+// too_many_redirects, or as soon as the redirects return to a URL for the
+// third time, which is a redirect_loop. A single return is allowed since
+// sites redirect back after setting a cookie. The given headers are dropped
+// from redirects to other hosts.
+func limitRedirects(max int, headers http.Header) func(*http.Request, []*http.Request) error {
+	if max <= 0 {
+		max = defaultMaxRedirects
+	}
+	return func(req *http.Request, via []*http.Request) error {
+		visits := 0
+		for _, previous := range via {
+			if previous.URL.String() == req.URL.String() {
+				visits++
+			}
+		}
+		if visits >= 2 {
+			return fmt.Errorf("redirect loop back to %s", req.URL)
+		}
+		if len(via) > max {
+			return fmt.Errorf("stopped after %d redirects", max)
+		}
+		dropHeaders(req, via, headers)
+		return nil
+	}
+}
+
+// stopAtRedirect is a redirect policy that returns the redirect response
+// itself instead of following it
+func stopAtRedirect(*http.Request, []*http.Request) error {
+	return http.ErrUseLastResponse
+}
+
+// recordRedirect notes where a response's redirects ended, the status of
+// the first hop, which tells permanent moves apart from temporary ones, and
+// every hop of the chain. A redirect response that wasn't followed is
+// recorded as a chain of one.
+func recordRedirect(result *LinkResult, resp *http.Response) {
+	if resp.Request == nil {
+		return
+	}
+	if resp.Request.Response == nil {
+		if location, err := resp.Location(); err == nil && isRedirectStatus(resp.StatusCode) {
+			result.FinalURL = location.String()
+			result.RedirectStatus = resp.StatusCode
+			result.RedirectChain = []RedirectHop{{URL: resp.Request.URL.String(), StatusCode: resp.StatusCode}}
+		}
+		return
+	}
+
+	var chain []RedirectHop
+	for hop := resp.Request.Response; hop != nil && hop.Request != nil; hop = hop.Request.Response {
+		chain = append([]RedirectHop{{URL: hop.Request.URL.String(), StatusCode: hop.StatusCode}}, chain...)
+	}
+	if len(chain) == 0 {
+		return
+	}
+	result.FinalURL = resp.Request.URL.String()
+	result.RedirectStatus = chain[0].StatusCode
+	result.RedirectChain = chain
+}
+
+// recordAbandonedRedirect records the chain of redirects that was given up
+// on, such as a loop, from the redirect response the client stopped at. Its
+// Location is recorded as the final URL.
+func recordAbandonedRedirect(result *LinkResult, resp *http.Response) {
+	var chain []RedirectHop
+	for hop := resp; hop != nil && hop.Request != nil; hop = hop.Request.Response {
+		chain = append([]RedirectHop{{URL: hop.Request.URL.String(), StatusCode: hop.StatusCode}}, chain...)
+	}
+	if len(chain) == 0 {
+		return
+	}
+	if location, err := resp.Location(); err == nil {
+		result.FinalURL = location.String()
+	}
+	result.RedirectStatus = chain[0].StatusCode
+	result.RedirectChain = chain
+}
+
+// flagRedirectChain marks a result that would otherwise pass when its
+// redirects downgrade from HTTPS to HTTP, or when it took more redirects
+// than MaxRedirectChain to resolve
+func (c *Checker) flagRedirectChain(result *LinkResult) {
+	if result.ErrorType != "" || len(result.RedirectChain) == 0 {
+		return
+	}
+	if downgrade := httpsDowngrade(result); downgrade != "" {
+		result.Error = fmt.Sprintf("redirect downgrades from HTTPS to HTTP at %s", downgrade)
+		result.ErrorType = ErrorTypeInsecureRedirect
+		return
+	}
+	if max := c.config.MaxRedirectChain; max > 0 && len(result.RedirectChain) > max {
+		result.Error = fmt.Sprintf("%d redirects to reach %s, more than %d", len(result.RedirectChain), result.FinalURL, max)
+		result.ErrorType = ErrorTypeLongRedirectChain
+	}
+}
+
+// httpsDowngrade returns the first URL in a result's redirect chain, or its
+// final URL, that uses http after an earlier one used https
+func httpsDowngrade(result *LinkResult) string {
+	urls := make([]string, 0, len(result.RedirectChain)+1)
+	for _, hop := range result.RedirectChain {
+		urls = append(urls, hop.URL)
+	}
+	urls = append(urls, result.FinalURL)
+
+	secure := false
+	for _, u := range urls {
+		switch {
+		case strings.HasPrefix(u, "https://"):
+			secure = true
+		case secure && strings.HasPrefix(u, "http://"):
+			return u
+		}
+	}
+	return ""
+}
+
+// isRedirectStatus reports whether a status code is a redirect
+func isRedirectStatus(code int) bool {
+	return code >= 300 && code < 400
+}
+
+// flagRedirect marks a result that would otherwise pass as redirected, for
+// WarnOnRedirect
+func flagRedirect(result *LinkResult) {
+	if result.ErrorType != "" || result.FinalURL == "" || result.FinalURL == result.URL {
+		return
+	}
+	result.Error = fmt.Sprintf("redirected (%d) to %s", result.RedirectStatus, result.FinalURL)
+	result.ErrorType = ErrorTypeRedirected
+}
+
+// Redirects returns the redirects encountered by the given results, with
+// the pages linking to each redirected URL
+func Redirects(results []LinkResult) []Redirect {
+	var redirects []Redirect
+	for _, result := range results {
+		if result.FinalURL == "" || result.FinalURL == result.URL {
+			continue
+		}
+		redirects = append(redirects, Redirect{
+			Source: result.URL,
+			Target: result.FinalURL,
+			Status: result.RedirectStatus,
+			Pages:  result.Sources,
+		})
+	}
+	return redirects
+}
+
+// WriteRedirectMap writes redirects to path, as CSV when the path ends in
+// .csv and as a JSON array otherwise
+func WriteRedirectMap(path string, redirects []Redirect) error {
+	var data []byte
+	if strings.EqualFold(filepath.Ext(path), ".csv") {
+		var buf strings.Builder
+		w := csv.NewWriter(&buf)
+		records := [][]string{{"source", "target", "status"}}
+		for _, redirect := range redirects {
+			records = append(records, []string{redirect.Source, redirect.Target, strconv.Itoa(redirect.Status)})
+		}
+		if err := w.WriteAll(records); err != nil {
+			return fmt.Errorf("encoding redirect map: %w", err)
+		}
+		data = []byte(buf.String())
+	} else {
+		if redirects == nil {
+			redirects = []Redirect{}
+		}
+		encoded, err := json.MarshalIndent(redirects, "", "  ")
+		if err != nil {
+			return fmt.Errorf("encoding redirect map: %w", err)
+		}
+		data = append(encoded, '\n')
+	}
+
+	if err := os.WriteFile(path, data, 0o644); err != nil { // #nosec G306 -- redirect map is a shareable report
+		return fmt.Errorf("writing redirect map: %w", err)
+	}
+	return nil
+}
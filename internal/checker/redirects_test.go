@@ -0,0 +1,265 @@
+package checker
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/joshbeard/link-validator/internal/config"
+)
+
+func TestCheckSingleLinkRecordsRedirects(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/new", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	mux.HandleFunc("/old", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/new", http.StatusMovedPermanently)
+	})
+	mux.HandleFunc("/temp", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/old", http.StatusFound)
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	checker := New(&config.Config{UserAgent: "TestBot/1.0", Timeout: 5 * time.Second, MaxConcurrent: 1})
+
+	testCases := []struct {
+		path   string
+		final  string
+		status int
+	}{
+		{"/new", "", 0},
+		{"/old", server.URL + "/new", http.StatusMovedPermanently},
+		{"/temp", server.URL + "/new", http.StatusFound},
+	}
+	for _, tc := range testCases {
+		result := checker.checkSingleLink(server.URL + tc.path)
+		if result.FinalURL != tc.final || result.RedirectStatus != tc.status {
+			t.Errorf("%s: expected %q (%d), got %q (%d)", tc.path, tc.final, tc.status, result.FinalURL, result.RedirectStatus)
+		}
+	}
+}
+
+func TestRedirectOptions(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/new", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	mux.HandleFunc("/old", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/new", http.StatusMovedPermanently)
+	})
+	mux.HandleFunc("/temp", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/old", http.StatusFound)
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	t.Run("records the chain", func(t *testing.T) {
+		checker := New(&config.Config{UserAgent: "TestBot/1.0", Timeout: 5 * time.Second, MaxConcurrent: 1})
+		result := checker.checkSingleLink(server.URL + "/temp")
+		expected := []RedirectHop{
+			{URL: server.URL + "/temp", StatusCode: http.StatusFound},
+			{URL: server.URL + "/old", StatusCode: http.StatusMovedPermanently},
+		}
+		if !reflect.DeepEqual(result.RedirectChain, expected) {
+			t.Errorf("Expected chain %+v, got %+v", expected, result.RedirectChain)
+		}
+		if result.ErrorType != "" {
+			t.Errorf("Expected redirects to pass by default, got %s", result.ErrorType)
+		}
+	})
+
+	t.Run("max redirects", func(t *testing.T) {
+		checker := New(&config.Config{UserAgent: "TestBot/1.0", Timeout: 5 * time.Second, MaxConcurrent: 1, MaxRedirects: 1})
+		if result := checker.checkSingleLink(server.URL + "/old"); result.ErrorType != "" {
+			t.Errorf("Expected one redirect to be followed, got %s: %s", result.ErrorType, result.Error)
+		}
+		if result := checker.checkSingleLink(server.URL + "/temp"); result.ErrorType != ErrorTypeTooManyRedirects {
+			t.Errorf("Expected too_many_redirects, got %s: %s", result.ErrorType, result.Error)
+		}
+	})
+
+	t.Run("stop at redirects", func(t *testing.T) {
+		checker := New(&config.Config{UserAgent: "TestBot/1.0", Timeout: 5 * time.Second, MaxConcurrent: 1, StopAtRedirects: true})
+		result := checker.checkSingleLink(server.URL + "/temp")
+		if result.ErrorType != "" || result.StatusCode != http.StatusFound {
+			t.Errorf("Expected an unfollowed 302 to pass, got %d %s", result.StatusCode, result.ErrorType)
+		}
+		if result.FinalURL != server.URL+"/old" || len(result.RedirectChain) != 1 {
+			t.Errorf("Expected the Location to be recorded, got %q %+v", result.FinalURL, result.RedirectChain)
+		}
+	})
+
+	t.Run("warn on redirect", func(t *testing.T) {
+		checker := New(&config.Config{UserAgent: "TestBot/1.0", Timeout: 5 * time.Second, MaxConcurrent: 1, WarnOnRedirect: true})
+		result := checker.checkSingleLink(server.URL + "/old")
+		if result.ErrorType != ErrorTypeRedirected || result.ErrorType.IsFailure() {
+			t.Errorf("Expected a redirected warning, got %s", result.ErrorType)
+		}
+		if result := checker.checkSingleLink(server.URL + "/new"); result.ErrorType != "" {
+			t.Errorf("Expected a direct link to pass, got %s", result.ErrorType)
+		}
+	})
+}
+
+func TestRedirectLoop(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/a", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/b", http.StatusFound)
+	})
+	mux.HandleFunc("/b", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/a", http.StatusFound)
+	})
+	mux.HandleFunc("/set-cookie", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/page?visited", http.StatusFound)
+	})
+	mux.HandleFunc("/page", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.RawQuery == "" {
+			http.Redirect(w, r, "/set-cookie", http.StatusFound)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	checker := New(&config.Config{UserAgent: "TestBot/1.0", Timeout: 5 * time.Second, MaxConcurrent: 1, MaxRedirects: 20})
+
+	result := checker.checkSingleLink(server.URL + "/a")
+	if result.ErrorType != ErrorTypeRedirectLoop {
+		t.Fatalf("Expected a redirect loop, got %s: %s", result.ErrorType, result.Error)
+	}
+	if len(result.RedirectChain) != 4 || result.RedirectChain[0].URL != server.URL+"/a" || result.FinalURL != server.URL+"/a" {
+		t.Errorf("Expected the loop's chain to be recorded, got %+v ending at %s", result.RedirectChain, result.FinalURL)
+	}
+	if !result.ErrorType.IsFailure() {
+		t.Error("Expected a redirect loop to be a broken link")
+	}
+
+	if result := checker.checkSingleLink(server.URL + "/page"); result.ErrorType != "" {
+		t.Errorf("Expected a single return to a URL to be allowed, got %s: %s", result.ErrorType, result.Error)
+	}
+}
+
+func TestFlagRedirectChain(t *testing.T) {
+	checker := New(&config.Config{MaxRedirectChain: 2})
+
+	tests := []struct {
+		name     string
+		result   LinkResult
+		expected ErrorType
+	}{
+		{"downgrade", LinkResult{
+			URL:           "https://example.com/a",
+			FinalURL:      "http://example.com/c",
+			RedirectChain: []RedirectHop{{URL: "https://example.com/a", StatusCode: 301}},
+		}, ErrorTypeInsecureRedirect},
+		{"upgrade", LinkResult{
+			URL:           "http://example.com/a",
+			FinalURL:      "https://example.com/a",
+			RedirectChain: []RedirectHop{{URL: "http://example.com/a", StatusCode: 301}},
+		}, ""},
+		{"long chain", LinkResult{
+			URL:      "https://example.com/a",
+			FinalURL: "https://example.com/d",
+			RedirectChain: []RedirectHop{
+				{URL: "https://example.com/a", StatusCode: 301},
+				{URL: "https://example.com/b", StatusCode: 301},
+				{URL: "https://example.com/c", StatusCode: 302},
+			},
+		}, ErrorTypeLongRedirectChain},
+		{"already broken", LinkResult{
+			URL:           "https://example.com/a",
+			FinalURL:      "http://example.com/c",
+			ErrorType:     ErrorTypeHTTP4xx,
+			RedirectChain: []RedirectHop{{URL: "https://example.com/a", StatusCode: 301}},
+		}, ErrorTypeHTTP4xx},
+	}
+	for _, test := range tests {
+		result := test.result
+		checker.flagRedirectChain(&result)
+		if result.ErrorType != test.expected {
+			t.Errorf("%s: expected %q, got %q (%s)", test.name, test.expected, result.ErrorType, result.Error)
+		}
+		if test.expected != "" && test.expected != test.result.ErrorType && result.ErrorType.IsFailure() {
+			t.Errorf("%s: expected %s not to count as broken", test.name, result.ErrorType)
+		}
+	}
+}
+
+func TestRedirects(t *testing.T) {
+	results := []LinkResult{
+		{URL: "https://example.com/ok", StatusCode: 200},
+		{URL: "https://example.com/old", StatusCode: 200, FinalURL: "https://example.com/new",
+			RedirectStatus: 301, Sources: []string{"https://example.com/"}},
+		{URL: "http://example.com/", StatusCode: 200, FinalURL: "https://example.com/", RedirectStatus: 308},
+	}
+
+	redirects := Redirects(results)
+	if len(redirects) != 2 {
+		t.Fatalf("Expected 2 redirects, got %+v", redirects)
+	}
+	if redirects[0].Source != "https://example.com/old" || redirects[0].Target != "https://example.com/new" ||
+		redirects[0].Status != 301 || len(redirects[0].Pages) != 1 {
+		t.Errorf("Unexpected redirect: %+v", redirects[0])
+	}
+}
+
+func TestWriteRedirectMap(t *testing.T) {
+	redirects := []Redirect{
+		{Source: "https://example.com/old", Target: "https://example.com/new", Status: 301},
+		{Source: "https://example.com/a,b", Target: "https://example.com/c", Status: 302},
+	}
+	dir := t.TempDir()
+
+	t.Run("csv", func(t *testing.T) {
+		path := filepath.Join(dir, "redirects.csv")
+		if err := WriteRedirectMap(path, redirects); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		expected := "source,target,status\n" +
+			"https://example.com/old,https://example.com/new,301\n" +
+			"\"https://example.com/a,b\",https://example.com/c,302\n"
+		if string(data) != expected {
+			t.Errorf("Unexpected CSV:\n%s", data)
+		}
+	})
+
+	t.Run("json", func(t *testing.T) {
+		path := filepath.Join(dir, "redirects.json")
+		if err := WriteRedirectMap(path, redirects); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var decoded []Redirect
+		if err := json.Unmarshal(data, &decoded); err != nil {
+			t.Fatalf("Invalid JSON: %v", err)
+		}
+		if len(decoded) != 2 || decoded[1].Source != "https://example.com/a,b" {
+			t.Errorf("Unexpected redirects: %+v", decoded)
+		}
+	})
+
+	t.Run("empty json", func(t *testing.T) {
+		path := filepath.Join(dir, "empty.json")
+		if err := WriteRedirectMap(path, nil); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if data, _ := os.ReadFile(path); string(data) != "[]\n" {
+			t.Errorf("Expected an empty array, got %q", data)
+		}
+	})
+}
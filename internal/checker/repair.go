@@ -0,0 +1,35 @@
+package checker
+
+import (
+	"fmt"
+	"strings"
+)
+
+// repairHref fixes common authoring defects that would otherwise make an
+// href unparsable or resolve to the wrong place: surrounding whitespace,
+// tabs and line breaks inside it (which browsers strip), unencoded spaces,
+// and "www." links missing their scheme. It returns the href unchanged when
+// there is nothing to repair.
+func repairHref(href string) string {
+	repaired := strings.TrimSpace(href)
+	repaired = strings.NewReplacer("\t", "", "\n", "", "\r", "", " ", "%20").Replace(repaired)
+	if strings.HasPrefix(strings.ToLower(repaired), "www.") {
+		repaired = "https://" + repaired
+	}
+	return repaired
+}
+
+// repairLink returns the href to extract from a page, repaired when URL
+// repair is enabled. Repairs are recorded as page issues with both forms, so
+// the source can still be fixed.
+func (c *Checker) repairLink(pageURL, href string) string {
+	if !c.config.RepairURLs {
+		return href
+	}
+	repaired := repairHref(href)
+	if repaired != href {
+		c.recordIssue(PageIssue{Page: pageURL, Type: IssueRepairedLink, Link: repaired,
+			Detail: fmt.Sprintf("repaired link %q to %q", href, repaired)})
+	}
+	return repaired
+}
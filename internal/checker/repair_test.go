@@ -0,0 +1,71 @@
+package checker
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/joshbeard/link-validator/internal/config"
+)
+
+func TestRepairHref(t *testing.T) {
+	testCases := []struct {
+		href     string
+		expected string
+	}{
+		{"/docs/", "/docs/"},
+		{"  /docs/\n", "/docs/"},
+		{"/docs/getting started/", "/docs/getting%20started/"},
+		{"https://example.com/a\n  /b", "https://example.com/a%20%20/b"},
+		{"https://exam\tple.com/", "https://example.com/"},
+		{"www.example.com/page", "https://www.example.com/page"},
+		{"WWW.Example.com", "https://WWW.Example.com"},
+		{"/www.example.com", "/www.example.com"},
+	}
+
+	for _, tc := range testCases {
+		if got := repairHref(tc.href); got != tc.expected {
+			t.Errorf("repairHref(%q) = %q, expected %q", tc.href, got, tc.expected)
+		}
+	}
+}
+
+func TestCrawlRepairsLinks(t *testing.T) {
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		if r.URL.Path == "/" {
+			fmt.Fprintf(w, `<a href=" %s/getting started/ ">Start</a>`, server.URL)
+		}
+	}))
+	defer server.Close()
+
+	for _, repair := range []bool{false, true} {
+		checker := New(&config.Config{UserAgent: "TestBot/1.0", Timeout: 5 * time.Second, MaxConcurrent: 1, RepairURLs: repair})
+		urls, err := checker.CrawlWebsite(server.URL+"/", 2)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		found := false
+		for _, u := range urls {
+			if u == server.URL+"/getting%20started/" {
+				found = true
+			}
+		}
+		if found != repair {
+			t.Errorf("repair=%v: expected repaired link found to be %v, got %v", repair, repair, urls)
+		}
+
+		issues := checker.Issues()
+		if repair && (len(issues) != 1 || issues[0].Type != IssueRepairedLink || !strings.Contains(issues[0].Detail, "getting started")) {
+			t.Errorf("Expected a repaired_link issue with the original href, got %+v", issues)
+		}
+		if !repair && len(issues) != 0 {
+			t.Errorf("Expected no issues without repair, got %+v", issues)
+		}
+	}
+}
@@ -0,0 +1,188 @@
+package checker
+
+import (
+	"iter"
+	"math"
+	"slices"
+	"sort"
+	"time"
+)
+
+// RepeatStat summarizes the results of checking a URL several times, to tell
+// flaky endpoints apart from consistently broken ones. A URL checked in
+// several locales has a stat per locale.
+type RepeatStat struct {
+	URL          string
+	Locale       string
+	Attempts     int
+	Successes    int
+	MeanLatency  time.Duration
+	LatencyStdev time.Duration
+}
+
+// SuccessRate returns the percentage of attempts that succeeded
+func (s RepeatStat) SuccessRate() float64 {
+	if s.Attempts == 0 {
+		return 0
+	}
+	return float64(s.Successes) / float64(s.Attempts) * 100
+}
+
+// Flaky reports whether the URL both succeeded and failed across attempts
+func (s RepeatStat) Flaky() bool {
+	return s.Successes > 0 && s.Successes < s.Attempts
+}
+
+// Recheck checks the links behind a round of results again, for another
+// round of the repeat report: links to files against the disk, external links
+// with their own limits and the rest as CheckLinks does. Results aren't
+// streamed, progress isn't shown and the check cache is neither consulted nor
+// updated, so every round is made of fresh requests and reported once.
+func (c *Checker) Recheck(results []LinkResult) []LinkResult {
+	var rechecked []LinkResult
+	c.RecheckTo(slices.Values(results), func(batch ...LinkResult) error {
+		rechecked = append(rechecked, batch...)
+		return nil
+	})
+	return rechecked
+}
+
+// RecheckTo checks the links behind a round of results again as Recheck
+// does, passing the new results to add a batch at a time. The results can be
+// read back from a ResultStore, and the links to check again are kept in
+// lists that spill to disk as the crawl's do, so a huge round needn't be held
+// in memory. It stops at the first error add returns.
+func (c *Checker) RecheckTo(results iter.Seq[LinkResult], add func(...LinkResult) error) error {
+	stream, progress, checkCache := c.stream, c.progress, c.cache
+	c.stream, c.progress, c.cache = nil, nil, nil
+	defer func() { c.stream, c.progress, c.cache = stream, progress, checkCache }()
+
+	urls, external := NewURLList(c.config.FrontierSpill), NewURLList(c.config.FrontierSpill)
+	defer urls.Close()
+	defer external.Close()
+	seen := make(urlSet)
+	for result := range results {
+		if seen.has(result.URL) {
+			continue
+		}
+		seen.add(result.URL)
+		var err error
+		switch check, ok := c.fileCheck(result.URL); {
+		case ok:
+			err = add(c.fileResult(result.URL, check()))
+		case result.External:
+			err = external.Add(result.URL)
+		default:
+			err = urls.Add(result.URL)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	if err := c.checkLinksTo(urls.All(), urls.Len(), c.config.MaxConcurrent, c.limiter, add); err != nil {
+		return err
+	}
+	if external.Len() > 0 {
+		if err := c.checkExternalLinksTo(external.All(), external.Len(), add); err != nil {
+			return err
+		}
+	}
+	if err := urls.Err(); err != nil {
+		return err
+	}
+	return external.Err()
+}
+
+// RepeatTally sums up rounds of results for the same URLs into RepeatStats
+// as each result is added, so the rounds needn't be held at once. A result
+// counts as a success when it has no error type, including accepted status
+// exceptions. Results answered from the check cache weren't requested, so
+// they aren't counted as attempts.
+type RepeatTally struct {
+	index     map[string]int
+	stats     []RepeatStat
+	latencies []runningStats
+}
+
+// NewRepeatTally returns an empty tally
+func NewRepeatTally() *RepeatTally {
+	return &RepeatTally{index: make(map[string]int)}
+}
+
+// Add counts results as attempts at their URLs in their locales
+func (t *RepeatTally) Add(results ...LinkResult) {
+	for _, result := range results {
+		if result.Unchanged {
+			continue
+		}
+		key := result.URL + "\x00" + result.Locale
+		i, ok := t.index[key]
+		if !ok {
+			i = len(t.stats)
+			t.index[key] = i
+			t.stats = append(t.stats, RepeatStat{URL: result.URL, Locale: result.Locale})
+			t.latencies = append(t.latencies, runningStats{})
+		}
+		t.stats[i].Attempts++
+		if result.ErrorType == "" {
+			t.stats[i].Successes++
+		}
+		t.latencies[i].add(float64(result.Elapsed()))
+	}
+}
+
+// Stats returns the tallied stats, ordered from the lowest success rate to
+// the highest and then by URL and locale
+func (t *RepeatTally) Stats() []RepeatStat {
+	stats := append([]RepeatStat(nil), t.stats...)
+	for i := range stats {
+		stats[i].MeanLatency = time.Duration(t.latencies[i].mean)
+		stats[i].LatencyStdev = time.Duration(t.latencies[i].stdev())
+	}
+
+	sort.SliceStable(stats, func(i, j int) bool {
+		if stats[i].SuccessRate() != stats[j].SuccessRate() {
+			return stats[i].SuccessRate() < stats[j].SuccessRate()
+		}
+		if stats[i].URL != stats[j].URL {
+			return stats[i].URL < stats[j].URL
+		}
+		return stats[i].Locale < stats[j].Locale
+	})
+	return stats
+}
+
+// RepeatStats summarizes rounds of results for the same URLs as a
+// RepeatTally does
+func RepeatStats(rounds [][]LinkResult) []RepeatStat {
+	tally := NewRepeatTally()
+	for _, round := range rounds {
+		tally.Add(round...)
+	}
+	return tally.Stats()
+}
+
+// runningStats keeps the mean and variance of values as they are added,
+// with Welford's algorithm, rather than the values themselves
+type runningStats struct {
+	count int
+	mean  float64
+	m2    float64
+}
+
+// add adds a value
+func (s *runningStats) add(value float64) {
+	s.count++
+	delta := value - s.mean
+	s.mean += delta / float64(s.count)
+	s.m2 += delta * (value - s.mean)
+}
+
+// stdev returns the population standard deviation of the values added
+func (s *runningStats) stdev() float64 {
+	if s.count == 0 {
+		return 0
+	}
+	return math.Sqrt(s.m2 / float64(s.count))
+}
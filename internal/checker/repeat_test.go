@@ -0,0 +1,142 @@
+package checker
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/joshbeard/link-validator/internal/config"
+)
+
+func TestRepeatStats(t *testing.T) {
+	rounds := [][]LinkResult{
+		{
+			{URL: "https://example.com/ok", DurationMS: 10},
+			{URL: "https://example.com/flaky", DurationMS: 100},
+			{URL: "https://example.com/down", ErrorType: ErrorTypeHTTP5xx, DurationMS: 5},
+		},
+		{
+			{URL: "https://example.com/ok", DurationMS: 30},
+			{URL: "https://example.com/flaky", ErrorType: ErrorTypeTimeout, DurationMS: 300},
+			{URL: "https://example.com/down", ErrorType: ErrorTypeHTTP5xx, DurationMS: 5},
+		},
+	}
+
+	stats := RepeatStats(rounds)
+	if len(stats) != 3 {
+		t.Fatalf("Expected 3 stats, got %+v", stats)
+	}
+
+	down, flaky, ok := stats[0], stats[1], stats[2]
+	if down.URL != "https://example.com/down" || down.SuccessRate() != 0 || down.Flaky() {
+		t.Errorf("Unexpected stat for the broken URL: %+v", down)
+	}
+	if flaky.URL != "https://example.com/flaky" || flaky.SuccessRate() != 50 || !flaky.Flaky() {
+		t.Errorf("Unexpected stat for the flaky URL: %+v", flaky)
+	}
+	if flaky.MeanLatency != 200*time.Millisecond || flaky.LatencyStdev != 100*time.Millisecond {
+		t.Errorf("Expected 200ms ± 100ms, got %s ± %s", flaky.MeanLatency, flaky.LatencyStdev)
+	}
+	if ok.URL != "https://example.com/ok" || ok.Attempts != 2 || ok.Successes != 2 || ok.Flaky() {
+		t.Errorf("Unexpected stat for the working URL: %+v", ok)
+	}
+	if ok.MeanLatency != 20*time.Millisecond || ok.LatencyStdev != 10*time.Millisecond {
+		t.Errorf("Expected 20ms ± 10ms, got %s ± %s", ok.MeanLatency, ok.LatencyStdev)
+	}
+}
+
+func TestRepeatStatsByLocale(t *testing.T) {
+	rounds := [][]LinkResult{
+		{
+			{URL: "https://example.com/", Locale: "en", DurationMS: 10},
+			{URL: "https://example.com/", Locale: "de", ErrorType: ErrorTypeHTTP4xx, DurationMS: 10},
+		},
+		{
+			{URL: "https://example.com/", Locale: "en", DurationMS: 10},
+			{URL: "https://example.com/", Locale: "de", ErrorType: ErrorTypeHTTP4xx, DurationMS: 10},
+		},
+	}
+
+	stats := RepeatStats(rounds)
+	if len(stats) != 2 {
+		t.Fatalf("Expected a stat per locale, got %+v", stats)
+	}
+	de, en := stats[0], stats[1]
+	if de.Locale != "de" || de.Successes != 0 || de.Attempts != 2 || de.Flaky() {
+		t.Errorf("Expected the URL to be broken in de, got %+v", de)
+	}
+	if en.Locale != "en" || en.Successes != 2 || en.Flaky() {
+		t.Errorf("Expected the URL to work in en, got %+v", en)
+	}
+}
+
+func TestRecheck(t *testing.T) {
+	requests := 0
+	external := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if requests%2 == 0 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer external.Close()
+
+	dir := t.TempDir()
+	page := fmt.Sprintf(`<a href="/about/">About</a><a href="%s/partner">Partner</a>`, external.URL)
+	if err := os.WriteFile(filepath.Join(dir, "index.html"), []byte(page), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	checker := New(&config.Config{UserAgent: "TestBot/1.0", Timeout: 5 * time.Second, MaxConcurrent: 1})
+	var stream bytes.Buffer
+	checker.StreamResults(&stream)
+
+	results, externalURLs, err := checker.CheckDirectory(dir)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	results = append(results, checker.CheckExternalLinks(externalURLs)...)
+	streamed := stream.String()
+
+	rounds := [][]LinkResult{results}
+	for round := 2; round <= 3; round++ {
+		rounds = append(rounds, checker.Recheck(results))
+	}
+	if stream.String() != streamed {
+		t.Errorf("Expected rechecks not to be streamed, got:\n%s", strings.TrimPrefix(stream.String(), streamed))
+	}
+	if lines := strings.Count(streamed, "\n"); lines != 2 {
+		t.Errorf("Expected one streamed line per link, got %d:\n%s", lines, streamed)
+	}
+
+	stats := RepeatStats(rounds)
+	if len(stats) != 2 {
+		t.Fatalf("Expected stats for the file and external links, got %+v", stats)
+	}
+	for _, stat := range stats {
+		if stat.Attempts != 3 {
+			t.Errorf("Expected %s to be checked in every round, got %d attempts", stat.URL, stat.Attempts)
+		}
+	}
+	if stats[0].URL != "/about/" || stats[0].Successes != 0 {
+		t.Errorf("Expected the missing file to fail every round, got %+v", stats[0])
+	}
+	if stats[1].URL != external.URL+"/partner" || !stats[1].Flaky() || !rounds[1][1].External {
+		t.Errorf("Expected the external link to be rechecked as external and flaky, got %+v", stats[1])
+	}
+}
+
+func TestRepeatStatsSkipsCachedResults(t *testing.T) {
+	stats := RepeatStats([][]LinkResult{
+		{{URL: "https://example.com/", Unchanged: true}},
+		{{URL: "https://example.com/", ErrorType: ErrorTypeTimeout, DurationMS: 5000}},
+	})
+	if len(stats) != 1 || stats[0].Attempts != 1 || stats[0].Successes != 0 {
+		t.Errorf("Expected only the requested result to count, got %+v", stats)
+	}
+}
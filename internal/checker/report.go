@@ -0,0 +1,94 @@
+package checker
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"iter"
+	"os"
+	"slices"
+)
+
+// RunInfo describes the run that produced a report, so archived reports can
+// be told apart and compared over time. Times are RFC 3339 in UTC.
+type RunInfo struct {
+	Version    string            `json:"version"`
+	Commit     string            `json:"commit,omitempty"`
+	StartedAt  string            `json:"started_at"`
+	FinishedAt string            `json:"finished_at"`
+	Config     map[string]string `json:"config"`
+}
+
+// Report is the full outcome of a run: every result and page issue along with
+// the run that produced them
+type Report struct {
+	Run     RunInfo      `json:"run"`
+	Results []LinkResult `json:"results"`
+	Issues  []PageIssue  `json:"page_issues"`
+
+	RobotsSkipped []string `json:"robots_skipped,omitempty"`
+
+	// Categories counts the results and skipped links in each link category
+	Categories map[LinkCategory]int `json:"categories,omitempty"`
+	Skipped    []LinkResult         `json:"skipped,omitempty"`
+}
+
+// WriteReport writes a report to path as JSON
+func WriteReport(path string, report Report) error {
+	return WriteReportFrom(path, report, slices.Values(report.Results))
+}
+
+// WriteReportFrom writes a report to path as WriteReport does, with results
+// in place of its Results. Each result is written as it is read, so a run's
+// results can stream from a ResultStore rather than be gathered first.
+func WriteReportFrom(path string, report Report, results iter.Seq[LinkResult]) error {
+	if report.Issues == nil {
+		report.Issues = []PageIssue{}
+	}
+	run, err := json.MarshalIndent(report.Run, "  ", "  ")
+	if err != nil {
+		return fmt.Errorf("encoding report: %w", err)
+	}
+	// The fields after the results are encoded as an object of their own,
+	// whose opening brace is dropped
+	rest, err := json.MarshalIndent(struct {
+		Issues        []PageIssue          `json:"page_issues"`
+		RobotsSkipped []string             `json:"robots_skipped,omitempty"`
+		Categories    map[LinkCategory]int `json:"categories,omitempty"`
+		Skipped       []LinkResult         `json:"skipped,omitempty"`
+	}{report.Issues, report.RobotsSkipped, report.Categories, report.Skipped}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encoding report: %w", err)
+	}
+
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o644) // #nosec G302 G304 -- the report is meant to be shared
+	if err != nil {
+		return fmt.Errorf("writing report: %w", err)
+	}
+	defer file.Close()
+	w := bufio.NewWriter(file)
+	fmt.Fprintf(w, "{\n  \"run\": %s,\n  \"results\": [", run)
+	first := true
+	for result := range results {
+		data, err := json.MarshalIndent(result, "    ", "  ")
+		if err != nil {
+			return fmt.Errorf("encoding report: %w", err)
+		}
+		if !first {
+			w.WriteString(",")
+		}
+		first = false
+		fmt.Fprintf(w, "\n    %s", data)
+	}
+	if !first {
+		w.WriteString("\n  ")
+	}
+	fmt.Fprintf(w, "],%s\n", rest[1:])
+	if err := w.Flush(); err != nil {
+		return fmt.Errorf("writing report: %w", err)
+	}
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("writing report: %w", err)
+	}
+	return nil
+}
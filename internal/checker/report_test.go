@@ -0,0 +1,69 @@
+package checker
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/joshbeard/link-validator/internal/config"
+)
+
+func TestCheckLinksRecordsCheckTime(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	checker := New(&config.Config{UserAgent: "TestBot/1.0", Timeout: 5 * time.Second, MaxConcurrent: 1})
+	before := time.Now().Add(-time.Second)
+	results := checker.CheckLinks([]string{server.URL})
+
+	checkedAt, err := time.Parse(time.RFC3339, results[0].CheckedAt)
+	if err != nil {
+		t.Fatalf("Expected an RFC 3339 check time, got %q: %v", results[0].CheckedAt, err)
+	}
+	if checkedAt.Before(before.Truncate(time.Second)) || checkedAt.After(time.Now()) {
+		t.Errorf("Expected the check time to be now, got %s", checkedAt)
+	}
+}
+
+func TestWriteReport(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "report.json")
+	report := Report{
+		Run: RunInfo{
+			Version:    "1.2.3",
+			StartedAt:  "2026-01-02T03:04:05Z",
+			FinishedAt: "2026-01-02T03:05:00Z",
+			Config:     map[string]string{"base-url": "https://example.com"},
+		},
+		Results: []LinkResult{{URL: "https://example.com/", StatusCode: 200, CheckedAt: "2026-01-02T03:04:30Z"}},
+	}
+	if err := WriteReport(path, report); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded map[string]any
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("Expected valid JSON, got %v", err)
+	}
+	run := decoded["run"].(map[string]any)
+	if run["version"] != "1.2.3" || run["started_at"] != "2026-01-02T03:04:05Z" || run["finished_at"] != "2026-01-02T03:05:00Z" {
+		t.Errorf("Unexpected run info: %v", run)
+	}
+	if run["config"].(map[string]any)["base-url"] != "https://example.com" {
+		t.Errorf("Expected the config snapshot, got %v", run["config"])
+	}
+	if issues, ok := decoded["page_issues"].([]any); !ok || len(issues) != 0 {
+		t.Errorf("Expected an empty page issue list, got %v", decoded["page_issues"])
+	}
+	results := decoded["results"].([]any)
+	if len(results) != 1 || results[0].(map[string]any)["checked_at"] != "2026-01-02T03:04:30Z" {
+		t.Errorf("Unexpected results: %v", results)
+	}
+}
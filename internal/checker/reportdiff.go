@@ -0,0 +1,73 @@
+package checker
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// ReportDiff is how the broken links of two runs differ
+type ReportDiff struct {
+	// NewlyBroken are broken in the later run but weren't in the earlier one
+	NewlyBroken []LinkResult `json:"newly_broken"`
+	// Fixed were broken in the earlier run and are either working or no
+	// longer linked in the later one
+	Fixed []LinkResult `json:"fixed"`
+	// StillBroken are broken in both runs
+	StillBroken []LinkResult `json:"still_broken"`
+}
+
+// LoadReport reads a report written by WriteReport
+func LoadReport(path string) (Report, error) {
+	data, err := os.ReadFile(path) // #nosec G304 -- path is provided by the user
+	if err != nil {
+		return Report{}, fmt.Errorf("reading report: %w", err)
+	}
+	var report Report
+	if err := json.Unmarshal(data, &report); err != nil {
+		return Report{}, fmt.Errorf("parsing report %s: %w", path, err)
+	}
+	return report, nil
+}
+
+// DiffReports compares the broken links of two reports by URL. Newly and
+// still broken links are taken from after, in the order they were checked;
+// fixed links are taken from before.
+func DiffReports(before, after Report) ReportDiff {
+	wasBroken := brokenByURL(before.Results)
+	isBroken := brokenByURL(after.Results)
+
+	diff := ReportDiff{NewlyBroken: []LinkResult{}, Fixed: []LinkResult{}, StillBroken: []LinkResult{}}
+	seen := make(map[string]bool)
+	for _, result := range after.Results {
+		if !result.ErrorType.IsFailure() || seen[result.URL] {
+			continue
+		}
+		seen[result.URL] = true
+		if wasBroken[result.URL] {
+			diff.StillBroken = append(diff.StillBroken, result)
+		} else {
+			diff.NewlyBroken = append(diff.NewlyBroken, result)
+		}
+	}
+	seen = make(map[string]bool)
+	for _, result := range before.Results {
+		if !result.ErrorType.IsFailure() || seen[result.URL] || isBroken[result.URL] {
+			continue
+		}
+		seen[result.URL] = true
+		diff.Fixed = append(diff.Fixed, result)
+	}
+	return diff
+}
+
+// brokenByURL returns the URLs of the broken results
+func brokenByURL(results []LinkResult) map[string]bool {
+	broken := make(map[string]bool)
+	for _, result := range results {
+		if result.ErrorType.IsFailure() {
+			broken[result.URL] = true
+		}
+	}
+	return broken
+}
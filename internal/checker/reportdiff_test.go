@@ -0,0 +1,76 @@
+package checker
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoadReport(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "report.json")
+	report := Report{
+		Run:     RunInfo{Version: "1.2.3"},
+		Results: []LinkResult{{URL: "https://example.com/gone", StatusCode: 404, ErrorType: ErrorTypeHTTP4xx}},
+	}
+	if err := WriteReport(path, report); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, err := LoadReport(path)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if loaded.Run.Version != "1.2.3" || len(loaded.Results) != 1 || loaded.Results[0].ErrorType != ErrorTypeHTTP4xx {
+		t.Errorf("Expected the written report, got %+v", loaded)
+	}
+
+	if err := os.WriteFile(path, []byte("not json"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadReport(path); err == nil {
+		t.Error("Expected an error for an invalid report")
+	}
+	if _, err := LoadReport(filepath.Join(t.TempDir(), "missing.json")); err == nil {
+		t.Error("Expected an error for a missing report")
+	}
+}
+
+func TestDiffReports(t *testing.T) {
+	before := Report{Results: []LinkResult{
+		{URL: "https://example.com/", StatusCode: 200},
+		{URL: "https://example.com/old", StatusCode: 404, ErrorType: ErrorTypeHTTP4xx},
+		{URL: "https://example.com/flaky", StatusCode: 503, ErrorType: ErrorTypeHTTP5xx},
+		{URL: "https://example.com/removed", StatusCode: 404, ErrorType: ErrorTypeHTTP4xx},
+	}}
+	after := Report{Results: []LinkResult{
+		{URL: "https://example.com/", StatusCode: 500, ErrorType: ErrorTypeHTTP5xx},
+		{URL: "https://example.com/old", StatusCode: 404, ErrorType: ErrorTypeHTTP4xx},
+		{URL: "https://example.com/flaky", StatusCode: 200},
+		{URL: "https://example.com/login", StatusCode: 302, ErrorType: ErrorTypeAuthRequired},
+		{URL: "https://nowhere.example/", ErrorType: ErrorTypeDNS},
+		{URL: "https://nowhere.example/", ErrorType: ErrorTypeDNS},
+	}}
+
+	diff := DiffReports(before, after)
+	urls := func(results []LinkResult) []string {
+		var list []string
+		for _, result := range results {
+			list = append(list, result.URL)
+		}
+		return list
+	}
+	if got := urls(diff.NewlyBroken); len(got) != 2 || got[0] != "https://example.com/" || got[1] != "https://nowhere.example/" {
+		t.Errorf("Unexpected newly broken links %v", got)
+	}
+	if got := urls(diff.StillBroken); len(got) != 1 || got[0] != "https://example.com/old" {
+		t.Errorf("Unexpected still broken links %v", got)
+	}
+	if got := urls(diff.Fixed); len(got) != 2 || got[0] != "https://example.com/flaky" || got[1] != "https://example.com/removed" {
+		t.Errorf("Unexpected fixed links %v", got)
+	}
+
+	empty := DiffReports(Report{}, Report{})
+	if empty.NewlyBroken == nil || empty.Fixed == nil || empty.StillBroken == nil {
+		t.Error("Expected empty lists rather than nil so they encode as []")
+	}
+}
@@ -0,0 +1,204 @@
+package checker
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"iter"
+	"os"
+)
+
+// ResultStore collects the results of a run without holding them all in
+// memory. Up to limit results are kept in memory; past that, every result is
+// appended to a temporary file and read back in order by All, so the reports
+// written at the end of a run stream from disk. A limit of 0 or less keeps
+// every result in memory.
+//
+// The results the summary looks at again, those that are flagged, slow,
+// redirected or in the baseline, are always kept in memory too, along with
+// the totals it prints.
+type ResultStore struct {
+	limit    int
+	baseline map[string]bool
+
+	results []LinkResult
+	notable []LinkResult
+
+	count      int
+	accepted   int
+	unchanged  int
+	categories map[LinkCategory]int
+
+	file   *os.File
+	writer *bufio.Writer
+	err    error
+}
+
+// NewResultStore returns an empty store holding up to limit results in
+// memory. Results whose URL is in baseline are marked as MarkBaseline does as
+// they are added.
+func NewResultStore(limit int, baseline map[string]bool) *ResultStore {
+	return &ResultStore{
+		limit:      limit,
+		baseline:   baseline,
+		categories: make(map[LinkCategory]int),
+	}
+}
+
+// Add adds results to the store, spilling every result held in memory to
+// the store's file once there are more than its limit
+func (s *ResultStore) Add(results ...LinkResult) error {
+	MarkBaseline(results, s.baseline)
+	for _, result := range results {
+		s.count++
+		if result.Accepted {
+			s.accepted++
+		}
+		if result.Unchanged {
+			s.unchanged++
+		}
+		if result.Category != "" {
+			s.categories[result.Category]++
+		}
+		if s.isNotable(result) {
+			s.notable = append(s.notable, result)
+		}
+
+		if s.file == nil {
+			s.results = append(s.results, result)
+			if s.limit <= 0 || len(s.results) <= s.limit {
+				continue
+			}
+			if err := s.spill(); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := s.write(result); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// isNotable reports whether the summary needs a result again once every
+// result has been added
+func (s *ResultStore) isNotable(result LinkResult) bool {
+	return result.ErrorType != "" || result.Slow || result.FinalURL != "" || s.baseline[result.URL]
+}
+
+// spill moves the results held in memory to a new temporary file, which
+// every later result is appended to
+func (s *ResultStore) spill() error {
+	file, err := os.CreateTemp("", "link-checker-results-*.jsonl")
+	if err != nil {
+		return fmt.Errorf("creating results file: %w", err)
+	}
+	s.file, s.writer = file, bufio.NewWriter(file)
+	for _, result := range s.results {
+		if err := s.write(result); err != nil {
+			return err
+		}
+	}
+	s.results = nil
+	return nil
+}
+
+// write appends a result to the store's file as a line of JSON
+func (s *ResultStore) write(result LinkResult) error {
+	data, err := json.Marshal(result)
+	if err != nil {
+		return fmt.Errorf("encoding result: %w", err)
+	}
+	if _, err := s.writer.Write(append(data, '\n')); err != nil {
+		return fmt.Errorf("writing results file: %w", err)
+	}
+	return nil
+}
+
+// All returns every result in the order it was added. Results that spilled
+// are read back from the store's file each time the sequence is ranged
+// over; a failure to read them ends the sequence early and is returned by
+// Err.
+func (s *ResultStore) All() iter.Seq[LinkResult] {
+	return func(yield func(LinkResult) bool) {
+		if s.file == nil {
+			for _, result := range s.results {
+				if !yield(result) {
+					return
+				}
+			}
+			return
+		}
+
+		if err := s.writer.Flush(); err != nil {
+			s.err = fmt.Errorf("writing results file: %w", err)
+			return
+		}
+		file, err := os.Open(s.file.Name())
+		if err != nil {
+			s.err = fmt.Errorf("opening results file: %w", err)
+			return
+		}
+		defer file.Close()
+		reader := bufio.NewReader(file)
+		for range s.count {
+			line, err := reader.ReadBytes('\n')
+			if err != nil {
+				s.err = fmt.Errorf("reading results file: %w", err)
+				return
+			}
+			var result LinkResult
+			if err := json.Unmarshal(line, &result); err != nil {
+				s.err = fmt.Errorf("decoding results file: %w", err)
+				return
+			}
+			if !yield(result) {
+				return
+			}
+		}
+	}
+}
+
+// Err returns the first error reading back results that spilled, if any
+func (s *ResultStore) Err() error {
+	return s.err
+}
+
+// Notable returns the results that are flagged, slow, redirected or in the
+// baseline, in the order they were added
+func (s *ResultStore) Notable() []LinkResult {
+	return s.notable
+}
+
+// Len returns how many results have been added
+func (s *ResultStore) Len() int {
+	return s.count
+}
+
+// Accepted returns how many results answered with an accepted status code
+func (s *ResultStore) Accepted() int {
+	return s.accepted
+}
+
+// Unchanged returns how many results were skipped as unchanged since their
+// last successful check
+func (s *ResultStore) Unchanged() int {
+	return s.unchanged
+}
+
+// Categories returns how many results there are in each category, as
+// CountByCategory does
+func (s *ResultStore) Categories() map[LinkCategory]int {
+	return s.categories
+}
+
+// Close removes the store's file, if results ever spilled to one
+func (s *ResultStore) Close() {
+	if s.file == nil {
+		return
+	}
+	s.file.Close()
+	os.Remove(s.file.Name())
+	s.file = nil
+}
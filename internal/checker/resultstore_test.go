@@ -0,0 +1,156 @@
+package checker
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"slices"
+	"testing"
+	"time"
+
+	"golang.org/x/time/rate"
+
+	"github.com/joshbeard/link-validator/internal/config"
+)
+
+func TestResultStore(t *testing.T) {
+	baseline := map[string]bool{"https://example.com/known": true}
+	store := NewResultStore(10, baseline)
+	defer store.Close()
+
+	var added []LinkResult
+	for i := range 1000 {
+		result := LinkResult{URL: fmt.Sprintf("https://example.com/%d", i), StatusCode: 200, Category: CategoryInternal}
+		switch i {
+		case 100:
+			result.URL = "https://example.com/known"
+			result.StatusCode, result.ErrorType, result.Severity = 404, ErrorTypeHTTP4xx, SeverityError
+		case 200:
+			result.Slow = true
+		case 300:
+			result.FinalURL = "https://example.com/moved"
+		case 400:
+			result.Accepted = true
+		case 500:
+			result.Unchanged = true
+		}
+		added = append(added, result)
+		if err := store.Add(result); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+	}
+
+	// Past the limit only the results the summary needs stay in memory
+	if len(store.results) != 0 || store.file == nil {
+		t.Fatalf("Expected every result to have spilled, got %d in memory", len(store.results))
+	}
+	notable := store.Notable()
+	if len(notable) != 3 || notable[0].URL != "https://example.com/known" || !notable[1].Slow || notable[2].FinalURL == "" {
+		t.Errorf("Expected the broken, slow and redirected results, got %+v", notable)
+	}
+	if !notable[0].Baseline || notable[0].Severity != SeverityWarning {
+		t.Errorf("Expected the known broken link to be marked as in the baseline, got %+v", notable[0])
+	}
+	if store.Len() != 1000 || store.Accepted() != 1 || store.Unchanged() != 1 || store.Categories()[CategoryInternal] != 1000 {
+		t.Errorf("Unexpected totals: %d checked, %d accepted, %d unchanged, %v", store.Len(), store.Accepted(), store.Unchanged(), store.Categories())
+	}
+
+	// The results read back in order, as often as they are ranged over
+	added[100].Baseline, added[100].Severity = true, SeverityWarning
+	for range 2 {
+		replayed := slices.Collect(store.All())
+		if len(replayed) != len(added) {
+			t.Fatalf("Expected %d results, got %d", len(added), len(replayed))
+		}
+		for i := range added {
+			if replayed[i].URL != added[i].URL || replayed[i].Severity != added[i].Severity || replayed[i].Slow != added[i].Slow {
+				t.Fatalf("Expected result %d to be %+v, got %+v", i, added[i], replayed[i])
+			}
+		}
+	}
+	if err := store.Err(); err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+
+	name := store.file.Name()
+	store.Close()
+	if _, err := os.Stat(name); !os.IsNotExist(err) {
+		t.Errorf("Expected the results file to be removed, got %v", err)
+	}
+
+	unlimited := NewResultStore(0, nil)
+	for i := range 100 {
+		unlimited.Add(LinkResult{URL: fmt.Sprint(i)})
+	}
+	if unlimited.file != nil || len(slices.Collect(unlimited.All())) != 100 {
+		t.Errorf("Expected every result in memory without a limit, got %d", len(unlimited.results))
+	}
+}
+
+func TestCheckLinksToBatches(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/broken" {
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	checker := New(&config.Config{UserAgent: "TestBot/1.0", Timeout: 5 * time.Second, MaxConcurrent: 20})
+	checker.limiter = rate.NewLimiter(rate.Inf, 0)
+	urls := []string{server.URL + "/broken"}
+	for i := range checkBatchSize + 500 {
+		urls = append(urls, fmt.Sprintf("%s/%d", server.URL, i))
+	}
+
+	// No batch handed on is larger than checkBatchSize, so a huge list's
+	// URLs and results are never all in memory, read back from the list's
+	// file and on their way to the store's
+	list := NewURLList(100)
+	defer list.Close()
+	if err := list.Add(urls...); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	store := NewResultStore(100, nil)
+	defer store.Close()
+	largest := 0
+	err := checker.checkLinksTo(list.All(), list.Len(), 20, checker.limiter, func(batch ...LinkResult) error {
+		largest = max(largest, len(batch))
+		return store.Add(batch...)
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if largest != checkBatchSize {
+		t.Errorf("Expected batches of %d results, got up to %d", checkBatchSize, largest)
+	}
+	if store.Len() != len(urls) || len(store.results) != 0 || len(store.Notable()) != 1 {
+		t.Errorf("Expected %d results with only the broken one in memory, got %d, %d in memory, %d notable",
+			len(urls), store.Len(), len(store.results), len(store.Notable()))
+	}
+
+	replayed := slices.Collect(store.All())
+	for i, result := range replayed {
+		if result.URL != urls[i] {
+			t.Fatalf("Expected results in the order checked, got %s at %d", result.URL, i)
+		}
+	}
+}
+
+func TestResultStoreElapsed(t *testing.T) {
+	store := NewResultStore(1, nil)
+	defer store.Close()
+
+	checked := LinkResult{URL: "https://example.com/", elapsed: 1500 * time.Microsecond, DurationMS: 1}
+	if checked.Elapsed() != 1500*time.Microsecond {
+		t.Errorf("Expected the exact duration of a checked result, got %s", checked.Elapsed())
+	}
+	if err := store.Add(checked, LinkResult{URL: "https://example.com/other"}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	for result := range store.All() {
+		if result.URL == checked.URL && result.Elapsed() != time.Millisecond {
+			t.Errorf("Expected a spilled result to keep its duration to the millisecond, got %s", result.Elapsed())
+		}
+	}
+}
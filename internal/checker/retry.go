@@ -0,0 +1,46 @@
+package checker
+
+import (
+	"net/http"
+	"sync/atomic"
+)
+
+// retryBudget caps the total number of retries spent across a whole run so
+// that widespread flakiness degrades to single attempts instead of
+// multiplying the run time.
+type retryBudget struct {
+	limit int64 // 0 means unlimited
+	used  atomic.Int64
+}
+
+// take reserves one retry from the budget, returning false once it is spent
+func (b *retryBudget) take() bool {
+	for {
+		used := b.used.Load()
+		if b.limit > 0 && used >= b.limit {
+			return false
+		}
+		if b.used.CompareAndSwap(used, used+1) {
+			return true
+		}
+	}
+}
+
+// RetriesUsed returns the number of retries consumed so far in this run
+func (c *Checker) RetriesUsed() int {
+	return int(c.retries.used.Load())
+}
+
+// shouldRetry reports whether a failed result looks transient and is worth
+// another attempt: timeouts, connection failures, rate limiting and server
+// errors.
+func shouldRetry(result LinkResult) bool {
+	switch result.ErrorType {
+	case ErrorTypeTimeout, ErrorTypeConnect, ErrorTypeHTTP5xx:
+		return true
+	case ErrorTypeHTTP4xx:
+		return result.StatusCode == http.StatusTooManyRequests
+	default:
+		return false
+	}
+}
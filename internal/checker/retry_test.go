@@ -0,0 +1,135 @@
+package checker
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/joshbeard/link-validator/internal/config"
+)
+
+func TestRetryBudgetTake(t *testing.T) {
+	t.Run("limited budget", func(t *testing.T) {
+		budget := &retryBudget{limit: 2}
+
+		if !budget.take() || !budget.take() {
+			t.Fatal("Expected first two retries to be granted")
+		}
+		if budget.take() {
+			t.Error("Expected third retry to be refused")
+		}
+		if budget.used.Load() != 2 {
+			t.Errorf("Expected 2 retries used, got %d", budget.used.Load())
+		}
+	})
+
+	t.Run("unlimited budget", func(t *testing.T) {
+		budget := &retryBudget{}
+
+		for i := 0; i < 100; i++ {
+			if !budget.take() {
+				t.Fatalf("Expected retry %d to be granted", i)
+			}
+		}
+	})
+}
+
+func TestShouldRetry(t *testing.T) {
+	testCases := []struct {
+		result   LinkResult
+		expected bool
+	}{
+		{LinkResult{StatusCode: 200}, false},
+		{LinkResult{StatusCode: 404, ErrorType: ErrorTypeHTTP4xx}, false},
+		{LinkResult{StatusCode: 429, ErrorType: ErrorTypeHTTP4xx}, true},
+		{LinkResult{StatusCode: 503, ErrorType: ErrorTypeHTTP5xx}, true},
+		{LinkResult{ErrorType: ErrorTypeConnect}, true},
+		{LinkResult{ErrorType: ErrorTypeTimeout}, true},
+		{LinkResult{ErrorType: ErrorTypeDNS}, false},
+		{LinkResult{ErrorType: ErrorTypeOther}, false},
+	}
+
+	for _, tc := range testCases {
+		if got := shouldRetry(tc.result); got != tc.expected {
+			t.Errorf("Result %+v: expected %v, got %v", tc.result, tc.expected, got)
+		}
+	}
+}
+
+func TestCheckSingleLinkRetries(t *testing.T) {
+	var hits atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if hits.Add(1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	t.Run("retries until success", func(t *testing.T) {
+		hits.Store(0)
+		cfg := &config.Config{
+			UserAgent:     "TestBot/1.0",
+			Timeout:       5 * time.Second,
+			MaxConcurrent: 1,
+			MaxRetries:    3,
+			RetryBudget:   10,
+		}
+		checker := New(cfg)
+		checker.retryDelay = 0
+
+		result := checker.checkSingleLink(server.URL)
+		if result.StatusCode != 200 {
+			t.Errorf("Expected status 200 after retries, got %d", result.StatusCode)
+		}
+		if result.Retries != 2 {
+			t.Errorf("Expected 2 retries, got %d", result.Retries)
+		}
+		if checker.RetriesUsed() != 2 {
+			t.Errorf("Expected 2 retries used, got %d", checker.RetriesUsed())
+		}
+	})
+
+	t.Run("budget exhausted degrades to single attempt", func(t *testing.T) {
+		hits.Store(0)
+		cfg := &config.Config{
+			UserAgent:     "TestBot/1.0",
+			Timeout:       5 * time.Second,
+			MaxConcurrent: 1,
+			MaxRetries:    3,
+			RetryBudget:   1,
+		}
+		checker := New(cfg)
+		checker.retryDelay = 0
+
+		result := checker.checkSingleLink(server.URL)
+		if result.StatusCode != 503 {
+			t.Errorf("Expected status 503 once budget is spent, got %d", result.StatusCode)
+		}
+		if checker.RetriesUsed() != 1 {
+			t.Errorf("Expected 1 retry used, got %d", checker.RetriesUsed())
+		}
+	})
+
+	t.Run("no retries by default", func(t *testing.T) {
+		hits.Store(0)
+		cfg := &config.Config{
+			UserAgent:     "TestBot/1.0",
+			Timeout:       5 * time.Second,
+			MaxConcurrent: 1,
+			RetryBudget:   10,
+		}
+		checker := New(cfg)
+
+		result := checker.checkSingleLink(server.URL)
+		if result.StatusCode != 503 {
+			t.Errorf("Expected status 503 without retries, got %d", result.StatusCode)
+		}
+		if checker.RetriesUsed() != 0 {
+			t.Errorf("Expected no retries used, got %d", checker.RetriesUsed())
+		}
+	})
+}
@@ -0,0 +1,294 @@
+package checker
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxRobotsSize limits how much of a robots.txt file is read, matching the
+// 500 KiB that RFC 9309 asks crawlers to parse at least
+const maxRobotsSize = 500 * 1024
+
+// robotsRules are the robots.txt rules that apply to our user agent
+type robotsRules struct {
+	allow      []string
+	disallow   []string
+	crawlDelay time.Duration
+}
+
+// robotsGroup is a group of rules in a robots.txt file and the user agents
+// it applies to
+type robotsGroup struct {
+	agents []string
+	rules  robotsRules
+}
+
+// parseRobots reads a robots.txt file and returns the rules of the group
+// that names the product token of userAgent, or else of the "*" group
+func parseRobots(r io.Reader, userAgent string) robotsRules {
+	var groups []*robotsGroup
+	var current *robotsGroup
+	inAgents := false
+
+	scanner := bufio.NewScanner(io.LimitReader(r, maxRobotsSize))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if i := strings.Index(line, "#"); i >= 0 {
+			line = line[:i]
+		}
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		key = strings.ToLower(strings.TrimSpace(key))
+		value = strings.TrimSpace(value)
+
+		if key == "user-agent" {
+			if !inAgents {
+				current = &robotsGroup{}
+				groups = append(groups, current)
+				inAgents = true
+			}
+			current.agents = append(current.agents, strings.ToLower(value))
+			continue
+		}
+		inAgents = false
+		if current == nil {
+			continue
+		}
+
+		switch key {
+		case "allow":
+			if value != "" {
+				current.rules.allow = append(current.rules.allow, value)
+			}
+		case "disallow":
+			if value != "" {
+				current.rules.disallow = append(current.rules.disallow, value)
+			}
+		case "crawl-delay":
+			if seconds, err := strconv.ParseFloat(value, 64); err == nil && seconds > 0 {
+				current.rules.crawlDelay = time.Duration(seconds * float64(time.Second))
+			}
+		}
+	}
+
+	token := strings.ToLower(userAgent)
+	if i := strings.IndexAny(token, "/ "); i >= 0 {
+		token = token[:i]
+	}
+	var wildcard *robotsRules
+	for _, group := range groups {
+		for _, agent := range group.agents {
+			switch {
+			case agent == "*":
+				if wildcard == nil {
+					wildcard = &group.rules
+				}
+			case token != "" && strings.Contains(token, agent):
+				return group.rules
+			}
+		}
+	}
+	if wildcard != nil {
+		return *wildcard
+	}
+	return robotsRules{}
+}
+
+// allowed reports whether the rules allow fetching a path. The longest
+// matching rule wins, and allow wins a tie.
+func (r robotsRules) allowed(path string) bool {
+	longest := -1
+	allowed := true
+	for _, pattern := range r.disallow {
+		if matchesRobotsPattern(pattern, path) && len(pattern) > longest {
+			longest = len(pattern)
+			allowed = false
+		}
+	}
+	for _, pattern := range r.allow {
+		if matchesRobotsPattern(pattern, path) && len(pattern) >= longest {
+			longest = len(pattern)
+			allowed = true
+		}
+	}
+	return allowed
+}
+
+// matchesRobotsPattern reports whether a robots.txt path pattern matches a
+// path. "*" matches any run of characters and a trailing "$" anchors the
+// pattern to the end of the path; otherwise patterns match as prefixes.
+func matchesRobotsPattern(pattern, path string) bool {
+	anchored := strings.HasSuffix(pattern, "$")
+	pattern = strings.TrimSuffix(pattern, "$")
+
+	parts := strings.Split(pattern, "*")
+	if !strings.HasPrefix(path, parts[0]) {
+		return false
+	}
+	rest := path[len(parts[0]):]
+	for i, part := range parts[1:] {
+		last := i == len(parts)-2
+		if last && anchored {
+			return strings.HasSuffix(rest, part)
+		}
+		index := strings.Index(rest, part)
+		if index < 0 {
+			return false
+		}
+		rest = rest[index+len(part):]
+	}
+	return !anchored || rest == ""
+}
+
+// LoadRobots fetches the robots.txt of the site at baseURL so that crawling
+// skips the paths it disallows for our user agent and waits its Crawl-delay
+// between requests to the site. A missing robots.txt allows everything.
+func (c *Checker) LoadRobots(baseURL string) error {
+	base, err := url.Parse(c.RewritePreview(baseURL))
+	if err != nil {
+		return fmt.Errorf("parsing base URL: %w", err)
+	}
+	robotsURL := base.ResolveReference(&url.URL{Path: "/robots.txt"}).String()
+
+	req, err := http.NewRequest("GET", robotsURL, nil)
+	if err != nil {
+		return fmt.Errorf("creating request: %w", err)
+	}
+	userAgent := c.userAgentFor(robotsURL)
+	req.Header.Set("User-Agent", userAgent)
+	c.authorize(req)
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("fetching robots.txt: %w", err)
+	}
+	defer resp.Body.Close()
+
+	rules := robotsRules{}
+	switch {
+	case resp.StatusCode == http.StatusOK:
+		rules = parseRobots(resp.Body, userAgent)
+	case resp.StatusCode >= 500:
+		return fmt.Errorf("robots.txt returned status %d", resp.StatusCode)
+	}
+
+	c.robotsHost = base.Host
+	c.robots = &rules
+	if rules.crawlDelay > 0 {
+		c.throttle.setMinInterval(base.Host, rules.crawlDelay)
+	}
+	return nil
+}
+
+// parseRobotsSitemaps returns the URLs of the Sitemap directives in a
+// robots.txt file. Unlike rules, they apply regardless of user agent.
+func parseRobotsSitemaps(r io.Reader) []string {
+	var sitemaps []string
+	scanner := bufio.NewScanner(io.LimitReader(r, maxRobotsSize))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if i := strings.Index(line, "#"); i >= 0 {
+			line = line[:i]
+		}
+		key, value, ok := strings.Cut(line, ":")
+		if !ok || !strings.EqualFold(strings.TrimSpace(key), "sitemap") {
+			continue
+		}
+		if value = strings.TrimSpace(value); value != "" {
+			sitemaps = append(sitemaps, value)
+		}
+	}
+	return sitemaps
+}
+
+// RobotsSitemaps returns the sitemap URLs listed by Sitemap directives in
+// the robots.txt of the site at baseURL, or none if it has no robots.txt
+func (c *Checker) RobotsSitemaps(baseURL string) []string {
+	base, err := url.Parse(baseURL)
+	if err != nil {
+		return nil
+	}
+	robotsURL := base.ResolveReference(&url.URL{Path: "/robots.txt"}).String()
+
+	req, err := http.NewRequest("GET", robotsURL, nil)
+	if err != nil {
+		return nil
+	}
+	req.Header.Set("User-Agent", c.userAgentFor(robotsURL))
+	c.authorize(req)
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		if c.config.Verbose {
+			fmt.Printf("No robots.txt at %s: %v\n", robotsURL, err)
+		}
+		return nil
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil
+	}
+
+	var sitemaps []string
+	for _, s := range parseRobotsSitemaps(resp.Body) {
+		if u, err := base.Parse(s); err == nil {
+			sitemaps = append(sitemaps, u.String())
+		}
+	}
+	return sitemaps
+}
+
+// disallowedByRobots reports whether robots.txt disallows a URL on the
+// crawled site, remembering it for RobotsSkipped
+func (c *Checker) disallowedByRobots(rawURL string) bool {
+	if c.robots == nil {
+		return false
+	}
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Host != c.robotsHost {
+		return false
+	}
+	path := u.EscapedPath()
+	if path == "" {
+		path = "/"
+	}
+	if u.RawQuery != "" {
+		path += "?" + u.RawQuery
+	}
+	if c.robots.allowed(path) {
+		return false
+	}
+
+	id, ok := c.urls.id(rawURL)
+	if !ok {
+		return true
+	}
+
+	c.inventoryMu.Lock()
+	defer c.inventoryMu.Unlock()
+	if !c.robotsSeen[id] {
+		c.robotsSeen[id] = true
+		c.robotsSkipped = append(c.robotsSkipped, id)
+		if c.config.Verbose {
+			fmt.Printf("Skipping %s (disallowed by robots.txt)\n", rawURL)
+		}
+	}
+	return true
+}
+
+// RobotsSkipped returns the URLs the crawl skipped because robots.txt
+// disallows them, in discovery order
+func (c *Checker) RobotsSkipped() []string {
+	c.inventoryMu.Lock()
+	order := append([]uint32(nil), c.robotsSkipped...)
+	c.inventoryMu.Unlock()
+	return c.urls.urlsOf(order)
+}
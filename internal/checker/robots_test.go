@@ -0,0 +1,167 @@
+package checker
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/joshbeard/link-validator/internal/config"
+)
+
+func TestParseRobots(t *testing.T) {
+	robots := `# Everyone
+User-agent: *
+Disallow: /private/
+Crawl-delay: 2
+
+User-agent: OtherBot
+User-agent: link-checker
+Disallow: /admin/
+Disallow: /*.pdf$
+Allow: /admin/public/
+Crawl-delay: 0.5
+`
+
+	rules := parseRobots(strings.NewReader(robots), "Link-Checker/1.0 (+https://example.com)")
+	if rules.crawlDelay != 500*time.Millisecond {
+		t.Errorf("Expected a crawl delay of 500ms, got %s", rules.crawlDelay)
+	}
+
+	tests := []struct {
+		path    string
+		allowed bool
+	}{
+		{"/", true},
+		{"/private/page", true},
+		{"/admin/", false},
+		{"/admin/settings", false},
+		{"/admin/public/page", true},
+		{"/docs/guide.pdf", false},
+		{"/docs/guide.pdf?download=1", true},
+	}
+	for _, tt := range tests {
+		if got := rules.allowed(tt.path); got != tt.allowed {
+			t.Errorf("allowed(%q) = %v, want %v", tt.path, got, tt.allowed)
+		}
+	}
+
+	wildcard := parseRobots(strings.NewReader(robots), "SomeBot/2.0")
+	if wildcard.allowed("/private/page") || !wildcard.allowed("/admin/") {
+		t.Errorf("Expected the * group to apply, got %+v", wildcard)
+	}
+	if wildcard.crawlDelay != 2*time.Second {
+		t.Errorf("Expected a crawl delay of 2s, got %s", wildcard.crawlDelay)
+	}
+
+	if rules := parseRobots(strings.NewReader("Disallow: /\n"), "SomeBot/2.0"); !rules.allowed("/") {
+		t.Error("Expected rules outside a group to be ignored")
+	}
+}
+
+func TestMatchesRobotsPattern(t *testing.T) {
+	tests := []struct {
+		pattern string
+		path    string
+		want    bool
+	}{
+		{"/docs", "/docs/intro", true},
+		{"/docs", "/blog", false},
+		{"/*/print", "/docs/intro/print", true},
+		{"/*.php$", "/index.php", true},
+		{"/*.php$", "/index.php5", false},
+		{"/exact$", "/exact", true},
+		{"/exact$", "/exact/more", false},
+		{"/*", "/anything", true},
+	}
+	for _, tt := range tests {
+		if got := matchesRobotsPattern(tt.pattern, tt.path); got != tt.want {
+			t.Errorf("matchesRobotsPattern(%q, %q) = %v, want %v", tt.pattern, tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestCrawlRespectsRobots(t *testing.T) {
+	var requested []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requested = append(requested, r.URL.Path)
+		switch r.URL.Path {
+		case "/robots.txt":
+			fmt.Fprint(w, "User-agent: *\nDisallow: /private/\n")
+		case "/":
+			w.Header().Set("Content-Type", "text/html")
+			fmt.Fprint(w, `<a href="/docs/">Docs</a><a href="/private/">Private</a>`)
+		default:
+			w.Header().Set("Content-Type", "text/html")
+		}
+	}))
+	defer server.Close()
+
+	checker := New(&config.Config{UserAgent: "TestBot/1.0", Timeout: 5 * time.Second, MaxConcurrent: 1})
+	if err := checker.LoadRobots(server.URL); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	urls, err := checker.CrawlWebsite(server.URL+"/", 2)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	expected := []string{server.URL + "/", server.URL + "/docs/"}
+	if !reflect.DeepEqual(urls, expected) {
+		t.Errorf("Expected %v, got %v", expected, urls)
+	}
+	if skipped := checker.RobotsSkipped(); !reflect.DeepEqual(skipped, []string{server.URL + "/private/"}) {
+		t.Errorf("Expected /private/ to be skipped, got %v", skipped)
+	}
+	for _, path := range requested {
+		if path == "/private/" {
+			t.Error("Expected /private/ not to be requested")
+		}
+	}
+}
+
+func TestLoadRobotsMissing(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	defer server.Close()
+
+	checker := New(&config.Config{Timeout: 5 * time.Second})
+	if err := checker.LoadRobots(server.URL); err != nil {
+		t.Fatalf("Expected a missing robots.txt to be fine, got %v", err)
+	}
+	if checker.disallowedByRobots(server.URL + "/anything") {
+		t.Error("Expected a missing robots.txt to allow everything")
+	}
+}
+
+func TestRobotsSitemaps(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/robots.txt" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		fmt.Fprint(w, `User-agent: *
+Disallow: /private/
+
+sitemap: /maps/pages.xml # relative
+Sitemap: https://cdn.example.com/sitemap.xml
+Sitemap:
+`)
+	}))
+	defer server.Close()
+
+	checker := New(&config.Config{Timeout: 5 * time.Second})
+	got := checker.RobotsSitemaps(server.URL + "/docs/")
+	want := []string{server.URL + "/maps/pages.xml", "https://cdn.example.com/sitemap.xml"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected %v, got %v", want, got)
+	}
+
+	missing := httptest.NewServer(http.NotFoundHandler())
+	defer missing.Close()
+	if got := checker.RobotsSitemaps(missing.URL); got != nil {
+		t.Errorf("Expected no sitemaps without robots.txt, got %v", got)
+	}
+}
@@ -0,0 +1,36 @@
+package checker
+
+import (
+	"math"
+	"math/rand"
+	"sort"
+)
+
+// SampleURLs deterministically selects a subset of urls for runs where full
+// coverage is too expensive. size caps the number of URLs and percent keeps
+// that share of them; when both are set the smaller sample wins and when
+// neither is set all URLs are returned. The same seed always yields the same
+// sample, and the selected URLs keep their original order.
+func SampleURLs(urls []string, size int, percent float64, seed int64) []string {
+	count := len(urls)
+	if percent > 0 && percent < 100 {
+		count = int(math.Ceil(float64(len(urls)) * percent / 100))
+	}
+	if size > 0 && size < count {
+		count = size
+	}
+	if count >= len(urls) {
+		return urls
+	}
+
+	// #nosec G404 -- sampling only needs to be reproducible, not secure
+	rng := rand.New(rand.NewSource(seed))
+	indices := rng.Perm(len(urls))[:count]
+	sort.Ints(indices)
+
+	sampled := make([]string, 0, count)
+	for _, i := range indices {
+		sampled = append(sampled, urls[i])
+	}
+	return sampled
+}
@@ -0,0 +1,70 @@
+package checker
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestSampleURLs(t *testing.T) {
+	urls := make([]string, 100)
+	for i := range urls {
+		urls[i] = fmt.Sprintf("https://example.com/page%d", i)
+	}
+
+	t.Run("no sampling", func(t *testing.T) {
+		if got := SampleURLs(urls, 0, 0, 1); len(got) != 100 {
+			t.Errorf("Expected all 100 URLs, got %d", len(got))
+		}
+	})
+
+	t.Run("fixed size", func(t *testing.T) {
+		if got := SampleURLs(urls, 10, 0, 1); len(got) != 10 {
+			t.Errorf("Expected 10 URLs, got %d", len(got))
+		}
+	})
+
+	t.Run("percentage rounds up", func(t *testing.T) {
+		if got := SampleURLs(urls, 0, 2.5, 1); len(got) != 3 {
+			t.Errorf("Expected 3 URLs, got %d", len(got))
+		}
+	})
+
+	t.Run("smaller of size and percent wins", func(t *testing.T) {
+		if got := SampleURLs(urls, 5, 50, 1); len(got) != 5 {
+			t.Errorf("Expected 5 URLs, got %d", len(got))
+		}
+		if got := SampleURLs(urls, 50, 5, 1); len(got) != 5 {
+			t.Errorf("Expected 5 URLs, got %d", len(got))
+		}
+	})
+
+	t.Run("size larger than input", func(t *testing.T) {
+		if got := SampleURLs(urls[:3], 10, 0, 1); len(got) != 3 {
+			t.Errorf("Expected 3 URLs, got %d", len(got))
+		}
+	})
+
+	t.Run("deterministic per seed and order preserving", func(t *testing.T) {
+		first := SampleURLs(urls, 10, 0, 42)
+		second := SampleURLs(urls, 10, 0, 42)
+		other := SampleURLs(urls, 10, 0, 7)
+
+		if fmt.Sprint(first) != fmt.Sprint(second) {
+			t.Errorf("Expected the same seed to give the same sample:\n%v\n%v", first, second)
+		}
+		if fmt.Sprint(first) == fmt.Sprint(other) {
+			t.Error("Expected different seeds to give different samples")
+		}
+
+		position := make(map[string]int, len(urls))
+		for i, u := range urls {
+			position[u] = i
+		}
+		for i := 1; i < len(first); i++ {
+			if position[first[i-1]] >= position[first[i]] {
+				t.Errorf("Expected sampled URLs to keep their original order: %v", first)
+				break
+			}
+		}
+	})
+}
@@ -0,0 +1,84 @@
+package checker
+
+import (
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/joshbeard/link-validator/internal/config"
+)
+
+// schemeAction returns the scheme policy's action for an href. Relative
+// references take the scheme of the page they're on, so they're checked.
+func (c *Checker) schemeAction(href string) string {
+	u, err := url.Parse(strings.TrimSpace(href))
+	if err != nil {
+		return config.SchemeIgnore
+	}
+	if u.Scheme == "" {
+		return config.SchemeCheck
+	}
+
+	policy := c.config.SchemePolicy
+	if policy == nil {
+		policy = defaultSchemePolicy
+	}
+	if action, ok := policy[strings.ToLower(u.Scheme)]; ok {
+		return action
+	}
+	if action, ok := policy["*"]; ok {
+		return action
+	}
+	return config.SchemeIgnore
+}
+
+// defaultSchemePolicy applies when the configuration doesn't set a policy
+var defaultSchemePolicy = config.ParseSchemePolicy("")
+
+// reportsSchemes reports whether the scheme policy flags any links
+func (c *Checker) reportsSchemes() bool {
+	for _, action := range c.config.SchemePolicy {
+		if action == config.SchemeReport {
+			return true
+		}
+	}
+	return false
+}
+
+// auditScheme flags a link whose scheme the policy reports as unsupported
+func (c *Checker) auditScheme(pageURL, href string) {
+	if c.schemeAction(href) != config.SchemeReport {
+		return
+	}
+	u, _ := url.Parse(strings.TrimSpace(href))
+	c.recordIssue(PageIssue{Page: pageURL, Type: IssueUnsupportedScheme, Link: strings.TrimSpace(href),
+		Detail: "link uses the unsupported " + strings.ToLower(u.Scheme) + ": scheme"})
+}
+
+// forbidsScheme reports whether the scheme policy fails links like href.
+// They are checked without a request, and reported as broken.
+func (c *Checker) forbidsScheme(href string) bool {
+	return c.schemeAction(href) == config.SchemeFail
+}
+
+// forbiddenSchemeResult returns the result of a link whose scheme the policy
+// fails
+func forbiddenSchemeResult(link string) LinkResult {
+	scheme := ""
+	if u, err := url.Parse(link); err == nil {
+		scheme = strings.ToLower(u.Scheme)
+	}
+	return LinkResult{
+		URL:       link,
+		Error:     "links with the " + scheme + ": scheme are not allowed by scheme-policy",
+		ErrorType: ErrorTypeForbiddenScheme,
+		Duration:  "0s",
+		CheckedAt: time.Now().UTC().Format(time.RFC3339),
+	}
+}
+
+// isMailto reports whether an href is a mailto: link, which is never checked
+func isMailto(href string) bool {
+	u, err := url.Parse(strings.TrimSpace(href))
+	return err == nil && strings.EqualFold(u.Scheme, "mailto")
+}
@@ -0,0 +1,116 @@
+package checker
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/joshbeard/link-validator/internal/config"
+)
+
+func TestSchemePolicy(t *testing.T) {
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		if r.URL.Path == "/" {
+			fmt.Fprintf(w, `<a href="/docs/">Docs</a>
+<a href="ftp://%s/file.txt">FTP</a>
+<a href="tel:+15550100">Call</a>
+<a href="mailto:team@example.com">Mail</a>
+<a href="javascript:void(0)">Menu</a>`, strings.TrimPrefix(server.URL, "http://"))
+		}
+	}))
+	defer server.Close()
+
+	testCases := []struct {
+		policy   string
+		reported []string
+	}{
+		{"", nil},
+		{"ftp=report,tel=report", []string{"ftp://", "tel:+15550100"}},
+		{"*=report,javascript=ignore", []string{"ftp://", "tel:+15550100", "mailto:team@example.com"}},
+	}
+
+	for _, tc := range testCases {
+		checker := New(&config.Config{
+			UserAgent:     "TestBot/1.0",
+			Timeout:       5 * time.Second,
+			MaxConcurrent: 1,
+			SchemePolicy:  config.ParseSchemePolicy(tc.policy),
+		})
+		urls, err := checker.CrawlWebsite(server.URL+"/", 2)
+		if err != nil {
+			t.Fatalf("%q: expected no error, got %v", tc.policy, err)
+		}
+		if len(urls) != 2 || urls[1] != server.URL+"/docs/" {
+			t.Errorf("%q: expected only http links to be checked, got %v", tc.policy, urls)
+		}
+
+		issues := checker.Issues()
+		if len(issues) != len(tc.reported) {
+			t.Errorf("%q: expected %d issues, got %+v", tc.policy, len(tc.reported), issues)
+			continue
+		}
+		for i, issue := range issues {
+			if issue.Type != IssueUnsupportedScheme || !strings.HasPrefix(issue.Link, tc.reported[i]) {
+				t.Errorf("%q: expected an unsupported scheme issue for %s, got %+v", tc.policy, tc.reported[i], issue)
+			}
+		}
+	}
+}
+
+func TestSchemePolicyFail(t *testing.T) {
+	requests := 0
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Header().Set("Content-Type", "text/html")
+		if r.URL.Path == "/" {
+			fmt.Fprintf(w, `<a href="/docs/">Docs</a>
+<a href=" ftp://%s/file.txt ">FTP</a>
+<a href="mailto:team@example.com">Mail</a>`, strings.TrimPrefix(server.URL, "http://"))
+		}
+	}))
+	defer server.Close()
+
+	checker := New(&config.Config{
+		UserAgent:     "TestBot/1.0",
+		Timeout:       5 * time.Second,
+		MaxConcurrent: 1,
+		SchemePolicy:  config.ParseSchemePolicy("ftp=fail,mailto=fail"),
+	})
+	urls, err := checker.CrawlWebsite(server.URL+"/", 2)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	ftpURL := "ftp://" + strings.TrimPrefix(server.URL, "http://") + "/file.txt"
+	expected := []string{server.URL + "/", server.URL + "/docs/", ftpURL, "mailto:team@example.com"}
+	if strings.Join(urls, " ") != strings.Join(expected, " ") {
+		t.Fatalf("Expected forbidden links to be checked but not crawled, got %v", urls)
+	}
+
+	requests = 0
+	results := checker.CheckLinks(urls[2:])
+	if requests != 0 {
+		t.Errorf("Expected no requests for forbidden links, got %d", requests)
+	}
+	for _, result := range results {
+		if result.ErrorType != ErrorTypeForbiddenScheme || !result.ErrorType.IsFailure() {
+			t.Errorf("Expected %s to fail as a forbidden scheme, got %+v", result.URL, result)
+		}
+		if len(result.Sources) != 1 || result.Sources[0] != server.URL+"/" {
+			t.Errorf("Expected the page linking to %s as its source, got %v", result.URL, result.Sources)
+		}
+	}
+	if results[0].Error != "links with the ftp: scheme are not allowed by scheme-policy" {
+		t.Errorf("Unexpected error %q", results[0].Error)
+	}
+
+	links, err := checker.ExtractLinks(strings.NewReader(`<a href="ftp://files.example.com/">FTP</a><a href="tel:+15550100">Call</a>`), "https://example.com/")
+	if err != nil || len(links) != 1 || links[0] != "ftp://files.example.com/" {
+		t.Errorf("Expected forbidden links in a document to be returned for checking, got %v (%v)", links, err)
+	}
+}
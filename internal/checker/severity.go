@@ -0,0 +1,53 @@
+package checker
+
+import "github.com/joshbeard/link-validator/internal/config"
+
+// Severity ranks a flagged result. Only error findings fail the run.
+type Severity string
+
+// Severities recorded in LinkResult.Severity
+const (
+	SeverityError   Severity = "error"
+	SeverityWarning Severity = "warning"
+	SeverityInfo    Severity = "info"
+)
+
+// ResultSeverity returns the severity of a flagged result. The first rule
+// matching its status code, error category or link category decides.
+// Otherwise results in the given fail-on categories are errors, or without
+// categories every broken link is, and other flagged results are warnings.
+// Results that weren't flagged have no severity.
+func ResultSeverity(result LinkResult, rules []config.SeverityRule, categories []string) Severity {
+	if result.ErrorType == "" {
+		return ""
+	}
+	for _, rule := range rules {
+		if (rule.StatusCode != 0 && rule.StatusCode == result.StatusCode) ||
+			(rule.StatusCode == 0 && (result.matches(rule.Category) || string(result.Category) == rule.Category)) {
+			return Severity(rule.Severity)
+		}
+	}
+	if failsOn(result, categories) {
+		return SeverityError
+	}
+	return SeverityWarning
+}
+
+// failsOn reports whether a result fails the run under the given
+// categories. With no categories every broken link does.
+func failsOn(result LinkResult, categories []string) bool {
+	if len(categories) == 0 {
+		return result.ErrorType.IsFailure()
+	}
+	for _, category := range categories {
+		if result.matches(category) {
+			return true
+		}
+	}
+	return false
+}
+
+// severity returns the severity of a result under the run's configuration
+func (c *Checker) severity(result LinkResult) Severity {
+	return ResultSeverity(result, c.config.SeverityRules, c.config.FailOnCategories)
+}
@@ -0,0 +1,74 @@
+package checker
+
+import (
+	"encoding/xml"
+	"fmt"
+	"os"
+	"sort"
+)
+
+// maxSitemapURLs is the most URLs the sitemaps.org protocol allows in one
+// sitemap
+const maxSitemapURLs = 50000
+
+// sitemapNamespace is the XML namespace of the sitemaps.org protocol
+const sitemapNamespace = "http://www.sitemaps.org/schemas/sitemap/0.9"
+
+// generatedSitemap is the document WriteSitemap writes. Sitemap is only
+// read, and ignores the namespace a written sitemap must declare.
+type generatedSitemap struct {
+	XMLName xml.Name              `xml:"urlset"`
+	Xmlns   string                `xml:"xmlns,attr"`
+	URLs    []generatedSitemapURL `xml:"url"`
+}
+
+// generatedSitemapURL is a <url> entry of a written sitemap
+type generatedSitemapURL struct {
+	Loc string `xml:"loc"`
+}
+
+// SitemapPages returns the discovered pages that belong in a sitemap of the
+// site, sorted: those on the site's hosts that served HTML, or whose type
+// wasn't seen, and that neither failed nor redirected when checked
+func (c *Checker) SitemapPages(results []LinkResult) []string {
+	excluded := make(map[string]bool)
+	for _, result := range results {
+		if result.ErrorType.IsFailure() || result.FinalURL != "" {
+			excluded[result.URL] = true
+		}
+	}
+
+	var pages []string
+	for entry := range c.Inventory() {
+		if excluded[entry.URL] || !c.isInternal(entry.URL) {
+			continue
+		}
+		if entry.ContentType != "" && entry.ContentType != "text/html" && entry.ContentType != "application/xhtml+xml" {
+			continue
+		}
+		pages = append(pages, entry.URL)
+	}
+	sort.Strings(pages)
+	return pages
+}
+
+// WriteSitemap writes urls to path as a sitemaps.org XML sitemap
+func WriteSitemap(path string, urls []string) error {
+	if len(urls) > maxSitemapURLs {
+		return fmt.Errorf("%d pages exceed the %d URLs a sitemap may list", len(urls), maxSitemapURLs)
+	}
+
+	sitemap := generatedSitemap{Xmlns: sitemapNamespace}
+	for _, u := range urls {
+		sitemap.URLs = append(sitemap.URLs, generatedSitemapURL{Loc: u})
+	}
+	data, err := xml.MarshalIndent(sitemap, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encoding sitemap: %w", err)
+	}
+	data = append([]byte(xml.Header), data...)
+	if err := os.WriteFile(path, append(data, '\n'), 0o644); err != nil { // #nosec G306 -- the sitemap is meant to be published
+		return fmt.Errorf("writing sitemap: %w", err)
+	}
+	return nil
+}
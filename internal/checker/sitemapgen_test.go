@@ -0,0 +1,88 @@
+package checker
+
+import (
+	"encoding/xml"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/joshbeard/link-validator/internal/config"
+)
+
+func TestSitemapPages(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/":
+			w.Header().Set("Content-Type", "text/html")
+			w.Write([]byte(`<a href="/docs/">Docs</a><a href="/guide.pdf">Guide</a><a href="/old">Old</a><a href="/gone">Gone</a>`))
+		case "/docs/":
+			w.Header().Set("Content-Type", "text/html")
+			w.Write([]byte(`<a href="/docs/intro?lang=en&amp;v=2">Intro</a>`))
+		case "/docs/intro":
+			w.Header().Set("Content-Type", "text/html")
+		case "/guide.pdf":
+			w.Header().Set("Content-Type", "application/pdf")
+		case "/old":
+			http.Redirect(w, r, "/docs/", http.StatusMovedPermanently)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	checker := New(&config.Config{BaseURL: server.URL, UserAgent: "TestBot/1.0", Timeout: 5 * time.Second, MaxConcurrent: 1})
+	if _, err := checker.CrawlWebsite(server.URL+"/", 3); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	results := []LinkResult{
+		{URL: server.URL + "/", StatusCode: 200},
+		{URL: server.URL + "/old", StatusCode: 200, FinalURL: server.URL + "/docs/"},
+		{URL: server.URL + "/gone", StatusCode: 404, ErrorType: ErrorTypeHTTP4xx},
+	}
+
+	pages := checker.SitemapPages(results)
+	expected := []string{server.URL + "/", server.URL + "/docs/", server.URL + "/docs/intro?lang=en&v=2"}
+	if !reflect.DeepEqual(pages, expected) {
+		t.Errorf("Expected %v, got %v", expected, pages)
+	}
+}
+
+func TestWriteSitemap(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "sitemap.xml")
+	urls := []string{"https://example.com/", "https://example.com/search?q=a&page=2"}
+	if err := WriteSitemap(path, urls); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, expected := range []string{
+		`<?xml version="1.0" encoding="UTF-8"?>`,
+		`<urlset xmlns="http://www.sitemaps.org/schemas/sitemap/0.9">`,
+		"<loc>https://example.com/search?q=a&amp;page=2</loc>",
+	} {
+		if !strings.Contains(string(data), expected) {
+			t.Errorf("Expected the sitemap to contain %q, got:\n%s", expected, data)
+		}
+	}
+
+	// The written sitemap reads back like any other
+	var sitemap Sitemap
+	if err := xml.Unmarshal(data, &sitemap); err != nil {
+		t.Fatalf("Expected a valid sitemap, got %v", err)
+	}
+	if len(sitemap.URLs) != 2 || sitemap.URLs[1].Loc != urls[1] {
+		t.Errorf("Expected the URLs to read back, got %+v", sitemap.URLs)
+	}
+
+	if err := WriteSitemap(path, make([]string, maxSitemapURLs+1)); err == nil {
+		t.Error("Expected an error for more URLs than a sitemap may list")
+	}
+}
@@ -0,0 +1,58 @@
+package checker
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// maxSoft404BodySize limits how much of a response body is searched for
+// soft 404 patterns
+const maxSoft404BodySize = 256 << 10
+
+// soft404Match returns the soft 404 pattern matched by the body of a
+// successful response, or "" when none matches. HEAD responses carry no
+// body, so the page is fetched again with GET. Only text responses, such as
+// HTML pages, are searched.
+func (c *Checker) soft404Match(client *http.Client, req *http.Request, resp *http.Response) string {
+	if len(c.config.Soft404Patterns) == 0 || resp.StatusCode < 200 || resp.StatusCode >= 300 || !isTextContent(resp.Header) {
+		return ""
+	}
+
+	if req.Method == http.MethodHead {
+		getReq := req.Clone(req.Context())
+		getReq.Method = http.MethodGet
+		c.throttle.wait(getReq.URL.Host)
+		getResp, err := client.Do(getReq)
+		if err != nil {
+			return ""
+		}
+		defer getResp.Body.Close()
+		c.throttle.update(getReq.URL.Host, getResp.Header, time.Now())
+		if getResp.StatusCode < 200 || getResp.StatusCode >= 300 || !isTextContent(getResp.Header) {
+			return ""
+		}
+		resp = getResp
+	}
+
+	body, err := decodeBody(resp)
+	if err != nil {
+		return ""
+	}
+	page, _ := io.ReadAll(io.LimitReader(body, maxSoft404BodySize))
+	for _, pattern := range c.config.Soft404Patterns {
+		if pattern.Match(page) {
+			return pattern.String()
+		}
+	}
+	return ""
+}
+
+// isTextContent reports whether a response is text, such as an HTML page,
+// or doesn't say what it is
+func isTextContent(header http.Header) bool {
+	contentType := strings.ToLower(header.Get("Content-Type"))
+	return contentType == "" || strings.HasPrefix(contentType, "text/") ||
+		strings.Contains(contentType, "html") || strings.Contains(contentType, "xml") || strings.Contains(contentType, "json")
+}
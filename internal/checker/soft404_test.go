@@ -0,0 +1,87 @@
+package checker
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/joshbeard/link-validator/internal/config"
+)
+
+func TestCheckSingleLinkSoft404(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/missing":
+			w.Header().Set("Content-Type", "text/html")
+			if r.Method == http.MethodGet {
+				w.Write([]byte(`<html><head><title>Oops</title></head><body><h1>Page Not Found</h1></body></html>`))
+			}
+		case "/found":
+			w.Header().Set("Content-Type", "text/html")
+			w.Write([]byte(`<html><head><title>Docs</title></head><body>Welcome</body></html>`))
+		case "/image.png":
+			w.Header().Set("Content-Type", "image/png")
+			w.Write([]byte(`Page Not Found`))
+		case "/gone":
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`Page Not Found`))
+		}
+	}))
+	defer server.Close()
+
+	checker := New(&config.Config{
+		UserAgent:       "TestBot/1.0",
+		Timeout:         5 * time.Second,
+		MaxConcurrent:   1,
+		Soft404Patterns: config.ParsePatterns("(?i)page not found"),
+	})
+
+	testCases := []struct {
+		path     string
+		expected ErrorType
+	}{
+		{"/missing", ErrorTypeSoft404},
+		{"/found", ""},
+		{"/image.png", ""},
+		{"/gone", ErrorTypeHTTP4xx},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.path, func(t *testing.T) {
+			result := checker.checkSingleLink(server.URL + tc.path)
+			if result.ErrorType != tc.expected {
+				t.Errorf("Expected error type %q, got %q (%s)", tc.expected, result.ErrorType, result.Error)
+			}
+		})
+	}
+
+	if result := New(&config.Config{UserAgent: "TestBot/1.0", Timeout: 5 * time.Second}).checkSingleLink(server.URL + "/missing"); result.ErrorType != "" {
+		t.Errorf("Expected no soft 404 without patterns, got %q", result.ErrorType)
+	}
+}
+
+func TestSoft404MatchWaitsForThrottle(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		w.Write([]byte(`<h1>Page Not Found</h1>`))
+	}))
+	defer server.Close()
+
+	checker := New(&config.Config{
+		UserAgent:       "TestBot/1.0",
+		Timeout:         5 * time.Second,
+		Soft404Patterns: config.ParsePatterns("(?i)page not found"),
+	})
+	req, _ := http.NewRequest(http.MethodHead, server.URL+"/missing", nil)
+	resp := &http.Response{StatusCode: http.StatusOK, Header: http.Header{"Content-Type": {"text/html"}}}
+
+	checker.throttle.pause(req.URL.Host, time.Now().Add(100*time.Millisecond))
+	start := time.Now()
+	if match := checker.soft404Match(http.DefaultClient, req, resp); match == "" {
+		t.Fatal("Expected the GET probe to match the soft 404 pattern")
+	}
+	if elapsed := time.Since(start); elapsed < 100*time.Millisecond {
+		t.Errorf("Expected the GET probe to wait for the host's pause, waited %v", elapsed)
+	}
+}
@@ -0,0 +1,106 @@
+package checker
+
+// recordSource remembers that sourceURL links to targetURL. Both are
+// recorded by their IDs in the checker's URL table.
+func (c *Checker) recordSource(targetURL, sourceURL string) {
+	targetID, ok := c.urls.id(targetURL)
+	if !ok {
+		return
+	}
+	sourceID, ok := c.urls.id(sourceURL)
+	if !ok {
+		return
+	}
+
+	// The pair is looked up in a set rather than the target's sources, so a
+	// link on every page of a site costs the same to record each time
+	pair := uint64(targetID)<<32 | uint64(sourceID)
+
+	c.sourcesMu.Lock()
+	defer c.sourcesMu.Unlock()
+	if _, ok := c.sourceSet[pair]; ok {
+		return
+	}
+	c.sourceSet[pair] = struct{}{}
+	c.sources[targetID] = append(c.sources[targetID], sourceID)
+}
+
+// Sources returns the pages known to link to targetURL, in discovery order
+func (c *Checker) Sources(targetURL string) []string {
+	targetID, ok := c.urls.lookup(targetURL)
+	if !ok {
+		return nil
+	}
+
+	c.sourcesMu.Lock()
+	ids := append([]uint32(nil), c.sources[targetID]...)
+	c.sourcesMu.Unlock()
+
+	return c.urls.urlsOf(ids)
+}
+
+// sourceCount returns how many pages are known to link to targetURL
+func (c *Checker) sourceCount(targetURL string) int {
+	targetID, ok := c.urls.lookup(targetURL)
+	if !ok {
+		return 0
+	}
+
+	c.sourcesMu.Lock()
+	defer c.sourcesMu.Unlock()
+	return len(c.sources[targetID])
+}
+
+// attachSources sets how many pages link to a result's URL and, for a
+// flagged, slow or redirected result the summary reports, which pages they
+// are. Working links only get the count: a link in the navigation of a huge
+// site has a source per page, each read back from the URL table.
+func (c *Checker) attachSources(result *LinkResult) {
+	if result.ErrorType == "" && !result.Slow && result.FinalURL == "" {
+		result.SourceCount = c.sourceCount(result.URL)
+		return
+	}
+	result.Sources = c.Sources(result.URL)
+	result.SourceCount = len(result.Sources)
+}
+
+// DedupeResults collapses results for the same URL into a single entry,
+// merging their referring pages so a URL linked from many pages is reported
+// once with the full list of sources. Results for different locales are kept
+// apart.
+func DedupeResults(results []LinkResult) []LinkResult {
+	deduped := make([]LinkResult, 0, len(results))
+	index := make(map[string]int, len(results))
+
+	for _, result := range results {
+		key := result.URL + "\x00" + result.Locale
+		i, seen := index[key]
+		if !seen {
+			index[key] = len(deduped)
+			result.Sources = mergeSources(nil, result.Sources)
+			result.SourceCount = max(result.SourceCount, len(result.Sources))
+			deduped = append(deduped, result)
+			continue
+		}
+
+		deduped[i].Sources = mergeSources(deduped[i].Sources, result.Sources)
+		deduped[i].SourceCount = max(deduped[i].SourceCount, result.SourceCount, len(deduped[i].Sources))
+	}
+
+	return deduped
+}
+
+// mergeSources appends the sources from extra that are not already in base
+func mergeSources(base, extra []string) []string {
+	seen := make(map[string]bool, len(base)+len(extra))
+	for _, source := range base {
+		seen[source] = true
+	}
+	for _, source := range extra {
+		if !seen[source] {
+			seen[source] = true
+			base = append(base, source)
+		}
+	}
+	return base
+}
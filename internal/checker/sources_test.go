@@ -0,0 +1,100 @@
+package checker
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/joshbeard/link-validator/internal/config"
+)
+
+func TestRecordSource(t *testing.T) {
+	checker := New(&config.Config{})
+
+	checker.recordSource("https://example.com/broken", "https://example.com/a")
+	checker.recordSource("https://example.com/broken", "https://example.com/b")
+	checker.recordSource("https://example.com/broken", "https://example.com/a")
+
+	sources := checker.Sources("https://example.com/broken")
+	if len(sources) != 2 {
+		t.Fatalf("Expected 2 unique sources, got %d: %v", len(sources), sources)
+	}
+	if sources[0] != "https://example.com/a" || sources[1] != "https://example.com/b" {
+		t.Errorf("Expected sources in discovery order, got %v", sources)
+	}
+
+	if checker.Sources("https://example.com/unknown") != nil {
+		t.Error("Expected no sources for an unknown URL")
+	}
+}
+
+func TestDedupeResults(t *testing.T) {
+	results := []LinkResult{
+		{URL: "https://example.com/broken", StatusCode: 404, Sources: []string{"/a", "/b"}},
+		{URL: "https://example.com/other", StatusCode: 500},
+		{URL: "https://example.com/broken", StatusCode: 404, Sources: []string{"/b", "/c"}},
+	}
+
+	deduped := DedupeResults(results)
+
+	if len(deduped) != 2 {
+		t.Fatalf("Expected 2 results after dedupe, got %d", len(deduped))
+	}
+	if deduped[0].URL != "https://example.com/broken" || deduped[1].URL != "https://example.com/other" {
+		t.Errorf("Expected original order to be preserved, got %+v", deduped)
+	}
+	if len(deduped[0].Sources) != 3 || deduped[0].SourceCount != 3 {
+		t.Errorf("Expected 3 merged sources, got %v (count %d)", deduped[0].Sources, deduped[0].SourceCount)
+	}
+	if deduped[1].SourceCount != 0 {
+		t.Errorf("Expected no sources for the second result, got %d", deduped[1].SourceCount)
+	}
+
+	localized := DedupeResults([]LinkResult{
+		{URL: "https://example.com/docs", StatusCode: 404, Locale: "de"},
+		{URL: "https://example.com/docs", StatusCode: 404, Locale: "fr"},
+	})
+	if len(localized) != 2 {
+		t.Errorf("Expected results for different locales to be kept apart, got %+v", localized)
+	}
+}
+
+func TestCrawlTracksSources(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		switch r.URL.Path {
+		case "/":
+			w.Write([]byte(`<a href="/page1">1</a><a href="/page2">2</a><a href="/missing">x</a>`))
+		case "/page1", "/page2":
+			w.Write([]byte(`<a href="/missing">x</a>`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	cfg := &config.Config{
+		UserAgent:     "TestBot/1.0",
+		Timeout:       5 * time.Second,
+		MaxConcurrent: 2,
+	}
+	checker := New(cfg)
+
+	urls, err := checker.CrawlWebsite(server.URL, 2)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	results := checker.CheckLinks(urls)
+	for _, result := range results {
+		if result.URL != server.URL+"/missing" {
+			continue
+		}
+		if result.SourceCount != 3 {
+			t.Errorf("Expected /missing to have 3 sources, got %d: %v", result.SourceCount, result.Sources)
+		}
+		return
+	}
+	t.Error("Expected /missing to be checked")
+}
@@ -0,0 +1,45 @@
+package checker
+
+import (
+	"encoding/json"
+	"io"
+	"sync"
+)
+
+// resultStream writes link results as newline-delimited JSON as soon as
+// they are known, so consumers can process a long run incrementally
+type resultStream struct {
+	mu  sync.Mutex
+	enc *json.Encoder
+	err error
+}
+
+// StreamResults writes every result checked from now on to w as one JSON
+// object per line
+func (c *Checker) StreamResults(w io.Writer) {
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(false)
+	c.stream = &resultStream{enc: enc}
+}
+
+// StreamErr returns the first error encountered writing streamed results
+func (c *Checker) StreamErr() error {
+	if c.stream == nil {
+		return nil
+	}
+	c.stream.mu.Lock()
+	defer c.stream.mu.Unlock()
+	return c.stream.err
+}
+
+// write streams a result, doing nothing when streaming is off or has failed
+func (s *resultStream) write(result LinkResult) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.err == nil {
+		s.err = s.enc.Encode(result)
+	}
+}
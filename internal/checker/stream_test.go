@@ -0,0 +1,53 @@
+package checker
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/joshbeard/link-validator/internal/config"
+)
+
+func TestStreamResults(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/ok", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	mux.HandleFunc("/missing", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	checker := New(&config.Config{UserAgent: "TestBot/1.0", Timeout: 5 * time.Second, MaxConcurrent: 1})
+	var buf bytes.Buffer
+	checker.StreamResults(&buf)
+
+	checker.CheckLinks([]string{server.URL + "/ok", server.URL + "/missing?a=1&b=2"})
+	if err := checker.StreamErr(); err != nil {
+		t.Fatalf("Expected no stream error, got %v", err)
+	}
+
+	output := buf.String()
+	if strings.Contains(output, `\u0026`) {
+		t.Errorf("Expected URLs without HTML escaping, got %s", output)
+	}
+
+	streamed := make(map[string]int)
+	scanner := bufio.NewScanner(strings.NewReader(output))
+	for scanner.Scan() {
+		var result LinkResult
+		if err := json.Unmarshal(scanner.Bytes(), &result); err != nil {
+			t.Fatalf("Expected one JSON object per line, got %q: %v", scanner.Text(), err)
+		}
+		streamed[result.URL] = result.StatusCode
+	}
+	if len(streamed) != 2 || streamed[server.URL+"/ok"] != 200 || streamed[server.URL+"/missing?a=1&b=2"] != 404 {
+		t.Errorf("Expected both results streamed, got %v", streamed)
+	}
+}
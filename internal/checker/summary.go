@@ -0,0 +1,131 @@
+package checker
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+)
+
+// maxSummaryRows caps the rows listed for each group in a Markdown summary.
+// GitHub rejects step summaries over 1 MiB.
+const maxSummaryRows = 100
+
+// Summary holds the totals of a run for WriteMarkdownSummary
+type Summary struct {
+	Checked       int
+	Broken        []LinkResult
+	AuthRequired  int
+	BotChallenges int
+	PageIssues    int
+	Accepted      int
+	Baseline      int
+	Slow          int
+	Duration      time.Duration
+}
+
+// WriteMarkdownSummary writes a Markdown report of a run, such as for a
+// GitHub Actions job summary: a table of totals followed by the broken links
+// grouped by status code, or by error type for links that got no response.
+func WriteMarkdownSummary(w io.Writer, summary Summary) error {
+	bw := bufio.NewWriter(w)
+
+	fmt.Fprintf(bw, "## Link Check Results\n\n")
+	if len(summary.Broken) == 0 {
+		fmt.Fprintf(bw, "✅ No broken links found.\n\n")
+	} else {
+		fmt.Fprintf(bw, "❌ Found %d broken links.\n\n", len(summary.Broken))
+	}
+
+	fmt.Fprintf(bw, "| | Count |\n|---|---:|\n")
+	fmt.Fprintf(bw, "| Links checked | %d |\n", summary.Checked)
+	fmt.Fprintf(bw, "| Broken links | %d |\n", len(summary.Broken))
+	if summary.AuthRequired > 0 {
+		fmt.Fprintf(bw, "| Requiring authentication | %d |\n", summary.AuthRequired)
+	}
+	if summary.BotChallenges > 0 {
+		fmt.Fprintf(bw, "| Blocked by bot protection | %d |\n", summary.BotChallenges)
+	}
+	if summary.Accepted > 0 {
+		fmt.Fprintf(bw, "| Accepted status codes | %d |\n", summary.Accepted)
+	}
+	if summary.Baseline > 0 {
+		fmt.Fprintf(bw, "| Known broken (baseline) | %d |\n", summary.Baseline)
+	}
+	if summary.Slow > 0 {
+		fmt.Fprintf(bw, "| Slow links | %d |\n", summary.Slow)
+	}
+	if summary.PageIssues > 0 {
+		fmt.Fprintf(bw, "| Page issues | %d |\n", summary.PageIssues)
+	}
+	fmt.Fprintf(bw, "\nDuration: %s\n", summary.Duration.Round(time.Second))
+
+	groups := make(map[string][]LinkResult)
+	for _, result := range summary.Broken {
+		label := summaryGroup(result)
+		groups[label] = append(groups[label], result)
+	}
+	labels := make([]string, 0, len(groups))
+	for label := range groups {
+		labels = append(labels, label)
+	}
+	sort.Strings(labels)
+
+	for _, label := range labels {
+		results := groups[label]
+		fmt.Fprintf(bw, "\n### %s (%d)\n\n", label, len(results))
+		fmt.Fprintf(bw, "| Link | Error | Linked from |\n|---|---|---|\n")
+		for i, result := range results {
+			if i == maxSummaryRows {
+				fmt.Fprintf(bw, "\n…and %d more\n", len(results)-maxSummaryRows)
+				break
+			}
+			link := result.URL
+			if result.Locale != "" {
+				link = fmt.Sprintf("%s [%s]", link, result.Locale)
+			}
+			fmt.Fprintf(bw, "| %s | %s | %s |\n",
+				markdownCell(link), markdownCell(result.Error), markdownCell(summarySources(result.Sources)))
+		}
+	}
+
+	return bw.Flush()
+}
+
+// summaryGroup names the group a broken link is listed under, such as
+// "HTTP 404 Not Found", or its error type, such as "timeout", for links
+// that got no error status
+func summaryGroup(result LinkResult) string {
+	if result.StatusCode >= 400 {
+		if text := http.StatusText(result.StatusCode); text != "" {
+			return fmt.Sprintf("HTTP %d %s", result.StatusCode, text)
+		}
+		return fmt.Sprintf("HTTP %d", result.StatusCode)
+	}
+	if result.ErrorType != "" {
+		return string(result.ErrorType)
+	}
+	return string(ErrorTypeOther)
+}
+
+// summarySources names the first page linking to a result and how many more
+// there are
+func summarySources(sources []string) string {
+	switch len(sources) {
+	case 0:
+		return ""
+	case 1:
+		return sources[0]
+	default:
+		return fmt.Sprintf("%s (+%d more)", sources[0], len(sources)-1)
+	}
+}
+
+// markdownCell escapes a value for a Markdown table cell
+func markdownCell(value string) string {
+	value = strings.ReplaceAll(value, "|", `\|`)
+	return strings.Join(strings.Fields(value), " ")
+}
@@ -0,0 +1,82 @@
+package checker
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWriteMarkdownSummary(t *testing.T) {
+	var buf bytes.Buffer
+	err := WriteMarkdownSummary(&buf, Summary{
+		Checked: 42,
+		Broken: []LinkResult{
+			{URL: "https://example.com/gone", StatusCode: 404, Error: "HTTP 404 404 Not Found", Sources: []string{"https://example.com/", "https://example.com/a", "https://example.com/b"}},
+			{URL: "https://example.com/down", StatusCode: 503, Error: "HTTP 503 503 Service Unavailable"},
+			{URL: "https://example.com/missing", StatusCode: 404, Error: "HTTP 404 404 Not Found", Locale: "de"},
+			{URL: "https://slow.example/", Error: "request failed: timeout | retry", ErrorType: ErrorTypeTimeout},
+		},
+		AuthRequired: 2,
+		PageIssues:   5,
+		Duration:     83 * time.Second,
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	summary := buf.String()
+
+	for _, expected := range []string{
+		"❌ Found 4 broken links.",
+		"| Links checked | 42 |",
+		"| Broken links | 4 |",
+		"| Requiring authentication | 2 |",
+		"| Page issues | 5 |",
+		"Duration: 1m23s",
+		"### HTTP 404 Not Found (2)",
+		"| https://example.com/gone | HTTP 404 404 Not Found | https://example.com/ (+2 more) |",
+		"| https://example.com/missing [de] |",
+		"### HTTP 503 Service Unavailable (1)",
+		"### timeout (1)",
+		`request failed: timeout \| retry`,
+	} {
+		if !strings.Contains(summary, expected) {
+			t.Errorf("Expected summary to contain %q, got:\n%s", expected, summary)
+		}
+	}
+	if strings.Contains(summary, "Blocked by bot protection") {
+		t.Error("Expected empty totals to be left out")
+	}
+	if strings.Index(summary, "### HTTP 404") > strings.Index(summary, "### HTTP 503") {
+		t.Error("Expected groups in order")
+	}
+}
+
+func TestWriteMarkdownSummaryLimitsRows(t *testing.T) {
+	broken := make([]LinkResult, maxSummaryRows+5)
+	for i := range broken {
+		broken[i] = LinkResult{URL: fmt.Sprintf("https://example.com/%d", i), StatusCode: 404}
+	}
+
+	var buf bytes.Buffer
+	if err := WriteMarkdownSummary(&buf, Summary{Broken: broken}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !strings.Contains(buf.String(), "…and 5 more") {
+		t.Errorf("Expected the group to be cut short, got:\n%s", buf.String())
+	}
+	if strings.Contains(buf.String(), fmt.Sprintf("https://example.com/%d |", maxSummaryRows)) {
+		t.Error("Expected rows past the limit to be left out")
+	}
+}
+
+func TestWriteMarkdownSummaryNoBrokenLinks(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteMarkdownSummary(&buf, Summary{Checked: 3}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !strings.Contains(buf.String(), "✅ No broken links found.") || strings.Contains(buf.String(), "###") {
+		t.Errorf("Unexpected summary:\n%s", buf.String())
+	}
+}
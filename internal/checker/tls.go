@@ -0,0 +1,40 @@
+package checker
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"os"
+)
+
+// tlsTransport returns a copy of the default transport that uses tlsConfig
+func tlsTransport(tlsConfig *tls.Config) *http.Transport {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = tlsConfig
+	return transport
+}
+
+// LoadCACert trusts the certificates in a PEM file, such as a private CA's,
+// in addition to the system's, so internal sites using them can be checked
+func (c *Checker) LoadCACert(path string) error {
+	data, err := os.ReadFile(path) // #nosec G304 -- path is provided by the user
+	if err != nil {
+		return fmt.Errorf("reading CA certificate: %w", err)
+	}
+	pool, err := x509.SystemCertPool()
+	if err != nil {
+		pool = x509.NewCertPool()
+	}
+	if !pool.AppendCertsFromPEM(data) {
+		return fmt.Errorf("no PEM certificates found in %s", path)
+	}
+
+	transport, ok := c.client.Transport.(*http.Transport)
+	if !ok {
+		transport = tlsTransport(&tls.Config{MinVersion: tls.VersionTLS12})
+		c.client.Transport = transport
+	}
+	transport.TLSClientConfig.RootCAs = pool
+	return nil
+}
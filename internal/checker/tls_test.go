@@ -0,0 +1,51 @@
+package checker
+
+import (
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/joshbeard/link-validator/internal/config"
+)
+
+func TestTLSVerification(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	newChecker := func(insecure bool) *Checker {
+		return New(&config.Config{UserAgent: "TestBot/1.0", Timeout: 5 * time.Second, MaxConcurrent: 1, InsecureSkipVerify: insecure})
+	}
+
+	if result := newChecker(false).checkSingleLink(server.URL); result.ErrorType != ErrorTypeTLS {
+		t.Errorf("Expected an untrusted certificate to fail with tls, got %q", result.ErrorType)
+	}
+	if result := newChecker(true).checkSingleLink(server.URL); result.ErrorType != "" {
+		t.Errorf("Expected insecure-skip-verify to pass, got %s", result.Error)
+	}
+
+	caFile := filepath.Join(t.TempDir(), "ca.pem")
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+	if err := os.WriteFile(caFile, certPEM, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	checker := newChecker(false)
+	if err := checker.LoadCACert(caFile); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if result := checker.checkSingleLink(server.URL); result.ErrorType != "" {
+		t.Errorf("Expected the trusted CA to pass, got %s", result.Error)
+	}
+
+	if err := os.WriteFile(caFile, []byte("not a certificate"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := newChecker(false).LoadCACert(caFile); err == nil {
+		t.Error("Expected an error for a file without certificates")
+	}
+}
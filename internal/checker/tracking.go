@@ -0,0 +1,51 @@
+package checker
+
+import (
+	"net/url"
+	"sort"
+	"strings"
+)
+
+// auditTrackingParams flags internal links carrying tracking parameters such
+// as utm_source. Tracking belongs on inbound campaign links; on internal
+// navigation it skews analytics and splits caches across URL variants.
+func (c *Checker) auditTrackingParams(pageURL *url.URL, link string) {
+	u, err := url.Parse(link)
+	if err != nil || u.RawQuery == "" {
+		return
+	}
+	if !strings.EqualFold(u.Host, pageURL.Host) && !c.isInternal(link) {
+		return
+	}
+
+	var found []string
+	for param := range u.Query() {
+		if matchesParam(param, c.config.TrackingParams) {
+			found = append(found, param)
+		}
+	}
+	if len(found) == 0 {
+		return
+	}
+	sort.Strings(found)
+
+	c.recordIssue(PageIssue{Page: pageURL.String(), Type: IssueTrackingParams, Link: link,
+		Detail: "internal link carries tracking parameters: " + strings.Join(found, ", ")})
+}
+
+// matchesParam reports whether a query parameter matches one of the
+// configured names. A trailing "*" matches any suffix, e.g. "utm_*".
+func matchesParam(param string, patterns []string) bool {
+	param = strings.ToLower(param)
+	for _, pattern := range patterns {
+		pattern = strings.ToLower(pattern)
+		if prefix, wildcard := strings.CutSuffix(pattern, "*"); wildcard {
+			if strings.HasPrefix(param, prefix) {
+				return true
+			}
+		} else if param == pattern {
+			return true
+		}
+	}
+	return false
+}
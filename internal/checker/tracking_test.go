@@ -0,0 +1,68 @@
+package checker
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/joshbeard/link-validator/internal/config"
+	"golang.org/x/net/html"
+)
+
+func TestMatchesParam(t *testing.T) {
+	patterns := config.ParseList(config.DefaultTrackingParams)
+	testCases := []struct {
+		param    string
+		expected bool
+	}{
+		{"utm_source", true},
+		{"UTM_Campaign", true},
+		{"fbclid", true},
+		{"gclid", true},
+		{"page", false},
+		{"utm", false},
+		{"q", false},
+	}
+
+	for _, tc := range testCases {
+		if got := matchesParam(tc.param, patterns); got != tc.expected {
+			t.Errorf("matchesParam(%q): expected %v, got %v", tc.param, tc.expected, got)
+		}
+	}
+}
+
+func TestAuditTrackingParams(t *testing.T) {
+	markup := `<html><body>
+<a href="/pricing/?utm_source=nav&utm_medium=header&plan=pro">Pricing</a>
+<a href="https://www.mysite.com/blog/?fbclid=abc">Blog</a>
+<a href="/search/?q=links">Search</a>
+<a href="https://partner.org/?utm_source=mysite">Partner</a>
+</body></html>`
+	doc, err := html.Parse(strings.NewReader(markup))
+	if err != nil {
+		t.Fatal(err)
+	}
+	page, _ := url.Parse("https://www.mysite.com/")
+
+	checker := New(&config.Config{
+		MaxConcurrent:      1,
+		BaseURL:            "https://www.mysite.com",
+		TrackingParamAudit: true,
+		TrackingParams:     config.ParseList(config.DefaultTrackingParams),
+	})
+	checker.auditPage(page.String(), doc, page)
+
+	issues := checker.Issues()
+	if len(issues) != 2 {
+		t.Fatalf("Expected 2 issues for internal links only, got %+v", issues)
+	}
+	if issues[0].Detail != "internal link carries tracking parameters: utm_medium, utm_source" {
+		t.Errorf("Unexpected detail: %s", issues[0].Detail)
+	}
+	if issues[1].Link != "https://www.mysite.com/blog/?fbclid=abc" {
+		t.Errorf("Unexpected link: %s", issues[1].Link)
+	}
+	if CountIssues(issues, IssueTrackingParams) != 2 {
+		t.Errorf("Expected both issues to be tracking_params, got %+v", issues)
+	}
+}
@@ -0,0 +1,299 @@
+package checker
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"iter"
+	"os"
+	"sync"
+)
+
+// spillList is an append-only list of strings. Up to limit strings are held
+// in memory; past that, they all move to a temporary file, one JSON string
+// per line, and only the offset of each line stays in memory so any string
+// can still be read back by its index. A limit of 0 or less keeps every
+// string in memory.
+type spillList struct {
+	limit   int
+	items   []string
+	offsets []int64
+	size    int64
+	file    *os.File
+	writer  *bufio.Writer
+}
+
+// add appends a string to the list, spilling every string held in memory to
+// the list's file once there are more than its limit
+func (l *spillList) add(s string) error {
+	if l.file == nil {
+		l.items = append(l.items, s)
+		if l.limit <= 0 || len(l.items) <= l.limit {
+			return nil
+		}
+		return l.spill()
+	}
+	return l.write(s)
+}
+
+// spill moves the strings held in memory to a new temporary file, which
+// every later string is appended to
+func (l *spillList) spill() error {
+	file, err := os.CreateTemp("", "link-checker-urls-*.jsonl")
+	if err != nil {
+		return fmt.Errorf("creating URL list file: %w", err)
+	}
+	l.file, l.writer = file, bufio.NewWriter(file)
+	l.offsets = make([]int64, 0, len(l.items))
+	for _, s := range l.items {
+		if err := l.write(s); err != nil {
+			return err
+		}
+	}
+	l.items = nil
+	return nil
+}
+
+// write appends a string to the list's file as a line of JSON
+func (l *spillList) write(s string) error {
+	data, err := json.Marshal(s)
+	if err != nil {
+		return fmt.Errorf("encoding URL list: %w", err)
+	}
+	if _, err := l.writer.Write(append(data, '\n')); err != nil {
+		return fmt.Errorf("writing URL list: %w", err)
+	}
+	l.offsets = append(l.offsets, l.size)
+	l.size += int64(len(data) + 1)
+	return nil
+}
+
+// len returns how many strings the list holds
+func (l *spillList) len() int {
+	if l.file == nil {
+		return len(l.items)
+	}
+	return len(l.offsets)
+}
+
+// get returns the string at index i, reading it back from the list's file
+// if it spilled
+func (l *spillList) get(i int) (string, error) {
+	if l.file == nil {
+		return l.items[i], nil
+	}
+	if err := l.writer.Flush(); err != nil {
+		return "", fmt.Errorf("writing URL list: %w", err)
+	}
+	end := l.size
+	if i+1 < len(l.offsets) {
+		end = l.offsets[i+1]
+	}
+	line := make([]byte, end-l.offsets[i])
+	if _, err := l.file.ReadAt(line, l.offsets[i]); err != nil {
+		return "", fmt.Errorf("reading URL list: %w", err)
+	}
+	var s string
+	if err := json.Unmarshal(line, &s); err != nil {
+		return "", fmt.Errorf("decoding URL list: %w", err)
+	}
+	return s, nil
+}
+
+// all calls yield with every string in the order it was added, until yield
+// returns false. Strings that spilled are read back from the list's file.
+func (l *spillList) all(yield func(string) bool) error {
+	if l.file == nil {
+		for _, s := range l.items {
+			if !yield(s) {
+				return nil
+			}
+		}
+		return nil
+	}
+
+	if err := l.writer.Flush(); err != nil {
+		return fmt.Errorf("writing URL list: %w", err)
+	}
+	file, err := os.Open(l.file.Name())
+	if err != nil {
+		return fmt.Errorf("opening URL list: %w", err)
+	}
+	defer file.Close()
+	reader := bufio.NewReader(file)
+	for range len(l.offsets) {
+		line, err := reader.ReadBytes('\n')
+		if err != nil {
+			return fmt.Errorf("reading URL list: %w", err)
+		}
+		var s string
+		if err := json.Unmarshal(line, &s); err != nil {
+			return fmt.Errorf("decoding URL list: %w", err)
+		}
+		if !yield(s) {
+			return nil
+		}
+	}
+	return nil
+}
+
+// close removes the list's file, if strings ever spilled to one
+func (l *spillList) close() {
+	if l.file == nil {
+		return
+	}
+	l.file.Close()
+	os.Remove(l.file.Name())
+	l.file = nil
+}
+
+// URLList is the list of distinct URLs a run checks, in the order they were
+// found. Up to limit URLs are held in memory; past that, they all move to a
+// temporary file and are read back in order by All, so a crawl of a huge
+// site doesn't have to hold every URL it found. URLs already in the list are
+// remembered by a 64-bit hash, as the crawl frontier does, and adding one
+// again does nothing.
+type URLList struct {
+	list spillList
+	seen urlSet
+	err  error
+}
+
+// NewURLList returns an empty list holding up to limit URLs in memory, or
+// every URL for a limit of 0 or less
+func NewURLList(limit int) *URLList {
+	return &URLList{list: spillList{limit: limit}, seen: make(urlSet)}
+}
+
+// Add appends the URLs not already in the list
+func (l *URLList) Add(urls ...string) error {
+	for _, u := range urls {
+		if l.seen.has(u) {
+			continue
+		}
+		if err := l.list.add(u); err != nil {
+			return err
+		}
+		l.seen.add(u)
+	}
+	return nil
+}
+
+// Has reports whether a URL is in the list
+func (l *URLList) Has(u string) bool {
+	return l.seen.has(u)
+}
+
+// Len returns how many URLs the list holds
+func (l *URLList) Len() int {
+	return l.list.len()
+}
+
+// All returns every URL in the order it was added. URLs that spilled are
+// read back from the list's file each time the sequence is ranged over; a
+// failure to read them ends the sequence early and is returned by Err.
+func (l *URLList) All() iter.Seq[string] {
+	return func(yield func(string) bool) {
+		if err := l.list.all(yield); err != nil && l.err == nil {
+			l.err = err
+		}
+	}
+}
+
+// Err returns the first error reading back URLs that spilled, if any
+func (l *URLList) Err() error {
+	return l.err
+}
+
+// Close removes the list's file, if URLs ever spilled to one
+func (l *URLList) Close() {
+	l.list.close()
+}
+
+// urlTable numbers the URLs the checker records sources and the inventory
+// for, so that those records hold 4-byte IDs rather than URLs. The URLs are
+// kept in a spillList, and found again by a 64-bit hash of each; two URLs
+// sharing a hash would share an ID, which is as unlikely as it is for the
+// crawl's set of queued URLs.
+type urlTable struct {
+	mu   sync.Mutex
+	ids  map[uint64]uint32
+	urls spillList
+	err  error
+}
+
+// newURLTable returns an empty table holding up to limit URLs in memory
+func newURLTable(limit int) *urlTable {
+	return &urlTable{ids: make(map[uint64]uint32), urls: spillList{limit: limit}}
+}
+
+// id returns the ID of a URL, adding it to the table if it is new. It
+// reports false when the URL couldn't be added.
+func (t *urlTable) id(u string) (uint32, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	hash := hashURL(u)
+	if id, ok := t.ids[hash]; ok {
+		return id, true
+	}
+	id := uint32(t.urls.len()) // #nosec G115 -- a run holds far fewer than 2^32 URLs
+	if err := t.urls.add(u); err != nil {
+		t.fail(err)
+		return 0, false
+	}
+	t.ids[hash] = id
+	return id, true
+}
+
+// lookup returns the ID of a URL already in the table
+func (t *urlTable) lookup(u string) (uint32, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	id, ok := t.ids[hashURL(u)]
+	return id, ok
+}
+
+// url returns the URL with the given ID, or "" when it can't be read back
+func (t *urlTable) url(id uint32) string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	u, err := t.urls.get(int(id))
+	if err != nil {
+		t.fail(err)
+	}
+	return u
+}
+
+// urlsOf returns the URLs with the given IDs, in the same order
+func (t *urlTable) urlsOf(ids []uint32) []string {
+	if len(ids) == 0 {
+		return nil
+	}
+	urls := make([]string, 0, len(ids))
+	for _, id := range ids {
+		urls = append(urls, t.url(id))
+	}
+	return urls
+}
+
+// firstErr returns the first error keeping the table's URLs, if any
+func (t *urlTable) firstErr() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.err
+}
+
+// fail records the first error keeping the table's URLs
+func (t *urlTable) fail(err error) {
+	if t.err == nil {
+		t.err = err
+	}
+}
+
+// close removes the table's file, if URLs ever spilled to one
+func (t *urlTable) close() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.urls.close()
+}
@@ -0,0 +1,144 @@
+package checker
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"reflect"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/joshbeard/link-validator/internal/config"
+)
+
+func TestURLList(t *testing.T) {
+	list := NewURLList(4)
+	defer list.Close()
+
+	var expected []string
+	for i := range 10 {
+		u := fmt.Sprintf("https://example.com/%d", i)
+		expected = append(expected, u)
+		if err := list.Add(u, u); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+	}
+	if list.Len() != 10 || !list.Has("https://example.com/3") || list.Has("https://example.com/10") {
+		t.Fatalf("Expected the 10 distinct URLs added, got %d", list.Len())
+	}
+	if list.list.file == nil || list.list.items != nil {
+		t.Fatal("Expected the URLs to have spilled to a file")
+	}
+
+	// The list reads back the same way each time it is ranged over
+	for range 2 {
+		if got := slices.Collect(list.All()); !reflect.DeepEqual(got, expected) {
+			t.Errorf("Expected %v, got %v", expected, got)
+		}
+	}
+	for u := range list.All() {
+		if u != expected[0] {
+			t.Errorf("Expected %s first, got %s", expected[0], u)
+		}
+		break
+	}
+	if err := list.Err(); err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+
+	name := list.list.file.Name()
+	list.Close()
+	if _, err := os.Stat(name); !os.IsNotExist(err) {
+		t.Errorf("Expected the list file to be removed, got %v", err)
+	}
+
+	unlimited := NewURLList(0)
+	for i := range 100 {
+		unlimited.Add(fmt.Sprint(i))
+	}
+	if unlimited.list.file != nil || len(unlimited.list.items) != 100 {
+		t.Errorf("Expected every URL in memory without a limit, got %d", len(unlimited.list.items))
+	}
+}
+
+func TestURLTable(t *testing.T) {
+	table := newURLTable(2)
+	defer table.close()
+
+	for i := range 5 {
+		id, ok := table.id(fmt.Sprintf("https://example.com/%d", i))
+		if !ok || id != uint32(i) {
+			t.Fatalf("Expected ID %d, got %d, %v", i, id, ok)
+		}
+	}
+	if id, ok := table.id("https://example.com/1"); !ok || id != 1 {
+		t.Errorf("Expected a known URL to keep its ID, got %d, %v", id, ok)
+	}
+	if _, ok := table.lookup("https://example.com/5"); ok {
+		t.Error("Expected lookup not to add a URL")
+	}
+	if table.urls.file == nil {
+		t.Fatal("Expected the URLs to have spilled to a file")
+	}
+	for i := range 5 {
+		if got, expected := table.url(uint32(i)), fmt.Sprintf("https://example.com/%d", i); got != expected {
+			t.Errorf("Expected %s for ID %d, got %s", expected, i, got)
+		}
+	}
+	if err := table.firstErr(); err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+}
+
+func TestCrawlSpillsURLs(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		if r.URL.Path == "/" {
+			for i := range 20 {
+				fmt.Fprintf(w, `<a href="/page/%d">Page</a>`, i)
+			}
+			fmt.Fprint(w, `<img src="/logo.png">`)
+			return
+		}
+		fmt.Fprint(w, `<a href="/">Home</a>`)
+	}))
+	defer server.Close()
+
+	crawl := func(spill int) ([]string, []InventoryEntry, []string, LinkCategory) {
+		checker := New(&config.Config{
+			UserAgent:     "TestBot/1.0",
+			Timeout:       5 * time.Second,
+			MaxConcurrent: 1,
+			FrontierSpill: spill,
+			CheckElements: []string{"a", "img"},
+		})
+		defer checker.Close()
+		list := NewURLList(spill)
+		defer list.Close()
+		if err := checker.CrawlWebsiteWithSeedsTo(list, server.URL+"/", nil, 2); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		logo := server.URL + "/logo.png"
+		return slices.Collect(list.All()), slices.Collect(checker.Inventory()), checker.Sources(server.URL + "/page/7"), checker.linkCategory(logo, checker.elementFor(logo))
+	}
+
+	urls, inventory, sources, category := crawl(0)
+	if len(urls) != 22 || len(inventory) != 21 {
+		t.Fatalf("Expected the home page, 20 pages and the logo, got %d URLs and %d inventory entries", len(urls), len(inventory))
+	}
+	spilledURLs, spilledInventory, spilledSources, spilledCategory := crawl(3)
+	if category != CategoryAsset || spilledCategory != CategoryAsset {
+		t.Errorf("Expected the logo to be an asset, got %s and %s", category, spilledCategory)
+	}
+	if !reflect.DeepEqual(spilledURLs, urls) {
+		t.Errorf("Expected spilled URLs to read back in the same order, got %v, expected %v", spilledURLs, urls)
+	}
+	if !reflect.DeepEqual(spilledInventory, inventory) {
+		t.Errorf("Expected the same inventory when spilled, got %v, expected %v", spilledInventory, inventory)
+	}
+	if !reflect.DeepEqual(spilledSources, sources) || len(sources) != 1 {
+		t.Errorf("Expected the home page as the only source, got %v and %v", spilledSources, sources)
+	}
+}
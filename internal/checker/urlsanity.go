@@ -0,0 +1,46 @@
+package checker
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+	"unicode"
+)
+
+// auditURLSanity flags hrefs that browsers may tolerate but are authoring
+// errors: unencoded whitespace or control characters, values that don't
+// parse as URLs at all (which the crawler otherwise skips without a word),
+// and URLs longer than the configured limit.
+func (c *Checker) auditURLSanity(pageURL, href, link string) {
+	// Browsers strip leading and trailing whitespace from hrefs
+	href = strings.TrimSpace(href)
+
+	if strings.IndexFunc(href, unicode.IsControl) >= 0 {
+		c.recordIssue(PageIssue{Page: pageURL, Type: IssueMalformedURL, Link: href,
+			Detail: "link contains control characters"})
+		return
+	}
+	if _, err := url.Parse(href); err != nil {
+		c.recordIssue(PageIssue{Page: pageURL, Type: IssueMalformedURL, Link: href,
+			Detail: "link is not a valid URL: " + unwrapURLError(err)})
+		return
+	}
+	if strings.ContainsFunc(href, unicode.IsSpace) {
+		c.recordIssue(PageIssue{Page: pageURL, Type: IssueMalformedURL, Link: href,
+			Detail: "link contains unencoded whitespace"})
+		return
+	}
+
+	if limit := c.config.MaxURLLength; limit > 0 && len(link) > limit {
+		c.recordIssue(PageIssue{Page: pageURL, Type: IssueLongURL, Link: link,
+			Detail: fmt.Sprintf("link is %d characters long (limit %d)", len(link), limit)})
+	}
+}
+
+// unwrapURLError drops the operation and URL that url.Error repeats
+func unwrapURLError(err error) string {
+	if urlErr, ok := err.(*url.Error); ok {
+		return urlErr.Err.Error()
+	}
+	return err.Error()
+}
@@ -0,0 +1,63 @@
+package checker
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/joshbeard/link-validator/internal/config"
+	"golang.org/x/net/html"
+)
+
+func TestAuditURLSanity(t *testing.T) {
+	testCases := []struct {
+		name     string
+		href     string
+		expected IssueType
+	}{
+		{"plain", "/docs/getting-started/", ""},
+		{"encoded space", "/docs/my%20file.pdf", ""},
+		{"surrounding whitespace", "  /docs/  ", ""},
+		{"unencoded space", "/docs/my file.pdf", IssueMalformedURL},
+		{"tab in query", "/search?q=a\tb", IssueMalformedURL},
+		{"control character", "/docs/\x01", IssueMalformedURL},
+		{"bad escape", "/docs/100%", IssueMalformedURL},
+		{"bad host", "http://exa mple.com/", IssueMalformedURL},
+		{"long", "/search?q=" + strings.Repeat("a", 100), IssueLongURL},
+	}
+
+	base, _ := url.Parse("https://example.com/")
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			checker := New(&config.Config{MaxConcurrent: 1, URLSanityAudit: true, MaxURLLength: 100})
+			checker.auditURLSanity(base.String(), tc.href, resolveReference(base, tc.href))
+
+			issues := checker.Issues()
+			if tc.expected == "" {
+				if len(issues) != 0 {
+					t.Errorf("Expected no issues, got %+v", issues)
+				}
+				return
+			}
+			if len(issues) != 1 || issues[0].Type != tc.expected {
+				t.Errorf("Expected one %q issue, got %+v", tc.expected, issues)
+			}
+		})
+	}
+}
+
+func TestAuditURLSanityDuringParse(t *testing.T) {
+	doc, err := html.Parse(strings.NewReader(`<a href="/my page/">Page</a><a href="/ok/">OK</a>`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	base, _ := url.Parse("https://example.com/")
+
+	checker := New(&config.Config{MaxConcurrent: 1, URLSanityAudit: true})
+	checker.auditPage(base.String(), doc, base)
+
+	issues := checker.Issues()
+	if len(issues) != 1 || issues[0].Link != "/my page/" {
+		t.Errorf("Expected the raw href to be reported, got %+v", issues)
+	}
+}
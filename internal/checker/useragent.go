@@ -0,0 +1,17 @@
+package checker
+
+// userAgentFor returns the user agent to send to a URL: the first matching
+// user agent rule, otherwise the next agent in the rotation, otherwise the
+// configured user agent
+func (c *Checker) userAgentFor(rawURL string) string {
+	for _, rule := range c.config.UserAgentRules {
+		if rule.Pattern.MatchString(rawURL) {
+			return rule.UserAgent
+		}
+	}
+	if agents := c.config.UserAgents; len(agents) > 0 {
+		next := c.agentIndex.Add(1) - 1
+		return agents[next%uint64(len(agents))]
+	}
+	return c.config.UserAgent
+}
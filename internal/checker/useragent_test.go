@@ -0,0 +1,58 @@
+package checker
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/joshbeard/link-validator/internal/config"
+)
+
+func TestUserAgentFor(t *testing.T) {
+	checker := New(&config.Config{
+		MaxConcurrent:  1,
+		UserAgent:      "TestBot/1.0",
+		UserAgents:     []string{"agent-a", "agent-b"},
+		UserAgentRules: config.ParseUserAgentRules(`twitter\.com=chrome`),
+	})
+
+	if got := checker.userAgentFor("https://twitter.com/someone"); got != config.UserAgentPresets["chrome"] {
+		t.Errorf("Expected the rule's user agent, got %q", got)
+	}
+	var rotated []string
+	for i := 0; i < 3; i++ {
+		rotated = append(rotated, checker.userAgentFor("https://example.com/"))
+	}
+	if rotated[0] != "agent-a" || rotated[1] != "agent-b" || rotated[2] != "agent-a" {
+		t.Errorf("Expected the agents to rotate, got %v", rotated)
+	}
+
+	checker = New(&config.Config{MaxConcurrent: 1, UserAgent: "TestBot/1.0"})
+	if got := checker.userAgentFor("https://example.com/"); got != "TestBot/1.0" {
+		t.Errorf("Expected the configured user agent, got %q", got)
+	}
+}
+
+func TestCheckLinksSendsRuleUserAgent(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.UserAgent() != config.UserAgentPresets["firefox"] {
+			w.WriteHeader(http.StatusForbidden)
+		}
+	}))
+	defer server.Close()
+
+	checker := New(&config.Config{
+		UserAgent:      "TestBot/1.0",
+		Timeout:        5 * time.Second,
+		MaxConcurrent:  1,
+		UserAgentRules: config.ParseUserAgentRules("/browser-only=firefox"),
+	})
+	results := checker.CheckLinks([]string{server.URL + "/browser-only", server.URL + "/other"})
+	if results[0].StatusCode != http.StatusOK {
+		t.Errorf("Expected the rule's user agent to be accepted, got %d", results[0].StatusCode)
+	}
+	if results[1].StatusCode != http.StatusForbidden {
+		t.Errorf("Expected the default user agent elsewhere, got %d", results[1].StatusCode)
+	}
+}
@@ -1,6 +1,9 @@
 package config
 
 import (
+	"fmt"
+	"net/http"
+	"net/url"
 	"os"
 	"regexp"
 	"strconv"
@@ -8,30 +11,181 @@ import (
 	"time"
 )
 
+// TimeoutOverride applies a custom request timeout to URLs matching Pattern
+type TimeoutOverride struct {
+	Pattern *regexp.Regexp
+	Timeout time.Duration
+}
+
+// PathRule rewrites paths matching Pattern, mapping repository files to site
+// paths or site paths back to files. Replacement may reference capture
+// groups, e.g. "/$1/".
+type PathRule struct {
+	Pattern     *regexp.Regexp
+	Replacement string
+}
+
+// DefaultLoginPatterns match common login and single sign-on pages. A link
+// that redirects to one of them is reported as requiring authentication.
+const DefaultLoginPatterns = `/login,/signin,/sign-in,/sso/,/oauth2?/authorize,accounts\.google\.com,login\.microsoftonline\.com`
+
+// DefaultTrackingParams are the query parameters added by common analytics
+// and ad platforms. A trailing "*" matches any suffix.
+const DefaultTrackingParams = `utm_*,fbclid,gclid,dclid,gbraid,wbraid,msclkid,mc_cid,mc_eid,yclid,_ga,_gl`
+
+// DefaultGetFallbackStatus are the status codes servers answer HEAD requests
+// with when they don't support them, though GET works
+const DefaultGetFallbackStatus = "403,405,501"
+
+// DefaultRetryBudget is how many retries a run makes in total before links
+// are no longer retried
+const DefaultRetryBudget = 200
+
+// DefaultExternalConcurrency is how many requests to other hosts are made at
+// once when external links are checked
+const DefaultExternalConcurrency = 5
+
+// DefaultFrontierSpill is how many pages waiting to be crawled are held in
+// memory before the rest spill to a temporary file
+const DefaultFrontierSpill = 100000
+
+// DefaultResultSpill is how many results are held in memory before they all
+// spill to a temporary file
+const DefaultResultSpill = 100000
+
 // Config holds all configuration for the link checker
 type Config struct {
-	SitemapURL      string
-	BaseURL         string
-	MaxDepth        int
-	Timeout         time.Duration
-	UserAgent       string
-	ExcludePatterns []*regexp.Regexp
-	FailOnError     bool
-	MaxConcurrent   int
-	Verbose         bool
-}
-
-// FromEnvironment creates a Config from GitHub Action environment variables
-func FromEnvironment() *Config {
+	SitemapURL           string
+	BaseURL              string
+	MaxDepth             int
+	Timeout              time.Duration
+	UserAgent            string
+	ExcludePatterns      []*regexp.Regexp
+	FailOnError          bool
+	MaxConcurrent        int
+	Verbose              bool
+	MaxRetries           int
+	RetryBudget          int
+	TimeoutOverrides     []TimeoutOverride
+	FailOnCategories     []string
+	StatusExceptions     map[string][]int
+	NewsMaxAge           time.Duration
+	SampleSize           int
+	SamplePercent        float64
+	SampleSeed           int64
+	SitemapFallback      bool
+	ProbeSitemap         bool
+	SeedsFile            string
+	ImportURLs           string
+	InventoryFile        string
+	LoginPatterns        []*regexp.Regexp
+	CacheFile            string
+	SkipUnchanged        bool
+	ChangedFiles         bool
+	PathRules            []PathRule
+	GitHubToken          string
+	PRNumber             int
+	FileRules            []PathRule
+	SiteConfig           string
+	PreviewURL           string
+	LinkTextAudit        bool
+	DuplicateIDAudit     bool
+	PlaceholderLinkAudit bool
+	TrackingParamAudit   bool
+	TrackingParams       []string
+	FailOnTrackingParams bool
+	URLSanityAudit       bool
+	MaxURLLength         int
+	RedirectMap          string
+	FixPR                bool
+	IgnoreFile           string
+	Format               string
+	OutputFile           string
+	Repeat               int
+	RepairURLs           bool
+	UserAgents           []string
+	UserAgentRules       []UserAgentRule
+	AcceptLanguage       string
+	AcceptLanguageRules  []AcceptLanguageRule
+	CheckLocales         []string
+	ReportFile           string
+	CacheDir             string
+	CacheMaxEntries      int
+	MaxMemoryMB          int
+	FrontierSpill        int
+	ResultSpill          int
+	LoginURL             string
+	LoginFields          url.Values
+	LoginSuccessSelector string
+	JSONURLs             []string
+	JSONPaths            []string
+	SeverityRules        []SeverityRule
+	CheckOrder           string
+	SchemePolicy         map[string]string
+	DecisionHook         string
+	PreferHTTPS          bool
+	CheckExternal        bool
+	ExternalConcurrency  int
+	RespectRobots        bool
+	CheckElements        []string
+	SitePath             string
+	CacheTTL             time.Duration
+	StopAtRedirects      bool
+	MaxRedirects         int
+	WarnOnRedirect       bool
+	AcceptStatus         []int
+	Files                []string
+	Headers              http.Header
+	BasicAuth            string
+	PRComment            bool
+	InsecureSkipVerify   bool
+	CACert               string
+	IncludePatterns      []*regexp.Regexp
+	MaxPages             int
+	Baseline             string
+	WriteBaseline        string
+	URLsFile             string
+	SlowThreshold        time.Duration
+	FailOnSlow           bool
+	Soft404Patterns      []*regexp.Regexp
+	DiscoverSitemap      bool
+	FailOn               *FailPolicy
+	Cookies              []*http.Cookie
+	MaxRedirectChain     int
+	ExcludeSelectors     []string
+	BaseURLs             []string
+	SitemapURLs          []string
+	NormalizeURLs        bool
+	IgnoreTrackingParams bool
+	GetFallbackStatus    []int
+	MetricsFile          string
+	PushgatewayURL       string
+	MetricsJob           string
+	WebhookURL           string
+	WebhookTemplate      string
+	WriteSitemap         string
+	MaxBodyMB            int
+	MailtoAudit          bool
+	MailtoMXLookup       bool
+	IgnoreQueryParams    bool
+	KeepParams           []string
+}
+
+// FromEnvironment creates a Config from GitHub Action environment variables.
+// It returns an error for a malformed value that would otherwise quietly turn
+// an optional setting off.
+func FromEnvironment() (*Config, error) {
 	cfg := &Config{
 		SitemapURL:    getEnv("INPUT_SITEMAP_URL", ""),
 		BaseURL:       getEnv("INPUT_BASE_URL", ""),
 		MaxDepth:      getEnvInt("INPUT_MAX_DEPTH", 3),
 		Timeout:       time.Duration(getEnvInt("INPUT_TIMEOUT", 30)) * time.Second,
-		UserAgent:     getEnv("INPUT_USER_AGENT", "GitHub-Action-Link-Checker/1.0"),
+		UserAgent:     ResolveUserAgent(getEnv("INPUT_USER_AGENT", "GitHub-Action-Link-Checker/1.0")),
 		FailOnError:   getEnvBool("INPUT_FAIL_ON_ERROR", true),
 		MaxConcurrent: getEnvInt("INPUT_MAX_CONCURRENT", 10),
 		Verbose:       getEnvBool("INPUT_VERBOSE", false),
+		MaxRetries:    getEnvInt("INPUT_MAX_RETRIES", 0),
+		RetryBudget:   getEnvInt("INPUT_RETRY_BUDGET", DefaultRetryBudget),
 	}
 
 	// Parse exclude patterns
@@ -48,7 +202,286 @@ func FromEnvironment() *Config {
 		}
 	}
 
-	return cfg
+	cfg.TimeoutOverrides = ParseTimeoutOverrides(getEnv("INPUT_TIMEOUT_OVERRIDES", ""))
+	cfg.FailOnCategories = ParseList(getEnv("INPUT_FAIL_ON_CATEGORIES", ""))
+	cfg.StatusExceptions = ParseStatusExceptions(getEnv("INPUT_STATUS_EXCEPTIONS", ""))
+	var err error
+	if cfg.NewsMaxAge, err = getEnvDuration("INPUT_NEWS_MAX_AGE"); err != nil {
+		return nil, err
+	}
+	cfg.SampleSize = getEnvInt("INPUT_SAMPLE", 0)
+	if cfg.SamplePercent, err = getEnvPercent("INPUT_SAMPLE_PERCENT"); err != nil {
+		return nil, err
+	}
+	cfg.SampleSeed = int64(getEnvInt("INPUT_SAMPLE_SEED", 0))
+	cfg.SitemapFallback = getEnvBool("INPUT_SITEMAP_FALLBACK_CRAWL", true)
+	cfg.ProbeSitemap = getEnvBool("INPUT_PROBE_SITEMAP", false)
+	cfg.SeedsFile = getEnv("INPUT_SEEDS_FILE", "")
+	cfg.ImportURLs = getEnv("INPUT_IMPORT_URLS", "")
+	cfg.InventoryFile = getEnv("INPUT_INVENTORY_FILE", "")
+	cfg.LoginPatterns = ParsePatterns(getEnv("INPUT_LOGIN_PATTERNS", DefaultLoginPatterns))
+	cfg.CacheFile = getEnv("INPUT_CACHE_FILE", "")
+	cfg.SkipUnchanged = getEnvBool("INPUT_SKIP_UNCHANGED", false)
+	cfg.ChangedFiles = getEnvBool("INPUT_CHANGED_FILES_ONLY", false)
+	cfg.PathRules = ParsePathRules(getEnv("INPUT_PATH_RULES", ""))
+	cfg.GitHubToken = getEnv("INPUT_GITHUB_TOKEN", os.Getenv("GITHUB_TOKEN"))
+	cfg.PRNumber = getEnvInt("INPUT_PR_NUMBER", 0)
+	cfg.FileRules = ParsePathRules(getEnv("INPUT_FILE_RULES", ""))
+	cfg.SiteConfig = getEnv("INPUT_SITE_CONFIG", "")
+	cfg.PreviewURL = getEnv("INPUT_PREVIEW_URL", "")
+	cfg.LinkTextAudit = getEnvBool("INPUT_LINK_TEXT_AUDIT", false)
+	cfg.DuplicateIDAudit = getEnvBool("INPUT_DUPLICATE_ID_AUDIT", true)
+	cfg.PlaceholderLinkAudit = getEnvBool("INPUT_PLACEHOLDER_LINK_AUDIT", true)
+	cfg.TrackingParamAudit = getEnvBool("INPUT_TRACKING_PARAM_AUDIT", false)
+	cfg.TrackingParams = ParseList(getEnv("INPUT_TRACKING_PARAMS", DefaultTrackingParams))
+	cfg.FailOnTrackingParams = getEnvBool("INPUT_FAIL_ON_TRACKING_PARAMS", false)
+	cfg.URLSanityAudit = getEnvBool("INPUT_URL_SANITY_AUDIT", true)
+	cfg.MaxURLLength = getEnvInt("INPUT_MAX_URL_LENGTH", 2048)
+	cfg.RedirectMap = getEnv("INPUT_REDIRECT_MAP", "")
+	cfg.FixPR = getEnvBool("INPUT_FIX_PR", false)
+	cfg.IgnoreFile = getEnv("INPUT_IGNORE_FILE", DefaultIgnoreFile)
+	cfg.Format = getEnv("INPUT_FORMAT", "text")
+	cfg.OutputFile = getEnv("INPUT_OUTPUT_FILE", "")
+	cfg.Repeat = getEnvInt("INPUT_REPEAT", 1)
+	cfg.RepairURLs = getEnvBool("INPUT_REPAIR_URLS", false)
+	cfg.UserAgents = ParseUserAgents(getEnv("INPUT_USER_AGENTS", ""))
+	cfg.UserAgentRules = ParseUserAgentRules(getEnv("INPUT_USER_AGENT_RULES", ""))
+	cfg.AcceptLanguage = getEnv("INPUT_ACCEPT_LANGUAGE", "")
+	cfg.AcceptLanguageRules = ParseAcceptLanguageRules(getEnv("INPUT_ACCEPT_LANGUAGE_RULES", ""))
+	cfg.CheckLocales = ParseList(getEnv("INPUT_CHECK_LOCALES", ""))
+	cfg.ReportFile = getEnv("INPUT_REPORT_FILE", "")
+	cfg.CacheDir = getEnv("INPUT_CACHE_DIR", "")
+	cfg.CacheMaxEntries = getEnvInt("INPUT_CACHE_MAX_ENTRIES", 50000)
+	cfg.MaxMemoryMB = getEnvInt("INPUT_MAX_MEMORY_MB", 0)
+	cfg.FrontierSpill = getEnvInt("INPUT_FRONTIER_SPILL", DefaultFrontierSpill)
+	cfg.ResultSpill = getEnvInt("INPUT_RESULT_SPILL", DefaultResultSpill)
+	cfg.LoginURL = getEnv("INPUT_LOGIN_URL", "")
+	cfg.LoginFields = ParseLoginFields(getEnv("INPUT_LOGIN_FIELDS", ""))
+	cfg.LoginSuccessSelector = getEnv("INPUT_LOGIN_SUCCESS_SELECTOR", "")
+	cfg.JSONURLs = ParseList(getEnv("INPUT_JSON_URLS", ""))
+	cfg.JSONPaths = ParseList(getEnv("INPUT_JSON_PATHS", ""))
+	cfg.SeverityRules = ParseSeverityRules(getEnv("INPUT_SEVERITY_RULES", ""))
+	cfg.CheckOrder = getEnv("INPUT_CHECK_ORDER", "discovery")
+	cfg.SchemePolicy = ParseSchemePolicy(getEnv("INPUT_SCHEME_POLICY", ""))
+	cfg.DecisionHook = getEnv("INPUT_DECISION_HOOK", "")
+	cfg.PreferHTTPS = getEnvBool("INPUT_PREFER_HTTPS", false)
+	cfg.CheckExternal = getEnvBool("INPUT_CHECK_EXTERNAL", false)
+	cfg.ExternalConcurrency = getEnvInt("INPUT_EXTERNAL_MAX_CONCURRENT", DefaultExternalConcurrency)
+	cfg.RespectRobots = getEnvBool("INPUT_RESPECT_ROBOTS", false)
+	cfg.CheckElements = ParseList(getEnv("INPUT_CHECK_ELEMENTS", "a"))
+	cfg.SitePath = getEnv("INPUT_PATH", "")
+	if cfg.CacheTTL, err = getEnvDuration("INPUT_CACHE_TTL"); err != nil {
+		return nil, err
+	}
+	cfg.StopAtRedirects = !getEnvBool("INPUT_FOLLOW_REDIRECTS", true)
+	cfg.MaxRedirects = getEnvInt("INPUT_MAX_REDIRECTS", 10)
+	cfg.WarnOnRedirect = getEnvBool("INPUT_WARN_ON_REDIRECT", false)
+	cfg.AcceptStatus = ParseStatusCodes(getEnv("INPUT_ACCEPT_STATUS", ""))
+	cfg.Files = ParseList(getEnv("INPUT_FILES", ""))
+	cfg.Headers = ParseHeaders(getEnv("INPUT_HEADERS", ""))
+	cfg.BasicAuth = getEnv("INPUT_BASIC_AUTH", "")
+	cfg.PRComment = getEnvBool("INPUT_PR_COMMENT", false)
+	cfg.InsecureSkipVerify = getEnvBool("INPUT_INSECURE_SKIP_VERIFY", false)
+	cfg.CACert = getEnv("INPUT_CA_CERT", "")
+	cfg.IncludePatterns = ParsePatterns(getEnv("INPUT_INCLUDE_PATTERNS", ""))
+	cfg.MaxPages = getEnvInt("INPUT_MAX_PAGES", 0)
+	cfg.Baseline = getEnv("INPUT_BASELINE", "")
+	cfg.WriteBaseline = getEnv("INPUT_WRITE_BASELINE", "")
+	cfg.URLsFile = getEnv("INPUT_URLS_FILE", "")
+	if cfg.SlowThreshold, err = getEnvDuration("INPUT_SLOW_THRESHOLD"); err != nil {
+		return nil, err
+	}
+	cfg.FailOnSlow = getEnvBool("INPUT_FAIL_ON_SLOW", false)
+	cfg.Soft404Patterns = ParsePatterns(getEnv("INPUT_SOFT_404_PATTERNS", ""))
+	cfg.DiscoverSitemap = getEnvBool("INPUT_DISCOVER_SITEMAP", false)
+	cfg.FailOn = ParseFailPolicy(getEnv("INPUT_FAIL_ON", ""))
+	cfg.Cookies = ParseCookies(getEnv("INPUT_COOKIES", ""))
+	cfg.MaxRedirectChain = getEnvInt("INPUT_MAX_REDIRECT_CHAIN", 0)
+	cfg.ExcludeSelectors = ParseList(getEnv("INPUT_EXCLUDE_SELECTORS", ""))
+	cfg.BaseURL, cfg.BaseURLs = SplitURLs(cfg.BaseURL)
+	cfg.SitemapURL, cfg.SitemapURLs = SplitURLs(cfg.SitemapURL)
+	cfg.NormalizeURLs = getEnvBool("INPUT_NORMALIZE_URLS", true)
+	cfg.IgnoreTrackingParams = getEnvBool("INPUT_IGNORE_TRACKING_PARAMS", false)
+	cfg.GetFallbackStatus = ParseStatusCodes(getEnv("INPUT_GET_FALLBACK_STATUS", DefaultGetFallbackStatus))
+	cfg.MetricsFile = getEnv("INPUT_METRICS_FILE", "")
+	cfg.PushgatewayURL = getEnv("INPUT_PUSHGATEWAY_URL", "")
+	cfg.MetricsJob = getEnv("INPUT_METRICS_JOB", "link-checker")
+	cfg.WebhookURL = getEnv("INPUT_WEBHOOK_URL", "")
+	cfg.WebhookTemplate = getEnv("INPUT_WEBHOOK_TEMPLATE", "")
+	cfg.WriteSitemap = getEnv("INPUT_WRITE_SITEMAP", "")
+	cfg.MaxBodyMB = getEnvInt("INPUT_MAX_BODY_MB", 50)
+	cfg.MailtoAudit = getEnvBool("INPUT_MAILTO_AUDIT", false)
+	cfg.MailtoMXLookup = getEnvBool("INPUT_MAILTO_MX_LOOKUP", false)
+	cfg.IgnoreQueryParams = getEnvBool("INPUT_IGNORE_QUERY_PARAMS", false)
+	cfg.KeepParams = ParseList(getEnv("INPUT_KEEP_PARAMS", ""))
+
+	return cfg, nil
+}
+
+// ParseList splits a comma-separated value into its trimmed, non-empty items
+func ParseList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// SplitURLs splits base or sitemap URLs separated by commas or newlines. It
+// returns the first, which settings for a single site apply to, and all of
+// them.
+func SplitURLs(value string) (string, []string) {
+	urls := ParseList(strings.ReplaceAll(value, "\n", ","))
+	if len(urls) == 0 {
+		return "", nil
+	}
+	return urls[0], urls
+}
+
+// ParsePatterns compiles a comma-separated list of regular expressions.
+// Invalid patterns are ignored.
+func ParsePatterns(value string) []*regexp.Regexp {
+	var patterns []*regexp.Regexp
+	for _, pattern := range ParseList(value) {
+		if regex, err := regexp.Compile(pattern); err == nil {
+			patterns = append(patterns, regex)
+		}
+	}
+	return patterns
+}
+
+// ParseTimeoutOverrides parses a comma-separated list of pattern=timeout
+// entries, e.g. "/downloads/=120s,api\.example\.com=5". Timeouts are Go
+// durations or plain seconds. Invalid entries are ignored.
+func ParseTimeoutOverrides(value string) []TimeoutOverride {
+	var overrides []TimeoutOverride
+	for _, entry := range strings.Split(value, ",") {
+		entry = strings.TrimSpace(entry)
+		idx := strings.LastIndex(entry, "=")
+		if idx <= 0 {
+			continue
+		}
+
+		timeout, ok := ParseDuration(strings.TrimSpace(entry[idx+1:]))
+		if !ok {
+			continue
+		}
+		regex, err := regexp.Compile(strings.TrimSpace(entry[:idx]))
+		if err != nil {
+			continue
+		}
+		overrides = append(overrides, TimeoutOverride{Pattern: regex, Timeout: timeout})
+	}
+	return overrides
+}
+
+// ParsePathRules parses a comma-separated list of pattern=replacement rules,
+// e.g. `^content/(.+)\.md$=/$1/`. Invalid entries are ignored.
+func ParsePathRules(value string) []PathRule {
+	var rules []PathRule
+	for _, entry := range ParseList(value) {
+		idx := strings.LastIndex(entry, "=")
+		if idx <= 0 {
+			continue
+		}
+		regex, err := regexp.Compile(strings.TrimSpace(entry[:idx]))
+		if err != nil {
+			continue
+		}
+		rules = append(rules, PathRule{Pattern: regex, Replacement: strings.TrimSpace(entry[idx+1:])})
+	}
+	return rules
+}
+
+// ParseStatusExceptions parses a comma-separated list of host=status entries,
+// e.g. "linkedin.com=999,example.org=403". A host may be listed more than
+// once to accept several codes. Invalid entries are ignored.
+func ParseStatusExceptions(value string) map[string][]int {
+	exceptions := make(map[string][]int)
+	for _, entry := range ParseList(value) {
+		host, code, found := strings.Cut(entry, "=")
+		if !found {
+			continue
+		}
+		host = strings.ToLower(strings.TrimSpace(host))
+		statusCode, err := strconv.Atoi(strings.TrimSpace(code))
+		if host == "" || err != nil {
+			continue
+		}
+		exceptions[host] = append(exceptions[host], statusCode)
+	}
+	return exceptions
+}
+
+// ParseStatusCodes parses a comma-separated list of status codes, e.g.
+// "403,429,999". Invalid entries are ignored.
+func ParseStatusCodes(value string) []int {
+	var codes []int
+	for _, entry := range ParseList(value) {
+		if code, err := strconv.Atoi(entry); err == nil {
+			codes = append(codes, code)
+		}
+	}
+	return codes
+}
+
+// ParseHeaders parses request headers given one per line as "Name: value".
+// A header may be given more than once. Lines without a name are ignored.
+func ParseHeaders(value string) http.Header {
+	headers := make(http.Header)
+	for _, line := range strings.Split(value, "\n") {
+		name, headerValue, found := strings.Cut(line, ":")
+		name = strings.TrimSpace(name)
+		if !found || name == "" {
+			continue
+		}
+		headers.Add(name, strings.TrimSpace(headerValue))
+	}
+	return headers
+}
+
+// ParseDuration accepts either a Go duration string or a whole number of seconds
+func ParseDuration(value string) (time.Duration, bool) {
+	if seconds, err := strconv.Atoi(value); err == nil {
+		return time.Duration(seconds) * time.Second, seconds > 0
+	}
+	duration, err := time.ParseDuration(value)
+	if err != nil || duration <= 0 {
+		return 0, false
+	}
+	return duration, true
+}
+
+// ParseDurationSetting parses an optional duration setting as ParseDuration
+// does. An empty value or 0 leaves the setting off; any other value that
+// isn't a positive duration is an error, so a typo doesn't quietly turn the
+// setting off.
+func ParseDurationSetting(value string) (time.Duration, error) {
+	value = strings.TrimSpace(value)
+	if value == "" || value == "0" {
+		return 0, nil
+	}
+	duration, ok := ParseDuration(value)
+	if !ok {
+		return 0, fmt.Errorf("%q is not a duration (expected a Go duration such as 720h or a number of seconds)", value)
+	}
+	return duration, nil
+}
+
+// ParsePercent parses an optional percentage setting. An empty value or 0
+// leaves the setting off; anything else must be a number in (0, 100].
+func ParsePercent(value string) (float64, error) {
+	value = strings.TrimSpace(value)
+	if value == "" || value == "0" {
+		return 0, nil
+	}
+	percent, err := strconv.ParseFloat(value, 64)
+	if err != nil || percent <= 0 || percent > 100 {
+		return 0, fmt.Errorf("%q is not a percentage (expected a number greater than 0 and at most 100)", value)
+	}
+	return percent, nil
 }
 
 func getEnv(key, defaultValue string) string {
@@ -67,6 +500,26 @@ func getEnvInt(key string, defaultValue int) int {
 	return defaultValue
 }
 
+// getEnvDuration returns an optional duration setting, off when it is unset
+// and an error naming the variable when it is malformed
+func getEnvDuration(key string) (time.Duration, error) {
+	duration, err := ParseDurationSetting(os.Getenv(key))
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s: %w", key, err)
+	}
+	return duration, nil
+}
+
+// getEnvPercent returns an optional percentage setting, off when it is unset
+// and an error naming the variable when it is malformed or out of range
+func getEnvPercent(key string) (float64, error) {
+	percent, err := ParsePercent(os.Getenv(key))
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s: %w", key, err)
+	}
+	return percent, nil
+}
+
 func getEnvBool(key string, defaultValue bool) bool {
 	if value := os.Getenv(key); value != "" {
 		if boolValue, err := strconv.ParseBool(value); err == nil {
@@ -2,6 +2,8 @@ package config
 
 import (
 	"os"
+	"reflect"
+	"strings"
 	"testing"
 	"time"
 )
@@ -19,6 +21,108 @@ func TestFromEnvironment(t *testing.T) {
 		"INPUT_FAIL_ON_ERROR",
 		"INPUT_MAX_CONCURRENT",
 		"INPUT_VERBOSE",
+		"INPUT_MAX_RETRIES",
+		"INPUT_RETRY_BUDGET",
+		"INPUT_TIMEOUT_OVERRIDES",
+		"INPUT_FAIL_ON_CATEGORIES",
+		"INPUT_STATUS_EXCEPTIONS",
+		"INPUT_NEWS_MAX_AGE",
+		"INPUT_SAMPLE",
+		"INPUT_SAMPLE_PERCENT",
+		"INPUT_SAMPLE_SEED",
+		"INPUT_SITEMAP_FALLBACK_CRAWL",
+		"INPUT_PROBE_SITEMAP",
+		"INPUT_SEEDS_FILE",
+		"INPUT_IMPORT_URLS",
+		"INPUT_INVENTORY_FILE",
+		"INPUT_LOGIN_PATTERNS",
+		"INPUT_CACHE_FILE",
+		"INPUT_SKIP_UNCHANGED",
+		"INPUT_CHANGED_FILES_ONLY",
+		"INPUT_PATH_RULES",
+		"INPUT_GITHUB_TOKEN",
+		"INPUT_PR_NUMBER",
+		"INPUT_FILE_RULES",
+		"INPUT_SITE_CONFIG",
+		"INPUT_PREVIEW_URL",
+		"INPUT_LINK_TEXT_AUDIT",
+		"INPUT_DUPLICATE_ID_AUDIT",
+		"INPUT_PLACEHOLDER_LINK_AUDIT",
+		"INPUT_TRACKING_PARAM_AUDIT",
+		"INPUT_TRACKING_PARAMS",
+		"INPUT_FAIL_ON_TRACKING_PARAMS",
+		"INPUT_URL_SANITY_AUDIT",
+		"INPUT_MAX_URL_LENGTH",
+		"INPUT_REDIRECT_MAP",
+		"INPUT_FIX_PR",
+		"INPUT_IGNORE_FILE",
+		"INPUT_FORMAT",
+		"INPUT_OUTPUT_FILE",
+		"INPUT_REPEAT",
+		"INPUT_REPAIR_URLS",
+		"INPUT_USER_AGENT_RULES",
+		"INPUT_USER_AGENTS",
+		"INPUT_ACCEPT_LANGUAGE",
+		"INPUT_ACCEPT_LANGUAGE_RULES",
+		"INPUT_CHECK_LOCALES",
+		"INPUT_REPORT_FILE",
+		"INPUT_CACHE_DIR",
+		"INPUT_CACHE_MAX_ENTRIES",
+		"INPUT_MAX_MEMORY_MB",
+		"INPUT_LOGIN_URL",
+		"INPUT_LOGIN_FIELDS",
+		"INPUT_LOGIN_SUCCESS_SELECTOR",
+		"INPUT_JSON_URLS",
+		"INPUT_JSON_PATHS",
+		"INPUT_SEVERITY_RULES",
+		"INPUT_CHECK_ORDER",
+		"INPUT_SCHEME_POLICY",
+		"INPUT_DECISION_HOOK",
+		"INPUT_PREFER_HTTPS",
+		"INPUT_CHECK_EXTERNAL",
+		"INPUT_EXTERNAL_MAX_CONCURRENT",
+		"INPUT_RESPECT_ROBOTS",
+		"INPUT_CHECK_ELEMENTS",
+		"INPUT_PATH",
+		"INPUT_CACHE_TTL",
+		"INPUT_FOLLOW_REDIRECTS",
+		"INPUT_MAX_REDIRECTS",
+		"INPUT_WARN_ON_REDIRECT",
+		"INPUT_ACCEPT_STATUS",
+		"INPUT_FILES",
+		"INPUT_HEADERS",
+		"INPUT_BASIC_AUTH",
+		"INPUT_PR_COMMENT",
+		"INPUT_INSECURE_SKIP_VERIFY",
+		"INPUT_CA_CERT",
+		"INPUT_INCLUDE_PATTERNS",
+		"INPUT_MAX_PAGES",
+		"INPUT_BASELINE",
+		"INPUT_WRITE_BASELINE",
+		"INPUT_URLS_FILE",
+		"INPUT_SLOW_THRESHOLD",
+		"INPUT_FAIL_ON_SLOW",
+		"INPUT_SOFT_404_PATTERNS",
+		"INPUT_DISCOVER_SITEMAP",
+		"INPUT_FAIL_ON",
+		"INPUT_COOKIES",
+		"INPUT_EXCLUDE_SELECTORS",
+		"INPUT_NORMALIZE_URLS",
+		"INPUT_IGNORE_TRACKING_PARAMS",
+		"INPUT_GET_FALLBACK_STATUS",
+		"INPUT_METRICS_FILE",
+		"INPUT_PUSHGATEWAY_URL",
+		"INPUT_METRICS_JOB",
+		"INPUT_WEBHOOK_URL",
+		"INPUT_WEBHOOK_TEMPLATE",
+		"INPUT_WRITE_SITEMAP",
+		"INPUT_MAX_BODY_MB",
+		"INPUT_MAILTO_AUDIT",
+		"INPUT_MAILTO_MX_LOOKUP",
+		"INPUT_IGNORE_QUERY_PARAMS",
+		"INPUT_KEEP_PARAMS",
+		"INPUT_FRONTIER_SPILL",
+		"INPUT_RESULT_SPILL",
 	}
 
 	for _, env := range envVars {
@@ -38,7 +142,10 @@ func TestFromEnvironment(t *testing.T) {
 	}()
 
 	t.Run("default values", func(t *testing.T) {
-		cfg := FromEnvironment()
+		cfg, err := FromEnvironment()
+		if err != nil {
+			t.Fatalf("FromEnvironment failed: %v", err)
+		}
 
 		if cfg.SitemapURL != "" {
 			t.Errorf("Expected empty SitemapURL, got %s", cfg.SitemapURL)
@@ -67,11 +174,137 @@ func TestFromEnvironment(t *testing.T) {
 		if len(cfg.ExcludePatterns) != 0 {
 			t.Errorf("Expected no exclude patterns, got %d", len(cfg.ExcludePatterns))
 		}
+		if cfg.MaxRetries != 0 {
+			t.Errorf("Expected MaxRetries 0, got %d", cfg.MaxRetries)
+		}
+		if !cfg.SitemapFallback {
+			t.Error("Expected SitemapFallback to default to true")
+		}
+		if cfg.RetryBudget != 200 {
+			t.Errorf("Expected RetryBudget 200, got %d", cfg.RetryBudget)
+		}
+		if len(cfg.LoginPatterns) == 0 {
+			t.Error("Expected default login patterns")
+		}
+		if cfg.LinkTextAudit {
+			t.Error("Expected LinkTextAudit to be false by default")
+		}
+		if !cfg.DuplicateIDAudit {
+			t.Error("Expected DuplicateIDAudit to be true by default")
+		}
+		if !cfg.PlaceholderLinkAudit {
+			t.Error("Expected PlaceholderLinkAudit to be true by default")
+		}
+		if cfg.TrackingParamAudit {
+			t.Error("Expected TrackingParamAudit to be false by default")
+		}
+		if len(cfg.TrackingParams) != len(ParseList(DefaultTrackingParams)) {
+			t.Errorf("Expected default tracking params, got %v", cfg.TrackingParams)
+		}
+		if !cfg.URLSanityAudit {
+			t.Error("Expected URLSanityAudit to be true by default")
+		}
+		if cfg.MaxURLLength != 2048 {
+			t.Errorf("Expected default MaxURLLength 2048, got %d", cfg.MaxURLLength)
+		}
+		if cfg.IgnoreFile != DefaultIgnoreFile {
+			t.Errorf("Expected default IgnoreFile %s, got %s", DefaultIgnoreFile, cfg.IgnoreFile)
+		}
+		if cfg.Format != "text" {
+			t.Errorf("Expected default Format to be text, got %s", cfg.Format)
+		}
+		if cfg.Repeat != 1 {
+			t.Errorf("Expected default Repeat to be 1, got %d", cfg.Repeat)
+		}
+		if cfg.RepairURLs {
+			t.Error("Expected default RepairURLs to be false")
+		}
+		if cfg.CacheMaxEntries != 50000 {
+			t.Errorf("Expected CacheMaxEntries 50000, got %d", cfg.CacheMaxEntries)
+		}
+		if cfg.MaxMemoryMB != 0 {
+			t.Errorf("Expected MaxMemoryMB 0, got %d", cfg.MaxMemoryMB)
+		}
+		if cfg.LoginURL != "" || cfg.LoginFields != nil || cfg.LoginSuccessSelector != "" {
+			t.Errorf("Expected no login settings, got %s, %v and %s", cfg.LoginURL, cfg.LoginFields, cfg.LoginSuccessSelector)
+		}
+		if cfg.JSONURLs != nil || cfg.JSONPaths != nil {
+			t.Errorf("Expected no JSON endpoints or paths, got %v and %v", cfg.JSONURLs, cfg.JSONPaths)
+		}
+		if cfg.SeverityRules != nil {
+			t.Errorf("Expected no SeverityRules, got %+v", cfg.SeverityRules)
+		}
+		if cfg.CheckOrder != "discovery" {
+			t.Errorf("Expected CheckOrder discovery, got %s", cfg.CheckOrder)
+		}
+		if cfg.SchemePolicy["https"] != "check" || cfg.SchemePolicy["*"] != "ignore" {
+			t.Errorf("Expected the default SchemePolicy, got %v", cfg.SchemePolicy)
+		}
+		if cfg.DecisionHook != "" {
+			t.Errorf("Expected no DecisionHook, got %s", cfg.DecisionHook)
+		}
+		if cfg.PreferHTTPS {
+			t.Error("Expected PreferHTTPS to default to false")
+		}
+		if cfg.CheckExternal || cfg.ExternalConcurrency != 5 {
+			t.Errorf("Expected external links off with 5 concurrent requests, got %v and %d", cfg.CheckExternal, cfg.ExternalConcurrency)
+		}
+		if cfg.RespectRobots {
+			t.Error("Expected RespectRobots to default to false")
+		}
+		if len(cfg.CheckElements) != 1 || cfg.CheckElements[0] != "a" {
+			t.Errorf("Expected CheckElements [a], got %v", cfg.CheckElements)
+		}
+		if cfg.SitePath != "" {
+			t.Errorf("Expected no SitePath, got %s", cfg.SitePath)
+		}
+		if cfg.CacheTTL != 0 {
+			t.Errorf("Expected no CacheTTL, got %s", cfg.CacheTTL)
+		}
+		if cfg.StopAtRedirects {
+			t.Error("Expected redirects to be followed by default")
+		}
+		if cfg.MaxRedirects != 10 {
+			t.Errorf("Expected default MaxRedirects 10, got %d", cfg.MaxRedirects)
+		}
+		if cfg.MaxPages != 0 {
+			t.Errorf("Expected MaxPages 0, got %d", cfg.MaxPages)
+		}
+		if cfg.FailOn != nil {
+			t.Errorf("Expected no fail-on policy, got %+v", cfg.FailOn)
+		}
+		if !cfg.NormalizeURLs {
+			t.Error("Expected NormalizeURLs to be true by default")
+		}
+		if cfg.IgnoreTrackingParams {
+			t.Error("Expected IgnoreTrackingParams to be false by default")
+		}
+		if !reflect.DeepEqual(cfg.GetFallbackStatus, []int{403, 405, 501}) {
+			t.Errorf("Expected the default GetFallbackStatus, got %v", cfg.GetFallbackStatus)
+		}
+		if cfg.MetricsJob != "link-checker" {
+			t.Errorf("Expected MetricsJob link-checker by default, got %s", cfg.MetricsJob)
+		}
+		if cfg.MaxBodyMB != 50 {
+			t.Errorf("Expected MaxBodyMB 50, got %d", cfg.MaxBodyMB)
+		}
+		if cfg.MailtoAudit || cfg.MailtoMXLookup {
+			t.Error("Expected mailto auditing to be off by default")
+		}
+		if cfg.IgnoreQueryParams || len(cfg.KeepParams) != 0 {
+			t.Error("Expected query parameters to be kept by default")
+		}
+		if cfg.FrontierSpill != DefaultFrontierSpill {
+			t.Errorf("Expected FrontierSpill %d, got %d", DefaultFrontierSpill, cfg.FrontierSpill)
+		}
+		if cfg.ResultSpill != DefaultResultSpill {
+			t.Errorf("Expected ResultSpill %d, got %d", DefaultResultSpill, cfg.ResultSpill)
+		}
 	})
 
 	t.Run("custom values", func(t *testing.T) {
 		os.Setenv("INPUT_SITEMAP_URL", "https://example.com/sitemap.xml")
-		os.Setenv("INPUT_BASE_URL", "https://example.com")
+		os.Setenv("INPUT_BASE_URL", "https://example.com,https://docs.example.com")
 		os.Setenv("INPUT_MAX_DEPTH", "5")
 		os.Setenv("INPUT_TIMEOUT", "60")
 		os.Setenv("INPUT_USER_AGENT", "CustomBot/1.0")
@@ -79,8 +312,113 @@ func TestFromEnvironment(t *testing.T) {
 		os.Setenv("INPUT_FAIL_ON_ERROR", "false")
 		os.Setenv("INPUT_MAX_CONCURRENT", "20")
 		os.Setenv("INPUT_VERBOSE", "true")
+		os.Setenv("INPUT_MAX_RETRIES", "2")
+		os.Setenv("INPUT_RETRY_BUDGET", "50")
+		os.Setenv("INPUT_TIMEOUT_OVERRIDES", "/downloads/=120s")
+		os.Setenv("INPUT_FAIL_ON_CATEGORIES", "4xx, dns")
+		os.Setenv("INPUT_STATUS_EXCEPTIONS", "linkedin.com=999")
+		os.Setenv("INPUT_NEWS_MAX_AGE", "48h")
+		os.Setenv("INPUT_SAMPLE", "100")
+		os.Setenv("INPUT_SAMPLE_PERCENT", "2.5")
+		os.Setenv("INPUT_SAMPLE_SEED", "42")
+		os.Setenv("INPUT_SITEMAP_FALLBACK_CRAWL", "false")
+		os.Setenv("INPUT_PROBE_SITEMAP", "true")
+		os.Setenv("INPUT_SEEDS_FILE", "seeds.txt")
+		os.Setenv("INPUT_IMPORT_URLS", "previous.json")
+		os.Setenv("INPUT_INVENTORY_FILE", "inventory.json")
 
-		cfg := FromEnvironment()
+		os.Setenv("INPUT_LOGIN_PATTERNS", "/auth/,sso\\.example\\.com")
+		os.Setenv("INPUT_CACHE_FILE", ".link-checker-cache.json")
+		os.Setenv("INPUT_SKIP_UNCHANGED", "true")
+		os.Setenv("INPUT_CHANGED_FILES_ONLY", "true")
+		os.Setenv("INPUT_PATH_RULES", `^content/(.+)\.md$=/$1/`)
+		os.Setenv("INPUT_GITHUB_TOKEN", "ghs_test")
+		os.Setenv("INPUT_PR_NUMBER", "42")
+		os.Setenv("INPUT_FILE_RULES", `^/docs/(.+)/$=content/docs/$1.md`)
+		os.Setenv("INPUT_SITE_CONFIG", "mkdocs.yml")
+		os.Setenv("INPUT_PREVIEW_URL", "https://deploy-preview-1--example.netlify.app")
+		os.Setenv("INPUT_LINK_TEXT_AUDIT", "true")
+		os.Setenv("INPUT_DUPLICATE_ID_AUDIT", "false")
+		os.Setenv("INPUT_PLACEHOLDER_LINK_AUDIT", "false")
+		os.Setenv("INPUT_TRACKING_PARAM_AUDIT", "true")
+		os.Setenv("INPUT_TRACKING_PARAMS", "utm_*, ref")
+		os.Setenv("INPUT_FAIL_ON_TRACKING_PARAMS", "true")
+		os.Setenv("INPUT_URL_SANITY_AUDIT", "false")
+		os.Setenv("INPUT_MAX_URL_LENGTH", "512")
+		os.Setenv("INPUT_REDIRECT_MAP", "redirects.csv")
+		os.Setenv("INPUT_FIX_PR", "true")
+		os.Setenv("INPUT_IGNORE_FILE", ".linkignore")
+		os.Setenv("INPUT_FORMAT", "ndjson")
+		os.Setenv("INPUT_OUTPUT_FILE", "results.ndjson")
+		os.Setenv("INPUT_REPEAT", "5")
+		os.Setenv("INPUT_REPAIR_URLS", "true")
+		os.Setenv("INPUT_USER_AGENT_RULES", `linkedin\.com=chrome`)
+		os.Setenv("INPUT_USER_AGENTS", "safari,firefox")
+		os.Setenv("INPUT_ACCEPT_LANGUAGE", "de-DE,de;q=0.9")
+		os.Setenv("INPUT_ACCEPT_LANGUAGE_RULES", "/fr/=fr")
+		os.Setenv("INPUT_CHECK_LOCALES", "en, de")
+		os.Setenv("INPUT_REPORT_FILE", "report.json")
+		os.Setenv("INPUT_CACHE_DIR", ".link-checker-cache")
+		os.Setenv("INPUT_CACHE_MAX_ENTRIES", "100")
+		os.Setenv("INPUT_MAX_MEMORY_MB", "512")
+		os.Setenv("INPUT_LOGIN_URL", "https://example.com/login")
+		os.Setenv("INPUT_LOGIN_FIELDS", "username=alice,remember=1")
+		os.Setenv("INPUT_LOGIN_SUCCESS_SELECTOR", "a.logout")
+		os.Setenv("INPUT_JSON_URLS", "https://api.example.com/items, https://api.example.com/docs")
+		os.Setenv("INPUT_JSON_PATHS", "$.data[*].links.self")
+		os.Setenv("INPUT_SEVERITY_RULES", "404=error,timeout=warning")
+		os.Setenv("INPUT_CHECK_ORDER", "importance")
+		os.Setenv("INPUT_SCHEME_POLICY", "ftp=report")
+		os.Setenv("INPUT_DECISION_HOOK", "./hooks/decide.sh")
+		os.Setenv("INPUT_PREFER_HTTPS", "true")
+		os.Setenv("INPUT_CHECK_EXTERNAL", "true")
+		os.Setenv("INPUT_EXTERNAL_MAX_CONCURRENT", "2")
+		os.Setenv("INPUT_RESPECT_ROBOTS", "true")
+		os.Setenv("INPUT_CHECK_ELEMENTS", "a,img,script")
+		os.Setenv("INPUT_PATH", "public")
+		os.Setenv("INPUT_CACHE_TTL", "24h")
+		os.Setenv("INPUT_FOLLOW_REDIRECTS", "false")
+		os.Setenv("INPUT_MAX_REDIRECTS", "3")
+		os.Setenv("INPUT_WARN_ON_REDIRECT", "true")
+		os.Setenv("INPUT_ACCEPT_STATUS", "403, 429,999,bad")
+		os.Setenv("INPUT_FILES", "README.md, docs/**/*.md")
+		os.Setenv("INPUT_HEADERS", "Authorization: Bearer abc\nX-Env: staging\nbogus")
+		os.Setenv("INPUT_BASIC_AUTH", "user:pass")
+		os.Setenv("INPUT_PR_COMMENT", "true")
+		os.Setenv("INPUT_INSECURE_SKIP_VERIFY", "true")
+		os.Setenv("INPUT_CA_CERT", "certs/ca.pem")
+		os.Setenv("INPUT_INCLUDE_PATTERNS", "/docs/.*, ^https://example\\.com/api/")
+		os.Setenv("INPUT_MAX_PAGES", "500")
+		os.Setenv("INPUT_BASELINE", ".link-checker-baseline")
+		os.Setenv("INPUT_WRITE_BASELINE", "baseline.txt")
+		os.Setenv("INPUT_URLS_FILE", "urls.txt")
+		os.Setenv("INPUT_SLOW_THRESHOLD", "2s")
+		os.Setenv("INPUT_FAIL_ON_SLOW", "true")
+		os.Setenv("INPUT_SOFT_404_PATTERNS", "Page not found,Oops")
+		os.Setenv("INPUT_DISCOVER_SITEMAP", "true")
+		os.Setenv("INPUT_FAIL_ON", "category:internal")
+		os.Setenv("INPUT_COOKIES", "session=abc123")
+		os.Setenv("INPUT_EXCLUDE_SELECTORS", "nav, .footer")
+		os.Setenv("INPUT_NORMALIZE_URLS", "false")
+		os.Setenv("INPUT_IGNORE_TRACKING_PARAMS", "true")
+		os.Setenv("INPUT_GET_FALLBACK_STATUS", "405, 999")
+		os.Setenv("INPUT_METRICS_FILE", "link-health.prom")
+		os.Setenv("INPUT_PUSHGATEWAY_URL", "http://pushgateway:9091")
+		os.Setenv("INPUT_METRICS_JOB", "docs")
+		os.Setenv("INPUT_WEBHOOK_URL", "https://hooks.slack.com/services/T000/B000/XXXX")
+		os.Setenv("INPUT_WEBHOOK_TEMPLATE", "{{len .Broken}} broken")
+		os.Setenv("INPUT_WRITE_SITEMAP", "public/sitemap.xml")
+		os.Setenv("INPUT_MAX_BODY_MB", "10")
+		os.Setenv("INPUT_MAILTO_AUDIT", "true")
+		os.Setenv("INPUT_MAILTO_MX_LOOKUP", "true")
+		os.Setenv("INPUT_IGNORE_QUERY_PARAMS", "true")
+		os.Setenv("INPUT_KEEP_PARAMS", "page, id")
+		os.Setenv("INPUT_FRONTIER_SPILL", "500")
+		os.Setenv("INPUT_RESULT_SPILL", "500")
+		cfg, err := FromEnvironment()
+		if err != nil {
+			t.Fatalf("FromEnvironment failed: %v", err)
+		}
 
 		if cfg.SitemapURL != "https://example.com/sitemap.xml" {
 			t.Errorf("Expected SitemapURL https://example.com/sitemap.xml, got %s", cfg.SitemapURL)
@@ -88,6 +426,9 @@ func TestFromEnvironment(t *testing.T) {
 		if cfg.BaseURL != "https://example.com" {
 			t.Errorf("Expected BaseURL https://example.com, got %s", cfg.BaseURL)
 		}
+		if !reflect.DeepEqual(cfg.BaseURLs, []string{"https://example.com", "https://docs.example.com"}) {
+			t.Errorf("Expected both base URLs, got %v", cfg.BaseURLs)
+		}
 		if cfg.MaxDepth != 5 {
 			t.Errorf("Expected MaxDepth 5, got %d", cfg.MaxDepth)
 		}
@@ -109,6 +450,306 @@ func TestFromEnvironment(t *testing.T) {
 		if len(cfg.ExcludePatterns) != 2 {
 			t.Errorf("Expected 2 exclude patterns, got %d", len(cfg.ExcludePatterns))
 		}
+		if cfg.MaxRetries != 2 {
+			t.Errorf("Expected MaxRetries 2, got %d", cfg.MaxRetries)
+		}
+		if cfg.RetryBudget != 50 {
+			t.Errorf("Expected RetryBudget 50, got %d", cfg.RetryBudget)
+		}
+		if len(cfg.TimeoutOverrides) != 1 {
+			t.Errorf("Expected 1 timeout override, got %d", len(cfg.TimeoutOverrides))
+		}
+		if len(cfg.FailOnCategories) != 2 || cfg.FailOnCategories[1] != "dns" {
+			t.Errorf("Expected fail-on categories [4xx dns], got %v", cfg.FailOnCategories)
+		}
+		if codes := cfg.StatusExceptions["linkedin.com"]; len(codes) != 1 || codes[0] != 999 {
+			t.Errorf("Expected linkedin.com status exception 999, got %v", codes)
+		}
+		if cfg.NewsMaxAge != 48*time.Hour {
+			t.Errorf("Expected NewsMaxAge 48h, got %v", cfg.NewsMaxAge)
+		}
+		if cfg.SampleSize != 100 || cfg.SamplePercent != 2.5 || cfg.SampleSeed != 42 {
+			t.Errorf("Expected sampling 100/2.5%%/42, got %d/%v%%/%d", cfg.SampleSize, cfg.SamplePercent, cfg.SampleSeed)
+		}
+		if cfg.SitemapFallback {
+			t.Error("Expected SitemapFallback false")
+		}
+		if !cfg.ProbeSitemap {
+			t.Error("Expected ProbeSitemap true")
+		}
+		if cfg.SeedsFile != "seeds.txt" {
+			t.Errorf("Expected SeedsFile seeds.txt, got %s", cfg.SeedsFile)
+		}
+		if cfg.ImportURLs != "previous.json" {
+			t.Errorf("Expected ImportURLs previous.json, got %s", cfg.ImportURLs)
+		}
+		if cfg.InventoryFile != "inventory.json" {
+			t.Errorf("Expected InventoryFile inventory.json, got %s", cfg.InventoryFile)
+		}
+		if len(cfg.LoginPatterns) != 2 || cfg.LoginPatterns[1].String() != `sso\.example\.com` {
+			t.Errorf("Expected 2 custom login patterns, got %v", cfg.LoginPatterns)
+		}
+		if cfg.CacheFile != ".link-checker-cache.json" {
+			t.Errorf("Expected CacheFile .link-checker-cache.json, got %s", cfg.CacheFile)
+		}
+		if !cfg.SkipUnchanged {
+			t.Error("Expected SkipUnchanged true")
+		}
+		if !cfg.ChangedFiles {
+			t.Error("Expected ChangedFiles true")
+		}
+		if len(cfg.PathRules) != 1 || cfg.PathRules[0].Replacement != "/$1/" {
+			t.Errorf("Expected one path rule, got %+v", cfg.PathRules)
+		}
+		if cfg.GitHubToken != "ghs_test" {
+			t.Errorf("Expected GitHubToken ghs_test, got %s", cfg.GitHubToken)
+		}
+		if cfg.PRNumber != 42 {
+			t.Errorf("Expected PRNumber 42, got %d", cfg.PRNumber)
+		}
+		if len(cfg.FileRules) != 1 || cfg.FileRules[0].Replacement != "content/docs/$1.md" {
+			t.Errorf("Expected one file rule, got %+v", cfg.FileRules)
+		}
+		if cfg.SiteConfig != "mkdocs.yml" {
+			t.Errorf("Expected SiteConfig mkdocs.yml, got %s", cfg.SiteConfig)
+		}
+		if cfg.PreviewURL != "https://deploy-preview-1--example.netlify.app" {
+			t.Errorf("Expected PreviewURL https://deploy-preview-1--example.netlify.app, got %s", cfg.PreviewURL)
+		}
+		if !cfg.LinkTextAudit {
+			t.Error("Expected LinkTextAudit to be true")
+		}
+		if cfg.DuplicateIDAudit {
+			t.Error("Expected DuplicateIDAudit to be false")
+		}
+		if cfg.PlaceholderLinkAudit {
+			t.Error("Expected PlaceholderLinkAudit to be false")
+		}
+		if !cfg.TrackingParamAudit {
+			t.Error("Expected TrackingParamAudit to be true")
+		}
+		if len(cfg.TrackingParams) != 2 || cfg.TrackingParams[1] != "ref" {
+			t.Errorf("Expected TrackingParams [utm_* ref], got %v", cfg.TrackingParams)
+		}
+		if !cfg.FailOnTrackingParams {
+			t.Error("Expected FailOnTrackingParams to be true")
+		}
+		if cfg.URLSanityAudit {
+			t.Error("Expected URLSanityAudit to be false")
+		}
+		if cfg.MaxURLLength != 512 {
+			t.Errorf("Expected MaxURLLength 512, got %d", cfg.MaxURLLength)
+		}
+		if cfg.RedirectMap != "redirects.csv" {
+			t.Errorf("Expected RedirectMap redirects.csv, got %s", cfg.RedirectMap)
+		}
+		if !cfg.FixPR {
+			t.Error("Expected FixPR to be true")
+		}
+		if cfg.IgnoreFile != ".linkignore" {
+			t.Errorf("Expected IgnoreFile .linkignore, got %s", cfg.IgnoreFile)
+		}
+		if cfg.Format != "ndjson" {
+			t.Errorf("Expected Format to be ndjson, got %s", cfg.Format)
+		}
+		if cfg.OutputFile != "results.ndjson" {
+			t.Errorf("Expected OutputFile to be results.ndjson, got %s", cfg.OutputFile)
+		}
+		if cfg.Repeat != 5 {
+			t.Errorf("Expected Repeat to be 5, got %d", cfg.Repeat)
+		}
+		if !cfg.RepairURLs {
+			t.Error("Expected RepairURLs to be true")
+		}
+		if len(cfg.UserAgentRules) != 1 || cfg.UserAgentRules[0].UserAgent != UserAgentPresets["chrome"] {
+			t.Errorf("Expected one linkedin.com user agent rule, got %+v", cfg.UserAgentRules)
+		}
+		if len(cfg.UserAgents) != 2 {
+			t.Errorf("Expected 2 rotating user agents, got %v", cfg.UserAgents)
+		}
+		if cfg.AcceptLanguage != "de-DE,de;q=0.9" {
+			t.Errorf("Expected AcceptLanguage to be de-DE,de;q=0.9, got %s", cfg.AcceptLanguage)
+		}
+		if len(cfg.AcceptLanguageRules) != 1 || cfg.AcceptLanguageRules[0].Language != "fr" {
+			t.Errorf("Expected one fr language rule, got %+v", cfg.AcceptLanguageRules)
+		}
+		if len(cfg.CheckLocales) != 2 || cfg.CheckLocales[1] != "de" {
+			t.Errorf("Expected locales [en de], got %v", cfg.CheckLocales)
+		}
+		if cfg.ReportFile != "report.json" {
+			t.Errorf("Expected ReportFile to be report.json, got %s", cfg.ReportFile)
+		}
+		if cfg.CacheDir != ".link-checker-cache" {
+			t.Errorf("Expected CacheDir .link-checker-cache, got %s", cfg.CacheDir)
+		}
+		if cfg.CacheMaxEntries != 100 {
+			t.Errorf("Expected CacheMaxEntries 100, got %d", cfg.CacheMaxEntries)
+		}
+		if cfg.MaxMemoryMB != 512 {
+			t.Errorf("Expected MaxMemoryMB 512, got %d", cfg.MaxMemoryMB)
+		}
+		if cfg.LoginURL != "https://example.com/login" {
+			t.Errorf("Expected LoginURL https://example.com/login, got %s", cfg.LoginURL)
+		}
+		if cfg.LoginFields.Get("username") != "alice" || cfg.LoginFields.Get("remember") != "1" {
+			t.Errorf("Expected LoginFields username=alice and remember=1, got %v", cfg.LoginFields)
+		}
+		if cfg.LoginSuccessSelector != "a.logout" {
+			t.Errorf("Expected LoginSuccessSelector a.logout, got %s", cfg.LoginSuccessSelector)
+		}
+		if len(cfg.JSONURLs) != 2 || cfg.JSONURLs[1] != "https://api.example.com/docs" {
+			t.Errorf("Expected 2 JSONURLs, got %v", cfg.JSONURLs)
+		}
+		if len(cfg.JSONPaths) != 1 || cfg.JSONPaths[0] != "$.data[*].links.self" {
+			t.Errorf("Expected JSONPaths [$.data[*].links.self], got %v", cfg.JSONPaths)
+		}
+		if len(cfg.SeverityRules) != 2 || cfg.SeverityRules[1].Category != "timeout" || cfg.SeverityRules[1].Severity != "warning" {
+			t.Errorf("Expected 2 SeverityRules, got %+v", cfg.SeverityRules)
+		}
+		if cfg.CheckOrder != "importance" {
+			t.Errorf("Expected CheckOrder importance, got %s", cfg.CheckOrder)
+		}
+		if cfg.SchemePolicy["ftp"] != "report" || cfg.SchemePolicy["http"] != "check" {
+			t.Errorf("Expected ftp=report over the default SchemePolicy, got %v", cfg.SchemePolicy)
+		}
+		if cfg.DecisionHook != "./hooks/decide.sh" {
+			t.Errorf("Expected DecisionHook ./hooks/decide.sh, got %s", cfg.DecisionHook)
+		}
+		if !cfg.PreferHTTPS {
+			t.Error("Expected PreferHTTPS to be true")
+		}
+		if !cfg.CheckExternal {
+			t.Error("Expected CheckExternal to be true")
+		}
+		if cfg.ExternalConcurrency != 2 {
+			t.Errorf("Expected ExternalConcurrency 2, got %d", cfg.ExternalConcurrency)
+		}
+		if !cfg.RespectRobots {
+			t.Error("Expected RespectRobots to be true")
+		}
+		if len(cfg.CheckElements) != 3 || cfg.CheckElements[1] != "img" {
+			t.Errorf("Expected CheckElements [a img script], got %v", cfg.CheckElements)
+		}
+		if cfg.SitePath != "public" {
+			t.Errorf("Expected SitePath public, got %s", cfg.SitePath)
+		}
+		if cfg.CacheTTL != 24*time.Hour {
+			t.Errorf("Expected CacheTTL 24h, got %s", cfg.CacheTTL)
+		}
+		if !cfg.StopAtRedirects {
+			t.Error("Expected StopAtRedirects to be true")
+		}
+		if cfg.MaxRedirects != 3 {
+			t.Errorf("Expected MaxRedirects 3, got %d", cfg.MaxRedirects)
+		}
+		if !cfg.WarnOnRedirect {
+			t.Error("Expected WarnOnRedirect to be true")
+		}
+		if len(cfg.AcceptStatus) != 3 || cfg.AcceptStatus[0] != 403 || cfg.AcceptStatus[2] != 999 {
+			t.Errorf("Expected AcceptStatus [403 429 999], got %v", cfg.AcceptStatus)
+		}
+		if len(cfg.Files) != 2 || cfg.Files[1] != "docs/**/*.md" {
+			t.Errorf("Expected Files [README.md docs/**/*.md], got %v", cfg.Files)
+		}
+		if cfg.Headers.Get("Authorization") != "Bearer abc" || cfg.Headers.Get("X-Env") != "staging" || len(cfg.Headers) != 2 {
+			t.Errorf("Expected two headers, got %v", cfg.Headers)
+		}
+		if cfg.BasicAuth != "user:pass" {
+			t.Errorf("Expected BasicAuth user:pass, got %q", cfg.BasicAuth)
+		}
+		if !cfg.PRComment {
+			t.Error("Expected PRComment to be true")
+		}
+		if !cfg.InsecureSkipVerify {
+			t.Error("Expected InsecureSkipVerify to be true")
+		}
+		if cfg.CACert != "certs/ca.pem" {
+			t.Errorf("Expected CACert certs/ca.pem, got %q", cfg.CACert)
+		}
+		if len(cfg.IncludePatterns) != 2 || !cfg.IncludePatterns[0].MatchString("https://example.com/docs/setup") {
+			t.Errorf("Expected 2 include patterns, got %v", cfg.IncludePatterns)
+		}
+		if cfg.MaxPages != 500 {
+			t.Errorf("Expected MaxPages 500, got %d", cfg.MaxPages)
+		}
+		if cfg.Baseline != ".link-checker-baseline" {
+			t.Errorf("Expected Baseline .link-checker-baseline, got %s", cfg.Baseline)
+		}
+		if cfg.WriteBaseline != "baseline.txt" {
+			t.Errorf("Expected WriteBaseline baseline.txt, got %s", cfg.WriteBaseline)
+		}
+		if cfg.URLsFile != "urls.txt" {
+			t.Errorf("Expected URLsFile urls.txt, got %s", cfg.URLsFile)
+		}
+		if cfg.SlowThreshold != 2*time.Second {
+			t.Errorf("Expected SlowThreshold 2s, got %s", cfg.SlowThreshold)
+		}
+		if !cfg.FailOnSlow {
+			t.Error("Expected FailOnSlow to be true")
+		}
+		if len(cfg.Soft404Patterns) != 2 {
+			t.Errorf("Expected 2 soft 404 patterns, got %d", len(cfg.Soft404Patterns))
+		}
+		if !cfg.DiscoverSitemap {
+			t.Error("Expected DiscoverSitemap true")
+		}
+		if cfg.FailOn == nil || len(cfg.FailOn.Categories) != 1 {
+			t.Errorf("Expected a fail-on policy, got %+v", cfg.FailOn)
+		}
+		if len(cfg.Cookies) != 1 || cfg.Cookies[0].Value != "abc123" {
+			t.Errorf("Expected a session cookie, got %v", cfg.Cookies)
+		}
+		if len(cfg.ExcludeSelectors) != 2 || cfg.ExcludeSelectors[1] != ".footer" {
+			t.Errorf("Expected exclude selectors, got %v", cfg.ExcludeSelectors)
+		}
+		if cfg.NormalizeURLs {
+			t.Error("Expected NormalizeURLs to be false")
+		}
+		if !cfg.IgnoreTrackingParams {
+			t.Error("Expected IgnoreTrackingParams to be true")
+		}
+		if !reflect.DeepEqual(cfg.GetFallbackStatus, []int{405, 999}) {
+			t.Errorf("Expected GetFallbackStatus [405 999], got %v", cfg.GetFallbackStatus)
+		}
+		if cfg.MetricsFile != "link-health.prom" {
+			t.Errorf("Expected MetricsFile link-health.prom, got %s", cfg.MetricsFile)
+		}
+		if cfg.PushgatewayURL != "http://pushgateway:9091" {
+			t.Errorf("Expected PushgatewayURL http://pushgateway:9091, got %s", cfg.PushgatewayURL)
+		}
+		if cfg.MetricsJob != "docs" {
+			t.Errorf("Expected MetricsJob docs, got %s", cfg.MetricsJob)
+		}
+		if cfg.WebhookURL != "https://hooks.slack.com/services/T000/B000/XXXX" {
+			t.Errorf("Expected WebhookURL, got %s", cfg.WebhookURL)
+		}
+		if cfg.WebhookTemplate != "{{len .Broken}} broken" {
+			t.Errorf("Expected WebhookTemplate, got %s", cfg.WebhookTemplate)
+		}
+		if cfg.WriteSitemap != "public/sitemap.xml" {
+			t.Errorf("Expected WriteSitemap public/sitemap.xml, got %s", cfg.WriteSitemap)
+		}
+		if cfg.MaxBodyMB != 10 {
+			t.Errorf("Expected MaxBodyMB 10, got %d", cfg.MaxBodyMB)
+		}
+		if !cfg.MailtoAudit {
+			t.Error("Expected MailtoAudit to be true")
+		}
+		if !cfg.MailtoMXLookup {
+			t.Error("Expected MailtoMXLookup to be true")
+		}
+		if !cfg.IgnoreQueryParams {
+			t.Error("Expected IgnoreQueryParams to be true")
+		}
+		if !reflect.DeepEqual(cfg.KeepParams, []string{"page", "id"}) {
+			t.Errorf("Expected KeepParams [page id], got %v", cfg.KeepParams)
+		}
+		if cfg.FrontierSpill != 500 {
+			t.Errorf("Expected FrontierSpill 500, got %d", cfg.FrontierSpill)
+		}
+		if cfg.ResultSpill != 500 {
+			t.Errorf("Expected ResultSpill 500, got %d", cfg.ResultSpill)
+		}
 	})
 
 	t.Run("invalid values fallback to defaults", func(t *testing.T) {
@@ -118,7 +759,10 @@ func TestFromEnvironment(t *testing.T) {
 		os.Setenv("INPUT_MAX_CONCURRENT", "abc")
 		os.Setenv("INPUT_VERBOSE", "yes")
 
-		cfg := FromEnvironment()
+		cfg, err := FromEnvironment()
+		if err != nil {
+			t.Fatalf("FromEnvironment failed: %v", err)
+		}
 
 		if cfg.MaxDepth != 3 {
 			t.Errorf("Expected MaxDepth to fallback to 3, got %d", cfg.MaxDepth)
@@ -136,6 +780,21 @@ func TestFromEnvironment(t *testing.T) {
 			t.Errorf("Expected Verbose to fallback to false, got %v", cfg.Verbose)
 		}
 	})
+
+	t.Run("malformed optional settings are errors", func(t *testing.T) {
+		for key, value := range map[string]string{
+			"INPUT_NEWS_MAX_AGE":   "30 days",
+			"INPUT_CACHE_TTL":      "1 day",
+			"INPUT_SLOW_THRESHOLD": "2 seconds",
+			"INPUT_SAMPLE_PERCENT": "ten",
+		} {
+			os.Setenv(key, value)
+			if _, err := FromEnvironment(); err == nil || !strings.Contains(err.Error(), key) {
+				t.Errorf("Expected an error naming %s=%q, got %v", key, value, err)
+			}
+			os.Unsetenv(key)
+		}
+	})
 }
 
 func TestExcludePatterns(t *testing.T) {
@@ -152,7 +811,10 @@ func TestExcludePatterns(t *testing.T) {
 	t.Run("valid patterns", func(t *testing.T) {
 		os.Setenv("INPUT_EXCLUDE_PATTERNS", ".*\\.pdf$,.*\\.zip$,.*example\\.com.*")
 
-		cfg := FromEnvironment()
+		cfg, err := FromEnvironment()
+		if err != nil {
+			t.Fatalf("FromEnvironment failed: %v", err)
+		}
 
 		if len(cfg.ExcludePatterns) != 3 {
 			t.Errorf("Expected 3 patterns, got %d", len(cfg.ExcludePatterns))
@@ -187,7 +849,10 @@ func TestExcludePatterns(t *testing.T) {
 	t.Run("invalid patterns ignored", func(t *testing.T) {
 		os.Setenv("INPUT_EXCLUDE_PATTERNS", ".*\\.pdf$,[invalid,.*\\.zip$")
 
-		cfg := FromEnvironment()
+		cfg, err := FromEnvironment()
+		if err != nil {
+			t.Fatalf("FromEnvironment failed: %v", err)
+		}
 
 		// Should only have 2 valid patterns (invalid one ignored)
 		if len(cfg.ExcludePatterns) != 2 {
@@ -198,10 +863,287 @@ func TestExcludePatterns(t *testing.T) {
 	t.Run("empty patterns", func(t *testing.T) {
 		os.Setenv("INPUT_EXCLUDE_PATTERNS", "")
 
-		cfg := FromEnvironment()
+		cfg, err := FromEnvironment()
+		if err != nil {
+			t.Fatalf("FromEnvironment failed: %v", err)
+		}
 
 		if len(cfg.ExcludePatterns) != 0 {
 			t.Errorf("Expected 0 patterns, got %d", len(cfg.ExcludePatterns))
 		}
 	})
 }
+
+func TestParseTimeoutOverrides(t *testing.T) {
+	overrides := ParseTimeoutOverrides(`/downloads/=120s, api\.example\.com=5,[invalid=10s,noequals,/bad=soon,/zero=0`)
+
+	if len(overrides) != 2 {
+		t.Fatalf("Expected 2 valid overrides, got %d", len(overrides))
+	}
+	if overrides[0].Pattern.String() != "/downloads/" || overrides[0].Timeout != 120*time.Second {
+		t.Errorf("Unexpected first override: %s=%v", overrides[0].Pattern, overrides[0].Timeout)
+	}
+	if overrides[1].Pattern.String() != `api\.example\.com` || overrides[1].Timeout != 5*time.Second {
+		t.Errorf("Unexpected second override: %s=%v", overrides[1].Pattern, overrides[1].Timeout)
+	}
+
+	if len(ParseTimeoutOverrides("")) != 0 {
+		t.Error("Expected no overrides for empty input")
+	}
+}
+
+func TestParseList(t *testing.T) {
+	items := ParseList(" a, b ,,c ,")
+	if len(items) != 3 || items[0] != "a" || items[1] != "b" || items[2] != "c" {
+		t.Errorf("Expected [a b c], got %v", items)
+	}
+	if len(ParseList("")) != 0 {
+		t.Error("Expected no items for empty input")
+	}
+}
+
+func TestSplitURLs(t *testing.T) {
+	first, all := SplitURLs("https://example.com, https://docs.example.com\nhttps://blog.example.com")
+	if first != "https://example.com" {
+		t.Errorf("Expected the first URL, got %q", first)
+	}
+	expected := []string{"https://example.com", "https://docs.example.com", "https://blog.example.com"}
+	if !reflect.DeepEqual(all, expected) {
+		t.Errorf("Expected %v, got %v", expected, all)
+	}
+	if first, all := SplitURLs(" "); first != "" || all != nil {
+		t.Errorf("Expected no URLs, got %q and %v", first, all)
+	}
+}
+
+func TestParseStatusExceptions(t *testing.T) {
+	exceptions := ParseStatusExceptions("linkedin.com=999, Example.org=403,example.org=429,bad,host=abc,=404")
+
+	if len(exceptions) != 2 {
+		t.Fatalf("Expected 2 hosts, got %d: %v", len(exceptions), exceptions)
+	}
+	if codes := exceptions["linkedin.com"]; len(codes) != 1 || codes[0] != 999 {
+		t.Errorf("Expected linkedin.com=[999], got %v", codes)
+	}
+	if codes := exceptions["example.org"]; len(codes) != 2 || codes[0] != 403 || codes[1] != 429 {
+		t.Errorf("Expected example.org=[403 429], got %v", codes)
+	}
+}
+
+func TestParsePatterns(t *testing.T) {
+	patterns := ParsePatterns(`/login, accounts\.google\.com,[invalid,`)
+	if len(patterns) != 2 {
+		t.Fatalf("Expected 2 valid patterns, got %d", len(patterns))
+	}
+	if patterns[0].String() != "/login" || patterns[1].String() != `accounts\.google\.com` {
+		t.Errorf("Unexpected patterns: %v", patterns)
+	}
+	if len(ParsePatterns(DefaultLoginPatterns)) != len(ParseList(DefaultLoginPatterns)) {
+		t.Error("Expected every default login pattern to compile")
+	}
+}
+
+func TestParsePathRules(t *testing.T) {
+	rules := ParsePathRules(`^content/(.+)\.md$=/$1/, ^static/(.*)=/$1,[invalid=/x,noequals`)
+
+	if len(rules) != 2 {
+		t.Fatalf("Expected 2 valid rules, got %d", len(rules))
+	}
+	if rules[0].Pattern.String() != `^content/(.+)\.md$` || rules[0].Replacement != "/$1/" {
+		t.Errorf("Unexpected first rule: %s=%s", rules[0].Pattern, rules[0].Replacement)
+	}
+	if rules[1].Pattern.String() != "^static/(.*)" || rules[1].Replacement != "/$1" {
+		t.Errorf("Unexpected second rule: %s=%s", rules[1].Pattern, rules[1].Replacement)
+	}
+}
+
+func TestParseUserAgents(t *testing.T) {
+	agents := ParseUserAgents("Chrome, curl/8.0,firefox")
+	if len(agents) != 3 {
+		t.Fatalf("Expected 3 agents, got %v", agents)
+	}
+	if agents[0] != UserAgentPresets["chrome"] || agents[1] != "curl/8.0" || agents[2] != UserAgentPresets["firefox"] {
+		t.Errorf("Unexpected agents: %v", agents)
+	}
+	if got := ResolveUserAgent("GitHub-Action-Link-Checker/1.0"); got != "GitHub-Action-Link-Checker/1.0" {
+		t.Errorf("Expected a literal user agent to be kept, got %q", got)
+	}
+}
+
+func TestParseUserAgentRules(t *testing.T) {
+	rules := ParseUserAgentRules(`twitter\.com=chrome, api\.example\.com=curl/8.0,[invalid=safari,noequals,empty=`)
+
+	if len(rules) != 2 {
+		t.Fatalf("Expected 2 valid rules, got %d", len(rules))
+	}
+	if rules[0].Pattern.String() != `twitter\.com` || rules[0].UserAgent != UserAgentPresets["chrome"] {
+		t.Errorf("Unexpected first rule: %s=%s", rules[0].Pattern, rules[0].UserAgent)
+	}
+	if rules[1].Pattern.String() != `api\.example\.com` || rules[1].UserAgent != "curl/8.0" {
+		t.Errorf("Unexpected second rule: %s=%s", rules[1].Pattern, rules[1].UserAgent)
+	}
+}
+
+func TestParseAcceptLanguageRules(t *testing.T) {
+	rules := ParseAcceptLanguageRules(`/de/=de-DE, \.fr/=fr,[invalid=en,noequals,empty=`)
+
+	if len(rules) != 2 {
+		t.Fatalf("Expected 2 valid rules, got %d", len(rules))
+	}
+	if rules[0].Pattern.String() != "/de/" || rules[0].Language != "de-DE" {
+		t.Errorf("Unexpected first rule: %s=%s", rules[0].Pattern, rules[0].Language)
+	}
+	if rules[1].Pattern.String() != `\.fr/` || rules[1].Language != "fr" {
+		t.Errorf("Unexpected second rule: %s=%s", rules[1].Pattern, rules[1].Language)
+	}
+}
+
+func TestParseLoginFields(t *testing.T) {
+	os.Setenv("TEST_LOGIN_USER", "alice")
+	os.Setenv("TEST_LOGIN_PASSWORD", "s3cret")
+	defer os.Unsetenv("TEST_LOGIN_USER")
+	defer os.Unsetenv("TEST_LOGIN_PASSWORD")
+
+	fields := ParseLoginFields("username=$TEST_LOGIN_USER, password=${TEST_LOGIN_PASSWORD}, remember=1, note=pa$$word, invalid, =x")
+	expected := map[string]string{
+		"username": "alice",
+		"password": "s3cret",
+		"remember": "1",
+		"note":     "pa$$word",
+	}
+	if len(fields) != len(expected) {
+		t.Errorf("Expected %d fields, got %v", len(expected), fields)
+	}
+	for name, value := range expected {
+		if got := fields.Get(name); got != value {
+			t.Errorf("Expected %s=%q, got %q", name, value, got)
+		}
+	}
+
+	if fields := ParseLoginFields(""); fields != nil {
+		t.Errorf("Expected no fields, got %v", fields)
+	}
+}
+
+func TestParseSeverityRules(t *testing.T) {
+	rules := ParseSeverityRules("404=Error, 5xx=warning,auth_required=info,timeout=fatal,=info,invalid")
+	expected := []SeverityRule{
+		{StatusCode: 404, Severity: "error"},
+		{Category: "5xx", Severity: "warning"},
+		{Category: "auth_required", Severity: "info"},
+	}
+	if len(rules) != len(expected) {
+		t.Fatalf("Expected %d rules, got %+v", len(expected), rules)
+	}
+	for i, rule := range rules {
+		if rule != expected[i] {
+			t.Errorf("Rule %d: expected %+v, got %+v", i, expected[i], rule)
+		}
+	}
+}
+
+func TestParseFailPolicy(t *testing.T) {
+	policy := ParseFailPolicy("status:4xx, 503; Category:internal\ncount>10;bogus:x;count<3;status:")
+	expected := &FailPolicy{Statuses: []string{"4xx", "503"}, Categories: []string{"internal"}, MaxCount: 10}
+	if !reflect.DeepEqual(policy, expected) {
+		t.Errorf("Expected %+v, got %+v", expected, policy)
+	}
+	if got := policy.String(); got != "status:4xx,503; category:internal; count>10" {
+		t.Errorf("Expected the policy to format as conditions, got %q", got)
+	}
+
+	if policy := ParseFailPolicy("count>=5"); policy == nil || policy.MaxCount != 4 {
+		t.Errorf("Expected count>=5 to tolerate 4 links, got %+v", policy)
+	}
+	for _, value := range []string{"", "count>=0", "count>x", "nothing"} {
+		if policy := ParseFailPolicy(value); policy != nil {
+			t.Errorf("Expected no policy for %q, got %+v", value, policy)
+		}
+	}
+}
+
+func TestParseCookies(t *testing.T) {
+	os.Setenv("TEST_SESSION_ID", "s3cret")
+	defer os.Unsetenv("TEST_SESSION_ID")
+
+	cookies := ParseCookies("session=$TEST_SESSION_ID; Domain=example.com; Path=/; Secure\n\nlocale = en\ninvalid\n=novalue")
+	if len(cookies) != 2 {
+		t.Fatalf("Expected 2 cookies, got %v", cookies)
+	}
+	session := cookies[0]
+	if session.Name != "session" || session.Value != "s3cret" || session.Domain != "example.com" || session.Path != "/" || !session.Secure {
+		t.Errorf("Unexpected session cookie %+v", session)
+	}
+	if cookies[1].Name != "locale" || cookies[1].Value != "en" {
+		t.Errorf("Unexpected locale cookie %+v", cookies[1])
+	}
+}
+
+func TestParseSchemePolicy(t *testing.T) {
+	policy := ParseSchemePolicy("FTP=report, mailto=ignore, tel=check, file=skip, *=report, http=ignore, =report, data=FAIL")
+	expected := map[string]string{
+		"http":   "ignore",
+		"https":  "check",
+		"ftp":    "report",
+		"mailto": "ignore",
+		"data":   "fail",
+		"*":      "report",
+	}
+	if len(policy) != len(expected) {
+		t.Errorf("Expected %v, got %v", expected, policy)
+	}
+	for scheme, action := range expected {
+		if policy[scheme] != action {
+			t.Errorf("Expected %s=%s, got %q", scheme, action, policy[scheme])
+		}
+	}
+
+	defaults := ParseSchemePolicy("")
+	if len(defaults) != 3 || defaults["http"] != "check" || defaults["https"] != "check" || defaults["*"] != "ignore" {
+		t.Errorf("Expected the default policy, got %v", defaults)
+	}
+}
+
+func TestParseDurationSetting(t *testing.T) {
+	tests := []struct {
+		value    string
+		expected time.Duration
+		wantErr  bool
+	}{
+		{"", 0, false},
+		{"0", 0, false},
+		{"720h", 720 * time.Hour, false},
+		{"90", 90 * time.Second, false},
+		{"30 days", 0, true},
+		{"7x", 0, true},
+		{"-1h", 0, true},
+	}
+	for _, tt := range tests {
+		duration, err := ParseDurationSetting(tt.value)
+		if (err != nil) != tt.wantErr || duration != tt.expected {
+			t.Errorf("ParseDurationSetting(%q) = %s, %v; expected %s, error %v", tt.value, duration, err, tt.expected, tt.wantErr)
+		}
+	}
+}
+
+func TestParsePercent(t *testing.T) {
+	tests := []struct {
+		value    string
+		expected float64
+		wantErr  bool
+	}{
+		{"", 0, false},
+		{"0", 0, false},
+		{"2.5", 2.5, false},
+		{"100", 100, false},
+		{"ten", 0, true},
+		{"150", 0, true},
+		{"-5", 0, true},
+	}
+	for _, tt := range tests {
+		percent, err := ParsePercent(tt.value)
+		if (err != nil) != tt.wantErr || percent != tt.expected {
+			t.Errorf("ParsePercent(%q) = %v, %v; expected %v, error %v", tt.value, percent, err, tt.expected, tt.wantErr)
+		}
+	}
+}
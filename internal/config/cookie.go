@@ -0,0 +1,32 @@
+package config
+
+import (
+	"net/http"
+	"strings"
+)
+
+// ParseCookies parses cookies to send with every request, one per line in
+// Set-Cookie form, e.g. "session=$SESSION_ID; Domain=example.com; Path=/".
+// As with login fields, a value of $NAME or ${NAME} is read from the
+// environment variable NAME. Invalid lines are ignored.
+func ParseCookies(value string) []*http.Cookie {
+	var cookies []*http.Cookie
+	for _, line := range strings.Split(value, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		name, rest, _ := strings.Cut(line, ";")
+		name, cookieValue, found := strings.Cut(name, "=")
+		if !found {
+			continue
+		}
+		cookieValue = expandLoginValue(strings.TrimSpace(cookieValue))
+		cookie, err := http.ParseSetCookie(strings.TrimSpace(name) + "=" + cookieValue + ";" + rest)
+		if err != nil {
+			continue
+		}
+		cookies = append(cookies, cookie)
+	}
+	return cookies
+}
@@ -0,0 +1,80 @@
+package config
+
+import (
+	"strconv"
+	"strings"
+)
+
+// FailPolicy decides when failing links fail the run. Statuses and
+// Categories narrow the links that count, matching every link when empty, and
+// the run fails when more than MaxCount links match.
+type FailPolicy struct {
+	Statuses   []string
+	Categories []string
+	MaxCount   int
+}
+
+// ParseFailPolicy parses fail-on conditions separated by semicolons or
+// newlines, e.g. "status:4xx,5xx; category:internal; count>10". A status
+// condition lists status codes or fail-on-categories categories, a category
+// condition lists link categories, and a count condition sets how many
+// matching links are tolerated, with count>=N meaning count>N-1. It returns
+// nil when there are no valid conditions. Invalid conditions are ignored.
+func ParseFailPolicy(value string) *FailPolicy {
+	var policy FailPolicy
+	valid := false
+	for _, condition := range strings.FieldsFunc(value, func(r rune) bool { return r == ';' || r == '\n' }) {
+		condition = strings.ToLower(strings.TrimSpace(condition))
+		if kind, values, found := strings.Cut(condition, ":"); found {
+			items := ParseList(values)
+			if len(items) == 0 {
+				continue
+			}
+			switch strings.TrimSpace(kind) {
+			case "status":
+				policy.Statuses = append(policy.Statuses, items...)
+			case "category":
+				policy.Categories = append(policy.Categories, items...)
+			default:
+				continue
+			}
+			valid = true
+			continue
+		}
+
+		if rest, ok := strings.CutPrefix(condition, "count"); ok {
+			rest = strings.TrimSpace(rest)
+			offset := 0
+			if strings.HasPrefix(rest, ">=") {
+				rest, offset = rest[2:], 1
+			} else if strings.HasPrefix(rest, ">") {
+				rest = rest[1:]
+			} else {
+				continue
+			}
+			count, err := strconv.Atoi(strings.TrimSpace(rest))
+			if err != nil || count-offset < 0 {
+				continue
+			}
+			policy.MaxCount = count - offset
+			valid = true
+		}
+	}
+	if !valid {
+		return nil
+	}
+	return &policy
+}
+
+// String formats the policy as fail-on conditions
+func (p *FailPolicy) String() string {
+	var conditions []string
+	if len(p.Statuses) > 0 {
+		conditions = append(conditions, "status:"+strings.Join(p.Statuses, ","))
+	}
+	if len(p.Categories) > 0 {
+		conditions = append(conditions, "category:"+strings.Join(p.Categories, ","))
+	}
+	conditions = append(conditions, "count>"+strconv.Itoa(p.MaxCount))
+	return strings.Join(conditions, "; ")
+}
@@ -0,0 +1,105 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// File is a config file holding settings shared by every workflow plus named
+// profiles (e.g. "production", "staging", "pr-preview") that override them.
+// Keys are input names such as "base-url" or "exclude-patterns".
+type File struct {
+	Settings map[string]any            `json:"settings"`
+	Profiles map[string]map[string]any `json:"profiles"`
+}
+
+// LoadFile reads a JSON config file
+func LoadFile(path string) (*File, error) {
+	data, err := os.ReadFile(path) // #nosec G304 -- path is provided by the user
+	if err != nil {
+		return nil, fmt.Errorf("reading config file: %w", err)
+	}
+
+	var file File
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("parsing config file %s: %w", path, err)
+	}
+	return &file, nil
+}
+
+// Resolve returns the settings for the named profile layered over the shared
+// settings, with every value converted to its input string form. An empty
+// profile name returns the shared settings alone.
+func (f *File) Resolve(profile string) (map[string]string, error) {
+	resolved := make(map[string]string)
+	if err := mergeSettings(resolved, f.Settings); err != nil {
+		return nil, err
+	}
+
+	if profile == "" {
+		return resolved, nil
+	}
+	overrides, ok := f.Profiles[profile]
+	if !ok {
+		names := make([]string, 0, len(f.Profiles))
+		for name := range f.Profiles {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		return nil, fmt.Errorf("unknown profile %q (available: %s)", profile, strings.Join(names, ", "))
+	}
+	if err := mergeSettings(resolved, overrides); err != nil {
+		return nil, fmt.Errorf("profile %s: %w", profile, err)
+	}
+	return resolved, nil
+}
+
+// InputEnv returns the environment variable GitHub Actions uses for an input
+func InputEnv(name string) string {
+	return "INPUT_" + strings.ToUpper(strings.ReplaceAll(name, "-", "_"))
+}
+
+// mergeSettings copies settings into dst, converting values the way they
+// would be written as action inputs. Lists are joined with commas.
+func mergeSettings(dst map[string]string, settings map[string]any) error {
+	for name, value := range settings {
+		converted, err := settingString(value)
+		if err != nil {
+			return fmt.Errorf("setting %s: %w", name, err)
+		}
+		dst[name] = converted
+	}
+	return nil
+}
+
+func settingString(value any) (string, error) {
+	switch v := value.(type) {
+	case string:
+		return v, nil
+	case bool:
+		return strconv.FormatBool(v), nil
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), nil
+	case nil:
+		return "", nil
+	case []any:
+		items := make([]string, 0, len(v))
+		for _, item := range v {
+			converted, err := settingString(item)
+			if err != nil {
+				return "", err
+			}
+			if _, nested := item.([]any); nested {
+				return "", fmt.Errorf("nested lists are not supported")
+			}
+			items = append(items, converted)
+		}
+		return strings.Join(items, ","), nil
+	default:
+		return "", fmt.Errorf("unsupported value %v", value)
+	}
+}
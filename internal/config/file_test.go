@@ -0,0 +1,101 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeConfigFile(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "link-checker.json")
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoadFile(t *testing.T) {
+	path := writeConfigFile(t, `{
+  "settings": {
+    "base-url": "https://example.com",
+    "max-depth": 3,
+    "verbose": false,
+    "exclude-patterns": [".*\\.pdf$", "/admin/"]
+  },
+  "profiles": {
+    "staging": {"base-url": "https://staging.example.com", "fail-on-error": false},
+    "pr-preview": {"max-depth": 1}
+  }
+}`)
+
+	file, err := LoadFile(path)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	t.Run("shared settings", func(t *testing.T) {
+		settings, err := file.Resolve("")
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		expected := map[string]string{
+			"base-url":         "https://example.com",
+			"max-depth":        "3",
+			"verbose":          "false",
+			"exclude-patterns": `.*\.pdf$,/admin/`,
+		}
+		if len(settings) != len(expected) {
+			t.Errorf("Expected %d settings, got %v", len(expected), settings)
+		}
+		for name, value := range expected {
+			if settings[name] != value {
+				t.Errorf("Setting %s: expected %q, got %q", name, value, settings[name])
+			}
+		}
+	})
+
+	t.Run("profile overrides", func(t *testing.T) {
+		settings, err := file.Resolve("staging")
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if settings["base-url"] != "https://staging.example.com" {
+			t.Errorf("Expected profile base URL, got %q", settings["base-url"])
+		}
+		if settings["fail-on-error"] != "false" {
+			t.Errorf("Expected profile-only setting, got %q", settings["fail-on-error"])
+		}
+		if settings["max-depth"] != "3" {
+			t.Errorf("Expected shared setting to be kept, got %q", settings["max-depth"])
+		}
+	})
+
+	t.Run("unknown profile", func(t *testing.T) {
+		_, err := file.Resolve("production")
+		if err == nil || !strings.Contains(err.Error(), "pr-preview, staging") {
+			t.Errorf("Expected an error listing the available profiles, got %v", err)
+		}
+	})
+}
+
+func TestLoadFileErrors(t *testing.T) {
+	if _, err := LoadFile(filepath.Join(t.TempDir(), "missing.json")); err == nil {
+		t.Error("Expected an error for a missing file")
+	}
+
+	file, err := LoadFile(writeConfigFile(t, `{"settings": {"headers": {"X-Token": "secret"}}}`))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if _, err := file.Resolve(""); err == nil {
+		t.Error("Expected an error for an object value")
+	}
+}
+
+func TestInputEnv(t *testing.T) {
+	if got := InputEnv("changed-files-only"); got != "INPUT_CHANGED_FILES_ONLY" {
+		t.Errorf("Expected INPUT_CHANGED_FILES_ONLY, got %s", got)
+	}
+}
@@ -0,0 +1,42 @@
+package config
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// DefaultIgnoreFile is read when present, so repositories migrating from
+// lychee keep their exclusions
+const DefaultIgnoreFile = ".lycheeignore"
+
+// LoadIgnoreFile reads a lychee-style ignore file: one regular expression
+// per line matched against each URL, with blank lines and lines starting
+// with "#" skipped. Unlike exclude-patterns, invalid patterns are reported.
+func LoadIgnoreFile(path string) ([]*regexp.Regexp, error) {
+	f, err := os.Open(path) // #nosec G304 -- path is provided by the user
+	if err != nil {
+		return nil, fmt.Errorf("reading ignore file: %w", err)
+	}
+	defer f.Close()
+
+	var patterns []*regexp.Regexp
+	scanner := bufio.NewScanner(f)
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		pattern, err := regexp.Compile(line)
+		if err != nil {
+			return nil, fmt.Errorf("%s:%d: invalid pattern: %w", path, lineNumber, err)
+		}
+		patterns = append(patterns, pattern)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("reading ignore file: %w", err)
+	}
+	return patterns, nil
+}
@@ -0,0 +1,53 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLoadIgnoreFile(t *testing.T) {
+	dir := t.TempDir()
+
+	t.Run("patterns", func(t *testing.T) {
+		path := filepath.Join(dir, ".lycheeignore")
+		content := "# Social sites block crawlers\nhttps://(www\\.)?linkedin\\.com\n\n  ^mailto:  \n"
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+
+		patterns, err := LoadIgnoreFile(path)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if len(patterns) != 2 {
+			t.Fatalf("Expected 2 patterns, got %d", len(patterns))
+		}
+		if !patterns[0].MatchString("https://www.linkedin.com/in/someone") {
+			t.Error("Expected the first pattern to match LinkedIn")
+		}
+		if patterns[1].String() != "^mailto:" {
+			t.Errorf("Expected surrounding whitespace to be trimmed, got %q", patterns[1].String())
+		}
+	})
+
+	t.Run("invalid pattern", func(t *testing.T) {
+		path := filepath.Join(dir, "invalid")
+		if err := os.WriteFile(path, []byte("ok\n(unclosed\n"), 0o600); err != nil {
+			t.Fatal(err)
+		}
+
+		_, err := LoadIgnoreFile(path)
+		if err == nil || !strings.Contains(err.Error(), "invalid:2") {
+			t.Errorf("Expected an error naming the line, got %v", err)
+		}
+	})
+
+	t.Run("missing file", func(t *testing.T) {
+		if _, err := LoadIgnoreFile(filepath.Join(dir, "missing")); !os.IsNotExist(errors.Unwrap(err)) {
+			t.Errorf("Expected a not-exist error, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,33 @@
+package config
+
+import (
+	"regexp"
+	"strings"
+)
+
+// AcceptLanguageRule sends Language as the Accept-Language header to URLs
+// matching Pattern
+type AcceptLanguageRule struct {
+	Pattern  *regexp.Regexp
+	Language string
+}
+
+// ParseAcceptLanguageRules parses a comma-separated list of pattern=language
+// rules, e.g. `/de/=de-DE,\.fr/=fr`. Each rule sends a single language tag.
+// Invalid entries are ignored.
+func ParseAcceptLanguageRules(value string) []AcceptLanguageRule {
+	var rules []AcceptLanguageRule
+	for _, entry := range ParseList(value) {
+		idx := strings.LastIndex(entry, "=")
+		if idx <= 0 {
+			continue
+		}
+		language := strings.TrimSpace(entry[idx+1:])
+		regex, err := regexp.Compile(strings.TrimSpace(entry[:idx]))
+		if err != nil || language == "" {
+			continue
+		}
+		rules = append(rules, AcceptLanguageRule{Pattern: regex, Language: language})
+	}
+	return rules
+}
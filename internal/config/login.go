@@ -0,0 +1,53 @@
+package config
+
+import (
+	"net/url"
+	"os"
+	"strings"
+)
+
+// ParseLoginFields parses a comma-separated list of name=value form fields,
+// e.g. "username=$LOGIN_USER,password=$LOGIN_PASSWORD". A value of $NAME or
+// ${NAME} is read from the environment variable NAME, so credentials can
+// come from secrets without appearing in the workflow. Invalid entries are
+// ignored.
+func ParseLoginFields(value string) url.Values {
+	fields := make(url.Values)
+	for _, entry := range ParseList(value) {
+		name, fieldValue, found := strings.Cut(entry, "=")
+		name = strings.TrimSpace(name)
+		if !found || name == "" {
+			continue
+		}
+		fields.Set(name, expandLoginValue(strings.TrimSpace(fieldValue)))
+	}
+	if len(fields) == 0 {
+		return nil
+	}
+	return fields
+}
+
+// expandLoginValue replaces a value that names an environment variable with
+// that variable's value. Other values, including ones merely containing a $,
+// are kept as written.
+func expandLoginValue(value string) string {
+	name, ok := strings.CutPrefix(value, "$")
+	if !ok {
+		return value
+	}
+	if braced, ok := strings.CutPrefix(name, "{"); ok {
+		name, ok = strings.CutSuffix(braced, "}")
+		if !ok {
+			return value
+		}
+	}
+	for _, ch := range name {
+		if ch != '_' && (ch < 'A' || ch > 'Z') && (ch < 'a' || ch > 'z') && (ch < '0' || ch > '9') {
+			return value
+		}
+	}
+	if name == "" {
+		return value
+	}
+	return os.Getenv(name)
+}
@@ -0,0 +1,44 @@
+package config
+
+import "strings"
+
+// Scheme actions used in a scheme policy
+const (
+	SchemeCheck  = "check"
+	SchemeReport = "report"
+	SchemeIgnore = "ignore"
+	SchemeFail   = "fail"
+)
+
+// DefaultSchemePolicy checks http and https links and silently ignores links
+// with any other scheme
+const DefaultSchemePolicy = "http=check,https=check,*=ignore"
+
+// ParseSchemePolicy parses a comma-separated list of scheme=action entries,
+// e.g. "ftp=fail,mailto=ignore", over DefaultSchemePolicy. Actions are
+// check, report (flag the link as an unsupported scheme), fail (report the
+// link as broken) and ignore; the * scheme sets the action for schemes that
+// aren't listed. Only http and https can be checked. Invalid entries are
+// ignored.
+func ParseSchemePolicy(value string) map[string]string {
+	policy := make(map[string]string)
+	for _, entry := range ParseList(DefaultSchemePolicy + "," + value) {
+		scheme, action, found := strings.Cut(entry, "=")
+		scheme = strings.ToLower(strings.TrimSpace(scheme))
+		action = strings.ToLower(strings.TrimSpace(action))
+		if !found || scheme == "" {
+			continue
+		}
+		switch action {
+		case SchemeCheck:
+			if scheme != "http" && scheme != "https" {
+				continue
+			}
+		case SchemeReport, SchemeIgnore, SchemeFail:
+		default:
+			continue
+		}
+		policy[scheme] = action
+	}
+	return policy
+}
@@ -0,0 +1,46 @@
+package config
+
+import (
+	"strconv"
+	"strings"
+)
+
+// SeverityRule assigns Severity to flagged results with StatusCode, or when
+// StatusCode is zero, to results whose error type or link category matches
+// Category
+type SeverityRule struct {
+	Category   string
+	StatusCode int
+	Severity   string
+}
+
+// ParseSeverityRules parses a comma-separated list of match=severity rules,
+// e.g. "404=error,5xx=warning,external=warning". A match is a status code, a
+// fail-on-categories category or a link category such as internal or
+// external. Severities are error, warning or info.
+// Invalid entries are ignored.
+func ParseSeverityRules(value string) []SeverityRule {
+	var rules []SeverityRule
+	for _, entry := range ParseList(value) {
+		match, severity, found := strings.Cut(entry, "=")
+		match = strings.ToLower(strings.TrimSpace(match))
+		severity = strings.ToLower(strings.TrimSpace(severity))
+		if !found || match == "" {
+			continue
+		}
+		switch severity {
+		case "error", "warning", "info":
+		default:
+			continue
+		}
+
+		rule := SeverityRule{Severity: severity}
+		if statusCode, err := strconv.Atoi(match); err == nil {
+			rule.StatusCode = statusCode
+		} else {
+			rule.Category = match
+		}
+		rules = append(rules, rule)
+	}
+	return rules
+}
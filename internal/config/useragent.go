@@ -0,0 +1,63 @@
+package config
+
+import (
+	"regexp"
+	"strings"
+)
+
+// UserAgentPresets are realistic browser user agents that can be named in
+// place of a full user agent string. Many sites reject unknown bots with a
+// 403 while serving the same page to browsers.
+var UserAgentPresets = map[string]string{
+	"chrome":  "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/131.0.0.0 Safari/537.36",
+	"edge":    "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/131.0.0.0 Safari/537.36 Edg/131.0.0.0",
+	"firefox": "Mozilla/5.0 (Windows NT 10.0; Win64; x64; rv:133.0) Gecko/20100101 Firefox/133.0",
+	"safari":  "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/18.1 Safari/605.1.15",
+	"mobile":  "Mozilla/5.0 (iPhone; CPU iPhone OS 18_1 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/18.1 Mobile/15E148 Safari/604.1",
+}
+
+// UserAgentRule sends UserAgent to URLs matching Pattern
+type UserAgentRule struct {
+	Pattern   *regexp.Regexp
+	UserAgent string
+}
+
+// ResolveUserAgent expands a preset name to its user agent string, returning
+// any other value unchanged
+func ResolveUserAgent(value string) string {
+	if preset, ok := UserAgentPresets[strings.ToLower(strings.TrimSpace(value))]; ok {
+		return preset
+	}
+	return value
+}
+
+// ParseUserAgents parses a comma-separated list of user agents to rotate
+// through. Since browser user agents contain commas, entries other than
+// single-token agents such as "curl/8.0" should be preset names.
+func ParseUserAgents(value string) []string {
+	var agents []string
+	for _, agent := range ParseList(value) {
+		agents = append(agents, ResolveUserAgent(agent))
+	}
+	return agents
+}
+
+// ParseUserAgentRules parses a comma-separated list of pattern=agent rules,
+// e.g. `twitter\.com=chrome,api\.example\.com=curl/8.0`. Agents are preset
+// names or user agent strings without commas. Invalid entries are ignored.
+func ParseUserAgentRules(value string) []UserAgentRule {
+	var rules []UserAgentRule
+	for _, entry := range ParseList(value) {
+		idx := strings.LastIndex(entry, "=")
+		if idx <= 0 {
+			continue
+		}
+		agent := strings.TrimSpace(entry[idx+1:])
+		regex, err := regexp.Compile(strings.TrimSpace(entry[:idx]))
+		if err != nil || agent == "" {
+			continue
+		}
+		rules = append(rules, UserAgentRule{Pattern: regex, UserAgent: ResolveUserAgent(agent)})
+	}
+	return rules
+}
@@ -0,0 +1,84 @@
+package github
+
+import (
+	"fmt"
+	"strings"
+)
+
+// maxCommentPages caps pagination of pull request comments
+const maxCommentPages = 10
+
+// maxCommentLength is the longest comment body the API accepts
+const maxCommentLength = 65536
+
+// Comment is a comment on an issue or pull request
+type Comment struct {
+	ID   int64  `json:"id"`
+	Body string `json:"body"`
+}
+
+// IssueComments lists the comments on an issue or pull request
+func (c *Client) IssueComments(repo string, number int) ([]Comment, error) {
+	var comments []Comment
+	for page := 1; page <= maxCommentPages; page++ {
+		var batch []Comment
+		path := fmt.Sprintf("/repos/%s/issues/%d/comments?per_page=100&page=%d", repo, number, page)
+		if err := c.get(path, &batch); err != nil {
+			return nil, fmt.Errorf("listing comments: %w", err)
+		}
+		comments = append(comments, batch...)
+		if len(batch) < 100 {
+			break
+		}
+	}
+	return comments, nil
+}
+
+// CreateComment adds a comment to an issue or pull request
+func (c *Client) CreateComment(repo string, number int, body string) error {
+	path := fmt.Sprintf("/repos/%s/issues/%d/comments", repo, number)
+	if err := c.do("POST", path, map[string]string{"body": body}, nil); err != nil {
+		return fmt.Errorf("creating comment: %w", err)
+	}
+	return nil
+}
+
+// UpdateComment replaces the body of a comment
+func (c *Client) UpdateComment(repo string, id int64, body string) error {
+	path := fmt.Sprintf("/repos/%s/issues/comments/%d", repo, id)
+	if err := c.do("PATCH", path, map[string]string{"body": body}, nil); err != nil {
+		return fmt.Errorf("updating comment: %w", err)
+	}
+	return nil
+}
+
+// FindComment returns the first comment on an issue or pull request that
+// contains marker, or nil when there is none
+func (c *Client) FindComment(repo string, number int, marker string) (*Comment, error) {
+	comments, err := c.IssueComments(repo, number)
+	if err != nil {
+		return nil, err
+	}
+	for i := range comments {
+		if strings.Contains(comments[i].Body, marker) {
+			return &comments[i], nil
+		}
+	}
+	return nil, nil
+}
+
+// StickyCommentBody returns body prefixed with marker, such as a hidden
+// "<!-- name -->" HTML comment, so the comment can be found and updated on
+// later runs. Bodies too long for a comment are cut off with a note.
+func StickyCommentBody(marker, body string) string {
+	full := marker + "\n" + body
+	if len(full) <= maxCommentLength {
+		return full
+	}
+	const note = "\n\n…truncated, see the job summary for the full report\n"
+	cut := full[:maxCommentLength-len(note)]
+	if i := strings.LastIndexByte(cut, '\n'); i > len(marker) {
+		cut = cut[:i]
+	}
+	return cut + note
+}
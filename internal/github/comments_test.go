@@ -0,0 +1,67 @@
+package github
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestComments(t *testing.T) {
+	var created, updated map[string]string
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /repos/owner/site/issues/7/comments", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `[{"id": 1, "body": "Looks good"}, {"id": 2, "body": "<!-- report -->\nOld report"}]`)
+	})
+	mux.HandleFunc("POST /repos/owner/site/issues/7/comments", func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&created)
+		w.WriteHeader(http.StatusCreated)
+	})
+	mux.HandleFunc("PATCH /repos/owner/site/issues/comments/2", func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&updated)
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	client := NewClient(server.URL, "secret")
+
+	comment, err := client.FindComment("owner/site", 7, "<!-- report -->")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if comment == nil || comment.ID != 2 {
+		t.Fatalf("Expected the marked comment, got %+v", comment)
+	}
+	if missing, err := client.FindComment("owner/site", 7, "<!-- other -->"); err != nil || missing != nil {
+		t.Errorf("Expected no comment for another marker, got %+v (%v)", missing, err)
+	}
+
+	if err := client.UpdateComment("owner/site", comment.ID, "New report"); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if updated["body"] != "New report" {
+		t.Errorf("Unexpected update request: %v", updated)
+	}
+	if err := client.CreateComment("owner/site", 7, "First report"); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if created["body"] != "First report" {
+		t.Errorf("Unexpected create request: %v", created)
+	}
+}
+
+func TestStickyCommentBody(t *testing.T) {
+	if body := StickyCommentBody("<!-- report -->", "Report"); body != "<!-- report -->\nReport" {
+		t.Errorf("Expected the marker to lead the body, got %q", body)
+	}
+
+	long := StickyCommentBody("<!-- report -->", strings.Repeat("| row |\n", maxCommentLength/4))
+	if len(long) > maxCommentLength {
+		t.Errorf("Expected at most %d bytes, got %d", maxCommentLength, len(long))
+	}
+	if !strings.HasPrefix(long, "<!-- report -->\n") || !strings.Contains(long, "| row |\n\n…truncated") {
+		t.Errorf("Expected a truncated body cut at a line, got ...%q", long[len(long)-80:])
+	}
+}
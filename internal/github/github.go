@@ -0,0 +1,137 @@
+// Package github is a minimal client for the parts of the GitHub REST API
+// the link checker uses when running on pull requests.
+package github
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+)
+
+// DefaultAPIURL is used when GITHUB_API_URL is not set
+const DefaultAPIURL = "https://api.github.com"
+
+// maxFilePages caps pagination of pull request files; the API itself stops
+// listing files after 3000
+const maxFilePages = 30
+
+// Client calls the GitHub REST API
+type Client struct {
+	apiURL     string
+	token      string
+	httpClient *http.Client
+}
+
+// NewClient creates a client for the API at apiURL, authenticating with token
+// when it is not empty
+func NewClient(apiURL, token string) *Client {
+	if apiURL == "" {
+		apiURL = DefaultAPIURL
+	}
+	return &Client{
+		apiURL:     strings.TrimSuffix(apiURL, "/"),
+		token:      token,
+		httpClient: &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// ChangedFile is a file touched by a pull request
+type ChangedFile struct {
+	Filename string `json:"filename"`
+	Status   string `json:"status"`
+}
+
+// PullRequestFiles lists the files changed by a pull request. Repo is in
+// owner/name form.
+func (c *Client) PullRequestFiles(repo string, number int) ([]ChangedFile, error) {
+	var files []ChangedFile
+	for page := 1; page <= maxFilePages; page++ {
+		var batch []ChangedFile
+		path := fmt.Sprintf("/repos/%s/pulls/%d/files?per_page=100&page=%d", repo, number, page)
+		if err := c.get(path, &batch); err != nil {
+			return nil, fmt.Errorf("listing pull request files: %w", err)
+		}
+		files = append(files, batch...)
+		if len(batch) < 100 {
+			break
+		}
+	}
+	return files, nil
+}
+
+// get fetches an API path and decodes the JSON response into v
+func (c *Client) get(path string, v any) error {
+	return c.do("GET", path, nil, v)
+}
+
+// do sends a request with an optional JSON body and decodes the JSON
+// response into v when it is not nil
+func (c *Client) do(method, path string, body, v any) error {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequest(method, c.apiURL+path, reader)
+	if err != nil {
+		return err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("X-GitHub-Api-Version", "2022-11-28")
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("GitHub API returned status %d for %s %s", resp.StatusCode, method, path)
+	}
+	if v == nil {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(v)
+}
+
+// PullRequestNumber reads the pull request number from a workflow event
+// payload, such as the file at GITHUB_EVENT_PATH
+func PullRequestNumber(eventPath string) (int, error) {
+	data, err := os.ReadFile(eventPath) // #nosec G304 -- path is provided by the runner
+	if err != nil {
+		return 0, fmt.Errorf("reading event payload: %w", err)
+	}
+
+	var event struct {
+		Number      int `json:"number"`
+		PullRequest struct {
+			Number int `json:"number"`
+		} `json:"pull_request"`
+	}
+	if err := json.Unmarshal(data, &event); err != nil {
+		return 0, fmt.Errorf("parsing event payload: %w", err)
+	}
+
+	if event.PullRequest.Number != 0 {
+		return event.PullRequest.Number, nil
+	}
+	if event.Number != 0 {
+		return event.Number, nil
+	}
+	return 0, fmt.Errorf("event payload is not for a pull request")
+}
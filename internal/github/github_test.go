@@ -0,0 +1,87 @@
+package github
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestPullRequestFiles(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/repos/owner/site/pulls/7/files" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		if r.Header.Get("Authorization") != "Bearer secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		// Two full pages followed by a partial one
+		var files []ChangedFile
+		count := 100
+		if r.URL.Query().Get("page") == "3" {
+			count = 5
+		}
+		for i := 0; i < count; i++ {
+			files = append(files, ChangedFile{Filename: fmt.Sprintf("content/%s-%d.md", r.URL.Query().Get("page"), i), Status: "modified"})
+		}
+		json.NewEncoder(w).Encode(files)
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL+"/", "secret")
+	files, err := client.PullRequestFiles("owner/site", 7)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(files) != 205 {
+		t.Errorf("Expected 205 files across pages, got %d", len(files))
+	}
+	if files[0].Filename != "content/1-0.md" || files[0].Status != "modified" {
+		t.Errorf("Unexpected first file: %+v", files[0])
+	}
+
+	if _, err := NewClient(server.URL, "").PullRequestFiles("owner/site", 7); err == nil {
+		t.Error("Expected an error for an unauthorized request")
+	}
+}
+
+func TestPullRequestNumber(t *testing.T) {
+	dir := t.TempDir()
+
+	testCases := []struct {
+		name     string
+		payload  string
+		expected int
+		wantErr  bool
+	}{
+		{"pull_request event", `{"number": 12, "pull_request": {"number": 12}}`, 12, false},
+		{"issue comment style", `{"number": 34}`, 34, false},
+		{"push event", `{"ref": "refs/heads/main"}`, 0, true},
+		{"invalid json", `{`, 0, true},
+	}
+
+	for i, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			path := filepath.Join(dir, fmt.Sprintf("event-%d.json", i))
+			os.WriteFile(path, []byte(tc.payload), 0o600)
+
+			number, err := PullRequestNumber(path)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("Expected error %v, got %v", tc.wantErr, err)
+			}
+			if number != tc.expected {
+				t.Errorf("Expected %d, got %d", tc.expected, number)
+			}
+		})
+	}
+
+	if _, err := PullRequestNumber(filepath.Join(dir, "missing.json")); err == nil {
+		t.Error("Expected an error for a missing payload")
+	}
+}
@@ -0,0 +1,108 @@
+package github
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// PullRequest describes a pull request to open
+type PullRequest struct {
+	Title string `json:"title"`
+	Head  string `json:"head"`
+	Base  string `json:"base"`
+	Body  string `json:"body"`
+}
+
+// DefaultBranch returns the name of a repository's default branch
+func (c *Client) DefaultBranch(repo string) (string, error) {
+	var repository struct {
+		DefaultBranch string `json:"default_branch"`
+	}
+	if err := c.get("/repos/"+repo, &repository); err != nil {
+		return "", fmt.Errorf("reading repository: %w", err)
+	}
+	return repository.DefaultBranch, nil
+}
+
+// BranchSHA returns the commit a branch points to
+func (c *Client) BranchSHA(repo, branch string) (string, error) {
+	var ref struct {
+		Object struct {
+			SHA string `json:"sha"`
+		} `json:"object"`
+	}
+	if err := c.get("/repos/"+repo+"/git/ref/heads/"+escapePath(branch), &ref); err != nil {
+		return "", fmt.Errorf("reading branch %s: %w", branch, err)
+	}
+	return ref.Object.SHA, nil
+}
+
+// CreateBranch creates a branch pointing at the given commit
+func (c *Client) CreateBranch(repo, branch, sha string) error {
+	body := map[string]string{"ref": "refs/heads/" + branch, "sha": sha}
+	if err := c.do("POST", "/repos/"+repo+"/git/refs", body, nil); err != nil {
+		return fmt.Errorf("creating branch %s: %w", branch, err)
+	}
+	return nil
+}
+
+// FileContents returns a file's content at ref along with its blob SHA,
+// which UpdateFile needs to replace it
+func (c *Client) FileContents(repo, path, ref string) ([]byte, string, error) {
+	var file struct {
+		Content  string `json:"content"`
+		Encoding string `json:"encoding"`
+		SHA      string `json:"sha"`
+	}
+	apiPath := "/repos/" + repo + "/contents/" + escapePath(path) + "?ref=" + url.QueryEscape(ref)
+	if err := c.get(apiPath, &file); err != nil {
+		return nil, "", fmt.Errorf("reading %s: %w", path, err)
+	}
+	if file.Encoding != "base64" {
+		return nil, "", fmt.Errorf("reading %s: unsupported encoding %q", path, file.Encoding)
+	}
+
+	// The API wraps base64 content across lines
+	content, err := base64.StdEncoding.DecodeString(strings.ReplaceAll(file.Content, "\n", ""))
+	if err != nil {
+		return nil, "", fmt.Errorf("decoding %s: %w", path, err)
+	}
+	return content, file.SHA, nil
+}
+
+// UpdateFile commits new content for a file to branch, replacing the blob
+// with the given SHA
+func (c *Client) UpdateFile(repo, path, branch, message string, content []byte, sha string) error {
+	body := map[string]string{
+		"message": message,
+		"content": base64.StdEncoding.EncodeToString(content),
+		"sha":     sha,
+		"branch":  branch,
+	}
+	if err := c.do("PUT", "/repos/"+repo+"/contents/"+escapePath(path), body, nil); err != nil {
+		return fmt.Errorf("updating %s: %w", path, err)
+	}
+	return nil
+}
+
+// CreatePullRequest opens a pull request and returns its web URL
+func (c *Client) CreatePullRequest(repo string, pr PullRequest) (string, error) {
+	var created struct {
+		HTMLURL string `json:"html_url"`
+	}
+	if err := c.do("POST", "/repos/"+repo+"/pulls", pr, &created); err != nil {
+		return "", fmt.Errorf("creating pull request: %w", err)
+	}
+	return created.HTMLURL, nil
+}
+
+// escapePath escapes each segment of a slash-separated path
+func escapePath(path string) string {
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		segments[i] = url.PathEscape(segment)
+	}
+	return strings.Join(segments, "/")
+}
@@ -0,0 +1,97 @@
+package github
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestPullRequestWorkflow(t *testing.T) {
+	var created map[string]string
+	var updated map[string]string
+	var opened PullRequest
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /repos/owner/site", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"default_branch": "main"}`)
+	})
+	mux.HandleFunc("GET /repos/owner/site/git/ref/heads/main", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"object": {"sha": "abc123"}}`)
+	})
+	mux.HandleFunc("POST /repos/owner/site/git/refs", func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&created)
+		w.WriteHeader(http.StatusCreated)
+	})
+	mux.HandleFunc("GET /repos/owner/site/contents/content/my%20page.md", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("ref") != "main" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		encoded := base64.StdEncoding.EncodeToString([]byte("Hello, links!"))
+		fmt.Fprintf(w, `{"encoding": "base64", "sha": "blob1", "content": "%s\n%s"}`, encoded[:8], encoded[8:])
+	})
+	mux.HandleFunc("PUT /repos/owner/site/contents/content/my%20page.md", func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&updated)
+	})
+	mux.HandleFunc("POST /repos/owner/site/pulls", func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&opened)
+		w.WriteHeader(http.StatusCreated)
+		fmt.Fprint(w, `{"html_url": "https://github.com/owner/site/pull/9"}`)
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	client := NewClient(server.URL, "secret")
+
+	branch, err := client.DefaultBranch("owner/site")
+	if err != nil || branch != "main" {
+		t.Fatalf("Expected main, got %q (%v)", branch, err)
+	}
+	sha, err := client.BranchSHA("owner/site", "main")
+	if err != nil || sha != "abc123" {
+		t.Fatalf("Expected abc123, got %q (%v)", sha, err)
+	}
+	if err := client.CreateBranch("owner/site", "fixes", sha); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if created["ref"] != "refs/heads/fixes" || created["sha"] != "abc123" {
+		t.Errorf("Unexpected branch request: %v", created)
+	}
+
+	content, blob, err := client.FileContents("owner/site", "content/my page.md", "main")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if string(content) != "Hello, links!" || blob != "blob1" {
+		t.Errorf("Unexpected file: %q %q", content, blob)
+	}
+
+	if err := client.UpdateFile("owner/site", "content/my page.md", "fixes", "Fix links", []byte("Fixed"), blob); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if updated["branch"] != "fixes" || updated["sha"] != "blob1" || updated["content"] != base64.StdEncoding.EncodeToString([]byte("Fixed")) {
+		t.Errorf("Unexpected update request: %v", updated)
+	}
+
+	prURL, err := client.CreatePullRequest("owner/site", PullRequest{Title: "Fix links", Head: "fixes", Base: "main"})
+	if err != nil || prURL != "https://github.com/owner/site/pull/9" {
+		t.Fatalf("Expected pull request URL, got %q (%v)", prURL, err)
+	}
+	if opened.Head != "fixes" || opened.Base != "main" {
+		t.Errorf("Unexpected pull request: %+v", opened)
+	}
+}
+
+func TestClientErrorStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	defer server.Close()
+
+	if err := NewClient(server.URL, "").CreateBranch("owner/site", "fixes", "abc"); err == nil {
+		t.Error("Expected an error for a forbidden request")
+	}
+}
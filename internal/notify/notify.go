@@ -0,0 +1,140 @@
+// Package notify posts a summary of a run to a chat webhook, such as a
+// Slack, Microsoft Teams or Discord incoming webhook, so scheduled checks
+// alert a channel when links break.
+package notify
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// MaxTopLinks caps the broken links listed in Message.Top
+const MaxTopLinks = 10
+
+// DefaultTemplate is the text/template a Message is rendered with when no
+// template is configured
+const DefaultTemplate = `🔗 {{len .Broken}} broken links found{{with .Site}} on {{.}}{{end}} ({{.Checked}} checked)
+{{range .Top}}• {{.URL}} ({{if .StatusCode}}{{.StatusCode}}{{else}}{{.ErrorType}}{{end}})
+{{end}}{{with .More}}…and {{.}} more
+{{end}}{{with .RunURL}}{{.}}
+{{end}}`
+
+// Link is a broken link listed in a notification
+type Link struct {
+	URL        string
+	StatusCode int
+	ErrorType  string
+	Error      string
+	Sources    []string
+}
+
+// Message is what a notification template is rendered with
+type Message struct {
+	// Site lists the base URLs or sitemaps that were checked
+	Site    string
+	Checked int
+	Broken  []Link
+	// Top is the first MaxTopLinks of Broken, and More how many are left out
+	Top    []Link
+	More   int
+	RunURL string
+}
+
+// NewMessage returns a message about a run, filling in Top and More
+func NewMessage(site string, checked int, broken []Link, runURL string) Message {
+	top := broken
+	if len(top) > MaxTopLinks {
+		top = top[:MaxTopLinks]
+	}
+	return Message{
+		Site:    site,
+		Checked: checked,
+		Broken:  broken,
+		Top:     top,
+		More:    len(broken) - len(top),
+		RunURL:  runURL,
+	}
+}
+
+// ParseTemplate parses a notification text/template, or DefaultTemplate
+// when text is empty
+func ParseTemplate(text string) (*template.Template, error) {
+	if text == "" {
+		text = DefaultTemplate
+	}
+	tmpl, err := template.New("notification").Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("parsing template: %w", err)
+	}
+	return tmpl, nil
+}
+
+// Render renders a message with a text/template, or DefaultTemplate when
+// text is empty
+func Render(text string, message Message) (string, error) {
+	tmpl, err := ParseTemplate(text)
+	if err != nil {
+		return "", err
+	}
+	var b strings.Builder
+	if err := tmpl.Execute(&b, message); err != nil {
+		return "", fmt.Errorf("rendering template: %w", err)
+	}
+	return b.String(), nil
+}
+
+// payload wraps text in the JSON document the webhook at webhookURL expects.
+// Discord takes "content" of up to 2000 characters; Slack, Teams and the
+// many services compatible with Slack's webhooks take "text".
+func payload(webhookURL, text string) ([]byte, error) {
+	key, limit := "text", 40000
+	if u, err := url.Parse(webhookURL); err == nil {
+		host := strings.ToLower(u.Hostname())
+		if host == "discord.com" || host == "discordapp.com" || strings.HasSuffix(host, ".discord.com") {
+			key, limit = "content", 2000
+		}
+	}
+	if runes := []rune(text); len(runes) > limit {
+		text = string(runes[:limit-1]) + "…"
+	}
+	return json.Marshal(map[string]string{key: text})
+}
+
+// Send posts text to a webhook. Webhook URLs carry their secret, so errors
+// leave the URL out.
+func Send(webhookURL, text string) error {
+	body, err := payload(webhookURL, text)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequest("POST", webhookURL, bytes.NewReader(body))
+	if err != nil {
+		return errors.New("creating request: invalid webhook URL")
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	client := &http.Client{Timeout: 30 * time.Second}
+	resp, err := client.Do(req)
+	if err != nil {
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			err = urlErr.Err
+		}
+		return fmt.Errorf("posting to webhook: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		detail, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("webhook returned status %d: %s", resp.StatusCode, strings.TrimSpace(string(detail)))
+	}
+	return nil
+}
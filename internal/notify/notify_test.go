@@ -0,0 +1,125 @@
+package notify
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestNewMessage(t *testing.T) {
+	var broken []Link
+	for i := 0; i < MaxTopLinks+3; i++ {
+		broken = append(broken, Link{URL: fmt.Sprintf("https://example.com/%d", i), StatusCode: 404})
+	}
+
+	message := NewMessage("https://example.com", 50, broken, "")
+	if len(message.Top) != MaxTopLinks || message.More != 3 {
+		t.Errorf("Expected %d top links and 3 more, got %d and %d", MaxTopLinks, len(message.Top), message.More)
+	}
+
+	message = NewMessage("https://example.com", 50, broken[:2], "")
+	if len(message.Top) != 2 || message.More != 0 {
+		t.Errorf("Expected every link on top, got %d and %d more", len(message.Top), message.More)
+	}
+}
+
+func TestRender(t *testing.T) {
+	broken := []Link{
+		{URL: "https://example.com/gone", StatusCode: 404, ErrorType: "http_4xx"},
+		{URL: "https://nowhere.example/", ErrorType: "dns"},
+	}
+	message := NewMessage("https://example.com", 120, broken, "https://github.com/owner/site/actions/runs/42")
+
+	text, err := Render("", message)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	expected := `🔗 2 broken links found on https://example.com (120 checked)
+• https://example.com/gone (404)
+• https://nowhere.example/ (dns)
+https://github.com/owner/site/actions/runs/42
+`
+	if text != expected {
+		t.Errorf("Expected:\n%s\ngot:\n%s", expected, text)
+	}
+
+	text, err = Render("{{.Checked}} checked, first: {{(index .Broken 0).URL}}", message)
+	if err != nil || text != "120 checked, first: https://example.com/gone" {
+		t.Errorf("Expected the custom template, got %q (%v)", text, err)
+	}
+
+	if _, err := Render("{{.Missing", message); err == nil {
+		t.Error("Expected an error for an invalid template")
+	}
+	if _, err := Render("{{.Missing}}", Message{}); err == nil {
+		t.Error("Expected an error for an unknown field")
+	}
+}
+
+func TestPayload(t *testing.T) {
+	tests := []struct {
+		webhookURL string
+		key        string
+	}{
+		{"https://hooks.slack.com/services/T000/B000/XXXX", "text"},
+		{"https://example.webhook.office.com/webhookb2/abc", "text"},
+		{"https://discord.com/api/webhooks/1/abc", "content"},
+		{"https://chat.example.com/hooks/abc", "text"},
+	}
+	for _, test := range tests {
+		body, err := payload(test.webhookURL, "3 broken links")
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		var document map[string]string
+		if err := json.Unmarshal(body, &document); err != nil || document[test.key] != "3 broken links" {
+			t.Errorf("%s: expected the text under %q, got %s", test.webhookURL, test.key, body)
+		}
+	}
+
+	body, _ := payload("https://discord.com/api/webhooks/1/abc", strings.Repeat("x", 3000))
+	var document map[string]string
+	json.Unmarshal(body, &document)
+	if length := len([]rune(document["content"])); length != 2000 {
+		t.Errorf("Expected Discord messages to be cut to 2000 characters, got %d", length)
+	}
+}
+
+func TestSend(t *testing.T) {
+	var received map[string]string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/hooks/revoked" {
+			http.Error(w, "invalid_token", http.StatusForbidden)
+			return
+		}
+		if r.Method != http.MethodPost || r.Header.Get("Content-Type") != "application/json" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		data, _ := io.ReadAll(r.Body)
+		json.Unmarshal(data, &received)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	if err := Send(server.URL+"/hooks/abc", "2 broken links"); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if received["text"] != "2 broken links" {
+		t.Errorf("Expected the message to be posted, got %v", received)
+	}
+
+	err := Send(server.URL+"/hooks/revoked", "2 broken links")
+	if err == nil || !strings.Contains(err.Error(), "status 403: invalid_token") {
+		t.Errorf("Expected the webhook's error, got %v", err)
+	}
+
+	err = Send("http://127.0.0.1:1/hooks/secret-token", "2 broken links")
+	if err == nil || strings.Contains(err.Error(), "secret-token") {
+		t.Errorf("Expected an error leaving out the webhook URL, got %v", err)
+	}
+}
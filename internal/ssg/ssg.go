@@ -0,0 +1,306 @@
+// Package ssg infers link checker settings from static site generator
+// configuration, so the site's base URL and content layout don't have to be
+// repeated in the workflow.
+package ssg
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/joshbeard/link-validator/internal/config"
+)
+
+// Site holds the settings inferred from a generator's config file
+type Site struct {
+	Generator  string
+	BaseURL    string
+	ContentDir string
+	// PathRules map content files to site paths
+	PathRules []config.PathRule
+	// FileRules map site paths back to content files
+	FileRules []config.PathRule
+}
+
+// configFiles lists the config files recognized for each generator, in the
+// order they are looked for
+var configFiles = []struct {
+	name      string
+	generator string
+}{
+	{"hugo.toml", "hugo"},
+	{"hugo.yaml", "hugo"},
+	{"hugo.yml", "hugo"},
+	{"hugo.json", "hugo"},
+	{"config.toml", "hugo"},
+	{"config.yaml", "hugo"},
+	{"config.yml", "hugo"},
+	{"config.json", "hugo"},
+	{"_config.yml", "jekyll"},
+	{"_config.yaml", "jekyll"},
+	{"mkdocs.yml", "mkdocs"},
+	{"mkdocs.yaml", "mkdocs"},
+}
+
+// ErrNoConfig is returned by Detect when no known config file exists
+var ErrNoConfig = errors.New("no Hugo, Jekyll or MkDocs config found")
+
+// Detect looks for a Hugo, Jekyll or MkDocs config file in dir and loads the
+// first one found
+func Detect(dir string) (*Site, error) {
+	for _, candidate := range configFiles {
+		path := filepath.Join(dir, candidate.name)
+		if _, err := os.Stat(path); err == nil {
+			return Load(path)
+		}
+	}
+	return nil, ErrNoConfig
+}
+
+// Load reads a generator config file, recognizing the generator from its
+// file name. Paths in the inferred rules are relative to the directory
+// containing the config file.
+func Load(path string) (*Site, error) {
+	generator := ""
+	for _, candidate := range configFiles {
+		if filepath.Base(path) == candidate.name {
+			generator = candidate.generator
+			break
+		}
+	}
+	if generator == "" {
+		return nil, fmt.Errorf("unrecognized site config %s", path)
+	}
+
+	data, err := os.ReadFile(path) // #nosec G304 -- path is provided by the user
+	if err != nil {
+		return nil, fmt.Errorf("reading site config: %w", err)
+	}
+	settings, err := readSettings(data, filepath.Ext(path))
+	if err != nil {
+		return nil, fmt.Errorf("parsing site config %s: %w", path, err)
+	}
+
+	// Rules match repository paths, so a config in a subdirectory prefixes
+	// every content path with that directory
+	root := filepath.ToSlash(filepath.Dir(path)) + "/"
+	if root == "./" {
+		root = ""
+	}
+
+	switch generator {
+	case "hugo":
+		return hugoSite(settings, root), nil
+	case "jekyll":
+		return jekyllSite(settings, root), nil
+	default:
+		return mkdocsSite(settings, root), nil
+	}
+}
+
+// Apply fills in the settings cfg leaves unset: the base URL and the rules
+// mapping files to pages and back
+func (s *Site) Apply(cfg *config.Config) {
+	if cfg.BaseURL == "" && cfg.SitemapURL == "" {
+		cfg.BaseURL = s.BaseURL
+	}
+	if len(cfg.PathRules) == 0 {
+		cfg.PathRules = s.PathRules
+	}
+	if len(cfg.FileRules) == 0 {
+		cfg.FileRules = s.FileRules
+	}
+}
+
+// hugoSite infers the layout of a Hugo site: sections are _index.md files,
+// page bundles are index.md files and other pages are served as directories
+func hugoSite(settings map[string]string, root string) *Site {
+	site := &Site{Generator: "hugo", BaseURL: settings["baseurl"], ContentDir: root + "content"}
+	if dir := settings["contentdir"]; dir != "" {
+		site.ContentDir = root + strings.Trim(dir, "/")
+	}
+
+	content := regexp.QuoteMeta(site.ContentDir)
+	prefix := basePath(site.BaseURL)
+	site.PathRules = rules(
+		`^`+content+`/_index\.md$`, prefix+"/",
+		`^`+content+`/(.+)/_?index\.md$`, prefix+"/$1/",
+		`^`+content+`/(.+)\.md$`, prefix+"/$1/",
+	)
+	site.FileRules = rules(
+		`^`+regexp.QuoteMeta(prefix)+`/$`, site.ContentDir+"/_index.md",
+		`^`+regexp.QuoteMeta(prefix)+`/(.+?)/?$`, site.ContentDir+"/$1.md",
+	)
+	return site
+}
+
+// jekyllSite infers the layout of a Jekyll site from its permalink style.
+// Pages render to .html files unless permalinks are "pretty", and posts are
+// published under their date.
+func jekyllSite(settings map[string]string, root string) *Site {
+	site := &Site{Generator: "jekyll", ContentDir: strings.TrimSuffix(root, "/")}
+	if site.ContentDir == "" {
+		site.ContentDir = "."
+	}
+	if siteURL := settings["url"]; siteURL != "" {
+		site.BaseURL = strings.TrimSuffix(siteURL, "/") + "/" + strings.Trim(settings["baseurl"], "/")
+		site.BaseURL = strings.TrimSuffix(site.BaseURL, "/") + "/"
+	}
+
+	prefix := basePath(site.BaseURL)
+	quoted := regexp.QuoteMeta(prefix)
+	source := regexp.QuoteMeta(root)
+	post := `^` + source + `_posts/(\d{4})-(\d{2})-(\d{2})-(.+)\.(?:md|markdown|html)$`
+	if settings["permalink"] == "pretty" {
+		site.PathRules = rules(
+			post, prefix+"/$1/$2/$3/$4/",
+			`^`+source+`index\.(?:md|markdown|html)$`, prefix+"/",
+			`^`+source+`(.+)/index\.(?:md|markdown|html)$`, prefix+"/$1/",
+			`^`+source+`([^_].*)\.(?:md|markdown|html)$`, prefix+"/$1/",
+		)
+		site.FileRules = rules(
+			`^`+quoted+`/$`, root+"index.md",
+			`^`+quoted+`/(\d{4})/(\d{2})/(\d{2})/([^/]+)/$`, root+"_posts/$1-$2-$3-$4.md",
+			`^`+quoted+`/(.+?)/?$`, root+"$1.md",
+		)
+		return site
+	}
+
+	site.PathRules = rules(
+		post, prefix+"/$1/$2/$3/$4.html",
+		`^`+source+`index\.(?:md|markdown|html)$`, prefix+"/",
+		`^`+source+`(.+)/index\.(?:md|markdown|html)$`, prefix+"/$1/",
+		`^`+source+`([^_].*)\.(?:md|markdown|html)$`, prefix+"/$1.html",
+	)
+	site.FileRules = rules(
+		`^`+quoted+`/$`, root+"index.md",
+		`^`+quoted+`/(\d{4})/(\d{2})/(\d{2})/([^/]+)\.html$`, root+"_posts/$1-$2-$3-$4.md",
+		`^`+quoted+`/(.+)\.html$`, root+"$1.md",
+		`^`+quoted+`/(.+?)/?$`, root+"$1/index.md",
+	)
+	return site
+}
+
+// mkdocsSite infers the layout of an MkDocs site, which serves each page as a
+// directory unless use_directory_urls is disabled
+func mkdocsSite(settings map[string]string, root string) *Site {
+	site := &Site{Generator: "mkdocs", BaseURL: settings["site_url"], ContentDir: root + "docs"}
+	if dir := settings["docs_dir"]; dir != "" {
+		site.ContentDir = root + strings.Trim(dir, "/")
+	}
+
+	docs := regexp.QuoteMeta(site.ContentDir)
+	prefix := basePath(site.BaseURL)
+	quoted := regexp.QuoteMeta(prefix)
+	if directoryURLs, err := strconv.ParseBool(settings["use_directory_urls"]); err == nil && !directoryURLs {
+		site.PathRules = rules(
+			`^`+docs+`/(?:index|README)\.md$`, prefix+"/index.html",
+			`^`+docs+`/(.+)/(?:index|README)\.md$`, prefix+"/$1/index.html",
+			`^`+docs+`/(.+)\.md$`, prefix+"/$1.html",
+		)
+		site.FileRules = rules(
+			`^`+quoted+`/(?:index\.html)?$`, site.ContentDir+"/index.md",
+			`^`+quoted+`/(.+)/index\.html$`, site.ContentDir+"/$1/index.md",
+			`^`+quoted+`/(.+)\.html$`, site.ContentDir+"/$1.md",
+		)
+		return site
+	}
+
+	site.PathRules = rules(
+		`^`+docs+`/(?:index|README)\.md$`, prefix+"/",
+		`^`+docs+`/(.+)/(?:index|README)\.md$`, prefix+"/$1/",
+		`^`+docs+`/(.+)\.md$`, prefix+"/$1/",
+	)
+	site.FileRules = rules(
+		`^`+quoted+`/$`, site.ContentDir+"/index.md",
+		`^`+quoted+`/(.+?)/?$`, site.ContentDir+"/$1.md",
+	)
+	return site
+}
+
+// basePath returns the path of a base URL without its trailing slash, so
+// rules for sites served from a subdirectory include it
+func basePath(baseURL string) string {
+	u, err := url.Parse(baseURL)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSuffix(u.Path, "/")
+}
+
+// rules builds path rules from alternating pattern and replacement values
+func rules(pairs ...string) []config.PathRule {
+	var built []config.PathRule
+	for i := 0; i+1 < len(pairs); i += 2 {
+		built = append(built, config.PathRule{Pattern: regexp.MustCompile(pairs[i]), Replacement: pairs[i+1]})
+	}
+	return built
+}
+
+// readSettings extracts top-level scalar settings from a TOML, YAML or JSON
+// config. Nested tables and lists are skipped; keys are lowercased.
+func readSettings(data []byte, ext string) (map[string]string, error) {
+	settings := make(map[string]string)
+
+	if ext == ".json" {
+		var raw map[string]any
+		if err := json.Unmarshal(data, &raw); err != nil {
+			return nil, err
+		}
+		for key, value := range raw {
+			switch v := value.(type) {
+			case string:
+				settings[strings.ToLower(key)] = v
+			case bool:
+				settings[strings.ToLower(key)] = strconv.FormatBool(v)
+			}
+		}
+		return settings, nil
+	}
+
+	separator := ":"
+	if ext == ".toml" {
+		separator = "="
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if ext == ".toml" && strings.HasPrefix(strings.TrimSpace(line), "[") {
+			// Everything after the first table belongs to that table
+			break
+		}
+		if line == "" || line[0] == ' ' || line[0] == '\t' || line[0] == '#' {
+			continue
+		}
+
+		key, value, found := strings.Cut(line, separator)
+		if !found {
+			continue
+		}
+		settings[strings.ToLower(strings.TrimSpace(key))] = unquote(value)
+	}
+	return settings, scanner.Err()
+}
+
+// unquote trims a scalar value, dropping trailing comments and quotes
+func unquote(value string) string {
+	value = strings.TrimSpace(value)
+	if len(value) >= 2 && (value[0] == '"' || value[0] == '\'') {
+		if end := strings.IndexByte(value[1:], value[0]); end >= 0 {
+			return value[1 : end+1]
+		}
+	}
+	if idx := strings.Index(value, " #"); idx >= 0 {
+		value = strings.TrimSpace(value[:idx])
+	}
+	return value
+}
@@ -0,0 +1,229 @@
+package ssg
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/joshbeard/link-validator/internal/checker"
+	"github.com/joshbeard/link-validator/internal/config"
+)
+
+func writeConfig(t *testing.T, dir, name, content string) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestDetect(t *testing.T) {
+	t.Run("no config", func(t *testing.T) {
+		if _, err := Detect(t.TempDir()); !errors.Is(err, ErrNoConfig) {
+			t.Errorf("Expected ErrNoConfig, got %v", err)
+		}
+	})
+
+	t.Run("mkdocs", func(t *testing.T) {
+		dir := t.TempDir()
+		writeConfig(t, dir, "mkdocs.yml", "site_name: Docs\nsite_url: https://docs.example.com/\n")
+
+		site, err := Detect(dir)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if site.Generator != "mkdocs" || site.BaseURL != "https://docs.example.com/" {
+			t.Errorf("Unexpected site: %+v", site)
+		}
+	})
+}
+
+func TestLoadHugo(t *testing.T) {
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(t.TempDir()); err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = os.Chdir(wd) }()
+
+	writeConfig(t, ".", "hugo.toml", `baseURL = "https://example.com/blog/"
+title = "Example"
+
+[params]
+baseURL = "https://ignored.example.com/"
+`)
+
+	site, err := Load("hugo.toml")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if site.BaseURL != "https://example.com/blog/" {
+		t.Errorf("Expected base URL from the top-level table, got %q", site.BaseURL)
+	}
+	if site.ContentDir != "content" {
+		t.Errorf("Expected default content dir, got %q", site.ContentDir)
+	}
+
+	pages := map[string]string{
+		"content/_index.md":            "https://example.com/blog/",
+		"content/docs/_index.md":       "https://example.com/blog/docs/",
+		"content/docs/bundle/index.md": "https://example.com/blog/docs/bundle/",
+		"content/docs/page.md":         "https://example.com/blog/docs/page/",
+	}
+	for file, expected := range pages {
+		if got, ok := checker.MapFileToURL(file, site.PathRules, site.BaseURL); !ok || got != expected {
+			t.Errorf("File %s: expected %s, got %s (%v)", file, expected, got, ok)
+		}
+	}
+
+	files := map[string]string{
+		"https://example.com/blog/":           "content/_index.md",
+		"https://example.com/blog/docs/page/": "content/docs/page.md",
+	}
+	for page, expected := range files {
+		if got, ok := checker.MapURLToFile(page, site.FileRules); !ok || got != expected {
+			t.Errorf("Page %s: expected %s, got %s (%v)", page, expected, got, ok)
+		}
+	}
+}
+
+func TestLoadHugoContentDir(t *testing.T) {
+	path := writeConfig(t, t.TempDir(), "config.yaml", "baseURL: https://example.com/\ncontentDir: 'pages' # where pages live\n")
+
+	site, err := Load(path)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if expected := filepath.ToSlash(filepath.Dir(path)) + "/pages"; site.ContentDir != expected {
+		t.Errorf("Expected content dir %s, got %q", expected, site.ContentDir)
+	}
+}
+
+func TestLoadJekyll(t *testing.T) {
+	t.Run("default permalinks", func(t *testing.T) {
+		dir := t.TempDir()
+		path := writeConfig(t, dir, "site/_config.yml", "title: Blog\nurl: \"https://example.com\"\nbaseurl: /blog\n")
+
+		site, err := Load(path)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if site.BaseURL != "https://example.com/blog/" {
+			t.Errorf("Expected url and baseurl to be joined, got %q", site.BaseURL)
+		}
+
+		// Paths are prefixed with the directory holding the config
+		root := filepath.ToSlash(filepath.Dir(path)) + "/"
+		pages := map[string]string{
+			root + "index.md":                    "https://example.com/blog/",
+			root + "about.md":                    "https://example.com/blog/about.html",
+			root + "_posts/2024-05-01-launch.md": "https://example.com/blog/2024/05/01/launch.html",
+		}
+		for file, expected := range pages {
+			if got, ok := checker.MapFileToURL(file, site.PathRules, site.BaseURL); !ok || got != expected {
+				t.Errorf("File %s: expected %s, got %s (%v)", file, expected, got, ok)
+			}
+		}
+		if got, _ := checker.MapURLToFile("https://example.com/blog/2024/05/01/launch.html", site.FileRules); got != root+"_posts/2024-05-01-launch.md" {
+			t.Errorf("Expected post to map back to its file, got %q", got)
+		}
+	})
+
+	t.Run("pretty permalinks", func(t *testing.T) {
+		dir := t.TempDir()
+		path := writeConfig(t, dir, "_config.yml", "url: https://example.com\npermalink: pretty\n")
+
+		site, err := Load(path)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		root := filepath.ToSlash(dir) + "/"
+		if got, _ := checker.MapFileToURL(root+"_posts/2024-05-01-launch.md", site.PathRules, site.BaseURL); got != "https://example.com/2024/05/01/launch/" {
+			t.Errorf("Unexpected post URL %q", got)
+		}
+		if got, _ := checker.MapURLToFile("https://example.com/about/", site.FileRules); got != root+"about.md" {
+			t.Errorf("Unexpected page file %q", got)
+		}
+	})
+}
+
+func TestLoadMkDocs(t *testing.T) {
+	t.Run("directory urls", func(t *testing.T) {
+		path := writeConfig(t, t.TempDir(), "mkdocs.yml", "site_url: https://example.com/docs/\ndocs_dir: src\nnav:\n  - Home: index.md\n")
+
+		site, err := Load(path)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		root := filepath.ToSlash(filepath.Dir(path)) + "/"
+		if site.ContentDir != root+"src" {
+			t.Errorf("Expected docs_dir to set the content dir, got %q", site.ContentDir)
+		}
+		if got, _ := checker.MapFileToURL(root+"src/guide/setup.md", site.PathRules, site.BaseURL); got != "https://example.com/docs/guide/setup/" {
+			t.Errorf("Unexpected page URL %q", got)
+		}
+		if got, _ := checker.MapURLToFile("https://example.com/docs/", site.FileRules); got != root+"src/index.md" {
+			t.Errorf("Unexpected index file %q", got)
+		}
+	})
+
+	t.Run("html urls", func(t *testing.T) {
+		path := writeConfig(t, t.TempDir(), "mkdocs.yml", "site_url: https://example.com/\nuse_directory_urls: false\n")
+
+		site, err := Load(path)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		root := filepath.ToSlash(filepath.Dir(path)) + "/"
+		if got, _ := checker.MapFileToURL(root+"docs/guide/setup.md", site.PathRules, site.BaseURL); got != "https://example.com/guide/setup.html" {
+			t.Errorf("Unexpected page URL %q", got)
+		}
+		if got, _ := checker.MapURLToFile("https://example.com/guide/setup.html", site.FileRules); got != root+"docs/guide/setup.md" {
+			t.Errorf("Unexpected page file %q", got)
+		}
+	})
+}
+
+func TestLoadUnrecognized(t *testing.T) {
+	path := writeConfig(t, t.TempDir(), "site.toml", `baseURL = "https://example.com/"`)
+	if _, err := Load(path); err == nil {
+		t.Error("Expected an error for an unrecognized config file")
+	}
+}
+
+func TestApply(t *testing.T) {
+	site := &Site{
+		BaseURL:   "https://example.com/",
+		PathRules: config.ParsePathRules(`^content/(.+)\.md$=/$1/`),
+		FileRules: config.ParsePathRules(`^/(.+)/$=content/$1.md`),
+	}
+
+	t.Run("fills unset settings", func(t *testing.T) {
+		cfg := &config.Config{}
+		site.Apply(cfg)
+		if cfg.BaseURL != site.BaseURL || len(cfg.PathRules) != 1 || len(cfg.FileRules) != 1 {
+			t.Errorf("Expected site settings to be applied, got %+v", cfg)
+		}
+	})
+
+	t.Run("keeps explicit settings", func(t *testing.T) {
+		cfg := &config.Config{
+			SitemapURL: "https://example.com/sitemap.xml",
+			PathRules:  config.ParsePathRules(`^a$=/a/,^b$=/b/`),
+		}
+		site.Apply(cfg)
+		if cfg.BaseURL != "" {
+			t.Errorf("Expected base URL to stay unset alongside a sitemap, got %q", cfg.BaseURL)
+		}
+		if len(cfg.PathRules) != 2 {
+			t.Errorf("Expected explicit path rules to be kept, got %d", len(cfg.PathRules))
+		}
+	})
+}
@@ -0,0 +1,227 @@
+// Package linkchecker checks the links of websites, sitemaps and URL lists.
+// It is the API of the link-checker command for embedding in other Go
+// programs:
+//
+//	lc, err := linkchecker.New(linkchecker.WithConcurrency(5))
+//	if err != nil {
+//		return err
+//	}
+//	results, err := lc.CrawlSite("https://example.com")
+//	if err != nil {
+//		return err
+//	}
+//	for _, result := range results {
+//		if result.Broken() {
+//			fmt.Println(result.URL, result.Error)
+//		}
+//	}
+//
+// The package is a facade over the command's internal checker with a
+// deliberately narrow surface: the Options cover crawling, matching and
+// request behaviour, and a Result carries what a program needs to act on a
+// link. Reporting features of the command, such as fail-on policies,
+// severities, baselines and page audits, are left to the caller, who can
+// apply their own policy to the Results. Unlike the command, URLs aren't
+// normalized, so links to a fragment of a page are checked as written.
+package linkchecker
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"time"
+
+	"github.com/joshbeard/link-validator/internal/checker"
+	"github.com/joshbeard/link-validator/internal/config"
+)
+
+// Checker checks links with a fixed set of Options. Each call starts from a
+// clean state, so a Checker can be reused and called concurrently.
+type Checker struct {
+	options Options
+	config  config.Config
+}
+
+// Result is the outcome of checking one URL
+type Result struct {
+	URL        string
+	StatusCode int
+	// Error describes why the link is flagged, empty for working links
+	Error string
+	// ErrorType classifies the error, such as "http_4xx", "timeout" or
+	// "soft_404"
+	ErrorType string
+	// ErrorDetail narrows down DNS, connection and TLS errors, such as
+	// "nxdomain" or "connection_refused"
+	ErrorDetail string
+	Duration    time.Duration
+	// Sources are the pages linking to the URL, when it was found by a crawl
+	Sources  []string
+	FinalURL string
+	External bool
+	// Category is the kind of link: "internal", "external", "asset" or
+	// "anchor" for a link to a fragment of a page on the site
+	Category string
+}
+
+// Broken reports whether the link is broken, as opposed to working or
+// unverifiable, such as a link to a login page
+func (r Result) Broken() bool {
+	return checker.ErrorType(r.ErrorType).IsFailure()
+}
+
+// New creates a Checker with the given options. It returns an error for an
+// invalid pattern.
+func New(opts ...Option) (*Checker, error) {
+	options := defaultOptions()
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	// Settings without an Option take the command's defaults
+	cfg := config.Config{
+		UserAgent:           options.UserAgent,
+		Timeout:             options.Timeout,
+		MaxConcurrent:       options.MaxConcurrent,
+		MaxRetries:          options.MaxRetries,
+		MaxDepth:            options.MaxDepth,
+		MaxPages:            options.MaxPages,
+		MaxRedirects:        options.MaxRedirects,
+		MaxBodyMB:           options.MaxBodyMB,
+		AcceptStatus:        options.AcceptStatus,
+		Headers:             options.Headers,
+		CheckExternal:       options.CheckExternal,
+		RetryBudget:         config.DefaultRetryBudget,
+		ExternalConcurrency: config.DefaultExternalConcurrency,
+		GetFallbackStatus:   config.ParseStatusCodes(config.DefaultGetFallbackStatus),
+		LoginPatterns:       config.ParsePatterns(config.DefaultLoginPatterns),
+		FrontierSpill:       config.DefaultFrontierSpill,
+	}
+	if cfg.MaxConcurrent <= 0 {
+		return nil, fmt.Errorf("concurrency must be positive, got %d", cfg.MaxConcurrent)
+	}
+	var err error
+	if cfg.ExcludePatterns, err = compilePatterns(options.ExcludePatterns); err != nil {
+		return nil, err
+	}
+	if cfg.IncludePatterns, err = compilePatterns(options.IncludePatterns); err != nil {
+		return nil, err
+	}
+	if cfg.Soft404Patterns, err = compilePatterns(options.Soft404Patterns); err != nil {
+		return nil, err
+	}
+	return &Checker{options: options, config: cfg}, nil
+}
+
+// Options returns the options the Checker was created with
+func (c *Checker) Options() Options {
+	return c.options
+}
+
+// CheckURLs checks the given URLs and returns their results in the same
+// order. Excluded URLs are skipped.
+func (c *Checker) CheckURLs(urls []string) []Result {
+	inner := c.newChecker(config.Config{})
+	defer inner.Close()
+	var checked []string
+	for _, u := range urls {
+		if !excluded(u, c.config.ExcludePatterns, c.config.IncludePatterns) {
+			checked = append(checked, u)
+		}
+	}
+	return convertResults(inner.CheckLinks(checked))
+}
+
+// CheckSitemap checks the URLs listed in a sitemap or sitemap index
+func (c *Checker) CheckSitemap(sitemapURL string) ([]Result, error) {
+	inner := c.newChecker(config.Config{SitemapURL: sitemapURL})
+	defer inner.Close()
+	urls, err := inner.GetURLsFromSitemap(sitemapURL)
+	if err != nil {
+		return nil, err
+	}
+	return convertResults(inner.CheckLinks(urls)), nil
+}
+
+// CrawlSite crawls a site from baseURL, within the depth or page limit, and
+// checks the pages and resources it finds. Links to other hosts are checked
+// too with WithExternalLinks.
+func (c *Checker) CrawlSite(baseURL string) ([]Result, error) {
+	inner := c.newChecker(config.Config{BaseURL: baseURL})
+	defer inner.Close()
+	depth := c.config.MaxDepth
+	if c.config.MaxPages > 0 {
+		depth = math.MaxInt
+	}
+	urls, err := inner.CrawlWebsite(baseURL, depth)
+	if err != nil {
+		return nil, err
+	}
+	results := inner.CheckLinks(urls)
+	if c.config.CheckExternal {
+		results = append(results, inner.CheckExternalLinks(inner.ExternalLinks())...)
+	}
+	return convertResults(results), nil
+}
+
+// newChecker returns a checker in a clean state for the site of a call,
+// given by the BaseURL or SitemapURL of site. Headers are only sent to it.
+func (c *Checker) newChecker(site config.Config) *checker.Checker {
+	cfg := c.config
+	cfg.BaseURL = site.BaseURL
+	cfg.SitemapURL = site.SitemapURL
+	return checker.New(&cfg)
+}
+
+// excluded reports whether a URL matches an exclude pattern, or include
+// patterns are set and it matches none of them
+func excluded(u string, exclude, include []*regexp.Regexp) bool {
+	for _, pattern := range exclude {
+		if pattern.MatchString(u) {
+			return true
+		}
+	}
+	if len(include) == 0 {
+		return false
+	}
+	for _, pattern := range include {
+		if pattern.MatchString(u) {
+			return false
+		}
+	}
+	return true
+}
+
+// compilePatterns compiles regular expressions, failing on the first
+// invalid one
+func compilePatterns(patterns []string) ([]*regexp.Regexp, error) {
+	var compiled []*regexp.Regexp
+	for _, pattern := range patterns {
+		regex, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid pattern %q: %w", pattern, err)
+		}
+		compiled = append(compiled, regex)
+	}
+	return compiled, nil
+}
+
+// convertResults converts the checker's results to the Result type
+func convertResults(results []checker.LinkResult) []Result {
+	converted := make([]Result, 0, len(results))
+	for _, result := range results {
+		converted = append(converted, Result{
+			URL:         result.URL,
+			StatusCode:  result.StatusCode,
+			Error:       result.Error,
+			ErrorType:   string(result.ErrorType),
+			ErrorDetail: result.ErrorDetail,
+			Duration:    result.Elapsed(),
+			Sources:     result.Sources,
+			FinalURL:    result.FinalURL,
+			External:    result.External,
+			Category:    string(result.Category),
+		})
+	}
+	return converted
+}
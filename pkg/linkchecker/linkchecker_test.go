@@ -0,0 +1,164 @@
+package linkchecker
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/joshbeard/link-validator/internal/config"
+)
+
+func newTestServer() *httptest.Server {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "text/html")
+		fmt.Fprint(w, `<a href="/about">About</a><a href="/missing">Missing</a><a href="/private/">Private</a>`)
+	})
+	mux.HandleFunc("/about", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		fmt.Fprint(w, `<p>About us</p>`)
+	})
+	mux.HandleFunc("/sitemap.xml", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/xml")
+		fmt.Fprintf(w, `<?xml version="1.0"?><urlset xmlns="http://www.sitemaps.org/schemas/sitemap/0.9"><url><loc>http://%s/about</loc></url></urlset>`, r.Host)
+	})
+	return httptest.NewServer(mux)
+}
+
+func TestCrawlSite(t *testing.T) {
+	server := newTestServer()
+	defer server.Close()
+
+	lc, err := New(WithTimeout(5*time.Second), WithConcurrency(1), WithExcludePatterns("/private/"))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	results, err := lc.CrawlSite(server.URL + "/")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	broken := make(map[string]bool)
+	for _, result := range results {
+		if result.URL == server.URL+"/private/" {
+			t.Error("Expected the excluded URL not to be checked")
+		}
+		broken[result.URL] = result.Broken()
+	}
+	if len(broken) != 3 || broken[server.URL+"/about"] || !broken[server.URL+"/missing"] {
+		t.Errorf("Unexpected results %+v", results)
+	}
+}
+
+func TestCheckSitemap(t *testing.T) {
+	server := newTestServer()
+	defer server.Close()
+
+	lc, err := New(WithConcurrency(1))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	results, err := lc.CheckSitemap(server.URL + "/sitemap.xml")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(results) != 1 || results[0].URL != server.URL+"/about" || results[0].StatusCode != 200 {
+		t.Errorf("Unexpected results %+v", results)
+	}
+}
+
+func TestCheckURLs(t *testing.T) {
+	server := newTestServer()
+	defer server.Close()
+
+	lc, err := New(WithConcurrency(1), WithIncludePatterns("/about$", "/missing$"), WithAcceptStatus(404))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	results := lc.CheckURLs([]string{server.URL + "/about", server.URL + "/missing", server.URL + "/other"})
+	if len(results) != 2 {
+		t.Fatalf("Expected 2 results, got %+v", results)
+	}
+	for _, result := range results {
+		if result.Broken() || result.Duration <= 0 {
+			t.Errorf("Expected %s to work with an accepted status, got %+v", result.URL, result)
+		}
+	}
+
+	lc, err = New(WithConcurrency(1))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	anchors := lc.CheckURLs([]string{server.URL + "/about#team"})
+	if len(anchors) != 1 || anchors[0].Category != "anchor" {
+		t.Errorf("Expected a link to a fragment to be an anchor, got %+v", anchors)
+	}
+}
+
+func TestNew(t *testing.T) {
+	if _, err := New(WithExcludePatterns("(")); err == nil {
+		t.Error("Expected an error for an invalid pattern")
+	}
+	if _, err := New(WithConcurrency(0)); err == nil {
+		t.Error("Expected an error for zero concurrency")
+	}
+
+	lc, err := New(WithUserAgent("Embedder/1.0"), WithMaxPages(50))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	options := lc.Options()
+	if options.UserAgent != "Embedder/1.0" || options.MaxPages != 50 || options.MaxDepth != 3 || options.Timeout != 30*time.Second || options.MaxBodyMB != 50 {
+		t.Errorf("Unexpected options %+v", options)
+	}
+}
+
+func TestNewMatchesCommandDefaults(t *testing.T) {
+	for _, key := range []string{
+		"INPUT_USER_AGENT", "INPUT_TIMEOUT", "INPUT_MAX_CONCURRENT", "INPUT_MAX_RETRIES",
+		"INPUT_RETRY_BUDGET", "INPUT_MAX_DEPTH", "INPUT_MAX_PAGES", "INPUT_MAX_REDIRECTS",
+		"INPUT_MAX_BODY_MB", "INPUT_EXTERNAL_MAX_CONCURRENT", "INPUT_GET_FALLBACK_STATUS",
+		"INPUT_LOGIN_PATTERNS", "INPUT_FRONTIER_SPILL",
+	} {
+		t.Setenv(key, "")
+	}
+	cli, err := config.FromEnvironment()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	lc, err := New()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	got := lc.config
+	for _, field := range []struct {
+		name      string
+		got, want any
+	}{
+		{"UserAgent", got.UserAgent, cli.UserAgent},
+		{"Timeout", got.Timeout, cli.Timeout},
+		{"MaxConcurrent", got.MaxConcurrent, cli.MaxConcurrent},
+		{"MaxRetries", got.MaxRetries, cli.MaxRetries},
+		{"RetryBudget", got.RetryBudget, cli.RetryBudget},
+		{"MaxDepth", got.MaxDepth, cli.MaxDepth},
+		{"MaxPages", got.MaxPages, cli.MaxPages},
+		{"MaxRedirects", got.MaxRedirects, cli.MaxRedirects},
+		{"MaxBodyMB", got.MaxBodyMB, cli.MaxBodyMB},
+		{"ExternalConcurrency", got.ExternalConcurrency, cli.ExternalConcurrency},
+		{"GetFallbackStatus", got.GetFallbackStatus, cli.GetFallbackStatus},
+		{"LoginPatterns", fmt.Sprint(got.LoginPatterns), fmt.Sprint(cli.LoginPatterns)},
+		{"FrontierSpill", got.FrontierSpill, cli.FrontierSpill},
+	} {
+		if !reflect.DeepEqual(field.got, field.want) {
+			t.Errorf("Expected %s %v as the command has, got %v", field.name, field.want, field.got)
+		}
+	}
+}
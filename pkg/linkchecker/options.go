@@ -0,0 +1,116 @@
+package linkchecker
+
+import (
+	"net/http"
+	"time"
+)
+
+// Options configure a Checker. Use the With functions to set them.
+type Options struct {
+	UserAgent       string
+	Timeout         time.Duration
+	MaxConcurrent   int
+	MaxRetries      int
+	MaxDepth        int
+	MaxPages        int
+	MaxRedirects    int
+	MaxBodyMB       int
+	ExcludePatterns []string
+	IncludePatterns []string
+	Soft404Patterns []string
+	AcceptStatus    []int
+	Headers         http.Header
+	CheckExternal   bool
+}
+
+// Option sets one of a Checker's Options
+type Option func(*Options)
+
+// defaultOptions returns the Options a Checker starts from, matching the
+// defaults of the link-checker command
+func defaultOptions() Options {
+	return Options{
+		UserAgent:     "GitHub-Action-Link-Checker/1.0",
+		Timeout:       30 * time.Second,
+		MaxConcurrent: 10,
+		MaxDepth:      3,
+		MaxRedirects:  10,
+		MaxBodyMB:     50,
+	}
+}
+
+// WithUserAgent sets the User-Agent header sent with every request
+func WithUserAgent(userAgent string) Option {
+	return func(o *Options) { o.UserAgent = userAgent }
+}
+
+// WithTimeout sets the timeout of each request
+func WithTimeout(timeout time.Duration) Option {
+	return func(o *Options) { o.Timeout = timeout }
+}
+
+// WithConcurrency sets how many requests are made at once, and per second
+func WithConcurrency(n int) Option {
+	return func(o *Options) { o.MaxConcurrent = n }
+}
+
+// WithRetries sets how often a link failing with a transient error is
+// checked again
+func WithRetries(n int) Option {
+	return func(o *Options) { o.MaxRetries = n }
+}
+
+// WithMaxDepth sets how many links deep a crawl follows from its start page
+func WithMaxDepth(depth int) Option {
+	return func(o *Options) { o.MaxDepth = depth }
+}
+
+// WithMaxPages limits a crawl to fetching this many pages, breadth first,
+// instead of limiting its depth
+func WithMaxPages(pages int) Option {
+	return func(o *Options) { o.MaxPages = pages }
+}
+
+// WithMaxBodyMB limits how many MiB of each page, sitemap or JSON body are
+// read, 0 for no limit. Larger pages are parsed up to the limit.
+func WithMaxBodyMB(mb int) Option {
+	return func(o *Options) { o.MaxBodyMB = mb }
+}
+
+// WithMaxRedirects sets how many redirects are followed for each link
+func WithMaxRedirects(n int) Option {
+	return func(o *Options) { o.MaxRedirects = n }
+}
+
+// WithExcludePatterns skips URLs matching any of the regular expressions
+func WithExcludePatterns(patterns ...string) Option {
+	return func(o *Options) { o.ExcludePatterns = append(o.ExcludePatterns, patterns...) }
+}
+
+// WithIncludePatterns only crawls and checks URLs matching one of the
+// regular expressions
+func WithIncludePatterns(patterns ...string) Option {
+	return func(o *Options) { o.IncludePatterns = append(o.IncludePatterns, patterns...) }
+}
+
+// WithSoft404Patterns reports pages answering with a 2xx status whose
+// content matches one of the regular expressions as broken
+func WithSoft404Patterns(patterns ...string) Option {
+	return func(o *Options) { o.Soft404Patterns = append(o.Soft404Patterns, patterns...) }
+}
+
+// WithAcceptStatus treats the status codes as working links
+func WithAcceptStatus(codes ...int) Option {
+	return func(o *Options) { o.AcceptStatus = append(o.AcceptStatus, codes...) }
+}
+
+// WithHeaders sends the headers with requests to the site being checked,
+// but not to other hosts
+func WithHeaders(headers http.Header) Option {
+	return func(o *Options) { o.Headers = headers.Clone() }
+}
+
+// WithExternalLinks also checks the links to other hosts found by a crawl
+func WithExternalLinks(check bool) Option {
+	return func(o *Options) { o.CheckExternal = check }
+}